@@ -0,0 +1,152 @@
+// Command kakei-admin runs maintenance tasks against the kakei-board database.
+//
+// Usage:
+//
+//	kakei-admin projections list
+//	kakei-admin projections rebuild <name>
+//	kakei-admin snapshots delete <aggregate-type>
+//	kakei-admin outbox dead
+//	kakei-admin outbox requeue <message-id>
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/database"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/ledger"
+	"github.com/kikeda1102/kakei-board/backend/internal/outbox"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+	"github.com/kikeda1102/kakei-board/backend/internal/settlement"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+	"github.com/kikeda1102/kakei-board/backend/internal/upcasters"
+	"github.com/kikeda1102/kakei-board/backend/internal/xp"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+const usage = `usage:
+  kakei-admin projections list
+  kakei-admin projections rebuild <name>
+  kakei-admin snapshots delete <aggregate-type>
+  kakei-admin outbox dead
+  kakei-admin outbox requeue <message-id>`
+
+// deadLetterLimit caps how many dead letters "outbox dead" prints.
+const deadLetterLimit = 100
+
+func main() {
+	args := os.Args[1:]
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("database config: %v", err)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		log.Fatalf("database open: %v", err)
+	}
+	defer db.Close()
+
+	if err := migrations.Run(db); err != nil {
+		log.Fatalf("migrations: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	defs := projectionDefinitions(db)
+
+	switch cmd := args[0] + " " + args[1]; {
+	case cmd == "snapshots delete" && len(args) == 3:
+		n, err := snapshot.NewMySQLStore(db).DeleteType(ctx, args[2])
+		if err != nil {
+			log.Fatalf("delete snapshots: %v", err)
+		}
+		log.Printf("deleted %d %s snapshots", n, args[2])
+	case cmd == "outbox dead" && len(args) == 2:
+		letters, err := outbox.NewMySQLStore(db, nil).DeadLetters(ctx, deadLetterLimit)
+		if err != nil {
+			log.Fatalf("list dead letters: %v", err)
+		}
+		for _, d := range letters {
+			fmt.Printf("%d\t%s\tevent %d\t%d attempts\t%s\t%s\n", d.ID, d.Subscriber, d.EventID,
+				d.Attempts, d.DeadAt.Format(time.RFC3339), d.LastError)
+		}
+	case cmd == "outbox requeue" && len(args) == 3:
+		id, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("invalid message id %q", args[2])
+		}
+		ok, err := outbox.NewMySQLStore(db, nil).Requeue(ctx, id)
+		if err != nil {
+			log.Fatalf("requeue: %v", err)
+		}
+		if !ok {
+			log.Fatalf("no dead-lettered message %d", id)
+		}
+		log.Printf("requeued outbox message %d", id)
+	case cmd == "projections list" && len(args) == 2:
+		names := make([]string, 0, len(defs))
+		for name := range defs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case cmd == "projections rebuild" && len(args) == 3:
+		def, ok := defs[args[2]]
+		if !ok {
+			log.Fatalf("unknown projection %q", args[2])
+		}
+		store := eventstore.NewUpcastingStore(eventstore.NewMySQLStore(db), upcasters.All())
+		rebuilder := projection.NewRebuilder(db, store, projection.NewMySQLCheckpointStore(db))
+		pos, err := rebuilder.Rebuild(ctx, def)
+		if err != nil {
+			log.Fatalf("rebuild %s: %v", def.Name, err)
+		}
+		log.Printf("rebuilt %s; checkpoint reset to position %d", def.Name, pos)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// projectionDefinitions returns every rebuildable projection keyed by name.
+func projectionDefinitions(db *sql.DB) map[string]projection.Definition {
+	defs := []projection.Definition{
+		household.ProjectionDefinition(db),
+		expense.ProjectionDefinition(db),
+		income.ProjectionDefinition(db),
+		summary.ProjectionDefinition(db),
+		settlement.ProjectionDefinition(db),
+		ledger.ProjectionDefinition(db),
+		score.ProjectionDefinition(db),
+		xp.ProjectionDefinition(db),
+	}
+
+	byName := make(map[string]projection.Definition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	return byName
+}
@@ -2,22 +2,57 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/achievement"
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
 	"github.com/kikeda1102/kakei-board/backend/internal/database"
 	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
 	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/idempotency"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/ledger"
 	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/outbox"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+	"github.com/kikeda1102/kakei-board/backend/internal/settlement"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+	"github.com/kikeda1102/kakei-board/backend/internal/upcasters"
+	"github.com/kikeda1102/kakei-board/backend/internal/user"
+	"github.com/kikeda1102/kakei-board/backend/internal/xp"
 	"github.com/kikeda1102/kakei-board/backend/migrations"
 )
 
-const shutdownTimeout = 10 * time.Second
+const (
+	shutdownTimeout = 10 * time.Second
+	// writeTimeout is how long the server spends on a request before it
+	// closes the connection.
+	writeTimeout = 30 * time.Second
+
+	// idempotencyStaleAfter is how long an idempotency key stays reserved by
+	// a request in flight before a retry may take it over. It must outlast
+	// any request, so it is writeTimeout plus a margin for a handler that
+	// is still finishing when its connection is closed.
+	idempotencyStaleAfter = writeTimeout + 30*time.Second
+
+	defaultIdempotencyTTL      = 24 * time.Hour
+	idempotencyCleanupInterval = time.Hour
+
+	defaultTokenTTL = 24 * time.Hour
+)
 
 func main() {
 	port := os.Getenv("APP_PORT")
@@ -25,25 +60,65 @@ func main() {
 		port = "8080"
 	}
 
-	cfg, err := database.ConfigFromEnv()
+	cfg, err := configFromEnv()
 	if err != nil {
-		log.Fatalf("database config: %v", err)
+		log.Fatalf("config: %v", err)
 	}
 
-	db, err := database.Open(cfg)
-	if err != nil {
-		log.Fatalf("database open: %v", err)
+	var d deps
+	switch storage := os.Getenv("APP_STORAGE"); storage {
+	case "", "mysql":
+		if len(cfg.authSecret) == 0 {
+			log.Fatalf("config: AUTH_SECRET must be set to at least %d bytes", user.MinSecretLength)
+		}
+		db := openMySQL()
+		defer db.Close()
+		d = mysqlDeps(db)
+	case "memory":
+		log.Println("APP_STORAGE=memory: all data is kept in memory and lost on exit")
+		if len(cfg.authSecret) == 0 {
+			log.Println("AUTH_SECRET is not set: tokens are signed with a random secret and invalidated on exit")
+			cfg.authSecret = make([]byte, user.MinSecretLength)
+			if _, err := rand.Read(cfg.authSecret); err != nil {
+				log.Fatalf("generate auth secret: %v", err)
+			}
+		}
+		d = memoryDeps()
+	default:
+		log.Fatalf("unknown APP_STORAGE %q (want mysql or memory)", storage)
 	}
-	defer db.Close()
 
-	if err := migrations.Run(db); err != nil {
-		log.Fatalf("migrations: %v", err)
+	runnerCtx, stopRunner := context.WithCancel(context.Background())
+	defer stopRunner()
+	var background sync.WaitGroup
+	for _, run := range []func(context.Context){
+		buildRunner(d).Run,
+		buildDispatcher(d).Run,
+		func(ctx context.Context) {
+			idempotency.RunCleaner(ctx, d.idempotency, idempotencyCleanupInterval)
+		},
+	} {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run(runnerCtx)
+		}()
 	}
+	backgroundDone := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(backgroundDone)
+	}()
 
-	handler := buildHandler(db)
+	tokens, err := user.NewTokens(cfg.authSecret, cfg.tokenTTL)
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	handler := buildHandler(d, cfg, tokens)
 	srv := &http.Server{
-		Addr:    ":" + port,
-		Handler: handler,
+		Addr:         ":" + port,
+		Handler:      handler,
+		WriteTimeout: writeTimeout,
 	}
 
 	// graceful shutdown
@@ -66,19 +141,181 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("shutdown: %v", err)
 	}
+
+	// Stop projections and the outbox only after in-flight requests have
+	// finished appending.
+	stopRunner()
+	select {
+	case <-backgroundDone:
+	case <-ctx.Done():
+		log.Println("background workers did not stop in time")
+	}
 	log.Println("server stopped")
 }
 
-func buildHandler(db *sql.DB) http.Handler {
-	mux := http.NewServeMux()
+// config holds settings read from the environment.
+type config struct {
+	idempotencyTTL time.Duration
+	// authSecret signs bearer tokens. It is empty if AUTH_SECRET is unset.
+	authSecret []byte
+	tokenTTL   time.Duration
+}
+
+func configFromEnv() (config, error) {
+	cfg := config{idempotencyTTL: defaultIdempotencyTTL, tokenTTL: defaultTokenTTL}
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return config{}, fmt.Errorf("IDEMPOTENCY_TTL must be a positive duration such as 24h, got %q", v)
+		}
+		cfg.idempotencyTTL = ttl
+	}
+	if v := os.Getenv("AUTH_SECRET"); v != "" {
+		if len(v) < user.MinSecretLength {
+			return config{}, fmt.Errorf("AUTH_SECRET must be at least %d bytes", user.MinSecretLength)
+		}
+		cfg.authSecret = []byte(v)
+	}
+	if v := os.Getenv("AUTH_TOKEN_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return config{}, fmt.Errorf("AUTH_TOKEN_TTL must be a positive duration such as 24h, got %q", v)
+		}
+		cfg.tokenTTL = ttl
+	}
+	return cfg, nil
+}
 
-	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+func openMySQL() *sql.DB {
+	cfg, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("database config: %v", err)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		log.Fatalf("database open: %v", err)
+	}
+
+	if err := migrations.Run(db); err != nil {
+		db.Close()
+		log.Fatalf("migrations: %v", err)
+	}
+	return db
+}
+
+// deps holds the storage-backed dependencies shared by the HTTP handler,
+// the projection runner and the outbox dispatcher.
+type deps struct {
+	ping        func(ctx context.Context) error
+	store       eventstore.Store
+	checkpoints projection.CheckpointStore
+	snapshots   snapshot.Store
+	memberships household.ReadModel
+	expenses    expense.ReadModel
+	incomes     income.ReadModel
+	summaries   summary.ReadModel
+	settlements settlement.ReadModel
+	ledger      ledger.ReadModel
+	scores      score.ReadModel
+	xp          xp.ReadModel
+	outbox      outbox.Store
+	subscribers []outbox.Subscriber
+	idempotency idempotency.Store
+}
+
+func mysqlDeps(db *sql.DB) deps {
+	events := eventstore.NewMySQLStore(db)
+	d := deps{
+		ping:        db.PingContext,
+		store:       eventstore.NewUpcastingStore(events, upcasters.All()),
+		checkpoints: projection.NewMySQLCheckpointStore(db),
+		snapshots:   snapshot.NewMySQLStore(db),
+		memberships: household.NewRepository(db),
+		expenses:    expense.NewRepository(db),
+		incomes:     income.NewRepository(db),
+		summaries:   summary.NewRepository(db),
+		settlements: settlement.NewRepository(db),
+		ledger:      ledger.NewRepository(db),
+		scores:      score.NewRepository(db),
+		xp:          xp.NewRepository(db),
+		idempotency: idempotency.NewMySQLStore(db),
+	}
+	d.subscribers = buildSubscribers(d)
+	outboxStore := outbox.NewMySQLStore(db, outbox.Names(d.subscribers...))
+	events.OnAppend = outboxStore.Enqueue
+	d.outbox = outboxStore
+	return d
+}
+
+// memoryDeps wires every dependency in memory, so the server and its tests
+// can run without a database.
+func memoryDeps() deps {
+	events := eventstore.NewMemoryStore()
+	d := deps{
+		ping:        func(context.Context) error { return nil },
+		store:       eventstore.NewUpcastingStore(events, upcasters.All()),
+		checkpoints: projection.NewMemoryCheckpointStore(),
+		snapshots:   snapshot.NewMemoryStore(),
+		memberships: household.NewMemoryRepository(),
+		expenses:    expense.NewMemoryRepository(),
+		incomes:     income.NewMemoryRepository(),
+		summaries:   summary.NewMemoryRepository(),
+		settlements: settlement.NewMemoryRepository(),
+		ledger:      ledger.NewMemoryRepository(),
+		scores:      score.NewMemoryRepository(),
+		xp:          xp.NewMemoryRepository(),
+		idempotency: idempotency.NewMemoryStore(),
+	}
+	d.subscribers = buildSubscribers(d)
+	outboxStore := outbox.NewMemoryStore(outbox.Names(d.subscribers...))
+	events.OnAppend = outboxStore.Enqueue
+	d.outbox = outboxStore
+	return d
+}
+
+func buildRunner(d deps) *projection.Runner {
+	return projection.NewRunner(d.store, d.checkpoints,
+		household.NewProjector(d.memberships),
+		expense.NewProjector(d.expenses),
+		income.NewProjector(d.incomes),
+		summary.NewProjector(d.summaries),
+		settlement.NewProjector(d.settlements),
+		ledger.NewProjector(d.ledger),
+		score.NewProjector(d.scores),
+		xp.NewProjector(d.xp),
+	)
+}
+
+// buildSubscribers returns the outbox subscribers, which react to events
+// with side effects that must not be lost, such as notifications. Read
+// models belong in buildRunner instead, since projections can be rebuilt
+// from the log and subscribers only see events appended after they were
+// registered.
+func buildSubscribers(d deps) []outbox.Subscriber {
+	changes := score.NewChangeLog(d.scores, d.checkpoints)
+	return []outbox.Subscriber{
+		achievement.NewEngine(d.store, changes),
+		xp.NewAwarder(d.store, changes),
+	}
+}
+
+func buildDispatcher(d deps) *outbox.Dispatcher {
+	return outbox.NewDispatcher(d.outbox, d.store, d.subscribers...)
+}
+
+// buildHandler serves health checks, registration and login to anyone, and
+// every other route only to requests with a bearer token from tokens.
+func buildHandler(d deps, cfg config, tokens *user.Tokens) http.Handler {
+	public := http.NewServeMux()
+
+	public.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
 		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 		defer cancel()
 
-		if err := db.PingContext(ctx); err != nil {
+		if err := d.ping(ctx); err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			log.Printf("health check failed: %v", err)
 			if _, wErr := w.Write([]byte(`{"status":"unhealthy"}`)); wErr != nil {
@@ -93,11 +330,29 @@ func buildHandler(db *sql.DB) http.Handler {
 		}
 	})
 
-	store := eventstore.NewMySQLStore(db)
-	projector := expense.NewProjector(db)
-	repo := expense.NewRepository(db)
-	expenseHandler := expense.NewHandler(store, projector, repo)
+	user.NewHandler(d.store, tokens).Register(public)
+
+	mux := http.NewServeMux()
+	books := household.NewBooks(d.store, d.snapshots)
+	accounts := account.NewAccounts(d.store)
+	expenseHandler := expense.NewHandler(d.store, d.expenses, books, accounts)
 	expenseHandler.Register(mux)
+	income.NewHandler(d.store, d.incomes, books, accounts).Register(mux)
+	household.NewHandler(d.store, d.snapshots, d.memberships).Register(mux)
+	summary.NewHandler(d.summaries, d.incomes, books).Register(mux)
+	settlement.NewHandler(d.settlements, books).Register(mux)
+	account.NewHandler(d.store, books).Register(mux)
+	ledger.NewHandler(d.ledger, books).Register(mux)
+	budget.NewHandler(d.store, d.snapshots, d.summaries).Register(mux)
+	score.NewHandler(d.scores).Register(mux)
+	achievement.NewHandler(d.store).Register(mux)
+	xp.NewHandler(d.xp).Register(mux)
+
+	// Idempotency keys are per user, so they are checked after the token.
+	idempotent := idempotency.Middleware(d.idempotency, cfg.idempotencyTTL, idempotencyStaleAfter, func(r *http.Request) string {
+		return middleware.UserID(r.Context())
+	})
+	public.Handle("/", middleware.Authenticate(tokens)(idempotent(mux)))
 
-	return middleware.CORS(mux)
+	return middleware.CORS(middleware.RequestContext(public))
 }
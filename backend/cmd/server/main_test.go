@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/user"
+)
+
+// newTestServer serves buildHandler over d with a fixed token secret.
+func newTestServer(t *testing.T, d deps) *httptest.Server {
+	t.Helper()
+	tokens, err := user.NewTokens([]byte(strings.Repeat("s", user.MinSecretLength)), time.Hour)
+	if err != nil {
+		t.Fatalf("new tokens: %v", err)
+	}
+	srv := httptest.NewServer(buildHandler(d, config{idempotencyTTL: time.Hour}, tokens))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// signUp registers username and logs in, returning the bearer token.
+func signUp(t *testing.T, srv *httptest.Server, username string) string {
+	t.Helper()
+	body := `{"username":"` + username + `","password":"correct horse"}`
+	resp, err := http.Post(srv.URL+"/users", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /users: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /users status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	resp, err = http.Post(srv.URL+"/sessions", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /sessions: %v", err)
+	}
+	defer resp.Body.Close()
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil || session.Token == "" {
+		t.Fatalf("POST /sessions status = %d, want a token", resp.StatusCode)
+	}
+	return session.Token
+}
+
+// request sends a request with token as its bearer token.
+func request(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+// TestBuildHandler_Memory exercises the full server wiring, including the
+// projection runner, without a database.
+func TestBuildHandler_Memory(t *testing.T) {
+	d := memoryDeps()
+
+	runner := buildRunner(d)
+	runner.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	srv := newTestServer(t, d)
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /health status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	token := signUp(t, srv, "alice")
+
+	body := `{"amount":1500,"category":"食費","memo":"コンビニ","date":"2026-02-20"}`
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/expenses", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-42")
+	req.Header.Set("X-Client", "mobile")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /expenses: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /expenses status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, "*")
+	}
+
+	events, err := d.store.ReadAllByType(ctx, "expense", 0, 0)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("len(events) = %d, want 1", len(events))
+	}
+	md := events[0].Metadata
+	if md.CorrelationID != "req-42" || md.CausationID != "req-42" || md.Client != "mobile" || md.SchemaVersion != 5 ||
+		md.UserID != user.ID("alice") || events[0].RecordedBy != user.ID("alice") {
+		t.Errorf("Metadata = %+v recorded by %s, want request req-42 from mobile at schema version 5 by alice",
+			md, events[0].RecordedBy)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp := request(t, http.MethodGet, srv.URL+"/expenses", token, "")
+		var expenses []struct {
+			Amount int64 `json:"amount"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&expenses)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("decode expenses: %v", err)
+		}
+
+		if len(expenses) == 1 && expenses[0].Amount == 1500 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expenses = %+v, want the recorded expense", expenses)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp = request(t, http.MethodPost, srv.URL+"/accounts", token, `{"name":"給与口座","type":"bank","date":"2026-02-01"}`)
+	var opened struct {
+		ID string `json:"id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&opened)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || err != nil {
+		t.Fatalf("POST /accounts status = %d (%v), want %d", resp.StatusCode, err, http.StatusCreated)
+	}
+
+	resp = request(t, http.MethodPost, srv.URL+"/incomes", token,
+		`{"amount":300000,"source":"salary","date":"2026-02-25","account_id":"`+opened.ID+`"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /incomes status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	for {
+		resp := request(t, http.MethodGet, srv.URL+"/summaries/monthly?month=2026-02", token, "")
+		var monthly struct {
+			Total      int64 `json:"total"`
+			Income     int64 `json:"income"`
+			NetSavings int64 `json:"net_savings"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&monthly)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("decode summary: %v", err)
+		}
+
+		if monthly.Total == 1500 && monthly.Income == 300000 && monthly.NetSavings == 298500 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("monthly summary = %+v, want 1500 spent of 300000 income", monthly)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for {
+		resp := request(t, http.MethodGet, srv.URL+"/accounts", token, "")
+		var list struct {
+			Accounts []struct {
+				ID      string `json:"id"`
+				Balance int64  `json:"balance"`
+			} `json:"accounts"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("decode accounts: %v", err)
+		}
+
+		if len(list.Accounts) == 1 && list.Accounts[0].ID == opened.ID && list.Accounts[0].Balance == 300000 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("accounts = %+v, want the salary paid into the account", list.Accounts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestBuildHandler_IdempotentPost checks that a retried POST /expenses with
+// the same Idempotency-Key records the expense once.
+func TestBuildHandler_IdempotentPost(t *testing.T) {
+	d := memoryDeps()
+	srv := newTestServer(t, d)
+	alice, bob := signUp(t, srv, "alice"), signUp(t, srv, "bob")
+
+	post := func(token, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/expenses", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "retry-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /expenses: %v", err)
+		}
+		defer resp.Body.Close()
+		var created struct {
+			ID string `json:"id"`
+		}
+		if resp.StatusCode == http.StatusCreated {
+			if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return resp.StatusCode, created.ID
+	}
+
+	body := `{"amount":1500,"category":"食費","date":"2026-02-20"}`
+	status, first := post(alice, body)
+	if status != http.StatusCreated {
+		t.Fatalf("first POST status = %d, want %d", status, http.StatusCreated)
+	}
+	status, retried := post(alice, body)
+	if status != http.StatusCreated || retried != first {
+		t.Errorf("retried POST = %d %q, want %d %q", status, retried, http.StatusCreated, first)
+	}
+	if status, _ := post(alice, `{"amount":2000,"category":"食費","date":"2026-02-20"}`); status != http.StatusUnprocessableEntity {
+		t.Errorf("POST with a different body status = %d, want %d", status, http.StatusUnprocessableEntity)
+	}
+	// Keys are per user, so bob's request with the same key is his own.
+	if status, id := post(bob, body); status != http.StatusCreated || id == first {
+		t.Errorf("bob's POST = %d %q, want %d with a new ID", status, id, http.StatusCreated)
+	}
+
+	events, err := d.store.ReadAllByType(context.Background(), "expense", 0, 0)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("len(events) = %d, want 2", len(events))
+	}
+}
+
+// TestBuildDispatcher_Achievements checks that recorded expenses reach the
+// achievement engine through the outbox, once the projection runner has
+// projected them into the change log the engine reads.
+func TestBuildDispatcher_Achievements(t *testing.T) {
+	d := memoryDeps()
+
+	runner := buildRunner(d)
+	runner.PollInterval = 10 * time.Millisecond
+	dispatcher := buildDispatcher(d)
+	dispatcher.PollInterval = 10 * time.Millisecond
+	dispatcher.MinBackoff = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, run := range []func(context.Context){runner.Run, dispatcher.Run} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx)
+		}()
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	srv := newTestServer(t, d)
+	token := signUp(t, srv, "alice")
+
+	resp := request(t, http.MethodPost, srv.URL+"/expenses", token, `{"amount":1500,"category":"食費","date":"2026-02-20"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /expenses status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp := request(t, http.MethodGet, srv.URL+"/achievements", token, "")
+		var achievements []struct {
+			Key      string `json:"key"`
+			Unlocked bool   `json:"unlocked"`
+		}
+		err := json.NewDecoder(resp.Body).Decode(&achievements)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("decode achievements: %v", err)
+		}
+
+		if len(achievements) > 0 && achievements[0].Key == "first-expense" && achievements[0].Unlocked {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("achievements = %+v, want first-expense unlocked", achievements)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestBuildHandler_Auth checks that every route but health checks,
+// registration and login requires a valid token.
+func TestBuildHandler_Auth(t *testing.T) {
+	srv := newTestServer(t, memoryDeps())
+
+	for _, token := range []string{"", "not-a-token"} {
+		resp := request(t, http.MethodGet, srv.URL+"/expenses", token, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("token %q: status = %d, want %d with a Bearer challenge", token, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+
+	token := signUp(t, srv, "alice")
+	resp := request(t, http.MethodGet, srv.URL+"/expenses", token, "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("with a token: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	resp, err := http.Post(srv.URL+"/sessions", "application/json",
+		strings.NewReader(`{"username":"alice","password":"wrong password"}`))
+	if err != nil {
+		t.Fatalf("POST /sessions: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("login with a wrong password: status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+// TestBuildHandler_Household checks that a member invited to a household
+// can record expenses and settlements in its book, and that strangers
+// cannot see its balances.
+func TestBuildHandler_Household(t *testing.T) {
+	srv := newTestServer(t, memoryDeps())
+	alice, bob, carol := signUp(t, srv, "alice"), signUp(t, srv, "bob"), signUp(t, srv, "carol")
+
+	decode := func(resp *http.Response, want int, v any) {
+		t.Helper()
+		defer resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s status = %d, want %d", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, want)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+
+	var household struct {
+		ID string `json:"id"`
+	}
+	decode(request(t, http.MethodPost, srv.URL+"/households", alice, `{"name":"我が家"}`), http.StatusCreated, &household)
+	var invitation struct {
+		Code string `json:"code"`
+	}
+	decode(request(t, http.MethodPost, srv.URL+"/households/"+household.ID+"/invitations", alice, `{"role":"editor"}`),
+		http.StatusCreated, &invitation)
+	var joined struct {
+		Role string `json:"role"`
+	}
+	decode(request(t, http.MethodPost, srv.URL+"/households/join", bob, `{"code":"`+invitation.Code+`"}`), http.StatusOK, &joined)
+	if joined.Role != "editor" {
+		t.Errorf("bob joined as %q, want editor", joined.Role)
+	}
+
+	body := `{"book_id":"` + household.ID + `","amount":1500,"category":"食費","date":"2026-02-20"}`
+	for _, tt := range []struct {
+		name, token string
+		want        int
+	}{
+		{"bob", bob, http.StatusCreated},
+		{"carol", carol, http.StatusNotFound},
+	} {
+		resp := request(t, http.MethodPost, srv.URL+"/expenses", tt.token, body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: POST /expenses to the household status = %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+
+	settle := `{"from":"` + user.ID("bob") + `","to":"` + user.ID("alice") + `","amount":500,"date":"2026-02-21"}`
+	for _, tt := range []struct {
+		name, method, path, token, body string
+		want                            int
+	}{
+		{"bob settles", http.MethodPost, "/households/" + household.ID + "/settlements", bob, settle, http.StatusCreated},
+		{"alice reads balances", http.MethodGet, "/households/" + household.ID + "/balances", alice, "", http.StatusOK},
+		{"carol reads balances", http.MethodGet, "/households/" + household.ID + "/balances", carol, "", http.StatusNotFound},
+	} {
+		resp := request(t, tt.method, srv.URL+tt.path, tt.token, tt.body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: %s %s status = %d, want %d", tt.name, tt.method, tt.path, resp.StatusCode, tt.want)
+		}
+	}
+}
@@ -7,4 +7,5 @@ require github.com/go-sql-driver/mysql v1.9.3
 require (
 	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/google/uuid v1.6.0
+	golang.org/x/crypto v0.43.0
 )
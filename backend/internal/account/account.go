@@ -0,0 +1,244 @@
+// Package account is the event-sourced account: a wallet, bank account,
+// card or e-money balance in a book that expenses are paid from and income
+// is paid into, and the transfers that move money between accounts.
+//
+// The running balances are kept by the ledger package.
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const aggregateType = "account"
+
+const (
+	eventTypeOpened     = "AccountOpened"
+	eventTypeReconciled = "AccountReconciled"
+)
+
+// schemaVersions holds the payload schema version of each account and
+// transfer event.
+var schemaVersions = map[string]int{
+	eventTypeOpened:      1,
+	eventTypeReconciled:  1,
+	eventTypeTransferred: 1,
+}
+
+const (
+	maxNameLength = 64
+	// DefaultCurrency is the currency of accounts opened without one.
+	DefaultCurrency = "JPY"
+)
+
+// Type is the kind of an account.
+type Type string
+
+const (
+	TypeCash Type = "cash"
+	TypeBank Type = "bank"
+	// TypeDebit is a debit card, which draws on a bank balance at once.
+	TypeDebit Type = "debit"
+	// TypeCredit is a credit card. Its balance is what is owed, so it is
+	// usually negative.
+	TypeCredit Type = "credit"
+	// TypeEMoney is a prepaid balance such as Suica or PayPay.
+	TypeEMoney Type = "emoney"
+)
+
+func (t Type) valid() bool {
+	return t == TypeCash || t == TypeBank || t == TypeDebit || t == TypeCredit || t == TypeEMoney
+}
+
+// currencyPattern matches ISO 4217 alphabetic codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var (
+	// ErrNotFound is returned when an account has no events, or the user
+	// has no role in its book.
+	ErrNotFound = errors.New("account not found")
+	// ErrBookNotFound is returned when the user has no role in a book.
+	ErrBookNotFound = errors.New("book not found")
+	// ErrForbidden is returned when the user's role in a book does not
+	// allow changing its accounts.
+	ErrForbidden = errors.New("your role in this book does not allow changes")
+	// ErrInvalidTransfer is returned when a transfer's accounts cannot be
+	// transferred between.
+	ErrInvalidTransfer = errors.New("invalid transfer")
+)
+
+// OpenCommand holds the data needed to open an account.
+type OpenCommand struct {
+	// BookID is the book the account belongs to. The handler fills in the
+	// opener's personal book when it is empty.
+	BookID string `json:"book_id"`
+	Name   string `json:"name"`
+	Type   Type   `json:"type"`
+	// OpeningBalance is the balance on Date, in the currency's smallest
+	// unit. It may be negative, as for a credit card with a bill to pay.
+	OpeningBalance int64 `json:"opening_balance"`
+	// Currency is an ISO 4217 code. The handler fills in DefaultCurrency
+	// when it is empty.
+	Currency string `json:"currency"`
+	Date     string `json:"date"`
+}
+
+// AccountOpenedPayload is the event payload for a new account.
+type AccountOpenedPayload struct {
+	BookID         string `json:"book_id"`
+	Name           string `json:"name"`
+	Type           Type   `json:"type"`
+	OpeningBalance int64  `json:"opening_balance"`
+	Currency       string `json:"currency"`
+	Date           string `json:"date"`
+}
+
+// ReconcileCommand holds the balance the user actually sees for an account,
+// such as on a statement or in a wallet, to check the ledger against.
+type ReconcileCommand struct {
+	Actual int64  `json:"actual"`
+	Date   string `json:"date"`
+}
+
+// AccountReconciledPayload is the event payload for a reconciliation.
+type AccountReconciledPayload struct {
+	Actual int64  `json:"actual"`
+	Date   string `json:"date"`
+}
+
+// Validate checks that the command fields are valid.
+func (c OpenCommand) Validate() error {
+	var errs []error
+
+	if c.BookID == "" {
+		errs = append(errs, fmt.Errorf("book_id is required"))
+	}
+	if c.Name == "" || len([]rune(c.Name)) > maxNameLength {
+		errs = append(errs, fmt.Errorf("name must be 1 to %d characters", maxNameLength))
+	}
+	if !c.Type.valid() {
+		errs = append(errs, fmt.Errorf("type must be %q, %q, %q, %q or %q",
+			TypeCash, TypeBank, TypeDebit, TypeCredit, TypeEMoney))
+	}
+	if !currencyPattern.MatchString(c.Currency) {
+		errs = append(errs, fmt.Errorf("currency must be a three-letter ISO 4217 code"))
+	}
+	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+		errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the command fields are valid.
+func (c ReconcileCommand) Validate() error {
+	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+		return fmt.Errorf("date must be in YYYY-MM-DD format")
+	}
+	return nil
+}
+
+// Open creates an event for opening an account.
+// This is a pure function that performs no I/O.
+func Open(id string, cmd OpenCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	a := &Account{ID: id}
+	return a.newEvent(eventTypeOpened, AccountOpenedPayload(cmd))
+}
+
+// Account is the account aggregate, rebuilt from its event stream.
+type Account struct {
+	ID             string
+	Book           string
+	Name           string
+	Type           Type
+	OpeningBalance int64
+	Currency       string
+	// Date is the day the account was opened with OpeningBalance.
+	Date    string
+	Version int
+}
+
+// LoadAccount rebuilds an Account from its events, which must be ordered by
+// version. Returns ErrNotFound when events is empty.
+func LoadAccount(events []eventstore.Event) (*Account, error) {
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	a := &Account{ID: events[0].AggregateID}
+	for _, event := range events {
+		if err := a.apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (a *Account) apply(event eventstore.Event) error {
+	if event.Version != a.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, a.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeOpened:
+		var payload AccountOpenedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		a.Book = payload.BookID
+		a.Name = payload.Name
+		a.Type = payload.Type
+		a.OpeningBalance = payload.OpeningBalance
+		a.Currency = payload.Currency
+		a.Date = payload.Date
+	case eventTypeReconciled:
+		// Reconciliations only matter to the ledger.
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	a.Version = event.Version
+	return nil
+}
+
+// Reconcile creates an AccountReconciled event at the next version. The
+// date must not be before the account was opened.
+// This is a pure function that performs no I/O and does not modify a.
+func (a *Account) Reconcile(cmd ReconcileCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	// Dates are YYYY-MM-DD, so string order is chronological order.
+	if cmd.Date < a.Date {
+		return eventstore.Event{}, fmt.Errorf("date must not be before the account was opened on %s", a.Date)
+	}
+	return a.newEvent(eventTypeReconciled, AccountReconciledPayload(cmd))
+}
+
+func (a *Account) newEvent(eventType string, payload any) (eventstore.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   a.ID,
+		AggregateType: aggregateType,
+		Version:       a.Version + 1,
+		EventType:     eventType,
+		Payload:       data,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventType]},
+	}, nil
+}
@@ -0,0 +1,127 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func openedAccount(t *testing.T, id string, cmd OpenCommand) *Account {
+	t.Helper()
+
+	event, err := Open(id, cmd)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	a, err := LoadAccount([]eventstore.Event{event})
+	if err != nil {
+		t.Fatalf("LoadAccount: %v", err)
+	}
+	return a
+}
+
+var wallet = OpenCommand{BookID: "book-1", Name: "財布", Type: TypeCash, OpeningBalance: 12000, Currency: "JPY", Date: "2026-03-01"}
+
+func TestOpen(t *testing.T) {
+	a := openedAccount(t, "wallet", wallet)
+
+	want := Account{ID: "wallet", Book: "book-1", Name: "財布", Type: TypeCash, OpeningBalance: 12000, Currency: "JPY", Date: "2026-03-01", Version: 1}
+	if *a != want {
+		t.Errorf("account = %+v, want %+v", *a, want)
+	}
+
+	// A credit card opens owing its unpaid bill.
+	card := OpenCommand{BookID: "book-1", Name: "楽天カード", Type: TypeCredit, OpeningBalance: -35000, Currency: "JPY", Date: "2026-03-01"}
+	if _, err := Open("card", card); err != nil {
+		t.Errorf("Open with a negative balance: %v", err)
+	}
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*OpenCommand)
+	}{
+		{"no book", func(c *OpenCommand) { c.BookID = "" }},
+		{"no name", func(c *OpenCommand) { c.Name = "" }},
+		{"unknown type", func(c *OpenCommand) { c.Type = "points" }},
+		{"lowercase currency", func(c *OpenCommand) { c.Currency = "jpy" }},
+		{"no currency", func(c *OpenCommand) { c.Currency = "" }},
+		{"invalid date", func(c *OpenCommand) { c.Date = "2026/03/01" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := wallet
+			tt.modify(&cmd)
+			if _, err := Open("wallet", cmd); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	a := openedAccount(t, "wallet", wallet)
+
+	event, err := a.Reconcile(ReconcileCommand{Actual: 11500, Date: "2026-03-01"})
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if event.Version != 2 || event.EventType != "AccountReconciled" {
+		t.Errorf("event = %s v%d, want AccountReconciled v2", event.EventType, event.Version)
+	}
+	r, ok, err := DecodeReconciliation(event)
+	if want := (Reconciliation{AccountID: "wallet", Version: 2, Actual: 11500, Date: "2026-03-01"}); err != nil || !ok || r != want {
+		t.Errorf("DecodeReconciliation = %+v, %v, %v, want %+v", r, ok, err, want)
+	}
+	if err := a.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if a.Version != 2 {
+		t.Errorf("Version = %d, want 2", a.Version)
+	}
+
+	if _, err := a.Reconcile(ReconcileCommand{Actual: 0, Date: "2026-02-28"}); err == nil {
+		t.Error("Reconcile before the account was opened: expected error, got nil")
+	}
+}
+
+func TestRecordTransfer(t *testing.T) {
+	a := openedAccount(t, "wallet", wallet)
+	suica := openedAccount(t, "suica", OpenCommand{BookID: "book-1", Name: "Suica", Type: TypeEMoney, Currency: "JPY", Date: "2026-03-01"})
+
+	cmd := TransferCommand{From: "wallet", To: "suica", Amount: 3000, Date: "2026-03-02", Memo: "チャージ"}
+	event, err := RecordTransfer("tr-1", a, suica, cmd)
+	if err != nil {
+		t.Fatalf("RecordTransfer: %v", err)
+	}
+	got, ok, err := DecodeTransfer(event)
+	want := Transfer{ID: "tr-1", Book: "book-1", From: "wallet", To: "suica", Amount: 3000, Date: "2026-03-02", Memo: "チャージ"}
+	if err != nil || !ok || got != want {
+		t.Errorf("DecodeTransfer = %+v, %v, %v, want %+v", got, ok, err, want)
+	}
+
+	other := openedAccount(t, "other", OpenCommand{BookID: "book-2", Name: "財布", Type: TypeCash, Currency: "JPY", Date: "2026-03-01"})
+	dollars := openedAccount(t, "dollars", OpenCommand{BookID: "book-1", Name: "USD", Type: TypeBank, Currency: "USD", Date: "2026-03-01"})
+	tests := []struct {
+		name     string
+		from, to *Account
+		cmd      TransferCommand
+	}{
+		{"same account", a, a, TransferCommand{From: "wallet", To: "wallet", Amount: 1, Date: "2026-03-02"}},
+		{"other book", a, other, TransferCommand{From: "wallet", To: "other", Amount: 1, Date: "2026-03-02"}},
+		{"other currency", a, dollars, TransferCommand{From: "wallet", To: "dollars", Amount: 1, Date: "2026-03-02"}},
+		{"mismatched command", a, suica, TransferCommand{From: "suica", To: "wallet", Amount: 1, Date: "2026-03-02"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RecordTransfer("tr-2", tt.from, tt.to, tt.cmd); !errors.Is(err, ErrInvalidTransfer) {
+				t.Errorf("RecordTransfer = %v, want ErrInvalidTransfer", err)
+			}
+		})
+	}
+	if _, err := RecordTransfer("tr-2", a, suica, TransferCommand{From: "wallet", To: "suica", Amount: 0, Date: "2026-03-02"}); err == nil {
+		t.Error("RecordTransfer of nothing: expected error, got nil")
+	}
+}
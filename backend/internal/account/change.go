@@ -0,0 +1,143 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// AggregateType and TransferAggregateType are the aggregate types of
+// account and transfer events, for readers of the event log that only want
+// those.
+const (
+	AggregateType         = aggregateType
+	TransferAggregateType = transferAggregateType
+)
+
+// Opening is an AccountOpened event decoded for read models kept outside
+// this package.
+type Opening struct {
+	AccountID      string
+	Book           string
+	Name           string
+	Type           Type
+	Currency       string
+	OpeningBalance int64
+	Date           string
+}
+
+// Reconciliation is an AccountReconciled event decoded for read models
+// kept outside this package.
+type Reconciliation struct {
+	AccountID string
+	// Version is the account's version after the reconciliation, which
+	// tells reconciliations of an account apart.
+	Version int
+	Actual  int64
+	Date    string
+}
+
+// Transfer is a TransferRecorded event decoded for read models kept
+// outside this package.
+type Transfer struct {
+	ID     string
+	Book   string
+	From   string
+	To     string
+	Amount int64
+	Date   string
+	Memo   string
+}
+
+// DecodeOpening decodes an AccountOpened event. ok is false for every
+// other event.
+func DecodeOpening(event eventstore.Event) (opening Opening, ok bool, err error) {
+	if event.AggregateType != aggregateType || event.EventType != eventTypeOpened {
+		return Opening{}, false, nil
+	}
+	var payload AccountOpenedPayload
+	if err := decode(event, &payload); err != nil {
+		return Opening{}, false, err
+	}
+	return Opening{
+		AccountID:      event.AggregateID,
+		Book:           payload.BookID,
+		Name:           payload.Name,
+		Type:           payload.Type,
+		Currency:       payload.Currency,
+		OpeningBalance: payload.OpeningBalance,
+		Date:           payload.Date,
+	}, true, nil
+}
+
+// DecodeReconciliation decodes an AccountReconciled event. ok is false for
+// every other event.
+func DecodeReconciliation(event eventstore.Event) (reconciliation Reconciliation, ok bool, err error) {
+	if event.AggregateType != aggregateType || event.EventType != eventTypeReconciled {
+		return Reconciliation{}, false, nil
+	}
+	var payload AccountReconciledPayload
+	if err := decode(event, &payload); err != nil {
+		return Reconciliation{}, false, err
+	}
+	return Reconciliation{
+		AccountID: event.AggregateID,
+		Version:   event.Version,
+		Actual:    payload.Actual,
+		Date:      payload.Date,
+	}, true, nil
+}
+
+// DecodeTransfer decodes a TransferRecorded event. ok is false for every
+// other event.
+func DecodeTransfer(event eventstore.Event) (transfer Transfer, ok bool, err error) {
+	if event.AggregateType != transferAggregateType || event.EventType != eventTypeTransferred {
+		return Transfer{}, false, nil
+	}
+	var payload TransferRecordedPayload
+	if err := decode(event, &payload); err != nil {
+		return Transfer{}, false, err
+	}
+	return Transfer{
+		ID:     event.AggregateID,
+		Book:   payload.BookID,
+		From:   payload.From,
+		To:     payload.To,
+		Amount: payload.Amount,
+		Date:   payload.Date,
+		Memo:   payload.Memo,
+	}, true, nil
+}
+
+func decode(event eventstore.Event, payload any) error {
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(event.Payload, payload); err != nil {
+		return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+	}
+	return nil
+}
+
+// Accounts looks up accounts from their event streams, for other slices
+// that record money paid from or into an account.
+type Accounts struct {
+	store eventstore.Store
+}
+
+// NewAccounts creates a new Accounts.
+func NewAccounts(store eventstore.Store) *Accounts {
+	return &Accounts{store: store}
+}
+
+// Load rebuilds the account with the given ID. It returns ErrNotFound if
+// there is none.
+func (a *Accounts) Load(ctx context.Context, id string) (*Account, error) {
+	events, err := a.store.Load(ctx, aggregateType, id)
+	if err != nil {
+		return nil, fmt.Errorf("load events: %w", err)
+	}
+	return LoadAccount(events)
+}
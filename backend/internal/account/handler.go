@@ -0,0 +1,227 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// Books tells what a user may do with a book.
+type Books interface {
+	Role(ctx context.Context, userID, bookID string) (household.Role, error)
+}
+
+// Handler handles HTTP requests that change accounts. Balances and ledgers
+// are served by the ledger package from its read model.
+//
+// Every route must be behind middleware.Authenticate. Accounts in books a
+// user may not read are reported as not found.
+type Handler struct {
+	store    eventstore.Store
+	accounts *Accounts
+	books    Books
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store, books Books) *Handler {
+	return &Handler{
+		store:    store,
+		accounts: NewAccounts(store),
+		books:    books,
+	}
+}
+
+// Register adds account routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /accounts", h.OpenAccount)
+	mux.HandleFunc("POST /accounts/{id}/reconciliations", h.ReconcileAccount)
+	mux.HandleFunc("POST /transfers", h.RecordTransfer)
+}
+
+type commandResponse struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+// OpenAccount handles POST /accounts. The account goes in the user's
+// personal book unless the body names another book_id, and is in yen
+// unless it names another currency.
+func (h *Handler) OpenAccount(w http.ResponseWriter, r *http.Request) {
+	var cmd OpenCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	ctx := r.Context()
+	if cmd.BookID == "" {
+		cmd.BookID = middleware.UserID(ctx)
+	}
+	if cmd.Currency == "" {
+		cmd.Currency = DefaultCurrency
+	}
+	event, err := Open(uuid.New().String(), cmd)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	role, err := h.bookRole(ctx, cmd.BookID)
+	if err == nil && !role.CanWrite() {
+		err = ErrForbidden
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, 0); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, commandResponse{ID: event.AggregateID, Version: event.Version})
+}
+
+// ReconcileAccount handles POST /accounts/{id}/reconciliations, recording
+// the balance the user actually has so the ledger can show how far it is
+// off.
+func (h *Handler) ReconcileAccount(w http.ResponseWriter, r *http.Request) {
+	var cmd ReconcileCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	a, err := h.loadWritableAccount(ctx, r.PathValue("id"))
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := a.Reconcile(cmd)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, a.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, commandResponse{ID: a.ID, Version: event.Version})
+}
+
+// RecordTransfer handles POST /transfers.
+func (h *Handler) RecordTransfer(w http.ResponseWriter, r *http.Request) {
+	var cmd TransferCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	from, err := h.loadWritableAccount(ctx, cmd.From)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	to, err := h.loadWritableAccount(ctx, cmd.To)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := RecordTransfer(uuid.New().String(), from, to, cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, 0); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, commandResponse{ID: event.AggregateID, Version: event.Version})
+}
+
+// loadWritableAccount loads an account in a book the request's user may
+// write. It returns ErrNotFound if they may not read the book either.
+func (h *Handler) loadWritableAccount(ctx context.Context, id string) (*Account, error) {
+	a, err := h.accounts.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role, err := h.bookRole(ctx, a.Book)
+	if errors.Is(err, ErrBookNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanWrite() {
+		return nil, ErrForbidden
+	}
+	return a, nil
+}
+
+// bookRole returns the request user's role in bookID, or ErrBookNotFound
+// if they have none.
+func (h *Handler) bookRole(ctx context.Context, bookID string) (household.Role, error) {
+	role, err := h.books.Role(ctx, middleware.UserID(ctx), bookID)
+	if errors.Is(err, household.ErrNotFound) {
+		return "", ErrBookNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up role in book %s: %w", bookID, err)
+	}
+	return role, nil
+}
+
+// writeCommandError maps domain errors from command handling to HTTP responses.
+func writeCommandError(w http.ResponseWriter, err error) {
+	var conflict *eventstore.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":           "account was modified concurrently; reload and retry",
+			"current_version": conflict.Actual,
+		})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrBookNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidTransfer):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("account command: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// transferAggregateType is the aggregate type of transfers. Each transfer
+// is a stream of its own, so transfers never contend with the accounts
+// they move money between.
+const transferAggregateType = "transfer"
+
+const eventTypeTransferred = "TransferRecorded"
+
+// TransferCommand holds the data needed to move money between two
+// accounts, such as charging Suica from a wallet or paying off a credit
+// card from a bank account.
+type TransferCommand struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+	Date   string `json:"date"`
+	Memo   string `json:"memo"`
+}
+
+// TransferRecordedPayload is the event payload for a transfer.
+type TransferRecordedPayload struct {
+	BookID string `json:"book_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+	Date   string `json:"date"`
+	Memo   string `json:"memo"`
+}
+
+// Validate checks that the command fields are valid.
+func (c TransferCommand) Validate() error {
+	var errs []error
+
+	if c.From == "" || c.To == "" {
+		errs = append(errs, fmt.Errorf("from and to are required"))
+	}
+	if c.Amount <= 0 {
+		errs = append(errs, fmt.Errorf("amount must be positive"))
+	}
+	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+		errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// RecordTransfer creates an event for a transfer from one account to
+// another, which must be different accounts of the same book and currency.
+// This is a pure function that performs no I/O.
+func RecordTransfer(id string, from, to *Account, cmd TransferCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	switch {
+	case from.ID != cmd.From || to.ID != cmd.To:
+		return eventstore.Event{}, fmt.Errorf("%w: accounts do not match the command", ErrInvalidTransfer)
+	case from.ID == to.ID:
+		return eventstore.Event{}, fmt.Errorf("%w: from and to must be different accounts", ErrInvalidTransfer)
+	case from.Book != to.Book:
+		return eventstore.Event{}, fmt.Errorf("%w: accounts must be in the same book", ErrInvalidTransfer)
+	case from.Currency != to.Currency:
+		return eventstore.Event{}, fmt.Errorf("%w: accounts must have the same currency", ErrInvalidTransfer)
+	}
+
+	payload, err := json.Marshal(TransferRecordedPayload{
+		BookID: from.Book,
+		From:   cmd.From,
+		To:     cmd.To,
+		Amount: cmd.Amount,
+		Date:   cmd.Date,
+		Memo:   cmd.Memo,
+	})
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   id,
+		AggregateType: transferAggregateType,
+		Version:       1,
+		EventType:     eventTypeTransferred,
+		Payload:       payload,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventTypeTransferred]},
+	}, nil
+}
@@ -0,0 +1,99 @@
+// Package achievement awards achievements for spending habits. An Engine
+// subscribed to expense and budget events checks every rule after each of
+// them and records newly earned achievements as AchievementUnlocked events.
+package achievement
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const aggregateType = "achievement"
+
+// AggregateType is the aggregate type of achievement events, for readers of
+// the event log that only want achievements.
+const AggregateType = aggregateType
+
+const eventTypeUnlocked = "AchievementUnlocked"
+
+// schemaVersions holds the payload schema version of each achievement event.
+var schemaVersions = map[string]int{
+	eventTypeUnlocked: 1,
+}
+
+// idNamespace derives achievement IDs from users and rule keys. Each
+// achievement is an aggregate unlocked at most once per user, at version 1.
+var idNamespace = uuid.MustParse("8b6f3a52-41d7-4c3e-a0f2-6d9e5c1b7e34")
+
+// ID returns the aggregate ID of userID's achievement for the rule with key.
+func ID(userID, key string) string {
+	return uuid.NewSHA1(idNamespace, []byte(userID+"/"+key)).String()
+}
+
+// AchievementUnlockedPayload is the event payload for an earned achievement.
+type AchievementUnlockedPayload struct {
+	Achievement string `json:"achievement"`
+	// EarnedOn is the day the rule was first satisfied, which can be
+	// earlier than the event when expenses are recorded late.
+	EarnedOn string `json:"earned_on"`
+}
+
+// unlock creates the AchievementUnlocked event for rule, earned on the given
+// day by the user who recorded trigger, in reaction to trigger.
+func unlock(rule Rule, earnedOn time.Time, trigger eventstore.Event) (eventstore.Event, error) {
+	data, err := json.Marshal(AchievementUnlockedPayload{
+		Achievement: rule.Key,
+		EarnedOn:    earnedOn.Format(time.DateOnly),
+	})
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   ID(trigger.RecordedBy, rule.Key),
+		AggregateType: aggregateType,
+		Version:       1,
+		EventType:     eventTypeUnlocked,
+		Payload:       data,
+		RecordedBy:    trigger.RecordedBy,
+		Metadata: eventstore.Metadata{
+			CorrelationID: trigger.Metadata.CorrelationID,
+			CausationID:   strconv.FormatUint(trigger.ID, 10),
+			UserID:        trigger.RecordedBy,
+			SchemaVersion: schemaVersions[eventTypeUnlocked],
+		},
+	}, nil
+}
+
+// Unlocked is an earned achievement as recorded in the event log.
+type Unlocked struct {
+	AchievementUnlockedPayload
+	// UserID is the user who earned the achievement.
+	UserID     string
+	UnlockedAt time.Time
+}
+
+// DecodeUnlocked decodes an AchievementUnlocked event for readers outside
+// this package. ok is false for events of other aggregate types.
+func DecodeUnlocked(event eventstore.Event) (u Unlocked, ok bool, err error) {
+	if event.AggregateType != aggregateType {
+		return Unlocked{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return Unlocked{}, false, err
+	}
+	if event.EventType != eventTypeUnlocked {
+		return Unlocked{}, false, fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	var payload AchievementUnlockedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return Unlocked{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+	}
+	return Unlocked{AchievementUnlockedPayload: payload, UserID: event.RecordedBy, UnlockedAt: event.OccurredAt}, true, nil
+}
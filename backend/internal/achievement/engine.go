@@ -0,0 +1,106 @@
+package achievement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+// Engine is the outbox subscriber that checks every rule after each expense
+// or budget event and records the achievements it newly earns for the user
+// who recorded the event.
+//
+// Rules judge the user's personal book only, the one their budgets apply
+// to; expenses recorded in a household book never earn achievements.
+//
+// Each achievement is appended at version 1 of its own aggregate, so a
+// redelivered event, or two events judged at once, cannot unlock one twice.
+// Handle fails until the expense change log has caught up with the event,
+// and the outbox delivers it again later.
+type Engine struct {
+	store    eventstore.Store
+	expenses ExpenseChanges
+	// loc is the time zone that decides which day an event occurred on.
+	loc *time.Location
+}
+
+// ExpenseChanges reads the expense changes in a book as they stood at an
+// event. score.ChangeLog implements it.
+type ExpenseChanges interface {
+	// ExpensesThrough returns the changes to the expenses in bookID up to
+	// and including the event at last, in log order.
+	ExpensesThrough(ctx context.Context, bookID string, last eventstore.Position) ([]expense.Change, error)
+}
+
+// NewEngine creates an Engine.
+func NewEngine(store eventstore.Store, expenses ExpenseChanges) *Engine {
+	return &Engine{store: store, expenses: expenses, loc: time.Local}
+}
+
+// Name implements outbox.Subscriber.
+func (e *Engine) Name() string {
+	return "achievements"
+}
+
+// Handle implements outbox.Subscriber.
+func (e *Engine) Handle(ctx context.Context, event eventstore.Event) error {
+	if event.AggregateType != expense.AggregateType && event.AggregateType != budget.Aggregate.Type {
+		return nil
+	}
+
+	unlocked, err := unlockedAchievements(ctx, e.store, event.RecordedBy)
+	if err != nil {
+		return err
+	}
+	facts, err := loadFacts(ctx, e.store, e.expenses, event, e.loc)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if _, ok := unlocked[rule.Key]; ok {
+			continue
+		}
+		earnedOn, ok := rule.Check(facts)
+		if !ok {
+			continue
+		}
+
+		earned, err := unlock(rule, earnedOn, event)
+		if err != nil {
+			return err
+		}
+		err = e.store.Append(ctx, []eventstore.Event{earned}, 0)
+		var conflict *eventstore.VersionConflictError
+		if err != nil && !errors.As(err, &conflict) {
+			return fmt.Errorf("unlock %s: %w", rule.Key, err)
+		}
+	}
+	return nil
+}
+
+// unlockedAchievements loads the achievement of every rule userID has
+// unlocked, keyed by rule key.
+func unlockedAchievements(ctx context.Context, store eventstore.Store, userID string) (map[string]Unlocked, error) {
+	unlocked := make(map[string]Unlocked)
+	for _, rule := range rules {
+		events, err := store.Load(ctx, aggregateType, ID(userID, rule.Key))
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", rule.Key, err)
+		}
+		if len(events) == 0 {
+			continue
+		}
+		u, _, err := DecodeUnlocked(events[0])
+		if err != nil {
+			return nil, err
+		}
+		unlocked[rule.Key] = u
+	}
+	return unlocked, nil
+}
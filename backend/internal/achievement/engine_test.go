@@ -0,0 +1,227 @@
+package achievement_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/achievement"
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+)
+
+// record appends expenses of alice's to store and returns every expense
+// event as read back, with the IDs and times the store stamped.
+func record(t *testing.T, store eventstore.Store, expenses ...expense.RecordExpenseCommand) []eventstore.Event {
+	t.Helper()
+	return recordAs(t, store, "alice", expenses...)
+}
+
+// recordAs is record for expenses of the given user's.
+func recordAs(t *testing.T, store eventstore.Store, userID string, expenses ...expense.RecordExpenseCommand) []eventstore.Event {
+	t.Helper()
+	ctx := context.Background()
+
+	for _, cmd := range expenses {
+		event, err := expense.RecordExpense(uuid.NewString(), cmd)
+		if err != nil {
+			t.Fatalf("record expense: %v", err)
+		}
+		event.RecordedBy = userID
+		if err := store.Append(ctx, []eventstore.Event{event}, 0); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	events, err := store.ReadAllByType(ctx, expense.AggregateType, 0, 0)
+	if err != nil {
+		t.Fatalf("read expenses: %v", err)
+	}
+	return events
+}
+
+// newEngine returns an Engine over a memory event store whose expense
+// changes are projected into the change log it reads as they are appended.
+func newEngine(t *testing.T) (*eventstore.MemoryStore, *achievement.Engine) {
+	t.Helper()
+
+	changes := score.NewMemoryRepository()
+	projector := score.NewProjector(changes)
+	store := eventstore.NewMemoryStore()
+	store.OnAppend = func(events []eventstore.Event) error {
+		for _, event := range events {
+			if err := projector.Apply(context.Background(), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return store, achievement.NewEngine(store, changes)
+}
+
+func unlockEvents(t *testing.T, store eventstore.Store) []eventstore.Event {
+	t.Helper()
+	events, err := store.ReadAllByType(context.Background(), "achievement", 0, 0)
+	if err != nil {
+		t.Fatalf("read achievements: %v", err)
+	}
+	return events
+}
+
+func TestEngine_Handle(t *testing.T) {
+	ctx := context.Background()
+	store, engine := newEngine(t)
+
+	thisMonth := time.Now().AddDate(0, 0, 1-time.Now().Day())
+	events := record(t, store,
+		expense.RecordExpenseCommand{Amount: 3000, Category: "食費", Date: thisMonth.AddDate(0, -2, 0).Format(time.DateOnly)},
+		expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: thisMonth.AddDate(0, -1, 0).Format(time.DateOnly)},
+	)
+
+	// Only the first expense had been recorded when it is judged, however
+	// late it is delivered.
+	if err := engine.Handle(ctx, events[0]); err != nil {
+		t.Fatalf("handle first: %v", err)
+	}
+	unlocked := unlockEvents(t, store)
+	if len(unlocked) != 1 {
+		t.Fatalf("%d achievements after the first expense, want 1", len(unlocked))
+	}
+	var payload achievement.AchievementUnlockedPayload
+	if err := json.Unmarshal(unlocked[0].Payload, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload.Achievement != "first-expense" || unlocked[0].Metadata.CausationID != strconv.FormatUint(events[0].ID, 10) {
+		t.Errorf("unlocked %s caused by %s, want first-expense caused by event %d",
+			payload.Achievement, unlocked[0].Metadata.CausationID, events[0].ID)
+	}
+
+	// Last month spent less than the month before.
+	for _, e := range append(events, events...) {
+		if err := engine.Handle(ctx, e); err != nil {
+			t.Fatalf("handle: %v", err)
+		}
+	}
+	unlocked = unlockEvents(t, store)
+	if len(unlocked) != 2 || unlocked[1].AggregateID != achievement.ID("alice", "beat-last-month") {
+		t.Fatalf("achievements = %+v, want first-expense and beat-last-month once each", unlocked)
+	}
+
+	// Achievements do not react to themselves.
+	if err := engine.Handle(ctx, unlocked[1]); err != nil {
+		t.Fatalf("handle achievement: %v", err)
+	}
+	if got := len(unlockEvents(t, store)); got != 2 {
+		t.Errorf("%d achievements after handling one, want 2", got)
+	}
+}
+
+func TestEngine_HandleBudget(t *testing.T) {
+	ctx := context.Background()
+	store, engine := newEngine(t)
+
+	set, err := budget.Aggregate.New(budget.ID("alice", "2026-02")).Set("2026-02", budget.SetBudgetCommand{Total: 1000})
+	if err != nil {
+		t.Fatalf("set budget: %v", err)
+	}
+	set.RecordedBy = "alice"
+	if err := store.Append(ctx, []eventstore.Event{set}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	events, _ := store.ReadAll(ctx, 0, 0)
+
+	if err := engine.Handle(ctx, events[0]); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := len(unlockEvents(t, store)); got != 0 {
+		t.Errorf("%d achievements without expenses, want 0", got)
+	}
+}
+
+func TestEngine_HandlePerUser(t *testing.T) {
+	ctx := context.Background()
+	store, engine := newEngine(t)
+
+	cmd := expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-02-01"}
+	record(t, store, cmd)
+	events := recordAs(t, store, "bob", cmd)
+	for _, e := range events {
+		if err := engine.Handle(ctx, e); err != nil {
+			t.Fatalf("handle: %v", err)
+		}
+	}
+
+	unlocked := unlockEvents(t, store)
+	if len(unlocked) != 2 {
+		t.Fatalf("%d achievements, want first-expense for alice and bob", len(unlocked))
+	}
+	for i, userID := range []string{"alice", "bob"} {
+		if unlocked[i].AggregateID != achievement.ID(userID, "first-expense") || unlocked[i].RecordedBy != userID {
+			t.Errorf("achievement %d = %s recorded by %s, want %s's first-expense",
+				i, unlocked[i].AggregateID, unlocked[i].RecordedBy, userID)
+		}
+	}
+}
+
+func TestEngine_HandleIgnoresHouseholdBooks(t *testing.T) {
+	ctx := context.Background()
+	store, engine := newEngine(t)
+
+	events := record(t, store, expense.RecordExpenseCommand{BookID: "home", Amount: 1000, Category: "食費", Date: "2026-02-01"})
+	if err := engine.Handle(ctx, events[0]); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got := len(unlockEvents(t, store)); got != 0 {
+		t.Errorf("%d achievements for a household expense, want 0", got)
+	}
+}
+
+func TestListAchievements(t *testing.T) {
+	store, engine := newEngine(t)
+	events := record(t, store, expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-02-01"})
+	if err := engine.Handle(context.Background(), events[0]); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	achievement.NewHandler(store).Register(mux)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(middleware.WithUser(r.Context(), "alice")))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/achievements")
+	if err != nil {
+		t.Fatalf("GET /achievements: %v", err)
+	}
+	defer resp.Body.Close()
+	var got []struct {
+		Key      string  `json:"key"`
+		Name     string  `json:"name"`
+		Unlocked bool    `json:"unlocked"`
+		EarnedOn *string `json:"earned_on"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if len(got) < 2 {
+		t.Fatalf("achievements = %+v, want every rule", got)
+	}
+	today := events[0].OccurredAt.In(time.Local).Format(time.DateOnly)
+	if first := got[0]; first.Key != "first-expense" || !first.Unlocked || first.EarnedOn == nil || *first.EarnedOn != today {
+		t.Errorf("first achievement = %+v, want first-expense earned today", first)
+	}
+	for _, a := range got[1:] {
+		if a.Unlocked || a.EarnedOn != nil || a.Name == "" {
+			t.Errorf("achievement = %+v, want it locked", a)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package achievement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Facts is what the rules judge: a user's spending in their personal book,
+// and their budgets, in the event log up to the event being reacted to.
+// Days are dates at midnight UTC.
+type Facts struct {
+	// Today is the day the event occurred. Days before it are over.
+	Today time.Time
+	// Spent is the spending per day, keyed by date. Days without
+	// spending are absent.
+	Spent map[string]int64
+	// Logged holds the days on which expenses were recorded, keyed by date.
+	Logged map[string]bool
+	// TotalLimits is the cap on total spending per month, keyed by YYYY-MM,
+	// for months whose budget sets one.
+	TotalLimits map[string]int64
+}
+
+// FirstLogged returns the first day an expense was recorded, or false if none
+// has been.
+func (f Facts) FirstLogged() (time.Time, bool) {
+	var first string
+	for day := range f.Logged {
+		if first == "" || day < first {
+			first = day
+		}
+	}
+	if first == "" {
+		return time.Time{}, false
+	}
+	return day(first), true
+}
+
+// MonthSpent returns the spending in the month starting at start.
+func (f Facts) MonthSpent(start time.Time) int64 {
+	var total int64
+	for d := start; d.Month() == start.Month(); d = d.AddDate(0, 0, 1) {
+		total += f.Spent[d.Format(time.DateOnly)]
+	}
+	return total
+}
+
+// loadFacts replays the budgets of the user who recorded trigger and the
+// expense changes in their personal book, whose ID is the user's, up to and
+// including trigger. Reading up to the trigger
+// rather than to the end of the log makes the outcome the same whenever the
+// event is delivered. Budgets are only read for the months from the first
+// expense recorded to today, the only ones the rules judge.
+func loadFacts(ctx context.Context, store eventstore.Store, changes ExpenseChanges, trigger eventstore.Event, loc *time.Location) (Facts, error) {
+	y, m, d := trigger.OccurredAt.In(loc).Date()
+	f := Facts{
+		Today:       time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		Spent:       make(map[string]int64),
+		Logged:      make(map[string]bool),
+		TotalLimits: make(map[string]int64),
+	}
+
+	history, err := changes.ExpensesThrough(ctx, trigger.RecordedBy, trigger.Position())
+	if err != nil {
+		return Facts{}, fmt.Errorf("read expenses: %w", err)
+	}
+	type state struct {
+		amount int64
+		date   string
+	}
+	expenses := make(map[string]state)
+	for _, c := range history {
+		switch {
+		case c.Voided:
+			delete(expenses, c.ID)
+		default:
+			expenses[c.ID] = state{amount: c.Amount, date: c.Date}
+		}
+		if c.Recorded() {
+			f.Logged[c.OccurredAt.In(loc).Format(time.DateOnly)] = true
+		}
+	}
+	for _, e := range expenses {
+		f.Spent[e.date] += e.amount
+	}
+
+	first, ok := f.FirstLogged()
+	if !ok {
+		return f, nil
+	}
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(f.Today); month = month.AddDate(0, 1, 0) {
+		b, err := budget.LoadAt(ctx, store, trigger.RecordedBy, month.Format("2006-01"), trigger.Position())
+		if err != nil {
+			return Facts{}, err
+		}
+		if b.Exists() && b.Total > 0 {
+			f.TotalLimits[b.Month] = b.Total
+		}
+	}
+	return f, nil
+}
+
+// day parses a date as midnight UTC.
+func day(date string) time.Time {
+	t, _ := time.Parse(time.DateOnly, date)
+	return t
+}
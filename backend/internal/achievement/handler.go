@@ -0,0 +1,70 @@
+package achievement
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// Handler handles HTTP requests for achievements.
+// Achievements are unlocked asynchronously by Engine through the outbox, so
+// one earned by a just-recorded expense can be missing for a moment.
+//
+// Every route must be behind middleware.Authenticate.
+type Handler struct {
+	store eventstore.Store
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store) *Handler {
+	return &Handler{store: store}
+}
+
+// Register adds achievement routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /achievements", h.ListAchievements)
+}
+
+type achievementResponse struct {
+	Key         string     `json:"key"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Unlocked    bool       `json:"unlocked"`
+	EarnedOn    *string    `json:"earned_on"`
+	UnlockedAt  *time.Time `json:"unlocked_at"`
+}
+
+// ListAchievements handles GET /achievements. It lists every achievement,
+// locked ones included, for the request user in the order the rules are
+// declared.
+func (h *Handler) ListAchievements(w http.ResponseWriter, r *http.Request) {
+	unlocked, err := unlockedAchievements(r.Context(), h.store, middleware.UserID(r.Context()))
+	if err != nil {
+		log.Printf("list achievements: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+
+	resp := make([]achievementResponse, len(rules))
+	for i, rule := range rules {
+		resp[i] = achievementResponse{Key: rule.Key, Name: rule.Name, Description: rule.Description}
+		if u, ok := unlocked[rule.Key]; ok {
+			resp[i].Unlocked = true
+			resp[i].EarnedOn = &u.EarnedOn
+			resp[i].UnlockedAt = &u.UnlockedAt
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
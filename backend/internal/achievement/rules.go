@@ -0,0 +1,162 @@
+package achievement
+
+import (
+	"slices"
+	"time"
+)
+
+// Rule is an achievement and the condition that earns it.
+type Rule struct {
+	// Key identifies the achievement in events. It must be stable.
+	Key         string
+	Name        string
+	Description string
+	// Check reports whether the facts earn the achievement and, if so, the
+	// first day they did.
+	Check func(f Facts) (earnedOn time.Time, ok bool)
+}
+
+// rules are every achievement there is. Add a rule here, with a case in
+// rules_test.go, to award a new achievement; existing users earn it with
+// their next expense if their history already qualifies.
+var rules = []Rule{
+	{
+		Key:         "first-expense",
+		Name:        "はじめの一歩",
+		Description: "はじめて支出を記録する",
+		Check: func(f Facts) (time.Time, bool) {
+			return f.FirstLogged()
+		},
+	},
+	{
+		Key:         "logging-week",
+		Name:        "記録の習慣",
+		Description: "7日続けて支出を記録する",
+		Check:       loggingStreak(7),
+	},
+	{
+		Key:         "no-spend-day",
+		Name:        "ノースペンドデー",
+		Description: "記録を始めてから、支出ゼロの日を1日過ごす",
+		Check:       noSpendDay,
+	},
+	{
+		Key:         "under-budget-week",
+		Name:        "予算内の1週間",
+		Description: "7日続けて、月の予算を日割りした額以内に収める",
+		Check:       underBudgetStreak(7),
+	},
+	{
+		Key:         "under-budget-month",
+		Name:        "予算内の30日",
+		Description: "30日続けて、月の予算を日割りした額以内に収める",
+		Check:       underBudgetStreak(30),
+	},
+	{
+		Key:         "beat-last-month",
+		Name:        "過去の自分に勝利",
+		Description: "終わった月の支出が前の月を下回る",
+		Check:       beatLastMonth,
+	},
+}
+
+// loggingStreak is earned by recording expenses on n consecutive days.
+func loggingStreak(n int) func(Facts) (time.Time, bool) {
+	return func(f Facts) (time.Time, bool) {
+		days := make([]string, 0, len(f.Logged))
+		for d := range f.Logged {
+			days = append(days, d)
+		}
+		slices.Sort(days)
+
+		run := 0
+		var prev time.Time
+		for _, s := range days {
+			d := day(s)
+			if run > 0 && d.Equal(prev.AddDate(0, 0, 1)) {
+				run++
+			} else {
+				run = 1
+			}
+			if run == n {
+				return d, true
+			}
+			prev = d
+		}
+		return time.Time{}, false
+	}
+}
+
+// noSpendDay is earned by a day without spending, once it is over, since the
+// first expense was recorded.
+func noSpendDay(f Facts) (time.Time, bool) {
+	first, ok := f.FirstLogged()
+	if !ok {
+		return time.Time{}, false
+	}
+	for d := first; d.Before(f.Today); d = d.AddDate(0, 0, 1) {
+		if f.Spent[d.Format(time.DateOnly)] == 0 {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// underBudgetStreak is earned by n consecutive days, all over, whose
+// spending stays within their month's total budget divided by its days.
+// Days before the first expense was recorded, or in months without a cap on
+// total spending, break the streak.
+func underBudgetStreak(n int) func(Facts) (time.Time, bool) {
+	return func(f Facts) (time.Time, bool) {
+		first, ok := f.FirstLogged()
+		if !ok {
+			return time.Time{}, false
+		}
+
+		run := 0
+		for d := first; d.Before(f.Today); d = d.AddDate(0, 0, 1) {
+			limit, ok := f.TotalLimits[d.Format("2006-01")]
+			daysInMonth := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			if ok && f.Spent[d.Format(time.DateOnly)]*int64(daysInMonth) <= limit {
+				run++
+			} else {
+				run = 0
+			}
+			if run == n {
+				return d, true
+			}
+		}
+		return time.Time{}, false
+	}
+}
+
+// beatLastMonth is earned by a month, once it is over, that spent less than
+// the month before it. Both months must have spending, so a month before
+// the first expense or one without any records does not count.
+func beatLastMonth(f Facts) (time.Time, bool) {
+	var earliest string
+	for d := range f.Spent {
+		if earliest == "" || d < earliest {
+			earliest = d
+		}
+	}
+	if earliest == "" {
+		return time.Time{}, false
+	}
+
+	first := day(earliest)
+	prev := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)
+	prevSpent := f.MonthSpent(prev)
+	for {
+		m := prev.AddDate(0, 1, 0)
+		end := m.AddDate(0, 1, -1)
+		if !end.Before(f.Today) {
+			return time.Time{}, false
+		}
+		spent := f.MonthSpent(m)
+		if spent > 0 && prevSpent > 0 && spent < prevSpent {
+			return end, true
+		}
+		prev, prevSpent = m, spent
+	}
+}
@@ -0,0 +1,131 @@
+package achievement
+
+import (
+	"testing"
+	"time"
+)
+
+// facts returns the facts of a user on today who recorded expenses on the
+// logged days.
+func facts(today string, logged []string, spent, limits map[string]int64) Facts {
+	f := Facts{Today: day(today), Spent: spent, Logged: make(map[string]bool), TotalLimits: limits}
+	for _, d := range logged {
+		f.Logged[d] = true
+	}
+	if f.Spent == nil {
+		f.Spent = map[string]int64{}
+	}
+	if f.TotalLimits == nil {
+		f.TotalLimits = map[string]int64{}
+	}
+	return f
+}
+
+// daily spends amount on every day from from to to, inclusive.
+func daily(from, to string, amount int64) map[string]int64 {
+	spent := make(map[string]int64)
+	for d := day(from); !d.After(day(to)); d = d.AddDate(0, 0, 1) {
+		spent[d.Format(time.DateOnly)] = amount
+	}
+	return spent
+}
+
+// with returns spent with the given days overridden.
+func with(spent map[string]int64, days map[string]int64) map[string]int64 {
+	for d, amount := range days {
+		spent[d] = amount
+	}
+	return spent
+}
+
+func days(from, to string) []string {
+	var days []string
+	for d := range daily(from, to, 0) {
+		days = append(days, d)
+	}
+	return days
+}
+
+func TestRules(t *testing.T) {
+	feb := map[string]int64{"2026-02": 28000} // 1000 a day
+
+	tests := []struct {
+		rule  string
+		name  string
+		facts Facts
+		want  string // the day earned, or empty if not earned
+	}{
+		{"first-expense", "nothing recorded", facts("2026-02-07", nil, nil, nil), ""},
+		{"first-expense", "recorded", facts("2026-02-07", []string{"2026-02-03", "2026-02-01"}, nil, nil), "2026-02-01"},
+
+		{"logging-week", "six days", facts("2026-02-07", days("2026-02-01", "2026-02-06"), nil, nil), ""},
+		{"logging-week", "seven days", facts("2026-02-07", days("2026-02-01", "2026-02-07"), nil, nil), "2026-02-07"},
+		{"logging-week", "after a gap", facts("2026-02-09", append(days("2026-02-03", "2026-02-09"), "2026-02-01"), nil, nil), "2026-02-09"},
+		{"logging-week", "across months", facts("2026-02-03", days("2026-01-28", "2026-02-03"), nil, nil), "2026-02-03"},
+
+		{"no-spend-day", "nothing recorded", facts("2026-02-07", nil, nil, nil), ""},
+		{"no-spend-day", "spent every day", facts("2026-02-07", []string{"2026-02-01"}, daily("2026-02-01", "2026-02-06", 500), nil), ""},
+		{"no-spend-day", "today is not over", facts("2026-02-02", []string{"2026-02-01"}, daily("2026-02-01", "2026-02-01", 500), nil), ""},
+		{"no-spend-day", "a day off", facts("2026-02-07", []string{"2026-02-01"},
+			with(daily("2026-02-01", "2026-02-06", 500), map[string]int64{"2026-02-04": 0}), nil), "2026-02-04"},
+		{"no-spend-day", "before the first record", facts("2026-02-03", []string{"2026-02-01"},
+			daily("2026-02-01", "2026-02-02", 500), nil), ""},
+
+		{"under-budget-week", "no budget", facts("2026-02-08", []string{"2026-02-01"}, daily("2026-02-01", "2026-02-07", 1000), nil), ""},
+		{"under-budget-week", "seven days within", facts("2026-02-08", []string{"2026-02-01"}, daily("2026-02-01", "2026-02-07", 1000), feb), "2026-02-07"},
+		{"under-budget-week", "seventh day not over", facts("2026-02-07", []string{"2026-02-01"}, daily("2026-02-01", "2026-02-07", 1000), feb), ""},
+		{"under-budget-week", "streak broken", facts("2026-02-11", []string{"2026-02-01"},
+			with(daily("2026-02-01", "2026-02-10", 1000), map[string]int64{"2026-02-03": 1001}), feb), "2026-02-10"},
+		{"under-budget-week", "next month has no budget", facts("2026-03-05", []string{"2026-02-25"}, nil, feb), ""},
+
+		{"under-budget-month", "thirty days", facts("2026-03-03", []string{"2026-02-01"}, nil,
+			map[string]int64{"2026-02": 28000, "2026-03": 31000}), "2026-03-02"},
+
+		{"beat-last-month", "month not over", facts("2026-02-28", []string{"2026-01-01"},
+			map[string]int64{"2026-01-10": 3000, "2026-02-10": 2000}, nil), ""},
+		{"beat-last-month", "spent less", facts("2026-03-01", []string{"2026-01-01"},
+			map[string]int64{"2026-01-10": 3000, "2026-02-10": 2000}, nil), "2026-02-28"},
+		{"beat-last-month", "an empty month does not count", facts("2026-04-01", []string{"2026-01-01"},
+			map[string]int64{"2026-01-10": 3000, "2026-03-10": 1000}, nil), ""},
+		{"beat-last-month", "later month", facts("2026-04-01", []string{"2026-01-01"},
+			map[string]int64{"2026-01-10": 2000, "2026-02-10": 3000, "2026-03-10": 2500}, nil), "2026-03-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.name, func(t *testing.T) {
+			earnedOn, ok := findRule(t, tt.rule).Check(tt.facts)
+
+			var got string
+			if ok {
+				got = earnedOn.Format(time.DateOnly)
+			}
+			if got != tt.want {
+				t.Errorf("earned on %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules_KeysAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if r.Key == "" || r.Name == "" || r.Check == nil {
+			t.Errorf("rule %+v is incomplete", r)
+		}
+		if seen[r.Key] {
+			t.Errorf("key %q is declared twice", r.Key)
+		}
+		seen[r.Key] = true
+	}
+}
+
+func findRule(t *testing.T, key string) Rule {
+	t.Helper()
+	for _, r := range rules {
+		if r.Key == key {
+			return r
+		}
+	}
+	t.Fatalf("no rule %q", key)
+	return Rule{}
+}
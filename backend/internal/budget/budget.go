@@ -0,0 +1,259 @@
+// Package budget is the event-sourced monthly budget: an optional cap on the
+// month's total spending plus optional limits per category.
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+)
+
+const aggregateType = "budget"
+
+const (
+	eventTypeSet      = "BudgetSet"
+	eventTypeAdjusted = "BudgetAdjusted"
+	eventTypeRemoved  = "BudgetRemoved"
+)
+
+// schemaVersions holds the payload schema version of each budget event.
+var schemaVersions = map[string]int{
+	eventTypeSet:      1,
+	eventTypeAdjusted: 1,
+	eventTypeRemoved:  1,
+}
+
+const (
+	// monthLayout is the format of the month a budget applies to.
+	monthLayout = "2006-01"
+
+	maxCategories     = 50
+	maxCategoryLength = 64
+)
+
+var (
+	// ErrNotFound is returned when a month has no budget.
+	ErrNotFound = errors.New("budget not found")
+	// ErrNoChanges is returned when setting a budget would not change it.
+	ErrNoChanges = errors.New("budget is already set to these limits")
+)
+
+// idNamespace derives budget IDs from users and months, so a month's budget
+// is found without a lookup table.
+var idNamespace = uuid.MustParse("5f0e4c9c-3b0a-4d8e-9c55-2b7f1f6d2a11")
+
+// ID returns the aggregate ID of userID's budget for month, given as
+// YYYY-MM.
+func ID(userID, month string) string {
+	return uuid.NewSHA1(idNamespace, []byte(userID+"/"+month)).String()
+}
+
+// ParseMonth checks that month is in YYYY-MM format and returns its first
+// day.
+func ParseMonth(month string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, month)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("month must be in YYYY-MM format")
+	}
+	return t, nil
+}
+
+// SetBudgetCommand holds the limits to set for a month. Total is the cap on
+// the month's total spending, with zero meaning no cap.
+type SetBudgetCommand struct {
+	Total      int64            `json:"total"`
+	Categories map[string]int64 `json:"categories"`
+}
+
+// BudgetSetPayload is the event payload for a budget set on a month that
+// had none, or whose budget was removed.
+type BudgetSetPayload struct {
+	Month      string           `json:"month"`
+	Total      int64            `json:"total"`
+	Categories map[string]int64 `json:"categories"`
+}
+
+// BudgetAdjustedPayload carries the full limits after the adjustment, so
+// readers can apply it without knowing the prior state.
+type BudgetAdjustedPayload struct {
+	Total      int64            `json:"total"`
+	Categories map[string]int64 `json:"categories"`
+}
+
+// BudgetRemovedPayload is the event payload for a removed budget.
+type BudgetRemovedPayload struct{}
+
+// Validate checks that the command fields are valid.
+func (c SetBudgetCommand) Validate() error {
+	var errs []error
+
+	if c.Total < 0 {
+		errs = append(errs, fmt.Errorf("total must not be negative"))
+	}
+	if c.Total == 0 && len(c.Categories) == 0 {
+		errs = append(errs, fmt.Errorf("total or at least one category limit is required"))
+	}
+	if len(c.Categories) > maxCategories {
+		errs = append(errs, fmt.Errorf("at most %d category limits are allowed", maxCategories))
+	}
+	for category, limit := range c.Categories {
+		if category == "" || len([]rune(category)) > maxCategoryLength {
+			errs = append(errs, fmt.Errorf("categories must be 1 to %d characters", maxCategoryLength))
+		}
+		if limit <= 0 {
+			errs = append(errs, fmt.Errorf("limit for %q must be positive", category))
+		}
+		if c.Total > 0 && limit > c.Total {
+			errs = append(errs, fmt.Errorf("limit for %q must not exceed total", category))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Budget is the budget aggregate, rebuilt from its event stream. Its fields
+// are exported so it can be snapshotted.
+type Budget struct {
+	ID         string           `json:"id"`
+	Month      string           `json:"month"`
+	Total      int64            `json:"total"`
+	Categories map[string]int64 `json:"categories"`
+	Removed    bool             `json:"removed"`
+	Version    int              `json:"version"`
+	// Owner is the user that first set the budget.
+	Owner string `json:"owner"`
+}
+
+// Exists reports whether the budget is set.
+func (b *Budget) Exists() bool {
+	return b.Version > 0 && !b.Removed
+}
+
+// Aggregate describes the budget aggregate to snapshot.Loader. Bump
+// StateVersion whenever Budget's JSON shape changes.
+var Aggregate = snapshot.Aggregate[*Budget]{
+	Type:         aggregateType,
+	StateVersion: 2,
+	New:          func(id string) *Budget { return &Budget{ID: id} },
+	Apply:        (*Budget).apply,
+	Version:      func(b *Budget) int { return b.Version },
+}
+
+// LoadAt replays userID's budget for month, given as YYYY-MM, from its
+// events up to and including the one at last, for readers that judge the
+// log as it stood then. The budget does not exist if it had no events by
+// then.
+func LoadAt(ctx context.Context, store eventstore.Store, userID, month string, last eventstore.Position) (*Budget, error) {
+	id := ID(userID, month)
+	events, err := store.Load(ctx, aggregateType, id)
+	if err != nil {
+		return nil, fmt.Errorf("load budget %s: %w", month, err)
+	}
+	b := Aggregate.New(id)
+	for _, e := range events {
+		if e.Position() > last {
+			break
+		}
+		if err := b.apply(e); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (b *Budget) apply(event eventstore.Event) error {
+	if event.Version != b.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, b.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeSet:
+		var payload BudgetSetPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		if event.Version == 1 {
+			b.Owner = event.RecordedBy
+		}
+		b.Month = payload.Month
+		b.Total = payload.Total
+		b.Categories = payload.Categories
+		b.Removed = false
+	case eventTypeAdjusted:
+		var payload BudgetAdjustedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		b.Total = payload.Total
+		b.Categories = payload.Categories
+	case eventTypeRemoved:
+		b.Total = 0
+		b.Categories = nil
+		b.Removed = true
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	b.Version = event.Version
+	return nil
+}
+
+// Set creates a BudgetSet event if the budget does not exist, or a
+// BudgetAdjusted event if it does, at the next version.
+// This is a pure function that performs no I/O and does not modify b.
+func (b *Budget) Set(month string, cmd SetBudgetCommand) (eventstore.Event, error) {
+	if _, err := ParseMonth(month); err != nil {
+		return eventstore.Event{}, err
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	categories := cmd.Categories
+	if categories == nil {
+		categories = map[string]int64{}
+	}
+
+	if !b.Exists() {
+		return b.newEvent(eventTypeSet, BudgetSetPayload{Month: month, Total: cmd.Total, Categories: categories})
+	}
+	if cmd.Total == b.Total && maps.Equal(categories, b.Categories) {
+		return eventstore.Event{}, ErrNoChanges
+	}
+	return b.newEvent(eventTypeAdjusted, BudgetAdjustedPayload{Total: cmd.Total, Categories: categories})
+}
+
+// Remove creates a BudgetRemoved event at the next version.
+// This is a pure function that performs no I/O and does not modify b.
+func (b *Budget) Remove() (eventstore.Event, error) {
+	if !b.Exists() {
+		return eventstore.Event{}, ErrNotFound
+	}
+	return b.newEvent(eventTypeRemoved, BudgetRemovedPayload{})
+}
+
+func (b *Budget) newEvent(eventType string, payload any) (eventstore.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   b.ID,
+		AggregateType: aggregateType,
+		Version:       b.Version + 1,
+		EventType:     eventType,
+		Payload:       data,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventType]},
+	}, nil
+}
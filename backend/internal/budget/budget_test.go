@@ -0,0 +1,153 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// apply folds events into b, failing the test on error.
+func apply(t *testing.T, b *Budget, events ...eventstore.Event) {
+	t.Helper()
+	for _, e := range events {
+		if err := b.apply(e); err != nil {
+			t.Fatalf("apply %s: %v", e.EventType, err)
+		}
+	}
+}
+
+func TestBudget_Lifecycle(t *testing.T) {
+	b := Aggregate.New(ID("alice", "2026-02"))
+	limits := SetBudgetCommand{Total: 100000, Categories: map[string]int64{"食費": 30000}}
+
+	if _, err := b.Remove(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("remove before set: err = %v, want ErrNotFound", err)
+	}
+
+	set, err := b.Set("2026-02", limits)
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if set.EventType != eventTypeSet || set.Version != 1 || set.AggregateID != b.ID {
+		t.Fatalf("event = %s v%d for %s, want BudgetSet v1 for %s", set.EventType, set.Version, set.AggregateID, b.ID)
+	}
+	apply(t, b, set)
+	if !b.Exists() || b.Month != "2026-02" || b.Total != 100000 || b.Categories["食費"] != 30000 {
+		t.Errorf("budget after set = %+v", b)
+	}
+
+	if _, err := b.Set("2026-02", limits); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("set again: err = %v, want ErrNoChanges", err)
+	}
+
+	adjusted, err := b.Set("2026-02", SetBudgetCommand{Categories: map[string]int64{"食費": 25000, "交通費": 5000}})
+	if err != nil {
+		t.Fatalf("adjust: %v", err)
+	}
+	if adjusted.EventType != eventTypeAdjusted || adjusted.Version != 2 {
+		t.Fatalf("event = %s v%d, want BudgetAdjusted v2", adjusted.EventType, adjusted.Version)
+	}
+	apply(t, b, adjusted)
+	if b.Total != 0 || len(b.Categories) != 2 || b.Categories["交通費"] != 5000 {
+		t.Errorf("budget after adjust = %+v", b)
+	}
+
+	removed, err := b.Remove()
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	apply(t, b, removed)
+	if b.Exists() {
+		t.Error("budget exists after removal")
+	}
+	if _, err := b.Remove(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("remove again: err = %v, want ErrNotFound", err)
+	}
+
+	// A removed budget is set afresh.
+	reset, err := b.Set("2026-02", limits)
+	if err != nil {
+		t.Fatalf("set after removal: %v", err)
+	}
+	if reset.EventType != eventTypeSet || reset.Version != 4 {
+		t.Errorf("event = %s v%d, want BudgetSet v4", reset.EventType, reset.Version)
+	}
+}
+
+func TestSetBudgetCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     SetBudgetCommand
+		wantErr bool
+	}{
+		{"total only", SetBudgetCommand{Total: 1000}, false},
+		{"categories only", SetBudgetCommand{Categories: map[string]int64{"食費": 1000}}, false},
+		{"both", SetBudgetCommand{Total: 1000, Categories: map[string]int64{"食費": 1000}}, false},
+		{"empty", SetBudgetCommand{}, true},
+		{"negative total", SetBudgetCommand{Total: -1, Categories: map[string]int64{"食費": 1000}}, true},
+		{"zero limit", SetBudgetCommand{Categories: map[string]int64{"食費": 0}}, true},
+		{"empty category", SetBudgetCommand{Categories: map[string]int64{"": 1000}}, true},
+		{"limit above total", SetBudgetCommand{Total: 1000, Categories: map[string]int64{"食費": 1001}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cmd.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestID(t *testing.T) {
+	if ID("alice", "2026-02") != ID("alice", "2026-02") {
+		t.Error("ID is not deterministic")
+	}
+	if ID("alice", "2026-02") == ID("alice", "2026-03") {
+		t.Error("different months share an ID")
+	}
+	if ID("alice", "2026-02") == ID("bob", "2026-02") {
+		t.Error("different users share an ID")
+	}
+	if len(ID("alice", "2026-02")) != 36 {
+		t.Errorf("len(ID) = %d, want 36 to fit events.aggregate_id", len(ID("alice", "2026-02")))
+	}
+}
+
+func TestLoadAt(t *testing.T) {
+	ctx := context.Background()
+	store := eventstore.NewMemoryStore()
+	b := Aggregate.New(ID("alice", "2026-02"))
+	for _, cmd := range []SetBudgetCommand{{Total: 1000}, {Total: 2000}} {
+		event, err := b.Set("2026-02", cmd)
+		if err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if err := store.Append(ctx, []eventstore.Event{event}, b.Version); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		apply(t, b, event)
+	}
+
+	tests := []struct {
+		month string
+		last  eventstore.Position
+		total int64
+	}{
+		{"2026-02", 0, 0},
+		{"2026-02", 1, 1000},
+		{"2026-02", 2, 2000},
+		{"2026-03", 2, 0},
+	}
+	for _, tt := range tests {
+		got, err := LoadAt(ctx, store, "alice", tt.month, tt.last)
+		if err != nil {
+			t.Fatalf("LoadAt(%s, %d): %v", tt.month, tt.last, err)
+		}
+		if got.Exists() != (tt.total > 0) || got.Total != tt.total {
+			t.Errorf("LoadAt(%s, %d) = %+v, want total %d", tt.month, tt.last, got, tt.total)
+		}
+	}
+}
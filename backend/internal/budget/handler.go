@@ -0,0 +1,183 @@
+package budget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+)
+
+// snapshotEvery is how many events a budget may grow by before it is
+// snapshotted again.
+const snapshotEvery = 20
+
+// Handler handles HTTP requests for the budget domain.
+// Spending comes from the summary read model, so progress can lag
+// just-recorded expenses for a moment. Every route must be behind
+// middleware.Authenticate, and acts on the user's own budgets.
+type Handler struct {
+	store    eventstore.Store
+	budgets  *snapshot.Loader[*Budget]
+	spending summary.Reader
+	// now returns the current time; today is its date in the local zone.
+	now func() time.Time
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store, snapshots snapshot.Store, spending summary.Reader) *Handler {
+	return &Handler{
+		store:    store,
+		budgets:  snapshot.NewLoader(store, snapshots, Aggregate, snapshot.Every(snapshotEvery)),
+		spending: spending,
+		now:      time.Now,
+	}
+}
+
+// Register adds budget routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("PUT /budgets/{month}", h.SetBudget)
+	mux.HandleFunc("DELETE /budgets/{month}", h.RemoveBudget)
+	mux.HandleFunc("GET /budgets/{month}/progress", h.BudgetProgress)
+}
+
+type setBudgetResponse struct {
+	Month   string `json:"month"`
+	Version int    `json:"version"`
+}
+
+// SetBudget handles PUT /budgets/{month}. Setting the limits a budget
+// already has succeeds without appending an event.
+func (h *Handler) SetBudget(w http.ResponseWriter, r *http.Request) {
+	var cmd SetBudgetCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	month := r.PathValue("month")
+	if _, err := ParseMonth(month); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	b, err := h.budgets.Load(ctx, ID(middleware.UserID(ctx), month))
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("load budget: %w", err))
+		return
+	}
+
+	event, err := b.Set(month, cmd)
+	if errors.Is(err, ErrNoChanges) {
+		writeJSON(w, http.StatusOK, setBudgetResponse{Month: month, Version: b.Version})
+		return
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, b.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, setBudgetResponse{Month: month, Version: event.Version})
+}
+
+// RemoveBudget handles DELETE /budgets/{month}.
+func (h *Handler) RemoveBudget(w http.ResponseWriter, r *http.Request) {
+	month := r.PathValue("month")
+	if _, err := ParseMonth(month); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	b, err := h.budgets.Load(ctx, ID(middleware.UserID(ctx), month))
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("load budget: %w", err))
+		return
+	}
+
+	event, err := b.Remove()
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, b.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BudgetProgress handles GET /budgets/{month}/progress.
+func (h *Handler) BudgetProgress(w http.ResponseWriter, r *http.Request) {
+	month := r.PathValue("month")
+	start, err := ParseMonth(month)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	b, err := h.budgets.Load(ctx, ID(userID, month))
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("load budget: %w", err))
+		return
+	}
+	if !b.Exists() {
+		writeCommandError(w, ErrNotFound)
+		return
+	}
+
+	totals, err := h.spending.DailyTotals(ctx, userID,
+		start.Format(time.DateOnly), start.AddDate(0, 1, -1).Format(time.DateOnly))
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("read spending: %w", err))
+		return
+	}
+
+	y, m, d := h.now().Date()
+	writeJSON(w, http.StatusOK, progress(b, totals, time.Date(y, m, d, 0, 0, 0, 0, time.UTC)))
+}
+
+// writeCommandError maps domain errors to HTTP responses.
+func writeCommandError(w http.ResponseWriter, err error) {
+	var conflict *eventstore.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":           "budget was modified concurrently; reload and retry",
+			"current_version": conflict.Actual,
+		})
+	case errors.Is(err, ErrNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("budget: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+// newTestServer serves the budget routes in memory on 2026-02-07, with the
+// given expenses of alice's already summarized.
+func newTestServer(t *testing.T, expenses ...expense.RecordExpenseCommand) (*httptest.Server, *eventstore.MemoryStore) {
+	t.Helper()
+
+	spending := summary.NewMemoryRepository()
+	projector := summary.NewProjector(spending)
+	for i, cmd := range expenses {
+		event, err := expense.RecordExpense(string(rune('a'+i)), cmd)
+		if err != nil {
+			t.Fatalf("record expense: %v", err)
+		}
+		event.RecordedBy = "alice"
+		if err := projector.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply expense: %v", err)
+		}
+	}
+
+	store := eventstore.NewMemoryStore()
+	h := NewHandler(store, snapshot.NewMemoryStore(), spending)
+	h.now = func() time.Time { return time.Date(2026, 2, 7, 21, 0, 0, 0, time.Local) }
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+	srv := httptest.NewServer(testhelper.AsUser(mux))
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func doRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+func TestSetBudgetAndProgress(t *testing.T) {
+	srv, store := newTestServer(t,
+		expense.RecordExpenseCommand{Amount: 6000, Category: "食費", Date: "2026-02-01"},
+		expense.RecordExpenseCommand{Amount: 1000, Category: "交通費", Date: "2026-02-03"},
+		expense.RecordExpenseCommand{Amount: 9999, Category: "食費", Date: "2026-03-01"},
+	)
+
+	body := `{"total":50000,"categories":{"食費":20000}}`
+	for i, wantVersion := range []int{1, 1} {
+		resp := doRequest(t, http.MethodPut, srv.URL+"/budgets/2026-02", body)
+		var got setBudgetResponse
+		err := json.NewDecoder(resp.Body).Decode(&got)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK || got.Version != wantVersion {
+			t.Fatalf("PUT #%d: status %d, version %d, err %v; want 200 at version %d",
+				i+1, resp.StatusCode, got.Version, err, wantVersion)
+		}
+	}
+	events, _ := store.Load(context.Background(), aggregateType, ID("alice", "2026-02"))
+	if len(events) != 1 || events[0].RecordedBy != "alice" {
+		t.Errorf("%d events after setting the same budget twice, want 1 recorded by alice", len(events))
+	}
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/budgets/2026-02/progress", "")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET progress status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var p Progress
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		t.Fatalf("decode progress: %v", err)
+	}
+
+	if p.AsOf != "2026-02-07" || p.Spent != 7000 {
+		t.Errorf("progress as of %s spent %d, want as of 2026-02-07 spent 7000", p.AsOf, p.Spent)
+	}
+	if p.Total == nil || p.Total.Limit != 50000 || p.Total.Remaining != 43000 || p.Total.Projected != 28000 {
+		t.Errorf("total = %+v, want 43000 remaining and 28000 projected", p.Total)
+	}
+	if len(p.Categories) != 1 || p.Categories[0].Projected != 24000 || p.Categories[0].ProjectedOverrun != 4000 {
+		t.Errorf("categories = %+v, want 食費 projected 4000 over", p.Categories)
+	}
+}
+
+func TestRemoveBudget(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPut, srv.URL+"/budgets/2026-02", `{"total":50000}`)
+	resp.Body.Close()
+
+	resp = doRequest(t, http.MethodDelete, srv.URL+"/budgets/2026-02", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodDelete, "/budgets/2026-02"},
+		{http.MethodGet, "/budgets/2026-02/progress"},
+		{http.MethodGet, "/budgets/2026-03/progress"},
+	} {
+		resp := doRequest(t, req.method, srv.URL+req.path, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s %s status = %d, want %d", req.method, req.path, resp.StatusCode, http.StatusNotFound)
+		}
+	}
+}
+
+func TestBudgets_ScopedToUser(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPut, srv.URL+"/budgets/2026-02", `{"total":50000}`)
+	resp.Body.Close()
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/budgets/2026-02/progress"},
+		{http.MethodDelete, "/budgets/2026-02"},
+	} {
+		r, err := http.NewRequest(req.method, srv.URL+req.path, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		r.Header.Set(testhelper.UserHeader, "bob")
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("%s %s: %v", req.method, req.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("bob: %s %s status = %d, want %d", req.method, req.path, resp.StatusCode, http.StatusNotFound)
+		}
+	}
+
+	resp = doRequest(t, http.MethodGet, srv.URL+"/budgets/2026-02/progress", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("alice: GET progress status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestSetBudget_ValidationError(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	tests := []struct {
+		name, month, body string
+	}{
+		{"invalid month", "2026-2", `{"total":1000}`},
+		{"no limits", "2026-02", `{}`},
+		{"negative total", "2026-02", `{"total":-1}`},
+		{"limit above total", "2026-02", `{"total":1000,"categories":{"食費":2000}}`},
+		{"invalid json", "2026-02", `{invalid}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doRequest(t, http.MethodPut, srv.URL+"/budgets/"+tt.month, tt.body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package budget
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+)
+
+// Progress compares a month's spending with its budget.
+type Progress struct {
+	Month   string `json:"month"`
+	Version int    `json:"version"`
+	// AsOf is the day the projections extrapolate from: today for the
+	// current month, the last day for past months and the day before the
+	// first for future months.
+	AsOf string `json:"as_of"`
+	// Spent is the month's spending across every category.
+	Spent int64 `json:"spent"`
+	// Total is nil if the budget has no cap on total spending.
+	Total *LimitProgress `json:"total"`
+	// Categories follow the order of their names.
+	Categories []CategoryProgress `json:"categories"`
+}
+
+// LimitProgress compares spending with one limit.
+type LimitProgress struct {
+	Limit int64 `json:"limit"`
+	Spent int64 `json:"spent"`
+	// Remaining is negative once the limit is exceeded.
+	Remaining   int64   `json:"remaining"`
+	PercentUsed float64 `json:"percent_used"`
+	// Projected is the spending at the end of the month if it continues at
+	// the average daily rate so far.
+	Projected int64 `json:"projected"`
+	// ProjectedOverrun is how far Projected exceeds the limit, or zero.
+	ProjectedOverrun int64 `json:"projected_overrun"`
+}
+
+// CategoryProgress compares a category's spending with its limit.
+type CategoryProgress struct {
+	Category string `json:"category"`
+	LimitProgress
+}
+
+// progress compares b with the month's daily totals as of today, which must
+// be a date at midnight UTC like the ones ParseMonth returns.
+func progress(b *Budget, totals []summary.DailyTotal, today time.Time) Progress {
+	start, _ := ParseMonth(b.Month)
+	end := start.AddDate(0, 1, -1)
+	asOf, elapsed := today, today.Day()
+	switch {
+	case today.Before(start):
+		asOf, elapsed = start.AddDate(0, 0, -1), 0
+	case today.After(end):
+		asOf, elapsed = end, end.Day()
+	}
+	daysInMonth := end.Day()
+
+	p := Progress{
+		Month:      b.Month,
+		Version:    b.Version,
+		AsOf:       asOf.Format(time.DateOnly),
+		Categories: []CategoryProgress{},
+	}
+	spent := make(map[string]int64)
+	for _, t := range totals {
+		p.Spent += t.Total
+		spent[t.Category] += t.Total
+	}
+
+	limit := func(limit, spent int64) LimitProgress {
+		projected := spent
+		if elapsed > 0 {
+			projected = spent * int64(daysInMonth) / int64(elapsed)
+		}
+		return LimitProgress{
+			Limit:            limit,
+			Spent:            spent,
+			Remaining:        limit - spent,
+			PercentUsed:      float64(spent) * 100 / float64(limit),
+			Projected:        projected,
+			ProjectedOverrun: max(projected-limit, 0),
+		}
+	}
+
+	if b.Total > 0 {
+		total := limit(b.Total, p.Spent)
+		p.Total = &total
+	}
+	for category, l := range b.Categories {
+		p.Categories = append(p.Categories, CategoryProgress{Category: category, LimitProgress: limit(l, spent[category])})
+	}
+	slices.SortFunc(p.Categories, func(a, b CategoryProgress) int {
+		return strings.Compare(a.Category, b.Category)
+	})
+	return p
+}
@@ -0,0 +1,71 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+)
+
+func TestProgress(t *testing.T) {
+	b := &Budget{Month: "2026-02", Version: 3, Total: 100000, Categories: map[string]int64{"食費": 20000, "交通費": 10000}}
+	totals := []summary.DailyTotal{
+		{Date: "2026-02-01", Category: "食費", Total: 8000, Count: 2},
+		{Date: "2026-02-05", Category: "食費", Total: 2000, Count: 1},
+		{Date: "2026-02-06", Category: "娯楽費", Total: 4000, Count: 1},
+	}
+
+	tests := []struct {
+		name      string
+		today     string
+		asOf      string
+		projected int64 // of 食費
+	}{
+		{"during the month", "2026-02-07", "2026-02-07", 40000},
+		{"after the month", "2026-03-15", "2026-02-28", 10000},
+		{"before the month", "2026-01-20", "2026-01-31", 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			today, _ := time.Parse(time.DateOnly, tt.today)
+			p := progress(b, totals, today)
+
+			if p.Month != "2026-02" || p.Version != 3 || p.AsOf != tt.asOf || p.Spent != 14000 {
+				t.Errorf("progress = %s v%d as of %s spent %d, want 2026-02 v3 as of %s spent 14000",
+					p.Month, p.Version, p.AsOf, p.Spent, tt.asOf)
+			}
+			if p.Total == nil || p.Total.Spent != 14000 || p.Total.Remaining != 86000 || p.Total.PercentUsed != 14 {
+				t.Errorf("total = %+v, want 14000 spent of 100000", p.Total)
+			}
+			if len(p.Categories) != 2 {
+				t.Fatalf("categories = %+v, want 2", p.Categories)
+			}
+
+			// Ordered by name: 交通費 before 食費.
+			transport, food := p.Categories[0], p.Categories[1]
+			if transport.Category != "交通費" || transport.Spent != 0 || transport.Remaining != 10000 {
+				t.Errorf("交通費 = %+v, want nothing spent", transport)
+			}
+			want := LimitProgress{
+				Limit: 20000, Spent: 10000, Remaining: 10000, PercentUsed: 50,
+				Projected: tt.projected, ProjectedOverrun: max(tt.projected-20000, 0),
+			}
+			if food.Category != "食費" || food.LimitProgress != want {
+				t.Errorf("食費 = %+v, want %+v", food, want)
+			}
+		})
+	}
+}
+
+func TestProgress_NoTotalCap(t *testing.T) {
+	b := &Budget{Month: "2026-02", Categories: map[string]int64{"食費": 1000}}
+	p := progress(b, []summary.DailyTotal{{Date: "2026-02-01", Category: "食費", Total: 1500}}, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	if p.Total != nil {
+		t.Errorf("total = %+v, want nil", p.Total)
+	}
+	if got := p.Categories[0]; got.Remaining != -500 || got.PercentUsed != 150 || got.ProjectedOverrun != 1500*28-1000 {
+		t.Errorf("食費 = %+v, want 500 over with a projected overrun of %d", got, 1500*28-1000)
+	}
+}
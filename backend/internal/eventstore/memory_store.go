@@ -0,0 +1,129 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements Store in memory with the same semantics as
+// MySQLStore. It is intended for tests and local runs without a database.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	events   []Event
+	versions map[streamKey]int
+	now      func() time.Time
+
+	// OnAppend, if set, runs under the store's lock with the stamped events
+	// before they become visible. Returning an error aborts the append.
+	OnAppend func(events []Event) error
+}
+
+type streamKey struct {
+	aggregateType string
+	aggregateID   string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		versions: make(map[streamKey]int),
+		now:      time.Now,
+	}
+}
+
+// Append persists events atomically, stamping their ID and OccurredAt.
+func (s *MemoryStore) Append(_ context.Context, events []Event, expectedVersion int) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := validateBatch(events, expectedVersion); err != nil {
+		return err
+	}
+	key := streamKey{events[0].AggregateType, events[0].AggregateID}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current := s.versions[key]; current != expectedVersion {
+		return &VersionConflictError{
+			AggregateID:   key.aggregateID,
+			AggregateType: key.aggregateType,
+			Expected:      expectedVersion,
+			Actual:        current,
+		}
+	}
+
+	// Match the microsecond precision of the DATETIME(6) column.
+	occurredAt := s.now().UTC().Truncate(time.Microsecond)
+	stamped := make([]Event, len(events))
+	for i, e := range events {
+		e.ID = uint64(len(s.events) + i + 1)
+		e.Payload = slices.Clone(e.Payload)
+		if e.RecordedBy == "" {
+			e.RecordedBy = defaultRecordedBy
+		}
+		e.OccurredAt = occurredAt
+		stamped[i] = e
+	}
+
+	if s.OnAppend != nil {
+		if err := s.OnAppend(slices.Clone(stamped)); err != nil {
+			return fmt.Errorf("on append: %w", err)
+		}
+	}
+	s.events = append(s.events, stamped...)
+	s.versions[key] = events[len(events)-1].Version
+	return nil
+}
+
+// Load returns all events for the given aggregate ordered by version.
+func (s *MemoryStore) Load(_ context.Context, aggregateType, aggregateID string) ([]Event, error) {
+	return s.filter(0, 0, func(e Event) bool {
+		return e.AggregateType == aggregateType && e.AggregateID == aggregateID
+	}), nil
+}
+
+// LoadFrom returns the aggregate's events after afterVersion ordered by version.
+func (s *MemoryStore) LoadFrom(_ context.Context, aggregateType, aggregateID string, afterVersion int) ([]Event, error) {
+	return s.filter(0, 0, func(e Event) bool {
+		return e.AggregateType == aggregateType && e.AggregateID == aggregateID && e.Version > afterVersion
+	}), nil
+}
+
+// ReadAll returns events from the global log in commit order.
+func (s *MemoryStore) ReadAll(_ context.Context, after Position, limit int) ([]Event, error) {
+	return s.filter(after, normalizeReadLimit(limit), func(Event) bool { return true }), nil
+}
+
+// ReadAllByType returns events of one aggregate type from the global log in commit order.
+func (s *MemoryStore) ReadAllByType(_ context.Context, aggregateType string, after Position, limit int) ([]Event, error) {
+	return s.filter(after, normalizeReadLimit(limit), func(e Event) bool {
+		return e.AggregateType == aggregateType
+	}), nil
+}
+
+// filter returns copies of up to limit matching events after the given
+// position. A zero limit means no limit.
+func (s *MemoryStore) filter(after Position, limit int, match func(Event) bool) []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Clamp before converting: positions past MaxInt would turn negative.
+	if after > Position(len(s.events)) {
+		after = Position(len(s.events))
+	}
+	var events []Event
+	for _, e := range s.events[after:] {
+		if limit > 0 && len(events) == limit {
+			break
+		}
+		if match(e) {
+			e.Payload = slices.Clone(e.Payload)
+			events = append(events, e)
+		}
+	}
+	return events
+}
@@ -0,0 +1,45 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(*testing.T) eventstore.Store {
+		return eventstore.NewMemoryStore()
+	})
+}
+
+func TestMemoryStore_OnAppendFailureAbortsAppend(t *testing.T) {
+	store := eventstore.NewMemoryStore()
+	store.OnAppend = func([]eventstore.Event) error { return errors.New("hook failed") }
+	testOnAppendFailureAbortsAppend(t, store)
+}
+
+// testOnAppendFailureAbortsAppend checks that an append whose OnAppend hook
+// fails leaves no events behind.
+func testOnAppendFailureAbortsAppend(t *testing.T, store eventstore.Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	event := eventstore.Event{
+		AggregateID: "a", AggregateType: "test", Version: 1,
+		EventType: "TestHappened", Payload: []byte(`{}`),
+	}
+	if err := store.Append(ctx, []eventstore.Event{event}, 0); err == nil {
+		t.Fatal("append succeeded although OnAppend failed")
+	}
+
+	events, err := store.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("%d events stored, want 0", len(events))
+	}
+}
@@ -3,18 +3,29 @@ package eventstore
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/go-sql-driver/mysql"
 )
 
-// mysqlDuplicateEntryCode is the MySQL error code for duplicate key violations.
-const mysqlDuplicateEntryCode = 1062
+const (
+	// mysqlDuplicateEntryCode is the MySQL error code for duplicate key violations.
+	mysqlDuplicateEntryCode = 1062
+	// mysqlDeadlockCode is the MySQL error code for a deadlock-induced rollback.
+	mysqlDeadlockCode = 1213
+)
 
 // MySQLStore implements Store backed by a MySQL events table.
 type MySQLStore struct {
 	db *sql.DB
+
+	// OnAppend, if set, runs inside the append transaction after the events
+	// are inserted, with ID and RecordedBy stamped (OccurredAt is not).
+	// Returning an error rolls the append back, so whatever it writes
+	// commits if and only if the events do.
+	OnAppend func(ctx context.Context, tx *sql.Tx, events []Event) error
 }
 
 // NewMySQLStore creates a new MySQLStore.
@@ -23,12 +34,21 @@ func NewMySQLStore(db *sql.DB) *MySQLStore {
 }
 
 // Append persists events in a single transaction.
-// Returns VersionConflictError when the UNIQUE constraint on
-// (aggregate_id, aggregate_type, version) is violated.
+// The current version of the stream is read with a locking read inside the
+// transaction, so concurrent appends to the same aggregate are serialized and
+// the loser gets a VersionConflictError carrying the version it lost to.
+//
+// All appends additionally take the row lock on event_append_lock before
+// inserting. This makes events.id allocation follow commit order, which is
+// what lets ReadAll readers treat every position they have passed as final.
 func (s *MySQLStore) Append(ctx context.Context, events []Event, expectedVersion int) error {
 	if len(events) == 0 {
 		return nil
 	}
+	if err := validateBatch(events, expectedVersion); err != nil {
+		return err
+	}
+	aggregateType, aggregateID := events[0].AggregateType, events[0].AggregateID
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -36,44 +56,150 @@ func (s *MySQLStore) Append(ctx context.Context, events []Event, expectedVersion
 	}
 	defer tx.Rollback()
 
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO event_append_lock (id) VALUES (1) ON DUPLICATE KEY UPDATE id = id`); err != nil {
+		return fmt.Errorf("acquire append lock: %w", err)
+	}
+
+	current, err := currentVersion(ctx, tx, aggregateType, aggregateID, true)
+	if err != nil {
+		return s.conflictOr(ctx, err, aggregateType, aggregateID, expectedVersion)
+	}
+	if current != expectedVersion {
+		return &VersionConflictError{
+			AggregateID:   aggregateID,
+			AggregateType: aggregateType,
+			Expected:      expectedVersion,
+			Actual:        current,
+		}
+	}
+
 	stmt, err := tx.PrepareContext(ctx,
-		`INSERT INTO events (aggregate_id, aggregate_type, version, event_type, payload, recorded_by)
-		 VALUES (?, ?, ?, ?, ?, ?)`)
+		`INSERT INTO events (aggregate_id, aggregate_type, version, event_type, payload, metadata, recorded_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return fmt.Errorf("prepare insert: %w", err)
 	}
 	defer stmt.Close()
 
-	for _, e := range events {
-		_, err := stmt.ExecContext(ctx,
-			e.AggregateID, e.AggregateType, e.Version, e.EventType, e.Payload, e.RecordedBy)
+	inserted := make([]Event, len(events))
+	for i, e := range events {
+		if e.RecordedBy == "" {
+			e.RecordedBy = defaultRecordedBy
+		}
+		metadata, err := json.Marshal(e.Metadata)
 		if err != nil {
-			var mysqlErr *mysql.MySQLError
-			if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntryCode {
-				return &VersionConflictError{
-					AggregateID:   e.AggregateID,
-					AggregateType: e.AggregateType,
-					Expected:      expectedVersion,
-				}
-			}
-			return fmt.Errorf("insert event: %w", err)
+			return fmt.Errorf("marshal metadata: %w", err)
+		}
+		res, err := stmt.ExecContext(ctx,
+			e.AggregateID, e.AggregateType, e.Version, e.EventType, e.Payload, metadata, e.RecordedBy)
+		if err != nil {
+			return s.conflictOr(ctx, fmt.Errorf("insert event: %w", err),
+				aggregateType, aggregateID, expectedVersion)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("last insert id: %w", err)
+		}
+		e.ID = uint64(id)
+		inserted[i] = e
+	}
+
+	if s.OnAppend != nil {
+		if err := s.OnAppend(ctx, tx, inserted); err != nil {
+			return fmt.Errorf("on append: %w", err)
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return s.conflictOr(ctx, fmt.Errorf("commit tx: %w", err),
+			aggregateType, aggregateID, expectedVersion)
 	}
 	return nil
 }
 
+// conflictOr translates duplicate-key and deadlock errors into a
+// VersionConflictError. Both happen when two writers race to create the same
+// stream: the locking read cannot lock rows that do not exist yet.
+func (s *MySQLStore) conflictOr(ctx context.Context, err error, aggregateType, aggregateID string, expectedVersion int) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) ||
+		(mysqlErr.Number != mysqlDuplicateEntryCode && mysqlErr.Number != mysqlDeadlockCode) {
+		return err
+	}
+
+	actual, vErr := currentVersion(ctx, s.db, aggregateType, aggregateID, false)
+	if vErr != nil {
+		return fmt.Errorf("%w (reading actual version: %v)", err, vErr)
+	}
+	return &VersionConflictError{
+		AggregateID:   aggregateID,
+		AggregateType: aggregateType,
+		Expected:      expectedVersion,
+		Actual:        actual,
+	}
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// currentVersion returns the highest version stored for the aggregate, or 0
+// when the stream is empty. With lock set, the read takes next-key locks on
+// the stream so no other transaction can append to it until this one ends.
+func currentVersion(ctx context.Context, q queryRower, aggregateType, aggregateID string, lock bool) (int, error) {
+	query := `SELECT COALESCE(MAX(version), 0) FROM events
+		 WHERE aggregate_type = ? AND aggregate_id = ?`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	var version int
+	if err := q.QueryRowContext(ctx, query, aggregateType, aggregateID).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read current version: %w", err)
+	}
+	return version, nil
+}
+
+const selectEvents = `SELECT id, aggregate_id, aggregate_type, version, event_type, payload, metadata, recorded_by, occurred_at
+		 FROM events`
+
 // Load returns all events for the given aggregate ordered by version.
 func (s *MySQLStore) Load(ctx context.Context, aggregateType, aggregateID string) ([]Event, error) {
-	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, aggregate_id, aggregate_type, version, event_type, payload, recorded_by, occurred_at
-		 FROM events
+	return s.query(ctx, selectEvents+`
 		 WHERE aggregate_type = ? AND aggregate_id = ?
 		 ORDER BY version ASC`,
 		aggregateType, aggregateID)
+}
+
+// LoadFrom returns the aggregate's events after afterVersion ordered by version.
+func (s *MySQLStore) LoadFrom(ctx context.Context, aggregateType, aggregateID string, afterVersion int) ([]Event, error) {
+	return s.query(ctx, selectEvents+`
+		 WHERE aggregate_type = ? AND aggregate_id = ? AND version > ?
+		 ORDER BY version ASC`,
+		aggregateType, aggregateID, afterVersion)
+}
+
+// ReadAll returns events from the global log in commit order.
+func (s *MySQLStore) ReadAll(ctx context.Context, after Position, limit int) ([]Event, error) {
+	return s.query(ctx, selectEvents+`
+		 WHERE id > ?
+		 ORDER BY id ASC
+		 LIMIT ?`,
+		after, normalizeReadLimit(limit))
+}
+
+// ReadAllByType returns events of one aggregate type from the global log in commit order.
+func (s *MySQLStore) ReadAllByType(ctx context.Context, aggregateType string, after Position, limit int) ([]Event, error) {
+	return s.query(ctx, selectEvents+`
+		 WHERE aggregate_type = ? AND id > ?
+		 ORDER BY id ASC
+		 LIMIT ?`,
+		aggregateType, after, normalizeReadLimit(limit))
+}
+
+func (s *MySQLStore) query(ctx context.Context, query string, args ...any) ([]Event, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query events: %w", err)
 	}
@@ -82,10 +208,17 @@ func (s *MySQLStore) Load(ctx context.Context, aggregateType, aggregateID string
 	var events []Event
 	for rows.Next() {
 		var e Event
+		var metadata []byte
 		if err := rows.Scan(&e.ID, &e.AggregateID, &e.AggregateType, &e.Version,
-			&e.EventType, &e.Payload, &e.RecordedBy, &e.OccurredAt); err != nil {
+			&e.EventType, &e.Payload, &metadata, &e.RecordedBy, &e.OccurredAt); err != nil {
 			return nil, fmt.Errorf("scan event: %w", err)
 		}
+		// Events written before the metadata column existed have none.
+		if metadata != nil {
+			if err := json.Unmarshal(metadata, &e.Metadata); err != nil {
+				return nil, fmt.Errorf("unmarshal metadata of event %d: %w", e.ID, err)
+			}
+		}
 		events = append(events, e)
 	}
 	if err := rows.Err(); err != nil {
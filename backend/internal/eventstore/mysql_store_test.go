@@ -0,0 +1,35 @@
+package eventstore_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore/storetest"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+func TestMySQLStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) eventstore.Store {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		return eventstore.NewMySQLStore(db)
+	})
+}
+
+func TestMySQLStore_OnAppendFailureRollsBack(t *testing.T) {
+	db := testhelper.OpenTestDB(t)
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	store := eventstore.NewMySQLStore(db)
+	store.OnAppend = func(context.Context, *sql.Tx, []eventstore.Event) error {
+		return errors.New("hook failed")
+	}
+	testOnAppendFailureAbortsAppend(t, store)
+}
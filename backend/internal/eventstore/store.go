@@ -2,11 +2,16 @@ package eventstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// defaultRecordedBy is stored when an event does not name who recorded it.
+const defaultRecordedBy = "anonymous"
+
 // Event represents a single domain event persisted in the event store.
+// The store assigns ID and OccurredAt on Append.
 type Event struct {
 	ID            uint64
 	AggregateID   string
@@ -14,29 +19,113 @@ type Event struct {
 	Version       int
 	EventType     string
 	Payload       []byte
+	Metadata      Metadata
 	RecordedBy    string
 	OccurredAt    time.Time
 }
 
+// Metadata records where an event came from, so that a request, an import
+// batch or a client release can be traced to the events it wrote. It never
+// affects how an event is applied.
+type Metadata struct {
+	// CorrelationID groups every event caused, directly or indirectly, by
+	// the same request. It is the X-Request-ID of the originating request.
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// CausationID identifies what directly caused the event: the request ID
+	// for events written by a command, or the causing event's ID for events
+	// written in reaction to another event.
+	CausationID string `json:"causation_id,omitempty"`
+	// UserID is the authenticated user that issued the command, if any.
+	UserID string `json:"user_id,omitempty"`
+	// Client is the kind of client that issued the command: web, mobile or import.
+	Client string `json:"client,omitempty"`
+	// SchemaVersion is the version of the payload's shape for its event type.
+	SchemaVersion int `json:"schema_version,omitempty"`
+}
+
+// Position is an event's place in the global event log, backed by events.id.
+// Positions increase in commit order but are not contiguous: a rolled-back
+// append leaves a permanent gap. The zero Position lies before every event,
+// so reading after it starts from the beginning of the log.
+type Position uint64
+
+// MaxReadLimit caps the number of events a single ReadAll call returns.
+const MaxReadLimit = 1000
+
+// Position returns the event's position in the global event log.
+func (e Event) Position() Position {
+	return Position(e.ID)
+}
+
+// ErrInvalidEvents is returned when a batch passed to Append is malformed:
+// events belong to different aggregates or their versions do not continue
+// contiguously from expectedVersion.
+var ErrInvalidEvents = errors.New("invalid events")
+
 // VersionConflictError indicates an optimistic concurrency violation.
 // Another writer appended events to the same aggregate before this write.
 type VersionConflictError struct {
 	AggregateID   string
 	AggregateType string
 	Expected      int
+	Actual        int
 }
 
 func (e *VersionConflictError) Error() string {
-	return fmt.Sprintf("version conflict on %s/%s: expected version %d",
-		e.AggregateType, e.AggregateID, e.Expected)
+	return fmt.Sprintf("version conflict on %s/%s: expected version %d, actual %d",
+		e.AggregateType, e.AggregateID, e.Expected, e.Actual)
+}
+
+// validateBatch checks that events target a single aggregate and that their
+// versions run contiguously from expectedVersion+1.
+func validateBatch(events []Event, expectedVersion int) error {
+	if expectedVersion < 0 {
+		return fmt.Errorf("%w: negative expected version %d", ErrInvalidEvents, expectedVersion)
+	}
+
+	first := events[0]
+	for i, e := range events {
+		if e.AggregateID != first.AggregateID || e.AggregateType != first.AggregateType {
+			return fmt.Errorf("%w: event %d targets %s/%s, want %s/%s", ErrInvalidEvents,
+				i, e.AggregateType, e.AggregateID, first.AggregateType, first.AggregateID)
+		}
+		if want := expectedVersion + 1 + i; e.Version != want {
+			return fmt.Errorf("%w: event %d has version %d, want %d", ErrInvalidEvents,
+				i, e.Version, want)
+		}
+	}
+	return nil
 }
 
 // Store defines the interface for appending and loading domain events.
 type Store interface {
 	// Append persists events atomically. If the current version of the
 	// aggregate does not match expectedVersion, a VersionConflictError is returned.
+	// The events must target one aggregate with versions expectedVersion+1,
+	// expectedVersion+2, and so on; otherwise ErrInvalidEvents is returned.
 	Append(ctx context.Context, events []Event, expectedVersion int) error
 
 	// Load returns all events for the given aggregate, ordered by version.
 	Load(ctx context.Context, aggregateType, aggregateID string) ([]Event, error)
+
+	// LoadFrom is like Load but only returns events with versions greater
+	// than afterVersion, for callers that already hold the aggregate's state
+	// at that version.
+	LoadFrom(ctx context.Context, aggregateType, aggregateID string, afterVersion int) ([]Event, error)
+
+	// ReadAll returns up to limit events positioned after the given position,
+	// across all aggregates in commit order. Passing the position of the last
+	// returned event pages through the log; an empty result means the reader
+	// has caught up. A limit outside 1..MaxReadLimit is treated as MaxReadLimit.
+	ReadAll(ctx context.Context, after Position, limit int) ([]Event, error)
+
+	// ReadAllByType is like ReadAll but only returns events of aggregateType.
+	ReadAllByType(ctx context.Context, aggregateType string, after Position, limit int) ([]Event, error)
+}
+
+func normalizeReadLimit(limit int) int {
+	if limit <= 0 || limit > MaxReadLimit {
+		return MaxReadLimit
+	}
+	return limit
 }
@@ -0,0 +1,453 @@
+// Package storetest provides a conformance suite that every
+// eventstore.Store implementation must pass.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Run runs the conformance suite against stores created by newStore,
+// which must return an empty store on every call.
+func Run(t *testing.T, newStore func(t *testing.T) eventstore.Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, store eventstore.Store)
+	}{
+		{"Append_ExpectedVersion", testAppendExpectedVersion},
+		{"Append_InvalidBatch", testAppendInvalidBatch},
+		{"Append_ConcurrentWriters", testAppendConcurrentWriters},
+		{"Append_StampsEvents", testAppendStampsEvents},
+		{"Load_UnknownAggregate", testLoadUnknownAggregate},
+		{"LoadFrom_AfterVersion", testLoadFromAfterVersion},
+		{"Metadata_RoundTrip", testMetadataRoundTrip},
+		{"ReadAll_Paging", testReadAllPaging},
+		{"ReadAll_SkipsGaps", testReadAllSkipsGaps},
+		{"ReadAll_AfterEnd", testReadAllAfterEnd},
+		{"ReadAll_ConcurrentWriters", testReadAllConcurrentWriters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+func newEvent(id string, version int) eventstore.Event {
+	return eventstore.Event{
+		AggregateID:   id,
+		AggregateType: "test",
+		Version:       version,
+		EventType:     "TestHappened",
+		Payload:       []byte(`{}`),
+		RecordedBy:    "anonymous",
+	}
+}
+
+func testAppendExpectedVersion(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1), newEvent("a", 2)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		version  int
+		expected int
+	}{
+		{"behind", 2, 1},
+		{"ahead", 4, 3},
+		{"new stream", 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Append(ctx, []eventstore.Event{newEvent("a", tt.version)}, tt.expected)
+
+			var conflict *eventstore.VersionConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("err = %v, want VersionConflictError", err)
+			}
+			if conflict.Expected != tt.expected {
+				t.Errorf("Expected = %d, want %d", conflict.Expected, tt.expected)
+			}
+			if conflict.Actual != 2 {
+				t.Errorf("Actual = %d, want 2", conflict.Actual)
+			}
+		})
+	}
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 3)}, 2); err != nil {
+		t.Fatalf("append at current version: %v", err)
+	}
+
+	events, err := store.Load(ctx, "test", "a")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("len(events) = %d, want 3", len(events))
+	}
+}
+
+func testAppendInvalidBatch(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		events   []eventstore.Event
+		expected int
+	}{
+		{"gap", []eventstore.Event{newEvent("a", 1), newEvent("a", 3)}, 0},
+		{"not after expected", []eventstore.Event{newEvent("a", 2)}, 0},
+		{"mixed aggregates", []eventstore.Event{newEvent("a", 1), newEvent("b", 2)}, 0},
+		{"negative expected", []eventstore.Event{newEvent("a", 0)}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Append(ctx, tt.events, tt.expected)
+			if !errors.Is(err, eventstore.ErrInvalidEvents) {
+				t.Fatalf("err = %v, want ErrInvalidEvents", err)
+			}
+		})
+	}
+
+	events, err := store.Load(ctx, "test", "a")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("len(events) = %d, want 0", len(events))
+	}
+}
+
+func testAppendConcurrentWriters(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	const writers = 8
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = store.Append(ctx, []eventstore.Event{newEvent("a", 2)}, 1)
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		var conflict *eventstore.VersionConflictError
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.As(err, &conflict):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("succeeded = %d, want 1", succeeded)
+	}
+}
+
+func readAllPages(t *testing.T, read func(after eventstore.Position) ([]eventstore.Event, error)) []eventstore.Event {
+	t.Helper()
+
+	var all []eventstore.Event
+	var after eventstore.Position
+	for {
+		page, err := read(after)
+		if err != nil {
+			t.Fatalf("read after %d: %v", after, err)
+		}
+		if len(page) == 0 {
+			return all
+		}
+		for _, e := range page {
+			if e.Position() <= after {
+				t.Fatalf("position %d not after %d", e.Position(), after)
+			}
+			after = e.Position()
+		}
+		all = append(all, page...)
+	}
+}
+
+func testReadAllPaging(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	other := newEvent("c", 1)
+	other.AggregateType = "other"
+	batches := [][]eventstore.Event{
+		{newEvent("a", 1), newEvent("a", 2)},
+		{newEvent("b", 1)},
+		{other},
+		{newEvent("a", 3)},
+	}
+	for _, batch := range batches {
+		if err := store.Append(ctx, batch, batch[0].Version-1); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	all := readAllPages(t, func(after eventstore.Position) ([]eventstore.Event, error) {
+		return store.ReadAll(ctx, after, 2)
+	})
+	want := []string{"a/1", "a/2", "b/1", "c/1", "a/3"}
+	if len(all) != len(want) {
+		t.Fatalf("len(all) = %d, want %d", len(all), len(want))
+	}
+	for i, e := range all {
+		if got := fmt.Sprintf("%s/%d", e.AggregateID, e.Version); got != want[i] {
+			t.Errorf("all[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+
+	byType := readAllPages(t, func(after eventstore.Position) ([]eventstore.Event, error) {
+		return store.ReadAllByType(ctx, "test", after, 2)
+	})
+	if len(byType) != 4 {
+		t.Fatalf("len(byType) = %d, want 4", len(byType))
+	}
+	for _, e := range byType {
+		if e.AggregateType != "test" {
+			t.Errorf("AggregateType = %q, want %q", e.AggregateType, "test")
+		}
+	}
+}
+
+func testReadAllSkipsGaps(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	// A failed append consumes no position readers could wait on.
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1)}, 0); err == nil {
+		t.Fatal("expected conflict, got nil")
+	}
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 2)}, 1); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	all := readAllPages(t, func(after eventstore.Position) ([]eventstore.Event, error) {
+		return store.ReadAll(ctx, after, 1)
+	})
+	if len(all) != 2 {
+		t.Fatalf("len(all) = %d, want 2", len(all))
+	}
+}
+
+func testReadAllAfterEnd(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1), newEvent("a", 2)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	for _, after := range []eventstore.Position{2, 3, math.MaxInt64 + 1, math.MaxUint64} {
+		all, err := store.ReadAll(ctx, after, 0)
+		if err != nil {
+			t.Fatalf("ReadAll after %d: %v", after, err)
+		}
+		byType, err := store.ReadAllByType(ctx, "test", after, 0)
+		if err != nil {
+			t.Fatalf("ReadAllByType after %d: %v", after, err)
+		}
+		if len(all) != 0 || len(byType) != 0 {
+			t.Errorf("after %d: %d and %d events, want none", after, len(all), len(byType))
+		}
+	}
+}
+
+func testReadAllConcurrentWriters(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	const (
+		writers          = 4
+		eventsPerWriter  = 10
+		totalEventsCount = writers * eventsPerWriter
+	)
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("writer-%d", w)
+			for v := 1; v <= eventsPerWriter; v++ {
+				if err := store.Append(ctx, []eventstore.Event{newEvent(id, v)}, v-1); err != nil {
+					t.Errorf("append %s/%d: %v", id, v, err)
+					return
+				}
+			}
+		}()
+	}
+
+	// Tail the log while writers are active. Every event must be seen exactly
+	// once, even though the reader advances past positions mid-write.
+	seen := make(map[eventstore.Position]bool)
+	var after eventstore.Position
+	for len(seen) < totalEventsCount {
+		page, err := store.ReadAll(ctx, after, 3)
+		if err != nil {
+			t.Fatalf("read all: %v", err)
+		}
+		for _, e := range page {
+			if seen[e.Position()] {
+				t.Fatalf("position %d read twice", e.Position())
+			}
+			seen[e.Position()] = true
+			after = e.Position()
+		}
+		if len(page) == 0 && t.Failed() {
+			break
+		}
+	}
+	wg.Wait()
+
+	if rest, err := store.ReadAll(ctx, after, 0); err != nil || len(rest) != 0 {
+		t.Errorf("after tailing: %d more events (err %v), want 0", len(rest), err)
+	}
+}
+
+func testAppendStampsEvents(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	// Allow for clock skew between the test and a database server.
+	before := time.Now().Add(-time.Minute)
+	recorded := newEvent("a", 1)
+	recorded.RecordedBy = ""
+	if err := store.Append(ctx, []eventstore.Event{recorded, newEvent("a", 2)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	after := time.Now().Add(time.Minute)
+
+	events, err := store.Load(ctx, "test", "a")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(events))
+	}
+
+	if events[0].ID == 0 || events[1].ID <= events[0].ID {
+		t.Errorf("IDs = %d, %d, want increasing non-zero", events[0].ID, events[1].ID)
+	}
+	for _, e := range events {
+		if e.OccurredAt.Before(before) || e.OccurredAt.After(after) {
+			t.Errorf("OccurredAt = %v, want between %v and %v", e.OccurredAt, before, after)
+		}
+		if e.OccurredAt.Location() != time.UTC {
+			t.Errorf("OccurredAt location = %v, want UTC", e.OccurredAt.Location())
+		}
+	}
+	if events[0].RecordedBy != "anonymous" {
+		t.Errorf("RecordedBy = %q, want %q", events[0].RecordedBy, "anonymous")
+	}
+	if string(events[1].Payload) != `{}` {
+		t.Errorf("Payload = %s, want {}", events[1].Payload)
+	}
+}
+
+func testLoadUnknownAggregate(t *testing.T, store eventstore.Store) {
+	events, err := store.Load(context.Background(), "test", "missing")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("len(events) = %d, want 0", len(events))
+	}
+}
+
+func testLoadFromAfterVersion(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	if err := store.Append(ctx, []eventstore.Event{newEvent("a", 1), newEvent("a", 2), newEvent("a", 3)}, 0); err != nil {
+		t.Fatalf("append a: %v", err)
+	}
+	if err := store.Append(ctx, []eventstore.Event{newEvent("b", 1), newEvent("b", 2)}, 0); err != nil {
+		t.Fatalf("append b: %v", err)
+	}
+
+	tests := []struct {
+		after int
+		want  []int
+	}{
+		{0, []int{1, 2, 3}},
+		{1, []int{2, 3}},
+		{3, nil},
+		{5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("after %d", tt.after), func(t *testing.T) {
+			events, err := store.LoadFrom(ctx, "test", "a", tt.after)
+			if err != nil {
+				t.Fatalf("load from: %v", err)
+			}
+			var got []int
+			for _, e := range events {
+				if e.AggregateID != "a" {
+					t.Fatalf("got event of aggregate %q, want a", e.AggregateID)
+				}
+				got = append(got, e.Version)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("versions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testMetadataRoundTrip(t *testing.T, store eventstore.Store) {
+	ctx := context.Background()
+
+	md := eventstore.Metadata{
+		CorrelationID: "req-1",
+		CausationID:   "req-1",
+		UserID:        "user-1",
+		Client:        "mobile",
+		SchemaVersion: 2,
+	}
+	withMetadata := newEvent("a", 1)
+	withMetadata.Metadata = md
+	if err := store.Append(ctx, []eventstore.Event{withMetadata, newEvent("a", 2)}, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	loaded, err := store.Load(ctx, "test", "a")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	read, err := store.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+
+	for name, events := range map[string][]eventstore.Event{"Load": loaded, "ReadAll": read} {
+		if len(events) != 2 {
+			t.Fatalf("%s: len(events) = %d, want 2", name, len(events))
+		}
+		if events[0].Metadata != md {
+			t.Errorf("%s: Metadata = %+v, want %+v", name, events[0].Metadata, md)
+		}
+		if events[1].Metadata != (eventstore.Metadata{}) {
+			t.Errorf("%s: Metadata = %+v, want zero", name, events[1].Metadata)
+		}
+	}
+}
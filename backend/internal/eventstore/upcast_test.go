@@ -0,0 +1,130 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func appendSuffix(suffix string) eventstore.Upcaster {
+	return func(payload []byte) ([]byte, error) {
+		return append(payload[:len(payload):len(payload)], suffix...), nil
+	}
+}
+
+func TestUpcasters_Upcast(t *testing.T) {
+	u := eventstore.NewUpcasters()
+	u.Register("Thing", 1, appendSuffix("+v2"))
+	u.Register("Thing", 2, appendSuffix("+v3"))
+
+	tests := []struct {
+		name        string
+		event       eventstore.Event
+		wantPayload string
+		wantVersion int
+	}{
+		{"legacy counts as v1", eventstore.Event{EventType: "Thing", Payload: []byte("p")}, "p+v2+v3", 3},
+		{"v1", eventstore.Event{EventType: "Thing", Payload: []byte("p"), Metadata: eventstore.Metadata{SchemaVersion: 1}}, "p+v2+v3", 3},
+		{"v2", eventstore.Event{EventType: "Thing", Payload: []byte("p"), Metadata: eventstore.Metadata{SchemaVersion: 2}}, "p+v3", 3},
+		{"current", eventstore.Event{EventType: "Thing", Payload: []byte("p"), Metadata: eventstore.Metadata{SchemaVersion: 3}}, "p", 3},
+		{"other type", eventstore.Event{EventType: "Other", Payload: []byte("p")}, "p", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := u.Upcast(tt.event)
+			if err != nil {
+				t.Fatalf("upcast: %v", err)
+			}
+			if string(got.Payload) != tt.wantPayload || got.Metadata.SchemaVersion != tt.wantVersion {
+				t.Errorf("got payload %q v%d, want %q v%d",
+					got.Payload, got.Metadata.SchemaVersion, tt.wantPayload, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestUpcasters_UpcastError(t *testing.T) {
+	errBroken := errors.New("broken")
+	u := eventstore.NewUpcasters()
+	u.Register("Thing", 1, func([]byte) ([]byte, error) { return nil, errBroken })
+
+	if _, err := u.Upcast(eventstore.Event{EventType: "Thing"}); !errors.Is(err, errBroken) {
+		t.Errorf("err = %v, want %v", err, errBroken)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	versions := map[string]int{"Thing": 2}
+
+	tests := []struct {
+		name    string
+		event   eventstore.Event
+		wantErr bool
+	}{
+		{"current", eventstore.Event{EventType: "Thing", Metadata: eventstore.Metadata{SchemaVersion: 2}}, false},
+		{"not upcast", eventstore.Event{EventType: "Thing", Metadata: eventstore.Metadata{SchemaVersion: 1}}, true},
+		{"legacy", eventstore.Event{EventType: "Thing"}, true},
+		{"unknown type", eventstore.Event{EventType: "Other", Metadata: eventstore.Metadata{SchemaVersion: 7}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := eventstore.CheckSchemaVersion(tt.event, versions); (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpcasters_RegisterTwicePanics(t *testing.T) {
+	u := eventstore.NewUpcasters()
+	u.Register("Thing", 1, appendSuffix(""))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("second Register did not panic")
+		}
+	}()
+	u.Register("Thing", 1, appendSuffix(""))
+}
+
+func TestUpcastingStore(t *testing.T) {
+	ctx := context.Background()
+	raw := eventstore.NewMemoryStore()
+	events := []eventstore.Event{
+		{AggregateID: "a", AggregateType: "test", Version: 1, EventType: "Thing", Payload: []byte(`"p"`)},
+		{AggregateID: "a", AggregateType: "test", Version: 2, EventType: "Thing", Payload: []byte(`"p"`),
+			Metadata: eventstore.Metadata{SchemaVersion: 2}},
+	}
+	if err := raw.Append(ctx, events, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	u := eventstore.NewUpcasters()
+	u.Register("Thing", 1, func([]byte) ([]byte, error) { return []byte(`"upcast"`), nil })
+	store := eventstore.NewUpcastingStore(raw, u)
+
+	reads := map[string]func() ([]eventstore.Event, error){
+		"Load":          func() ([]eventstore.Event, error) { return store.Load(ctx, "test", "a") },
+		"ReadAll":       func() ([]eventstore.Event, error) { return store.ReadAll(ctx, 0, 0) },
+		"ReadAllByType": func() ([]eventstore.Event, error) { return store.ReadAllByType(ctx, "test", 0, 0) },
+	}
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			got, err := read()
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("got %d events, want 2", len(got))
+			}
+			if string(got[0].Payload) != `"upcast"` || got[0].Metadata.SchemaVersion != 2 {
+				t.Errorf("event 1 = %s v%d, want upcast v2", got[0].Payload, got[0].Metadata.SchemaVersion)
+			}
+			if string(got[1].Payload) != `"p"` {
+				t.Errorf("event 2 = %s, want it untouched", got[1].Payload)
+			}
+		})
+	}
+}
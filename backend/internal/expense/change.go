@@ -0,0 +1,102 @@
+package expense
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// AggregateType is the aggregate type of expense events, for readers of the
+// event log that only want expenses.
+const AggregateType = aggregateType
+
+// Change is an expense event decoded for read models and subscribers kept
+// outside this package, so they need not know the event types and payloads.
+type Change struct {
+	ID      string
+	Version int
+	// Voided is set for ExpenseVoided, whose change carries no fields.
+	Voided bool
+	// The expense's fields after the change, for ExpenseRecorded and
+	// ExpenseCorrected. A correction carries every field, changed or not.
+	Amount   int64
+	Category string
+	Memo     string
+	Date     string
+	Tags     []string
+	// Split is nil unless the expense is shared in a household book.
+	Split *Split
+	// AccountID is the account the expense was paid from, if any.
+	AccountID string
+
+	OccurredAt time.Time
+	Metadata   eventstore.Metadata
+	// RecordedBy is the user that made the change.
+	RecordedBy string
+	// Book is the book the expense belongs to, set on the change that
+	// recorded it.
+	Book string
+}
+
+// Recorded reports whether the change recorded the expense.
+func (c Change) Recorded() bool {
+	return c.Version == 1
+}
+
+// DecodeChange decodes an expense event, which must have been read through
+// a store wrapped with RegisterUpcasters. ok is false for events of other
+// aggregate types.
+func DecodeChange(event eventstore.Event) (change Change, ok bool, err error) {
+	if event.AggregateType != aggregateType {
+		return Change{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return Change{}, false, err
+	}
+
+	change = Change{
+		ID:         event.AggregateID,
+		Version:    event.Version,
+		OccurredAt: event.OccurredAt,
+		Metadata:   event.Metadata,
+		RecordedBy: event.RecordedBy,
+	}
+	var payload ExpenseCorrectedPayload
+	switch event.EventType {
+	case eventTypeRecorded:
+		var recorded ExpenseRecordedPayload
+		if err := json.Unmarshal(event.Payload, &recorded); err != nil {
+			return Change{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		change.Book = bookOf(recorded, event.RecordedBy)
+		payload = ExpenseCorrectedPayload{
+			Amount:    recorded.Amount,
+			Category:  recorded.Category,
+			Memo:      recorded.Memo,
+			Date:      recorded.Date,
+			Tags:      recorded.Tags,
+			Split:     recorded.Split,
+			AccountID: recorded.AccountID,
+		}
+	case eventTypeCorrected:
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return Change{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+	case eventTypeVoided:
+		change.Voided = true
+		return change, true, nil
+	default:
+		return Change{}, false, fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	change.Amount = payload.Amount
+	change.Category = payload.Category
+	change.Memo = payload.Memo
+	change.Date = payload.Date
+	change.Tags = normalizeTags(payload.Tags)
+	change.Split = payload.Split
+	change.AccountID = payload.AccountID
+	return change, true, nil
+}
@@ -4,36 +4,135 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
 )
 
 const aggregateType = "expense"
-const eventTypeRecorded = "ExpenseRecorded"
+
+const (
+	eventTypeRecorded  = "ExpenseRecorded"
+	eventTypeCorrected = "ExpenseCorrected"
+	eventTypeVoided    = "ExpenseVoided"
+)
+
+// schemaVersions holds the current payload schema version of each event type.
+// Bump the version and register an upcaster in upcast.go whenever a payload
+// changes shape.
+var schemaVersions = map[string]int{
+	eventTypeRecorded:  5,
+	eventTypeCorrected: 4,
+	eventTypeVoided:    1,
+}
+
+const (
+	maxTags      = 10
+	maxTagLength = 32
+)
+
+// MaxAmount is the largest amount of an expense or income, in yen. It keeps
+// ratio splits, which multiply an amount by a share's ratio, and totals of
+// many amounts well clear of int64 overflow.
+const MaxAmount = 1_000_000_000_000
+
+var (
+	// ErrNotFound is returned when an expense has no events.
+	ErrNotFound = errors.New("expense not found")
+	// ErrVoided is returned when a command targets a voided expense.
+	ErrVoided = errors.New("expense is voided")
+	// ErrNoChanges is returned when a correction would not change anything.
+	ErrNoChanges = errors.New("correction does not change the expense")
+	// ErrBookNotFound is returned when the user has no role in a book.
+	ErrBookNotFound = errors.New("book not found")
+	// ErrForbidden is returned when the user's role in a book does not
+	// allow changing its expenses.
+	ErrForbidden = errors.New("your role in this book does not allow changes")
+	// ErrInvalidAccount is returned when an expense names an account that
+	// is not in its book.
+	ErrInvalidAccount = errors.New("invalid account")
+)
 
 // RecordExpenseCommand holds the data needed to record a new expense.
 type RecordExpenseCommand struct {
-	Amount   int64  `json:"amount"`
-	Category string `json:"category"`
-	Memo     string `json:"memo"`
-	Date     string `json:"date"`
+	// BookID is the book to record the expense in. Empty means the
+	// recorder's personal book.
+	BookID   string   `json:"book_id"`
+	Amount   int64    `json:"amount"`
+	Category string   `json:"category"`
+	Memo     string   `json:"memo"`
+	Date     string   `json:"date"`
+	Tags     []string `json:"tags"`
+	// Split is who paid and how the expense is shared. Nil leaves it
+	// unsplit; only household books can be split.
+	Split *Split `json:"split"`
+	// AccountID is the account the expense was paid from, which must be in
+	// the same book. Empty leaves it unassigned.
+	AccountID string `json:"account_id"`
 }
 
 // ExpenseRecordedPayload is the event payload stored in the event store.
+// Schema v2 added Tags; v3 added BookID, which is empty for expenses
+// recorded before books, in the recorder's personal book; v4 added Split,
+// resolved to yen; v5 added AccountID.
 type ExpenseRecordedPayload struct {
-	Amount   int64  `json:"amount"`
-	Category string `json:"category"`
-	Memo     string `json:"memo"`
-	Date     string `json:"date"`
+	BookID    string   `json:"book_id"`
+	Amount    int64    `json:"amount"`
+	Category  string   `json:"category"`
+	Memo      string   `json:"memo"`
+	Date      string   `json:"date"`
+	Tags      []string `json:"tags"`
+	Split     *Split   `json:"split"`
+	AccountID string   `json:"account_id"`
+}
+
+// CorrectExpenseCommand holds the fields to change on an existing expense.
+// Nil fields are left as they are.
+type CorrectExpenseCommand struct {
+	Amount   *int64    `json:"amount"`
+	Category *string   `json:"category"`
+	Memo     *string   `json:"memo"`
+	Date     *string   `json:"date"`
+	Tags     *[]string `json:"tags"`
+	// Split replaces the expense's split. When it is nil but the amount
+	// changes, an equal or ratio split is shared out again; a fixed split
+	// must then be given anew.
+	Split *Split `json:"split"`
+	// AccountID replaces the account the expense was paid from. An empty
+	// string unassigns it.
+	AccountID *string `json:"account_id"`
+}
+
+// ExpenseCorrectedPayload carries the full state of the expense after the
+// correction, so projections can apply it without knowing the prior state.
+// Schema v2 added Tags; v3 added Split; v4 added AccountID.
+type ExpenseCorrectedPayload struct {
+	Amount    int64    `json:"amount"`
+	Category  string   `json:"category"`
+	Memo      string   `json:"memo"`
+	Date      string   `json:"date"`
+	Tags      []string `json:"tags"`
+	Split     *Split   `json:"split"`
+	AccountID string   `json:"account_id"`
+}
+
+// VoidExpenseCommand holds the data needed to void an expense.
+type VoidExpenseCommand struct {
+	Reason string `json:"reason"`
+}
+
+// ExpenseVoidedPayload is the event payload for a voided expense.
+type ExpenseVoidedPayload struct {
+	Reason string `json:"reason"`
 }
 
 // Validate checks that the command fields are valid.
 func (c RecordExpenseCommand) Validate() error {
 	var errs []error
 
-	if c.Amount <= 0 {
-		errs = append(errs, fmt.Errorf("amount must be positive"))
+	if c.Amount <= 0 || c.Amount > MaxAmount {
+		errs = append(errs, fmt.Errorf("amount must be 1 to %d", MaxAmount))
 	}
 	if c.Category == "" {
 		errs = append(errs, fmt.Errorf("category is required"))
@@ -41,22 +140,89 @@ func (c RecordExpenseCommand) Validate() error {
 	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
 		errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
 	}
+	errs = append(errs, validateTags(c.Tags))
+	if c.Split != nil {
+		errs = append(errs, c.Split.Validate())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the fields present in the command are valid.
+func (c CorrectExpenseCommand) Validate() error {
+	var errs []error
+
+	if c.Amount == nil && c.Category == nil && c.Memo == nil && c.Date == nil && c.Tags == nil && c.Split == nil &&
+		c.AccountID == nil {
+		errs = append(errs, fmt.Errorf("at least one field must be provided"))
+	}
+	if c.Amount != nil && (*c.Amount <= 0 || *c.Amount > MaxAmount) {
+		errs = append(errs, fmt.Errorf("amount must be 1 to %d", MaxAmount))
+	}
+	if c.Category != nil && *c.Category == "" {
+		errs = append(errs, fmt.Errorf("category must not be empty"))
+	}
+	if c.Date != nil {
+		if _, err := time.Parse(time.DateOnly, *c.Date); err != nil {
+			errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+		}
+	}
+	if c.Tags != nil {
+		errs = append(errs, validateTags(*c.Tags))
+	}
+	if c.Split != nil {
+		errs = append(errs, c.Split.Validate())
+	}
 
 	return errors.Join(errs...)
 }
 
+func validateTags(tags []string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("at most %d tags are allowed", maxTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag == "" || len([]rune(tag)) > maxTagLength {
+			return fmt.Errorf("tags must be 1 to %d characters", maxTagLength)
+		}
+		if seen[tag] {
+			return fmt.Errorf("duplicate tag %q", tag)
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// normalizeTags returns an empty slice for nil so payloads always carry a
+// JSON array.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 // RecordExpense creates an event for recording a new expense.
 // This is a pure function that performs no I/O.
 func RecordExpense(id string, cmd RecordExpenseCommand) (eventstore.Event, error) {
 	if err := cmd.Validate(); err != nil {
 		return eventstore.Event{}, err
 	}
+	split, err := resolveSplit(cmd.Split, cmd.Amount)
+	if err != nil {
+		return eventstore.Event{}, err
+	}
 
 	payload, err := json.Marshal(ExpenseRecordedPayload{
-		Amount:   cmd.Amount,
-		Category: cmd.Category,
-		Memo:     cmd.Memo,
-		Date:     cmd.Date,
+		BookID:    cmd.BookID,
+		Amount:    cmd.Amount,
+		Category:  cmd.Category,
+		Memo:      cmd.Memo,
+		Date:      cmd.Date,
+		Tags:      normalizeTags(cmd.Tags),
+		Split:     split,
+		AccountID: cmd.AccountID,
 	})
 	if err != nil {
 		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
@@ -68,5 +234,187 @@ func RecordExpense(id string, cmd RecordExpenseCommand) (eventstore.Event, error
 		Version:       1,
 		EventType:     eventTypeRecorded,
 		Payload:       payload,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventTypeRecorded]},
+	}, nil
+}
+
+// resolveSplit resolves split, which may be nil, against amount.
+func resolveSplit(split *Split, amount int64) (*Split, error) {
+	if split == nil {
+		return nil, nil
+	}
+	resolved, err := split.Resolve(amount)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// bookOf returns the book an ExpenseRecorded payload recorded by
+// recordedBy belongs to.
+func bookOf(payload ExpenseRecordedPayload, recordedBy string) string {
+	if payload.BookID == "" {
+		return recordedBy
+	}
+	return payload.BookID
+}
+
+// Expense is the expense aggregate, rebuilt from its event stream.
+type Expense struct {
+	ID       string
+	Amount   int64
+	Category string
+	Memo     string
+	Date     string
+	Tags     []string
+	Voided   bool
+	Version  int
+	// Book is the book the expense belongs to.
+	Book string
+	// Split is nil unless the expense is shared in a household book.
+	Split *Split
+	// AccountID is the account the expense was paid from, if any.
+	AccountID string
+}
+
+// LoadExpense rebuilds an Expense from its events, which must be ordered by
+// version. Returns ErrNotFound when events is empty.
+func LoadExpense(events []eventstore.Event) (*Expense, error) {
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	e := &Expense{ID: events[0].AggregateID}
+	for _, event := range events {
+		if err := e.apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *Expense) apply(event eventstore.Event) error {
+	if event.Version != e.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, e.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeRecorded:
+		var payload ExpenseRecordedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		e.Amount = payload.Amount
+		e.Category = payload.Category
+		e.Memo = payload.Memo
+		e.Date = payload.Date
+		e.Tags = payload.Tags
+		e.Book = bookOf(payload, event.RecordedBy)
+		e.Split = payload.Split
+		e.AccountID = payload.AccountID
+	case eventTypeCorrected:
+		var payload ExpenseCorrectedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		e.Amount = payload.Amount
+		e.Category = payload.Category
+		e.Memo = payload.Memo
+		e.Date = payload.Date
+		e.Tags = payload.Tags
+		e.Split = payload.Split
+		e.AccountID = payload.AccountID
+	case eventTypeVoided:
+		e.Voided = true
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	e.Version = event.Version
+	return nil
+}
+
+// Correct creates an ExpenseCorrected event at the next version.
+// This is a pure function that performs no I/O and does not modify e.
+func (e *Expense) Correct(cmd CorrectExpenseCommand) (eventstore.Event, error) {
+	if e.Voided {
+		return eventstore.Event{}, ErrVoided
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+
+	corrected := ExpenseCorrectedPayload{
+		Amount:    e.Amount,
+		Category:  e.Category,
+		Memo:      e.Memo,
+		Date:      e.Date,
+		Tags:      normalizeTags(e.Tags),
+		AccountID: e.AccountID,
+	}
+	if cmd.Amount != nil {
+		corrected.Amount = *cmd.Amount
+	}
+	if cmd.Category != nil {
+		corrected.Category = *cmd.Category
+	}
+	if cmd.Memo != nil {
+		corrected.Memo = *cmd.Memo
+	}
+	if cmd.Date != nil {
+		corrected.Date = *cmd.Date
+	}
+	if cmd.Tags != nil {
+		corrected.Tags = normalizeTags(*cmd.Tags)
+	}
+	if cmd.AccountID != nil {
+		corrected.AccountID = *cmd.AccountID
+	}
+	split := e.Split
+	if cmd.Split != nil {
+		split = cmd.Split
+	}
+	split, err := resolveSplit(split, corrected.Amount)
+	if err != nil {
+		return eventstore.Event{}, err
+	}
+	corrected.Split = split
+
+	if corrected.Amount == e.Amount && corrected.Category == e.Category &&
+		corrected.Memo == e.Memo && corrected.Date == e.Date &&
+		slices.Equal(corrected.Tags, e.Tags) && equalSplits(corrected.Split, e.Split) &&
+		corrected.AccountID == e.AccountID {
+		return eventstore.Event{}, ErrNoChanges
+	}
+
+	return e.newEvent(eventTypeCorrected, corrected)
+}
+
+// Void creates an ExpenseVoided event at the next version.
+// This is a pure function that performs no I/O and does not modify e.
+func (e *Expense) Void(cmd VoidExpenseCommand) (eventstore.Event, error) {
+	if e.Voided {
+		return eventstore.Event{}, ErrVoided
+	}
+	return e.newEvent(eventTypeVoided, ExpenseVoidedPayload(cmd))
+}
+
+func (e *Expense) newEvent(eventType string, payload any) (eventstore.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   e.ID,
+		AggregateType: aggregateType,
+		Version:       e.Version + 1,
+		EventType:     eventType,
+		Payload:       data,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventType]},
 	}, nil
 }
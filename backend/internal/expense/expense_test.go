@@ -2,11 +2,17 @@ package expense
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
 )
 
 func TestRecordExpense_Success(t *testing.T) {
 	cmd := RecordExpenseCommand{
+		BookID:   "book-1",
 		Amount:   1500,
 		Category: "食費",
 		Memo:     "コンビニ",
@@ -35,6 +41,9 @@ func TestRecordExpense_Success(t *testing.T) {
 	if err := json.Unmarshal(event.Payload, &payload); err != nil {
 		t.Fatalf("unmarshal payload: %v", err)
 	}
+	if payload.BookID != "book-1" {
+		t.Errorf("payload.BookID = %q, want %q", payload.BookID, "book-1")
+	}
 	if payload.Amount != 1500 {
 		t.Errorf("payload.Amount = %d, want 1500", payload.Amount)
 	}
@@ -47,6 +56,35 @@ func TestRecordExpense_Success(t *testing.T) {
 	if payload.Date != "2026-02-20" {
 		t.Errorf("payload.Date = %q, want %q", payload.Date, "2026-02-20")
 	}
+	if payload.Tags == nil || len(payload.Tags) != 0 {
+		t.Errorf("payload.Tags = %#v, want empty slice", payload.Tags)
+	}
+	if event.Metadata.SchemaVersion != 5 {
+		t.Errorf("SchemaVersion = %d, want 5", event.Metadata.SchemaVersion)
+	}
+}
+
+func TestRecordExpense_InvalidTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+	}{
+		{"empty tag", []string{""}},
+		{"too long", []string{strings.Repeat("あ", 33)}},
+		{"duplicate", []string{"旅行", "旅行"}},
+		{"too many", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RecordExpense("test-id", RecordExpenseCommand{
+				Amount: 1500, Category: "食費", Date: "2026-02-20", Tags: tt.tags,
+			})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
 }
 
 func TestRecordExpense_EmptyMemo(t *testing.T) {
@@ -77,6 +115,7 @@ func TestRecordExpense_InvalidAmount(t *testing.T) {
 	}{
 		{"zero", 0},
 		{"negative", -100},
+		{"above the maximum", MaxAmount + 1},
 	}
 
 	for _, tt := range tests {
@@ -93,6 +132,10 @@ func TestRecordExpense_InvalidAmount(t *testing.T) {
 			}
 		})
 	}
+
+	if _, err := RecordExpense("test-id", RecordExpenseCommand{Amount: MaxAmount, Category: "食費", Date: "2026-02-20"}); err != nil {
+		t.Errorf("RecordExpense(MaxAmount): %v", err)
+	}
 }
 
 func TestRecordExpense_MissingCategory(t *testing.T) {
@@ -144,3 +187,203 @@ func TestRecordExpense_MultipleErrors(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func recordedExpense(t *testing.T) *Expense {
+	t.Helper()
+
+	event, err := RecordExpense("test-id", RecordExpenseCommand{
+		Amount:   1500,
+		Category: "食費",
+		Memo:     "コンビニ",
+		Date:     "2026-02-20",
+	})
+	if err != nil {
+		t.Fatalf("record expense: %v", err)
+	}
+
+	exp, err := LoadExpense([]eventstore.Event{event})
+	if err != nil {
+		t.Fatalf("load expense: %v", err)
+	}
+	return exp
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestLoadExpense_NoEvents(t *testing.T) {
+	_, err := LoadExpense(nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadExpense_Rehydrates(t *testing.T) {
+	recorded, err := RecordExpense("test-id", RecordExpenseCommand{
+		Amount: 1500, Category: "食費", Memo: "コンビニ", Date: "2026-02-20",
+	})
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	events := []eventstore.Event{recorded}
+
+	exp, err := LoadExpense(events)
+	if err != nil {
+		t.Fatalf("load expense: %v", err)
+	}
+	corrected, err := exp.Correct(CorrectExpenseCommand{Amount: ptr(int64(1200))})
+	if err != nil {
+		t.Fatalf("correct: %v", err)
+	}
+	events = append(events, corrected)
+
+	exp, err = LoadExpense(events)
+	if err != nil {
+		t.Fatalf("load expense: %v", err)
+	}
+	voided, err := exp.Void(VoidExpenseCommand{Reason: "duplicate"})
+	if err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	events = append(events, voided)
+
+	got, err := LoadExpense(events)
+	if err != nil {
+		t.Fatalf("load expense: %v", err)
+	}
+
+	if got.Amount != 1200 {
+		t.Errorf("Amount = %d, want 1200", got.Amount)
+	}
+	if got.Category != "食費" {
+		t.Errorf("Category = %q, want %q", got.Category, "食費")
+	}
+	if !got.Voided {
+		t.Error("Voided = false, want true")
+	}
+	if got.Version != 3 {
+		t.Errorf("Version = %d, want 3", got.Version)
+	}
+}
+
+func TestLoadExpense_VersionGap(t *testing.T) {
+	recorded, _ := RecordExpense("test-id", RecordExpenseCommand{
+		Amount: 1500, Category: "食費", Date: "2026-02-20",
+	})
+	voided, _ := (&Expense{ID: "test-id", Version: 2}).Void(VoidExpenseCommand{})
+
+	if _, err := LoadExpense([]eventstore.Event{recorded, voided}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCorrect_Success(t *testing.T) {
+	exp := recordedExpense(t)
+
+	event, err := exp.Correct(CorrectExpenseCommand{
+		Category: ptr("日用品"),
+		Memo:     ptr(""),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event.Version != 2 {
+		t.Errorf("Version = %d, want 2", event.Version)
+	}
+	if event.EventType != "ExpenseCorrected" {
+		t.Errorf("EventType = %q, want %q", event.EventType, "ExpenseCorrected")
+	}
+
+	var payload ExpenseCorrectedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	want := ExpenseCorrectedPayload{Amount: 1500, Category: "日用品", Memo: "", Date: "2026-02-20", Tags: []string{}}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+
+	if exp.Category != "食費" || exp.Version != 1 {
+		t.Error("Correct must not modify the aggregate")
+	}
+}
+
+func TestCorrect_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  CorrectExpenseCommand
+	}{
+		{"no fields", CorrectExpenseCommand{}},
+		{"zero amount", CorrectExpenseCommand{Amount: ptr(int64(0))}},
+		{"amount above the maximum", CorrectExpenseCommand{Amount: ptr(int64(MaxAmount + 1))}},
+		{"empty category", CorrectExpenseCommand{Category: ptr("")}},
+		{"invalid date", CorrectExpenseCommand{Date: ptr("2026/02/20")}},
+		{"empty tag", CorrectExpenseCommand{Tags: ptr([]string{""})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := recordedExpense(t)
+			if _, err := exp.Correct(tt.cmd); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCorrect_NoChanges(t *testing.T) {
+	exp := recordedExpense(t)
+
+	for _, cmd := range []CorrectExpenseCommand{
+		{Amount: ptr(int64(1500))},
+		{Tags: ptr([]string{})},
+	} {
+		if _, err := exp.Correct(cmd); !errors.Is(err, ErrNoChanges) {
+			t.Fatalf("Correct(%+v) err = %v, want ErrNoChanges", cmd, err)
+		}
+	}
+}
+
+func TestCorrect_Tags(t *testing.T) {
+	exp := recordedExpense(t)
+
+	event, err := exp.Correct(CorrectExpenseCommand{Tags: ptr([]string{"旅行"})})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := exp.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !reflect.DeepEqual(exp.Tags, []string{"旅行"}) {
+		t.Errorf("Tags = %v, want [旅行]", exp.Tags)
+	}
+}
+
+func TestVoid_Success(t *testing.T) {
+	exp := recordedExpense(t)
+
+	event, err := exp.Void(VoidExpenseCommand{Reason: "duplicate"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Version != 2 {
+		t.Errorf("Version = %d, want 2", event.Version)
+	}
+	if event.EventType != "ExpenseVoided" {
+		t.Errorf("EventType = %q, want %q", event.EventType, "ExpenseVoided")
+	}
+}
+
+func TestVoidedExpense_RejectsCommands(t *testing.T) {
+	exp := recordedExpense(t)
+	exp.Voided = true
+
+	if _, err := exp.Correct(CorrectExpenseCommand{Amount: ptr(int64(100))}); !errors.Is(err, ErrVoided) {
+		t.Errorf("Correct err = %v, want ErrVoided", err)
+	}
+	if _, err := exp.Void(VoidExpenseCommand{}); !errors.Is(err, ErrVoided) {
+		t.Errorf("Void err = %v, want ErrVoided", err)
+	}
+}
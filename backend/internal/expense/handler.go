@@ -3,27 +3,57 @@ package expense
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"strconv"
+	"net/url"
 
 	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
 	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
 )
 
 // Handler handles HTTP requests for the expense domain.
+// Commands only append events; the read model is updated asynchronously by
+// Projector running under a projection.Runner.
+//
+// Every route must be behind middleware.Authenticate. Users only see the
+// expenses of books they may read, and only change those of books they may
+// write; other expenses are reported as not found.
 type Handler struct {
-	store     eventstore.Store
-	projector *Projector
-	repo      *Repository
+	store    eventstore.Store
+	repo     Reader
+	books    Books
+	accounts Accounts
+}
+
+// Books tells what a user may do with a book. household.Books implements
+// it.
+type Books interface {
+	// Role returns userID's role in bookID, or household.ErrNotFound if
+	// they have none.
+	Role(ctx context.Context, userID, bookID string) (household.Role, error)
+}
+
+// Accounts looks up the account an expense or income is paid from or into.
+// account.Accounts implements it.
+type Accounts interface {
+	// Load returns the account with the given ID, or account.ErrNotFound
+	// if there is none.
+	Load(ctx context.Context, accountID string) (*account.Account, error)
 }
 
 // NewHandler creates a new Handler.
-func NewHandler(store eventstore.Store, projector *Projector, repo *Repository) *Handler {
+func NewHandler(store eventstore.Store, repo Reader, books Books, accounts Accounts) *Handler {
 	return &Handler{
-		store:     store,
-		projector: projector,
-		repo:      repo,
+		store:    store,
+		repo:     repo,
+		books:    books,
+		accounts: accounts,
 	}
 }
 
@@ -31,13 +61,18 @@ func NewHandler(store eventstore.Store, projector *Projector, repo *Repository)
 func (h *Handler) Register(mux *http.ServeMux) {
 	mux.HandleFunc("POST /expenses", h.RecordExpense)
 	mux.HandleFunc("GET /expenses", h.ListExpenses)
+	mux.HandleFunc("GET /expenses/{id}", h.GetExpense)
+	mux.HandleFunc("GET /expenses/{id}/history", h.ExpenseHistory)
+	mux.HandleFunc("PATCH /expenses/{id}", h.CorrectExpense)
+	mux.HandleFunc("DELETE /expenses/{id}", h.VoidExpense)
 }
 
 type recordExpenseResponse struct {
 	ID string `json:"id"`
 }
 
-// RecordExpense handles POST /expenses.
+// RecordExpense handles POST /expenses. The expense goes in the user's
+// personal book unless the body names another book_id.
 func (h *Handler) RecordExpense(w http.ResponseWriter, r *http.Request) {
 	var cmd RecordExpenseCommand
 	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
@@ -45,6 +80,10 @@ func (h *Handler) RecordExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx := r.Context()
+	if cmd.BookID == "" {
+		cmd.BookID = middleware.UserID(ctx)
+	}
 	id := uuid.New().String()
 	event, err := RecordExpense(id, cmd)
 	if err != nil {
@@ -52,35 +91,234 @@ func (h *Handler) RecordExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	if err := h.store.Append(ctx, []eventstore.Event{event}, 0); err != nil {
-		log.Printf("append event: %v", err)
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	role, err := h.bookRole(ctx, cmd.BookID)
+	if err == nil && !role.CanWrite() {
+		err = ErrForbidden
+	}
+	if err == nil {
+		err = h.checkSplit(ctx, cmd.BookID, cmd.Split)
+	}
+	if err == nil {
+		err = h.checkAccount(ctx, cmd.BookID, cmd.AccountID)
+	}
+	if err != nil {
+		writeCommandError(w, err)
 		return
 	}
 
-	if err := h.projector.Apply(ctx, event); err != nil {
-		log.Printf("apply projection: %v", err)
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, 0); err != nil {
+		writeCommandError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, recordExpenseResponse{ID: id})
 }
 
-// ListExpenses handles GET /expenses.
+type correctExpenseResponse struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+// CorrectExpense handles PATCH /expenses/{id}.
+func (h *Handler) CorrectExpense(w http.ResponseWriter, r *http.Request) {
+	var cmd CorrectExpenseCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	exp, err := h.loadWritableExpense(ctx, r.PathValue("id"))
+	if err == nil {
+		err = h.checkSplit(ctx, exp.Book, cmd.Split)
+	}
+	if err == nil && cmd.AccountID != nil {
+		err = h.checkAccount(ctx, exp.Book, *cmd.AccountID)
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := exp.Correct(cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, exp.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, correctExpenseResponse{ID: exp.ID, Version: event.Version})
+}
+
+// VoidExpense handles DELETE /expenses/{id}.
+// The request body is optional and may carry a reason.
+func (h *Handler) VoidExpense(w http.ResponseWriter, r *http.Request) {
+	var cmd VoidExpenseCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	ctx := r.Context()
+	exp, err := h.loadWritableExpense(ctx, r.PathValue("id"))
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := exp.Void(cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, exp.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadWritableExpense loads an expense in a book the request's user may
+// write. It returns ErrNotFound if they may not read the book either.
+func (h *Handler) loadWritableExpense(ctx context.Context, id string) (*Expense, error) {
+	events, err := h.store.Load(ctx, aggregateType, id)
+	if err != nil {
+		return nil, fmt.Errorf("load events: %w", err)
+	}
+	exp, err := LoadExpense(events)
+	if err != nil {
+		return nil, err
+	}
+	role, err := h.bookRole(ctx, exp.Book)
+	if errors.Is(err, ErrBookNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanWrite() {
+		return nil, ErrForbidden
+	}
+	return exp, nil
+}
+
+// checkSplit checks that split, which may be nil, is in a household book
+// and shared only between its members.
+func (h *Handler) checkSplit(ctx context.Context, bookID string, split *Split) error {
+	if split == nil {
+		return nil
+	}
+	if bookID == middleware.UserID(ctx) {
+		return fmt.Errorf("%w: only household books can be split", ErrInvalidSplit)
+	}
+	for _, userID := range split.Users() {
+		_, err := h.books.Role(ctx, userID, bookID)
+		if errors.Is(err, household.ErrNotFound) {
+			return fmt.Errorf("%w: %s is not a member of the book", ErrInvalidSplit, userID)
+		}
+		if err != nil {
+			return fmt.Errorf("look up role of %s in book %s: %w", userID, bookID, err)
+		}
+	}
+	return nil
+}
+
+// checkAccount checks that accountID, which may be empty, is an account in
+// bookID. Expenses are always in yen, so the account must be too.
+func (h *Handler) checkAccount(ctx context.Context, bookID, accountID string) error {
+	if accountID == "" {
+		return nil
+	}
+	acc, err := h.accounts.Load(ctx, accountID)
+	if errors.Is(err, account.ErrNotFound) || err == nil && acc.Book != bookID {
+		return fmt.Errorf("%w: %s is not an account of the book", ErrInvalidAccount, accountID)
+	}
+	if err != nil {
+		return fmt.Errorf("look up account %s: %w", accountID, err)
+	}
+	if acc.Currency != account.DefaultCurrency {
+		return fmt.Errorf("%w: accounts must have the same currency, but %s is in %s", ErrInvalidAccount, accountID, acc.Currency)
+	}
+	return nil
+}
+
+// bookRole returns the request user's role in bookID, or ErrBookNotFound
+// if they have none.
+func (h *Handler) bookRole(ctx context.Context, bookID string) (household.Role, error) {
+	role, err := h.books.Role(ctx, middleware.UserID(ctx), bookID)
+	if errors.Is(err, household.ErrNotFound) {
+		return "", ErrBookNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up role in book %s: %w", bookID, err)
+	}
+	return role, nil
+}
+
+// writeCommandError maps domain errors from command handling to HTTP responses.
+func writeCommandError(w http.ResponseWriter, err error) {
+	var conflict *eventstore.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":           "expense was modified concurrently; reload and retry",
+			"current_version": conflict.Actual,
+		})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrBookNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrVoided):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrNoChanges), errors.Is(err, ErrInvalidSplit), errors.Is(err, ErrInvalidAccount):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("expense command: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+// ListExpenses handles GET /expenses. See ParseListQuery for the query
+// parameters. If there are more expenses, the Link header carries the URL of
+// the next page with rel="next".
 func (h *Handler) ListExpenses(w http.ResponseWriter, r *http.Request) {
-	limit := queryInt(r.Context(), r, "limit", defaultLimit)
-	offset := queryInt(r.Context(), r, "offset", 0)
+	q, err := ParseListQuery(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	ctx := r.Context()
+	if q.BookID == "" {
+		q.BookID = middleware.UserID(ctx)
+	}
+	if _, err := h.bookRole(ctx, q.BookID); err != nil {
+		writeQueryError(w, err)
+		return
+	}
 
-	expenses, err := h.repo.List(r.Context(), limit, offset)
+	page, err := h.repo.List(ctx, q)
 	if err != nil {
 		log.Printf("list expenses: %v", err)
 		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
 		return
 	}
 
+	if page.Next != nil {
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, nextPageURL(r.URL, page.Next)))
+	}
+
 	// Return empty array instead of null
+	expenses := page.Expenses
 	if expenses == nil {
 		expenses = []ExpenseRow{}
 	}
@@ -88,16 +326,71 @@ func (h *Handler) ListExpenses(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, expenses)
 }
 
-func queryInt(_ context.Context, r *http.Request, key string, defaultVal int) int {
-	s := r.URL.Query().Get(key)
-	if s == "" {
-		return defaultVal
+// GetExpense handles GET /expenses/{id}. It reads the read model, so an
+// expense that was just recorded can be missing for a moment.
+func (h *Handler) GetExpense(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	exp, err := h.repo.Get(ctx, r.PathValue("id"))
+	if err == nil {
+		_, err = h.bookRole(ctx, exp.BookID)
+	}
+	if errors.Is(err, ErrBookNotFound) {
+		err = ErrNotFound
 	}
-	v, err := strconv.Atoi(s)
 	if err != nil {
-		return defaultVal
+		writeQueryError(w, err)
+		return
 	}
-	return v
+	writeJSON(w, http.StatusOK, exp)
+}
+
+// ExpenseHistory handles GET /expenses/{id}/history. It replays the event
+// stream, so it includes voided expenses and is never behind.
+func (h *Handler) ExpenseHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	events, err := h.store.Load(ctx, aggregateType, r.PathValue("id"))
+	if err != nil {
+		writeQueryError(w, fmt.Errorf("load events: %w", err))
+		return
+	}
+	if len(events) > 0 {
+		exp, err := LoadExpense(events)
+		if err == nil {
+			_, err = h.bookRole(ctx, exp.Book)
+		}
+		if errors.Is(err, ErrBookNotFound) {
+			events = nil
+		} else if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+	}
+	history, err := History(events)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// writeQueryError maps errors from reads to HTTP responses.
+func writeQueryError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrBookNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	log.Printf("expense query: %v", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+}
+
+// nextPageURL returns the request's URL with the cursor advanced to next.
+// The next page is always fetched by cursor, even if this one was fetched
+// by offset.
+func nextPageURL(u *url.URL, next *Cursor) string {
+	v := u.Query()
+	v.Del("offset")
+	v.Set("cursor", next.Encode())
+	return (&url.URL{Path: u.Path, RawQuery: v.Encode()}).String()
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
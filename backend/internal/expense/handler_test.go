@@ -1,44 +1,274 @@
 package expense_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
 	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
 	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
 	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
 	"github.com/kikeda1102/kakei-board/backend/migrations"
 )
 
-func setupHandler(t *testing.T) http.Handler {
-	t.Helper()
+type backend struct {
+	name string
+	open func(t *testing.T) (eventstore.Store, projection.CheckpointStore, expense.ReadModel)
+}
+
+// backends lists every storage the handler tests run against. The MySQL
+// backend is skipped unless TEST_DATABASE_URL is set.
+var backends = []backend{
+	{"memory", func(*testing.T) (eventstore.Store, projection.CheckpointStore, expense.ReadModel) {
+		return eventstore.NewMemoryStore(), projection.NewMemoryCheckpointStore(), expense.NewMemoryRepository()
+	}},
+	{"mysql", func(t *testing.T) (eventstore.Store, projection.CheckpointStore, expense.ReadModel) {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		return eventstore.NewMySQLStore(db), projection.NewMySQLCheckpointStore(db), expense.NewRepository(db)
+	}},
+}
+
+// forEachBackend runs fn once per backend with the expense routes and the
+// projection runner running in the background. catchUp blocks until the
+// read model reflects every appended event.
+func forEachBackend(t *testing.T, fn func(t *testing.T, handler http.Handler, catchUp func())) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			store, checkpoints, readModel := b.open(t)
+			store = upcastingStore(store)
+
+			projector := expense.NewProjector(readModel)
+			startRunner(t, projection.NewRunner(store, checkpoints, projector))
+
+			mux := http.NewServeMux()
+			expense.NewHandler(store, readModel, testBooks, testAccounts).Register(mux)
+
+			catchUp := func() {
+				t.Helper()
+				waitFor(t, func() bool {
+					pos, err := checkpoints.Load(context.Background(), projector.Name())
+					if err != nil {
+						t.Fatalf("load checkpoint: %v", err)
+					}
+					pending, err := store.ReadAll(context.Background(), pos, 1)
+					if err != nil {
+						t.Fatalf("read events: %v", err)
+					}
+					return len(pending) == 0
+				})
+			}
+			fn(t, testhelper.AsUser(mux), catchUp)
+		})
+	}
+}
+
+// sharedBook is a household book in testBooks.
+const sharedBook = "household-1"
+
+// testBooks gives every user their personal book, and alice, bob and carol
+// the roles of owner, viewer and editor in sharedBook.
+var testBooks = fakeBooks{
+	sharedBook: {"alice": household.RoleOwner, "bob": household.RoleViewer, "carol": household.RoleEditor},
+}
+
+type fakeBooks map[string]map[string]household.Role
+
+func (b fakeBooks) Role(_ context.Context, userID, bookID string) (household.Role, error) {
+	if bookID == userID {
+		return household.RoleOwner, nil
+	}
+	role, ok := b[bookID][userID]
+	if !ok {
+		return "", household.ErrNotFound
+	}
+	return role, nil
+}
 
-	db := testhelper.OpenTestDB(t)
-	if err := migrations.Run(db); err != nil {
-		t.Fatalf("run migrations: %v", err)
+// testAccounts holds the accounts tests may name.
+var testAccounts = fakeAccounts{
+	"wallet":  {ID: "wallet", Book: "alice", Currency: "JPY"},
+	"joint":   {ID: "joint", Book: sharedBook, Currency: "JPY"},
+	"dollars": {ID: "dollars", Book: "alice", Currency: "USD"},
+}
+
+type fakeAccounts map[string]*account.Account
+
+func (a fakeAccounts) Load(_ context.Context, accountID string) (*account.Account, error) {
+	acc, ok := a[accountID]
+	if !ok {
+		return nil, account.ErrNotFound
 	}
+	return acc, nil
+}
+
+func startRunner(t *testing.T, runner *projection.Runner) {
+	t.Helper()
 
-	store := eventstore.NewMySQLStore(db)
-	projector := expense.NewProjector(db)
-	repo := expense.NewRepository(db)
-	h := expense.NewHandler(store, projector, repo)
+	runner.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
 
-	mux := http.NewServeMux()
-	h.Register(mux)
-	return mux
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
 }
 
 func TestRecordAndListExpenses(t *testing.T) {
-	handler := setupHandler(t)
-	srv := httptest.NewServer(handler)
-	defer srv.Close()
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
 
-	// POST: record an expense
-	body := `{"amount":1500,"category":"食費","memo":"コンビニ","date":"2026-02-20"}`
-	resp, err := http.Post(srv.URL+"/expenses", "application/json", strings.NewReader(body))
+		// POST: record an expense
+		body := `{"amount":1500,"category":"食費","memo":"コンビニ","date":"2026-02-20"}`
+		resp, err := http.Post(srv.URL+"/expenses", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST /expenses: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("POST status = %d, want %d", resp.StatusCode, http.StatusCreated)
+		}
+
+		var created struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if created.ID == "" {
+			t.Fatal("expected non-empty ID")
+		}
+
+		// GET: list expenses once the projection has caught up
+		catchUp()
+		resp2, err := http.Get(srv.URL + "/expenses")
+		if err != nil {
+			t.Fatalf("GET /expenses: %v", err)
+		}
+		defer resp2.Body.Close()
+
+		if resp2.StatusCode != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", resp2.StatusCode, http.StatusOK)
+		}
+
+		var expenses []expense.ExpenseRow
+		if err := json.NewDecoder(resp2.Body).Decode(&expenses); err != nil {
+			t.Fatalf("decode expenses: %v", err)
+		}
+
+		if len(expenses) != 1 {
+			t.Fatalf("len(expenses) = %d, want 1", len(expenses))
+		}
+
+		got := expenses[0]
+		if got.ID != created.ID {
+			t.Errorf("ID = %q, want %q", got.ID, created.ID)
+		}
+		if got.Amount != 1500 {
+			t.Errorf("Amount = %d, want 1500", got.Amount)
+		}
+		if got.Category != "食費" {
+			t.Errorf("Category = %q, want %q", got.Category, "食費")
+		}
+		if got.Memo != "コンビニ" {
+			t.Errorf("Memo = %q, want %q", got.Memo, "コンビニ")
+		}
+		if got.Date != "2026-02-20" {
+			t.Errorf("Date = %q, want %q", got.Date, "2026-02-20")
+		}
+	})
+}
+
+func TestRecordExpense_ValidationError(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, _ func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		tests := []struct {
+			name string
+			body string
+		}{
+			{"missing amount", `{"category":"食費","date":"2026-02-20"}`},
+			{"zero amount", `{"amount":0,"category":"食費","date":"2026-02-20"}`},
+			{"missing category", `{"amount":1000,"date":"2026-02-20"}`},
+			{"invalid date", `{"amount":1000,"category":"食費","date":"invalid"}`},
+			{"invalid json", `{invalid}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp, err := http.Post(srv.URL+"/expenses", "application/json", strings.NewReader(tt.body))
+				if err != nil {
+					t.Fatalf("POST /expenses: %v", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+				}
+			})
+		}
+	})
+}
+
+func TestListExpenses_Empty(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, _ func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL + "/expenses")
+		if err != nil {
+			t.Fatalf("GET /expenses: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		var expenses []expense.ExpenseRow
+		if err := json.NewDecoder(resp.Body).Decode(&expenses); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+
+		if len(expenses) != 0 {
+			t.Errorf("len(expenses) = %d, want 0", len(expenses))
+		}
+	})
+}
+
+func recordViaAPI(t *testing.T, srvURL, body string) string {
+	t.Helper()
+
+	resp, err := http.Post(srvURL+"/expenses", "application/json", strings.NewReader(body))
 	if err != nil {
 		t.Fatalf("POST /expenses: %v", err)
 	}
@@ -54,100 +284,533 @@ func TestRecordAndListExpenses(t *testing.T) {
 	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
-	if created.ID == "" {
-		t.Fatal("expected non-empty ID")
+	return created.ID
+}
+
+func doRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	return doRequestAs(t, "", method, url, body)
+}
+
+// doRequestAs makes a request as userID, or as alice if it is empty.
+func doRequestAs(t *testing.T, userID, method, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set(testhelper.UserHeader, userID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
 	}
+	return resp
+}
 
-	// GET: list expenses
-	resp2, err := http.Get(srv.URL + "/expenses")
+func listViaAPI(t *testing.T, srvURL, query string) []expense.ExpenseRow {
+	t.Helper()
+
+	resp, err := http.Get(srvURL + "/expenses?" + query)
 	if err != nil {
 		t.Fatalf("GET /expenses: %v", err)
 	}
-	defer resp2.Body.Close()
+	defer resp.Body.Close()
 
-	if resp2.StatusCode != http.StatusOK {
-		t.Fatalf("GET status = %d, want %d", resp2.StatusCode, http.StatusOK)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /expenses?%s status = %d, want %d", query, resp.StatusCode, http.StatusOK)
 	}
 
 	var expenses []expense.ExpenseRow
-	if err := json.NewDecoder(resp2.Body).Decode(&expenses); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&expenses); err != nil {
 		t.Fatalf("decode expenses: %v", err)
 	}
+	return expenses
+}
 
-	if len(expenses) != 1 {
-		t.Fatalf("len(expenses) = %d, want 1", len(expenses))
-	}
+func TestListExpenses_Query(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
 
-	got := expenses[0]
-	if got.ID != created.ID {
-		t.Errorf("ID = %q, want %q", got.ID, created.ID)
-	}
-	if got.Amount != 1500 {
-		t.Errorf("Amount = %d, want 1500", got.Amount)
-	}
-	if got.Category != "食費" {
-		t.Errorf("Category = %q, want %q", got.Category, "食費")
-	}
-	if got.Memo != "コンビニ" {
-		t.Errorf("Memo = %q, want %q", got.Memo, "コンビニ")
-	}
-	if got.Date != "2026-02-20" {
-		t.Errorf("Date = %q, want %q", got.Date, "2026-02-20")
-	}
+		// Recorded one after another, so created_at follows this order.
+		ids := map[string]string{}
+		for _, e := range []struct{ name, body string }{
+			{"lunch", `{"amount":800,"category":"食費","memo":"Lunch","date":"2026-02-03"}`},
+			{"soap", `{"amount":400,"category":"日用品","memo":"石けん","date":"2026-02-10"}`},
+			{"dinner", `{"amount":3000,"category":"食費","memo":"dinner with friends","date":"2026-02-10"}`},
+			{"train", `{"amount":800,"category":"交通費","memo":"","date":"2026-01-31"}`},
+			{"snack", `{"amount":150,"category":"食費","memo":"100%_off","date":"2026-03-01"}`},
+		} {
+			ids[recordViaAPI(t, srv.URL, e.body)] = e.name
+		}
+		catchUp()
+
+		tests := []struct {
+			query string
+			want  []string
+		}{
+			{"", []string{"snack", "dinner", "soap", "lunch", "train"}},
+			{"from=2026-02-01&to=2026-02-28", []string{"dinner", "soap", "lunch"}},
+			{"to=2026-02-03", []string{"lunch", "train"}},
+			{"category=食費", []string{"snack", "dinner", "lunch"}},
+			{"category=食費&category=交通費&from=2026-02-01&to=2026-02-28", []string{"dinner", "lunch"}},
+			{"min_amount=400&max_amount=800", []string{"soap", "lunch", "train"}},
+			{"q=LUNCH", []string{"lunch"}},
+			{"q=%25_", []string{"snack"}},
+			{"q=_", []string{"snack"}},
+			{"sort=amount", []string{"dinner", "train", "lunch", "soap", "snack"}},
+			{"sort=amount&order=asc", []string{"snack", "soap", "lunch", "train", "dinner"}},
+			{"sort=created_at&order=asc", []string{"lunch", "soap", "dinner", "train", "snack"}},
+			{"order=asc&limit=2&offset=1", []string{"lunch", "soap"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.query, func(t *testing.T) {
+				var got []string
+				for _, e := range listViaAPI(t, srv.URL, tt.query) {
+					got = append(got, ids[e.ID])
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
 }
 
-func TestRecordExpense_ValidationError(t *testing.T) {
-	handler := setupHandler(t)
-	srv := httptest.NewServer(handler)
-	defer srv.Close()
-
-	tests := []struct {
-		name string
-		body string
-	}{
-		{"missing amount", `{"category":"食費","date":"2026-02-20"}`},
-		{"zero amount", `{"amount":0,"category":"食費","date":"2026-02-20"}`},
-		{"missing category", `{"amount":1000,"date":"2026-02-20"}`},
-		{"invalid date", `{"amount":1000,"category":"食費","date":"invalid"}`},
-		{"invalid json", `{invalid}`},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := http.Post(srv.URL+"/expenses", "application/json", strings.NewReader(tt.body))
-			if err != nil {
-				t.Fatalf("POST /expenses: %v", err)
+func TestListExpenses_CursorPagination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		// Several expenses share a date, so pages split inside a date.
+		for _, date := range []string{"2026-02-01", "2026-02-02", "2026-02-02", "2026-02-02", "2026-02-03"} {
+			recordViaAPI(t, srv.URL, `{"amount":100,"category":"食費","date":"`+date+`"}`)
+		}
+		catchUp()
+		want := listViaAPI(t, srv.URL, "")
+
+		for _, sort := range []string{"sort=date", "sort=amount&order=asc", "sort=created_at"} {
+			t.Run(sort, func(t *testing.T) {
+				want := listViaAPI(t, srv.URL, sort)
+
+				var got []string
+				next := "/expenses?limit=2&" + sort
+				for pages := 0; next != ""; pages++ {
+					if pages > len(want) {
+						t.Fatal("pagination does not end")
+					}
+					var rows []expense.ExpenseRow
+					rows, next = getPage(t, srv.URL+next)
+					for _, row := range rows {
+						got = append(got, row.ID)
+					}
+				}
+
+				if len(got) != len(want) {
+					t.Fatalf("paged through %d expenses, want %d", len(got), len(want))
+				}
+				for i := range want {
+					if got[i] != want[i].ID {
+						t.Errorf("got[%d] = %s, want %s", i, got[i], want[i].ID)
+					}
+				}
+			})
+		}
+
+		t.Run("stable under inserts", func(t *testing.T) {
+			first, next := getPage(t, srv.URL+"/expenses?limit=2")
+			if next == "" {
+				t.Fatal("no next page")
 			}
-			defer resp.Body.Close()
+			// A newer expense sorts onto the first page; offset paging
+			// would repeat first[1] on the second page.
+			recordViaAPI(t, srv.URL, `{"amount":100,"category":"食費","date":"2026-02-04"}`)
+			catchUp()
 
-			if resp.StatusCode != http.StatusBadRequest {
-				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			second, _ := getPage(t, srv.URL+next)
+			if len(second) != 2 || second[0].ID != want[2].ID || second[1].ID != want[3].ID {
+				t.Errorf("second page = %v after %v, want %s and %s", ids(second), ids(first), want[2].ID, want[3].ID)
 			}
 		})
-	}
+	})
 }
 
-func TestListExpenses_Empty(t *testing.T) {
-	handler := setupHandler(t)
-	srv := httptest.NewServer(handler)
-	defer srv.Close()
+// getPage fetches a page of expenses and returns it with the next page's
+// URL from the Link header, which is empty on the last page.
+func getPage(t *testing.T, url string) ([]expense.ExpenseRow, string) {
+	t.Helper()
 
-	resp, err := http.Get(srv.URL + "/expenses")
+	resp, err := http.Get(url)
 	if err != nil {
-		t.Fatalf("GET /expenses: %v", err)
+		t.Fatalf("GET %s: %v", url, err)
 	}
 	defer resp.Body.Close()
-
 	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		t.Fatalf("GET %s status = %d, want %d", url, resp.StatusCode, http.StatusOK)
 	}
 
-	var expenses []expense.ExpenseRow
-	if err := json.NewDecoder(resp.Body).Decode(&expenses); err != nil {
-		t.Fatalf("decode: %v", err)
+	var rows []expense.ExpenseRow
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		t.Fatalf("decode expenses: %v", err)
 	}
 
-	if len(expenses) != 0 {
-		t.Errorf("len(expenses) = %d, want 0", len(expenses))
+	link := resp.Header.Get("Link")
+	if link == "" {
+		return rows, ""
 	}
+	next, ok := strings.CutSuffix(link, `>; rel="next"`)
+	if !ok || !strings.HasPrefix(next, "<") {
+		t.Fatalf("Link = %q, want a rel=next link", link)
+	}
+	return rows, next[1:]
+}
+
+func ids(rows []expense.ExpenseRow) []string {
+	var ids []string
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func TestListExpenses_InvalidQuery(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, _ func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		for _, query := range []string{
+			"limit=abc",
+			"limit=-1",
+			"offset=1.5",
+			"from=2026-2-1",
+			"to=tomorrow",
+			"from=2026-03-01&to=2026-02-01",
+			"category=",
+			"min_amount=x",
+			"min_amount=1000&max_amount=10",
+			"sort=memo",
+			"order=up",
+			"cursor=garbage",
+			"cursor=" + (expense.Cursor{Sort: expense.SortByDate, ID: "x"}).Encode() + "&offset=10",
+			"cursor=" + (expense.Cursor{Sort: expense.SortByDate, ID: "x"}).Encode() + "&sort=amount",
+			"cursor=" + (expense.Cursor{Sort: expense.SortByDate, ID: "x"}).Encode() + "&order=asc",
+		} {
+			t.Run(query, func(t *testing.T) {
+				resp, err := http.Get(srv.URL + "/expenses?" + query)
+				if err != nil {
+					t.Fatalf("GET /expenses: %v", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+				}
+			})
+		}
+	})
+}
+
+func TestCorrectExpense(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":15000,"category":"食費","memo":"コンビニ","date":"2026-02-20"}`)
+
+		resp := doRequest(t, http.MethodPatch, srv.URL+"/expenses/"+id, `{"amount":1500}`)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("PATCH status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+
+		var corrected struct {
+			Version int `json:"version"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&corrected); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if corrected.Version != 2 {
+			t.Errorf("version = %d, want 2", corrected.Version)
+		}
+
+		catchUp()
+		expenses := listViaAPI(t, srv.URL, "")
+		if len(expenses) != 1 {
+			t.Fatalf("len(expenses) = %d, want 1", len(expenses))
+		}
+		if expenses[0].Amount != 1500 {
+			t.Errorf("Amount = %d, want 1500", expenses[0].Amount)
+		}
+		if expenses[0].Memo != "コンビニ" {
+			t.Errorf("Memo = %q, want %q", expenses[0].Memo, "コンビニ")
+		}
+	})
+}
+
+func TestCorrectExpense_Errors(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, _ func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":1500,"category":"食費","date":"2026-02-20"}`)
+
+		tests := []struct {
+			name string
+			id   string
+			body string
+			want int
+		}{
+			{"unknown id", "00000000-0000-0000-0000-000000000000", `{"amount":100}`, http.StatusNotFound},
+			{"empty body", id, `{}`, http.StatusBadRequest},
+			{"invalid amount", id, `{"amount":-1}`, http.StatusBadRequest},
+			{"no changes", id, `{"amount":1500}`, http.StatusBadRequest},
+			{"invalid json", id, `{invalid}`, http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := doRequest(t, http.MethodPatch, srv.URL+"/expenses/"+tt.id, tt.body)
+				defer resp.Body.Close()
+
+				if resp.StatusCode != tt.want {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestVoidExpense(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":1500,"category":"食費","date":"2026-02-20"}`)
+
+		resp := doRequest(t, http.MethodDelete, srv.URL+"/expenses/"+id, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("DELETE status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+
+		catchUp()
+		if expenses := listViaAPI(t, srv.URL, ""); len(expenses) != 0 {
+			t.Errorf("len(expenses) = %d, want 0", len(expenses))
+		}
+
+		// Voided expenses can no longer be changed.
+		resp = doRequest(t, http.MethodDelete, srv.URL+"/expenses/"+id, `{"reason":"again"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("second DELETE status = %d, want %d", resp.StatusCode, http.StatusConflict)
+		}
+
+		resp = doRequest(t, http.MethodPatch, srv.URL+"/expenses/"+id, `{"amount":100}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("PATCH status = %d, want %d", resp.StatusCode, http.StatusConflict)
+		}
+	})
+}
+
+func TestGetExpense(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":1500,"category":"食費","memo":"コンビニ","date":"2026-02-20","tags":["lunch"]}`)
+		voided := recordViaAPI(t, srv.URL, `{"amount":500,"category":"食費","date":"2026-02-20"}`)
+		resp := doRequest(t, http.MethodDelete, srv.URL+"/expenses/"+voided, "")
+		resp.Body.Close()
+		catchUp()
+
+		resp = doRequest(t, http.MethodGet, srv.URL+"/expenses/"+id, "")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var got expense.ExpenseRow
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("decode expense: %v", err)
+		}
+		if got.ID != id || got.Amount != 1500 || got.Memo != "コンビニ" || !slices.Equal(got.Tags, []string{"lunch"}) {
+			t.Errorf("got %+v", got)
+		}
+
+		for _, missing := range []string{voided, "00000000-0000-0000-0000-000000000000"} {
+			resp := doRequest(t, http.MethodGet, srv.URL+"/expenses/"+missing, "")
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("GET %s status = %d, want %d", missing, resp.StatusCode, http.StatusNotFound)
+			}
+		}
+	})
+}
+
+func TestExpenses_ScopedToUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":1500,"category":"食費","date":"2026-02-20"}`)
+		catchUp()
+
+		asBob := func(method, path, body string) int {
+			t.Helper()
+			req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			req.Header.Set(testhelper.UserHeader, "bob")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+			defer resp.Body.Close()
+			if method == http.MethodGet && path == "/expenses" {
+				var expenses []expense.ExpenseRow
+				if err := json.NewDecoder(resp.Body).Decode(&expenses); err != nil {
+					t.Fatalf("decode expenses: %v", err)
+				}
+				if len(expenses) != 0 {
+					t.Errorf("bob lists %d expenses, want 0", len(expenses))
+				}
+			}
+			return resp.StatusCode
+		}
+
+		for _, req := range []struct {
+			method, path, body string
+			want               int
+		}{
+			{http.MethodGet, "/expenses", "", http.StatusOK},
+			{http.MethodGet, "/expenses/" + id, "", http.StatusNotFound},
+			{http.MethodGet, "/expenses/" + id + "/history", "", http.StatusNotFound},
+			{http.MethodPatch, "/expenses/" + id, `{"amount":1}`, http.StatusNotFound},
+			{http.MethodDelete, "/expenses/" + id, "", http.StatusNotFound},
+		} {
+			if got := asBob(req.method, req.path, req.body); got != req.want {
+				t.Errorf("bob: %s %s status = %d, want %d", req.method, req.path, got, req.want)
+			}
+		}
+
+		if expenses := listViaAPI(t, srv.URL, ""); len(expenses) != 1 || expenses[0].Amount != 1500 {
+			t.Errorf("alice's expenses = %+v, want the one she recorded", expenses)
+		}
+	})
+}
+
+func TestExpenses_SharedBook(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, catchUp func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		recordViaAPI(t, srv.URL, `{"amount":800,"category":"食費","date":"2026-02-19"}`)
+		id := recordViaAPI(t, srv.URL, `{"book_id":"`+sharedBook+`","amount":1500,"category":"食費","date":"2026-02-20"}`)
+		catchUp()
+
+		if got := listViaAPI(t, srv.URL, ""); len(got) != 1 || got[0].BookID != "alice" {
+			t.Errorf("alice's personal book = %+v, want only the expense recorded in it", got)
+		}
+		if got := listViaAPI(t, srv.URL, "book="+sharedBook); len(got) != 1 || got[0].ID != id || got[0].BookID != sharedBook {
+			t.Errorf("shared book = %+v, want %s", got, id)
+		}
+
+		for _, req := range []struct {
+			user, method, path, body string
+			want                     int
+		}{
+			{"bob", http.MethodGet, "/expenses?book=" + sharedBook, "", http.StatusOK},
+			{"bob", http.MethodGet, "/expenses/" + id, "", http.StatusOK},
+			{"bob", http.MethodGet, "/expenses/" + id + "/history", "", http.StatusOK},
+			{"bob", http.MethodPatch, "/expenses/" + id, `{"amount":1}`, http.StatusForbidden},
+			{"bob", http.MethodDelete, "/expenses/" + id, "", http.StatusForbidden},
+			{"bob", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":1,"category":"食費","date":"2026-02-20"}`, http.StatusForbidden},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"amount":1200}`, http.StatusOK},
+			{"dave", http.MethodGet, "/expenses?book=" + sharedBook, "", http.StatusNotFound},
+			{"dave", http.MethodGet, "/expenses/" + id, "", http.StatusNotFound},
+			{"dave", http.MethodPatch, "/expenses/" + id, `{"amount":1}`, http.StatusNotFound},
+			{"dave", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":1,"category":"食費","date":"2026-02-20"}`, http.StatusNotFound},
+			{"alice", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":900,"category":"外食","date":"2026-02-21","split":{"paid_by":"alice","method":"equal","shares":[{"user_id":"alice"},{"user_id":"bob"},{"user_id":"carol"}]}}`, http.StatusCreated},
+			{"alice", http.MethodPost, "/expenses", `{"amount":900,"category":"外食","date":"2026-02-21","split":{"paid_by":"alice","method":"equal","shares":[{"user_id":"alice"}]}}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":900,"category":"外食","date":"2026-02-21","split":{"paid_by":"dave","method":"equal","shares":[{"user_id":"alice"}]}}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":900,"category":"外食","date":"2026-02-21","split":{"paid_by":"alice","method":"fixed","shares":[{"user_id":"bob","amount":800}]}}`, http.StatusBadRequest},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"split":{"paid_by":"carol","method":"equal","shares":[{"user_id":"carol"},{"user_id":"dave"}]}}`, http.StatusBadRequest},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"split":{"paid_by":"carol","method":"ratio","shares":[{"user_id":"carol","ratio":1},{"user_id":"bob","ratio":2}]}}`, http.StatusOK},
+			{"alice", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":900,"category":"外食","date":"2026-02-21","account_id":"joint"}`, http.StatusCreated},
+			{"alice", http.MethodPost, "/expenses", `{"book_id":"` + sharedBook + `","amount":900,"category":"外食","date":"2026-02-21","account_id":"wallet"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/expenses", `{"amount":900,"category":"外食","date":"2026-02-21","account_id":"missing"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/expenses", `{"amount":900,"category":"外食","date":"2026-02-21","account_id":"dollars"}`, http.StatusBadRequest},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"account_id":"joint"}`, http.StatusOK},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"account_id":"wallet"}`, http.StatusBadRequest},
+			{"carol", http.MethodPatch, "/expenses/" + id, `{"account_id":""}`, http.StatusOK},
+		} {
+			resp := doRequestAs(t, req.user, req.method, srv.URL+req.path, req.body)
+			resp.Body.Close()
+			if resp.StatusCode != req.want {
+				t.Errorf("%s: %s %s status = %d, want %d", req.user, req.method, req.path, resp.StatusCode, req.want)
+			}
+		}
+	})
+}
+
+func TestExpenseHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, handler http.Handler, _ func()) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		id := recordViaAPI(t, srv.URL, `{"amount":1500,"category":"食費","memo":"コンビニ","date":"2026-02-20"}`)
+		for _, req := range []struct{ method, body string }{
+			{http.MethodPatch, `{"amount":1200,"tags":["lunch"]}`},
+			{http.MethodDelete, `{"reason":"duplicate"}`},
+		} {
+			resp := doRequest(t, req.method, srv.URL+"/expenses/"+id, req.body)
+			resp.Body.Close()
+		}
+
+		resp := doRequest(t, http.MethodGet, srv.URL+"/expenses/"+id+"/history", "")
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET history status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var history []expense.HistoryEntry
+		if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+			t.Fatalf("decode history: %v", err)
+		}
+
+		want := []struct {
+			eventType string
+			changes   string
+		}{
+			{"ExpenseRecorded", `[{"field":"amount","from":null,"to":1500},{"field":"category","from":null,"to":"食費"},{"field":"memo","from":null,"to":"コンビニ"},{"field":"date","from":null,"to":"2026-02-20"},{"field":"tags","from":null,"to":[]}]`},
+			{"ExpenseCorrected", `[{"field":"amount","from":1500,"to":1200},{"field":"tags","from":[],"to":["lunch"]}]`},
+			{"ExpenseVoided", `[{"field":"voided","from":false,"to":true}]`},
+		}
+		if len(history) != len(want) {
+			t.Fatalf("len(history) = %d, want %d", len(history), len(want))
+		}
+		for i, w := range want {
+			got := history[i]
+			changes, _ := json.Marshal(got.Changes)
+			if got.Version != i+1 || got.EventType != w.eventType || string(changes) != w.changes {
+				t.Errorf("history[%d] = v%d %s %s, want v%d %s %s",
+					i, got.Version, got.EventType, changes, i+1, w.eventType, w.changes)
+			}
+			if got.RecordedBy != "alice" || got.OccurredAt.IsZero() {
+				t.Errorf("history[%d] recorded by %q at %s, want alice and a time", i, got.RecordedBy, got.OccurredAt)
+			}
+		}
+
+		resp = doRequest(t, http.MethodGet, srv.URL+"/expenses/00000000-0000-0000-0000-000000000000/history", "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("unknown ID status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+	})
 }
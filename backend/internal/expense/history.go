@@ -0,0 +1,89 @@
+package expense
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// HistoryEntry describes one event of an expense for people to read.
+type HistoryEntry struct {
+	Version    int             `json:"version"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	RecordedBy string          `json:"recorded_by"`
+	Client     string          `json:"client,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	// Changes lists the fields the event changed, in a fixed field order.
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is a field's value before and after an event. From is nil for
+// the fields set when the expense was recorded.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// History replays an expense's events, which must be ordered by version,
+// and describes what each of them changed. Returns ErrNotFound when events
+// is empty.
+func History(events []eventstore.Event) ([]HistoryEntry, error) {
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	e := &Expense{ID: events[0].AggregateID}
+	entries := make([]HistoryEntry, 0, len(events))
+	for _, event := range events {
+		before := *e
+		if err := e.apply(event); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, HistoryEntry{
+			Version:    event.Version,
+			EventType:  event.EventType,
+			Payload:    json.RawMessage(event.Payload),
+			RecordedBy: event.RecordedBy,
+			Client:     event.Metadata.Client,
+			OccurredAt: event.OccurredAt,
+			Changes:    diff(before, *e, event.Version == 1),
+		})
+	}
+	return entries, nil
+}
+
+// diff lists the fields that differ between before and after. If recorded,
+// every field is listed as newly set.
+func diff(before, after Expense, recorded bool) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, from, to any, changed bool) {
+		switch {
+		case recorded:
+			changes = append(changes, FieldChange{Field: field, To: to})
+		case changed:
+			changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("amount", before.Amount, after.Amount, before.Amount != after.Amount)
+	add("category", before.Category, after.Category, before.Category != after.Category)
+	add("memo", before.Memo, after.Memo, before.Memo != after.Memo)
+	add("date", before.Date, after.Date, before.Date != after.Date)
+	add("tags", normalizeTags(before.Tags), normalizeTags(after.Tags), !slices.Equal(before.Tags, after.Tags))
+	if !recorded || after.Split != nil {
+		// Most expenses are never split, so their history leaves it out.
+		add("split", before.Split, after.Split, !equalSplits(before.Split, after.Split))
+	}
+	if !recorded || after.AccountID != "" {
+		add("account_id", before.AccountID, after.AccountID, before.AccountID != after.AccountID)
+	}
+	if !recorded {
+		add("voided", before.Voided, after.Voided, before.Voided != after.Voided)
+	}
+	return changes
+}
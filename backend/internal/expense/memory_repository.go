@@ -0,0 +1,161 @@
+package expense
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryRepository implements ReadModel in memory with the same ordering and
+// pagination as Repository. It is intended for tests and local runs without
+// a database.
+type MemoryRepository struct {
+	mu   sync.RWMutex
+	rows map[string]memoryRow
+}
+
+type memoryRow struct {
+	ExpenseRow
+	voided bool
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{rows: make(map[string]memoryRow)}
+}
+
+// List returns the expenses matching q with pagination.
+// Voided expenses are excluded.
+func (r *MemoryRepository) List(_ context.Context, q ListQuery) (Page, error) {
+	limit, offset := clampPage(q.Limit, q.Offset)
+
+	r.mu.RLock()
+	expenses := make([]ExpenseRow, 0, len(r.rows))
+	for _, row := range r.rows {
+		if !row.voided && matches(q, row.ExpenseRow) && pastCursor(q, row.ExpenseRow) {
+			row.Tags = slices.Clone(row.Tags)
+			expenses = append(expenses, row.ExpenseRow)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(expenses, func(a, b ExpenseRow) int {
+		c := compareRows(q.Sort, a, b)
+		if !q.Ascending {
+			c = -c
+		}
+		return c
+	})
+
+	if offset >= len(expenses) {
+		return Page{}, nil
+	}
+	return newPage(q, expenses[offset:min(offset+limit+1, len(expenses))], limit), nil
+}
+
+// Get returns a non-voided expense by ID.
+func (r *MemoryRepository) Get(_ context.Context, id string) (ExpenseRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.rows[id]
+	if !ok || row.voided {
+		return ExpenseRow{}, ErrNotFound
+	}
+	row.Tags = slices.Clone(row.Tags)
+	return row.ExpenseRow, nil
+}
+
+// matches reports whether row passes q's filters.
+func matches(q ListQuery, row ExpenseRow) bool {
+	// Dates are YYYY-MM-DD, so string order is chronological order.
+	switch {
+	case q.BookID != "" && row.BookID != q.BookID,
+		q.From != "" && row.Date < q.From,
+		q.To != "" && row.Date > q.To,
+		len(q.Categories) > 0 && !slices.Contains(q.Categories, row.Category),
+		q.MinAmount != nil && row.Amount < *q.MinAmount,
+		q.MaxAmount != nil && row.Amount > *q.MaxAmount,
+		q.Memo != "" && !strings.Contains(strings.ToLower(row.Memo), strings.ToLower(q.Memo)):
+		return false
+	}
+	return true
+}
+
+// pastCursor reports whether row comes after q.After in q's order.
+func pastCursor(q ListQuery, row ExpenseRow) bool {
+	if q.After == nil {
+		return true
+	}
+	c := compareRows(q.sortKey(), row, q.After.row())
+	return c > 0 && q.Ascending || c < 0 && !q.Ascending
+}
+
+// compareRows orders rows ascending by key, then created_at, then ID.
+func compareRows(key SortKey, a, b ExpenseRow) int {
+	var c int
+	switch key {
+	case SortByAmount:
+		c = cmp.Compare(a.Amount, b.Amount)
+	case SortByCreatedAt:
+	default:
+		c = strings.Compare(a.Date, b.Date)
+	}
+	return cmp.Or(c, a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+}
+
+// InsertExpense adds a row unless one with the same ID already exists.
+func (r *MemoryRepository) InsertExpense(_ context.Context, row ExpenseRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rows[row.ID]; !ok {
+		row.Tags = slices.Clone(normalizeTags(row.Tags))
+		r.rows[row.ID] = memoryRow{ExpenseRow: row}
+	}
+	return nil
+}
+
+// UpdateExpense overwrites a row with a newer version.
+func (r *MemoryRepository) UpdateExpense(_ context.Context, row ExpenseRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rows[row.ID]
+	if !ok {
+		return errNotProjected
+	}
+	if stored.Version >= row.Version {
+		return nil
+	}
+
+	row.UserID = stored.UserID
+	row.BookID = stored.BookID
+	row.CreatedAt = stored.CreatedAt
+	row.Tags = slices.Clone(normalizeTags(row.Tags))
+	stored.ExpenseRow = row
+	r.rows[row.ID] = stored
+	return nil
+}
+
+// VoidExpense soft-deletes a row at a newer version.
+func (r *MemoryRepository) VoidExpense(_ context.Context, id string, version int, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rows[id]
+	if !ok {
+		return errNotProjected
+	}
+	if stored.Version >= version {
+		return nil
+	}
+
+	stored.Version = version
+	stored.voided = true
+	r.rows[id] = stored
+	return nil
+}
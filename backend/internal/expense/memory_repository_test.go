@@ -0,0 +1,115 @@
+package expense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryRepository_ListOrdering(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	base := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := []ExpenseRow{
+		{ID: "old", Date: "2026-02-19", CreatedAt: base.Add(3 * time.Hour)},
+		{ID: "same-day-early", Date: "2026-02-20", CreatedAt: base.Add(1 * time.Hour)},
+		{ID: "same-day-late", Date: "2026-02-20", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "voided", Date: "2026-02-21", CreatedAt: base},
+	}
+	for _, row := range rows {
+		row.Version = 1
+		if err := repo.InsertExpense(ctx, row); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	if err := repo.VoidExpense(ctx, "voided", 2, base); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+
+	page, err := repo.List(ctx, ListQuery{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	got := page.Expenses
+	want := []string{"same-day-late", "same-day-early", "old"}
+	if len(got) != len(want) {
+		t.Fatalf("len(got) = %d, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Errorf("got[%d].ID = %q, want %q", i, got[i].ID, id)
+		}
+	}
+}
+
+func TestMemoryRepository_ListPagination(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	for i := range maxLimit + 10 {
+		row := ExpenseRow{ID: fmt.Sprintf("e-%03d", i), Date: "2026-02-20", Version: 1}
+		if err := repo.InsertExpense(ctx, row); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		want          int
+	}{
+		{"default limit", 0, 0, defaultLimit},
+		{"negative limit", -1, 0, defaultLimit},
+		{"clamped to max", maxLimit + 1, 0, maxLimit},
+		{"negative offset", 10, -5, 10},
+		{"last page", 50, maxLimit, 10},
+		{"past the end", 10, maxLimit + 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.List(ctx, ListQuery{Limit: tt.limit, Offset: tt.offset})
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if got := page.Expenses; len(got) != tt.want {
+				t.Errorf("len(got) = %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRepository_VersionGuard(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	if err := repo.UpdateExpense(ctx, ExpenseRow{ID: "e", Version: 2}); !errors.Is(err, errNotProjected) {
+		t.Fatalf("update before insert: err = %v, want errNotProjected", err)
+	}
+
+	if err := repo.InsertExpense(ctx, ExpenseRow{ID: "e", Amount: 100, Version: 1}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := repo.UpdateExpense(ctx, ExpenseRow{ID: "e", Amount: 300, Version: 3}); err != nil {
+		t.Fatalf("update v3: %v", err)
+	}
+	// Replays of older events must not roll the row back.
+	if err := repo.UpdateExpense(ctx, ExpenseRow{ID: "e", Amount: 200, Version: 2}); err != nil {
+		t.Fatalf("update v2: %v", err)
+	}
+	if err := repo.InsertExpense(ctx, ExpenseRow{ID: "e", Amount: 100, Version: 1}); err != nil {
+		t.Fatalf("reinsert: %v", err)
+	}
+
+	page, err := repo.List(ctx, ListQuery{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	got := page.Expenses
+	if len(got) != 1 || got[0].Amount != 300 || got[0].Version != 3 {
+		t.Errorf("got = %+v, want amount 300 at version 3", got)
+	}
+}
@@ -7,26 +7,68 @@ import (
 	"fmt"
 
 	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
 )
 
-// Projector applies expense events to the read model (expenses table).
+const (
+	// projectionName identifies the expenses read model's checkpoint.
+	projectionName = "expenses"
+	// expensesTable is the read-model table the projection writes to.
+	expensesTable = "expenses"
+)
+
+// Projector applies expense events to the read model. An expense is inserted
+// once and then moved only to newer versions, so replays change nothing.
 type Projector struct {
-	db *sql.DB
+	w Writer
 }
 
 // NewProjector creates a new Projector.
-func NewProjector(db *sql.DB) *Projector {
-	return &Projector{db: db}
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL expenses projection for rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{expensesTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{db: db, table: tables[expensesTable]})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
 }
 
 // Apply processes an event and updates the read model accordingly.
+// Events of other aggregate types are ignored.
 func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	if event.AggregateType != aggregateType {
+		return nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	var err error
 	switch event.EventType {
 	case eventTypeRecorded:
-		return p.applyRecorded(ctx, event)
+		err = p.applyRecorded(ctx, event)
+	case eventTypeCorrected:
+		err = p.applyCorrected(ctx, event)
+	case eventTypeVoided:
+		err = p.w.VoidExpense(ctx, event.AggregateID, event.Version, event.OccurredAt)
 	default:
 		return fmt.Errorf("unknown event type: %s", event.EventType)
 	}
+	if err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
 }
 
 func (p *Projector) applyRecorded(ctx context.Context, event eventstore.Event) error {
@@ -35,12 +77,33 @@ func (p *Projector) applyRecorded(ctx context.Context, event eventstore.Event) e
 		return fmt.Errorf("unmarshal payload: %w", err)
 	}
 
-	_, err := p.db.ExecContext(ctx,
-		`INSERT INTO expenses (id, amount, category, memo, date) VALUES (?, ?, ?, ?, ?)`,
-		event.AggregateID, payload.Amount, payload.Category, payload.Memo, payload.Date,
-	)
-	if err != nil {
-		return fmt.Errorf("insert expense: %w", err)
+	return p.w.InsertExpense(ctx, ExpenseRow{
+		ID:        event.AggregateID,
+		UserID:    event.RecordedBy,
+		BookID:    bookOf(payload, event.RecordedBy),
+		Amount:    payload.Amount,
+		Category:  payload.Category,
+		Memo:      payload.Memo,
+		Date:      payload.Date,
+		Tags:      payload.Tags,
+		Version:   event.Version,
+		CreatedAt: event.OccurredAt,
+	})
+}
+
+func (p *Projector) applyCorrected(ctx context.Context, event eventstore.Event) error {
+	var payload ExpenseCorrectedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
 	}
-	return nil
+
+	return p.w.UpdateExpense(ctx, ExpenseRow{
+		ID:       event.AggregateID,
+		Amount:   payload.Amount,
+		Category: payload.Category,
+		Memo:     payload.Memo,
+		Date:     payload.Date,
+		Tags:     payload.Tags,
+		Version:  event.Version,
+	})
 }
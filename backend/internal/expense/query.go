@@ -0,0 +1,227 @@
+package expense
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SortKey is a field the expense list can be ordered by.
+type SortKey string
+
+const (
+	SortByDate      SortKey = "date"
+	SortByAmount    SortKey = "amount"
+	SortByCreatedAt SortKey = "created_at"
+)
+
+// ListQuery selects, orders and pages expenses. Zero-valued filters match
+// every expense.
+type ListQuery struct {
+	// BookID matches the expenses in the book.
+	BookID string
+	// From and To bound the expense date, inclusive, as YYYY-MM-DD.
+	From string
+	To   string
+	// Categories matches expenses in any of the given categories.
+	Categories []string
+	// MinAmount and MaxAmount bound the amount, inclusive.
+	MinAmount *int64
+	MaxAmount *int64
+	// Memo matches expenses whose memo contains it, ignoring case.
+	Memo string
+
+	// Sort defaults to SortByDate. Ties are broken by created_at, then ID,
+	// in the same direction.
+	Sort      SortKey
+	Ascending bool
+
+	// After continues the listing after the row the cursor points at.
+	// Offset must be zero when it is set.
+	After  *Cursor
+	Limit  int
+	Offset int
+}
+
+// Cursor marks a row in a listing, so the next page can start right after
+// it. Keyset pagination neither slows down with depth nor skips or repeats
+// rows when expenses are added between pages, unlike offsets.
+type Cursor struct {
+	Sort      SortKey   `json:"s"`
+	Ascending bool      `json:"a,omitempty"`
+	Date      string    `json:"d,omitempty"`
+	Amount    int64     `json:"m,omitempty"`
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// Page is one page of a listing.
+type Page struct {
+	Expenses []ExpenseRow
+	// Next points at the last row of the page, or is nil on the last page.
+	Next *Cursor
+}
+
+// cursorAt returns the cursor that continues q's listing after row.
+func cursorAt(q ListQuery, row ExpenseRow) *Cursor {
+	c := &Cursor{Sort: q.sortKey(), Ascending: q.Ascending, CreatedAt: row.CreatedAt, ID: row.ID}
+	switch c.Sort {
+	case SortByDate:
+		c.Date = row.Date
+	case SortByAmount:
+		c.Amount = row.Amount
+	}
+	return c
+}
+
+// row returns the sort fields the cursor points at.
+func (c Cursor) row() ExpenseRow {
+	return ExpenseRow{ID: c.ID, Date: c.Date, Amount: c.Amount, CreatedAt: c.CreatedAt}
+}
+
+// Encode returns the cursor as an opaque URL-safe string.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c) // cannot fail for these field types
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a string returned by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, errInvalidCursor
+	}
+	return &c, nil
+}
+
+var errInvalidCursor = errors.New("cursor is invalid")
+
+// sortKey returns q.Sort with the default applied.
+func (q ListQuery) sortKey() SortKey {
+	if q.Sort == "" {
+		return SortByDate
+	}
+	return q.Sort
+}
+
+// Validate checks that the query's fields are valid.
+func (q ListQuery) Validate() error {
+	var errs []error
+
+	from, fromErr := time.Parse(time.DateOnly, q.From)
+	if q.From != "" && fromErr != nil {
+		errs = append(errs, fmt.Errorf("from must be in YYYY-MM-DD format"))
+	}
+	to, toErr := time.Parse(time.DateOnly, q.To)
+	if q.To != "" && toErr != nil {
+		errs = append(errs, fmt.Errorf("to must be in YYYY-MM-DD format"))
+	}
+	if fromErr == nil && toErr == nil && from.After(to) {
+		errs = append(errs, fmt.Errorf("from must not be after to"))
+	}
+	for _, c := range q.Categories {
+		if c == "" {
+			errs = append(errs, fmt.Errorf("category must not be empty"))
+			break
+		}
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MinAmount > *q.MaxAmount {
+		errs = append(errs, fmt.Errorf("min_amount must not be greater than max_amount"))
+	}
+	switch q.Sort {
+	case "", SortByDate, SortByAmount, SortByCreatedAt:
+	default:
+		errs = append(errs, fmt.Errorf("sort must be one of date, amount, created_at"))
+	}
+	if q.Limit < 0 {
+		errs = append(errs, fmt.Errorf("limit must not be negative"))
+	}
+	if q.Offset < 0 {
+		errs = append(errs, fmt.Errorf("offset must not be negative"))
+	}
+	if q.After != nil {
+		if q.Offset != 0 {
+			errs = append(errs, fmt.Errorf("cursor and offset cannot be combined"))
+		}
+		if q.After.Sort != q.sortKey() || q.After.Ascending != q.Ascending {
+			errs = append(errs, fmt.Errorf("cursor was issued for a different sort order"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ParseListQuery reads a ListQuery from GET /expenses query parameters and
+// validates it:
+//
+//	book                    book ID, defaulting to the user's personal book
+//	from, to                YYYY-MM-DD, inclusive
+//	category                repeatable
+//	min_amount, max_amount  inclusive
+//	q                       memo substring
+//	sort                    date (default), amount or created_at
+//	order                   desc (default) or asc
+//	limit
+//	cursor                  from the previous page's Link header
+//	offset                  deprecated in favour of cursor
+func ParseListQuery(v url.Values) (ListQuery, error) {
+	q := ListQuery{
+		BookID:     v.Get("book"),
+		From:       v.Get("from"),
+		To:         v.Get("to"),
+		Categories: v["category"],
+		Memo:       v.Get("q"),
+		Sort:       SortKey(v.Get("sort")),
+	}
+
+	var errs []error
+	parseInt := func(key string) *int64 {
+		s := v.Get(key)
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be an integer", key))
+			return nil
+		}
+		return &n
+	}
+	q.MinAmount = parseInt("min_amount")
+	q.MaxAmount = parseInt("max_amount")
+	if n := parseInt("limit"); n != nil {
+		q.Limit = int(*n)
+	}
+	if n := parseInt("offset"); n != nil {
+		q.Offset = int(*n)
+	}
+
+	if s := v.Get("cursor"); s != "" {
+		c, err := DecodeCursor(s)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		q.After = c
+	}
+
+	switch v.Get("order") {
+	case "", "desc":
+	case "asc":
+		q.Ascending = true
+	default:
+		errs = append(errs, fmt.Errorf("order must be asc or desc"))
+	}
+
+	if err := errors.Join(append(errs, q.Validate())...); err != nil {
+		return ListQuery{}, err
+	}
+	return q, nil
+}
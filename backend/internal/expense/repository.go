@@ -3,7 +3,10 @@ package expense
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -12,28 +15,81 @@ const (
 	maxLimit     = 200
 )
 
+// errNotProjected is returned by Writer when an update targets an expense
+// whose ExpenseRecorded event has not been applied yet.
+var errNotProjected = errors.New("expense is not projected yet")
+
 // ExpenseRow represents a row from the expenses read model.
 type ExpenseRow struct {
-	ID        string    `json:"id"`
+	ID string `json:"id"`
+	// UserID is the user that recorded the expense.
+	UserID    string    `json:"-"`
+	BookID    string    `json:"book_id"`
 	Amount    int64     `json:"amount"`
 	Category  string    `json:"category"`
 	Memo      string    `json:"memo"`
 	Date      string    `json:"date"`
+	Tags      []string  `json:"tags"`
+	Version   int       `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// Repository reads from the expenses read model.
+// Reader is the query side of the expenses read model.
+type Reader interface {
+	// List returns a page of the non-voided expenses matching q in q's
+	// order. q.Limit is clamped to 1..maxLimit, with non-positive values
+	// meaning defaultLimit; negative offsets mean 0.
+	List(ctx context.Context, q ListQuery) (Page, error)
+
+	// Get returns the expense with the given ID, or ErrNotFound if it does
+	// not exist, is voided, or has not been projected yet.
+	Get(ctx context.Context, id string) (ExpenseRow, error)
+}
+
+// Writer is the projection side of the expenses read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// InsertExpense adds a row. It does nothing if the row already exists.
+	InsertExpense(ctx context.Context, row ExpenseRow) error
+
+	// UpdateExpense overwrites the row's fields if row.Version is newer than
+	// the stored version. Returns errNotProjected if the row does not exist.
+	UpdateExpense(ctx context.Context, row ExpenseRow) error
+
+	// VoidExpense marks the row voided at the given version if it is newer
+	// than the stored version. Returns errNotProjected if the row does not exist.
+	VoidExpense(ctx context.Context, id string, version int, voidedAt time.Time) error
+}
+
+// ReadModel combines both sides of the expenses read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// Repository implements ReadModel on top of the MySQL expenses table.
 type Repository struct {
-	db *sql.DB
+	db    *sql.DB
+	table string
 }
 
 // NewRepository creates a new Repository.
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, table: expensesTable}
 }
 
-// List returns expenses ordered by date descending with pagination.
-func (r *Repository) List(ctx context.Context, limit, offset int) ([]ExpenseRow, error) {
+// newPage trims rows, fetched with one extra row, to limit and sets the
+// next cursor if there are more rows.
+func newPage(q ListQuery, rows []ExpenseRow, limit int) Page {
+	if len(rows) <= limit {
+		return Page{Expenses: rows}
+	}
+	rows = rows[:limit]
+	return Page{Expenses: rows, Next: cursorAt(q, rows[limit-1])}
+}
+
+// clampPage applies the pagination defaults and bounds documented on Reader.
+func clampPage(limit, offset int) (int, int) {
 	if limit <= 0 {
 		limit = defaultLimit
 	}
@@ -43,29 +99,236 @@ func (r *Repository) List(ctx context.Context, limit, offset int) ([]ExpenseRow,
 	if offset < 0 {
 		offset = 0
 	}
+	return limit, offset
+}
+
+// List returns the expenses matching q with pagination.
+// Voided expenses are excluded.
+func (r *Repository) List(ctx context.Context, q ListQuery) (Page, error) {
+	limit, offset := clampPage(q.Limit, q.Offset)
 
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, amount, category, memo, date, created_at
-		 FROM expenses
-		 ORDER BY date DESC, created_at DESC
-		 LIMIT ? OFFSET ?`,
-		limit, offset,
+	where, args := listFilter(q)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s
+		 FROM %s
+		 WHERE %s
+		 ORDER BY %s
+		 LIMIT ? OFFSET ?`, expenseColumns, r.table, where, listOrder(q)),
+		// One extra row tells whether there is a next page.
+		append(args, limit+1, offset)...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("query expenses: %w", err)
+		return Page{}, fmt.Errorf("query expenses: %w", err)
 	}
 	defer rows.Close()
 
 	var expenses []ExpenseRow
 	for rows.Next() {
-		var e ExpenseRow
-		if err := rows.Scan(&e.ID, &e.Amount, &e.Category, &e.Memo, &e.Date, &e.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scan expense: %w", err)
+		e, err := scanExpense(rows)
+		if err != nil {
+			return Page{}, err
 		}
 		expenses = append(expenses, e)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate expenses: %w", err)
+		return Page{}, fmt.Errorf("iterate expenses: %w", err)
+	}
+	return newPage(q, expenses, limit), nil
+}
+
+// Get returns a non-voided expense by ID.
+func (r *Repository) Get(ctx context.Context, id string) (ExpenseRow, error) {
+	row := r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM %s WHERE id = ? AND voided_at IS NULL`, expenseColumns, r.table), id)
+	e, err := scanExpense(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ExpenseRow{}, ErrNotFound
+	}
+	return e, err
+}
+
+// expenseColumns are the columns scanExpense reads, in order.
+const expenseColumns = `id, user_id, book_id, amount, category, memo, DATE_FORMAT(date, '%Y-%m-%d'), tags, version, created_at`
+
+func scanExpense(row interface{ Scan(...any) error }) (ExpenseRow, error) {
+	var (
+		e    ExpenseRow
+		tags []byte
+	)
+	if err := row.Scan(&e.ID, &e.UserID, &e.BookID, &e.Amount, &e.Category, &e.Memo, &e.Date, &tags, &e.Version, &e.CreatedAt); err != nil {
+		return ExpenseRow{}, fmt.Errorf("scan expense: %w", err)
+	}
+	// Rows projected before tags existed hold NULL.
+	e.Tags = []string{}
+	if tags != nil {
+		if err := json.Unmarshal(tags, &e.Tags); err != nil {
+			return ExpenseRow{}, fmt.Errorf("unmarshal tags of expense %s: %w", e.ID, err)
+		}
+	}
+	return e, nil
+}
+
+// listFilter builds the WHERE clause selecting the expenses q matches.
+func listFilter(q ListQuery) (string, []any) {
+	conds := []string{"voided_at IS NULL"}
+	var args []any
+
+	if q.BookID != "" {
+		conds = append(conds, "book_id = ?")
+		args = append(args, q.BookID)
+	}
+	if q.From != "" {
+		conds = append(conds, "date >= ?")
+		args = append(args, q.From)
+	}
+	if q.To != "" {
+		conds = append(conds, "date <= ?")
+		args = append(args, q.To)
+	}
+	if len(q.Categories) > 0 {
+		conds = append(conds, "category IN (?"+strings.Repeat(", ?", len(q.Categories)-1)+")")
+		for _, c := range q.Categories {
+			args = append(args, c)
+		}
+	}
+	if q.MinAmount != nil {
+		conds = append(conds, "amount >= ?")
+		args = append(args, *q.MinAmount)
+	}
+	if q.MaxAmount != nil {
+		conds = append(conds, "amount <= ?")
+		args = append(args, *q.MaxAmount)
+	}
+	if q.Memo != "" {
+		// The table's collation makes LIKE case-insensitive. Backslash is
+		// LIKE's default escape character.
+		conds = append(conds, "memo LIKE ?")
+		args = append(args, "%"+likeEscaper.Replace(q.Memo)+"%")
+	}
+	if c := q.After; c != nil {
+		cond, cargs := keysetCondition(c)
+		conds = append(conds, cond)
+		args = append(args, cargs...)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// keysetCondition builds the keyset condition selecting the rows after c in
+// c's order, written out rather than as a row comparison so MySQL can use
+// idx_expenses_listing for it.
+func keysetCondition(c *Cursor) (string, []any) {
+	op := "<"
+	if c.Ascending {
+		op = ">"
+	}
+	tie := fmt.Sprintf("(created_at %[1]s ? OR (created_at = ? AND id %[1]s ?))", op)
+	tieArgs := []any{c.CreatedAt, c.CreatedAt, c.ID}
+
+	switch c.Sort {
+	case SortByAmount:
+		return fmt.Sprintf("(amount %s ? OR (amount = ? AND %s))", op, tie),
+			append([]any{c.Amount, c.Amount}, tieArgs...)
+	case SortByCreatedAt:
+		return tie, tieArgs
+	default:
+		return fmt.Sprintf("(date %s ? OR (date = ? AND %s))", op, tie),
+			append([]any{c.Date, c.Date}, tieArgs...)
+	}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// listOrder builds the ORDER BY clause for q. The sort key is one of the
+// validated SortKey values, never user input.
+func listOrder(q ListQuery) string {
+	dir := "DESC"
+	if q.Ascending {
+		dir = "ASC"
+	}
+	keys := []string{"created_at", "id"}
+	switch q.Sort {
+	case SortByAmount:
+		keys = append([]string{"amount"}, keys...)
+	case SortByCreatedAt:
+	default:
+		keys = append([]string{"date"}, keys...)
+	}
+	for i, k := range keys {
+		keys[i] = k + " " + dir
+	}
+	return strings.Join(keys, ", ")
+}
+
+// InsertExpense adds a row unless one with the same ID already exists.
+func (r *Repository) InsertExpense(ctx context.Context, row ExpenseRow) error {
+	tags, err := json.Marshal(normalizeTags(row.Tags))
+	if err != nil {
+		return fmt.Errorf("marshal tags: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, user_id, book_id, amount, category, memo, date, tags, version, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE id = id`, r.table),
+		row.ID, row.UserID, row.BookID, row.Amount, row.Category, row.Memo, row.Date, tags, row.Version, row.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert expense: %w", err)
+	}
+	return nil
+}
+
+// UpdateExpense overwrites a row with a newer version.
+func (r *Repository) UpdateExpense(ctx context.Context, row ExpenseRow) error {
+	tags, err := json.Marshal(normalizeTags(row.Tags))
+	if err != nil {
+		return fmt.Errorf("marshal tags: %w", err)
+	}
+
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE %s SET amount = ?, category = ?, memo = ?, date = ?, tags = ?, version = ?
+		 WHERE id = ? AND version < ?`, r.table),
+		row.Amount, row.Category, row.Memo, row.Date, tags, row.Version, row.ID, row.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("update expense: %w", err)
+	}
+	return r.checkApplied(ctx, res, row.ID)
+}
+
+// VoidExpense soft-deletes a row at a newer version.
+func (r *Repository) VoidExpense(ctx context.Context, id string, version int, voidedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE %s SET voided_at = ?, version = ?
+		 WHERE id = ? AND version < ?`, r.table),
+		voidedAt, version, id, version,
+	)
+	if err != nil {
+		return fmt.Errorf("void expense: %w", err)
+	}
+	return r.checkApplied(ctx, res, id)
+}
+
+// checkApplied tells a replayed update, which matches no row because the
+// version guard filters it out, apart from an update to a row that does not
+// exist yet.
+func (r *Repository) checkApplied(ctx context.Context, res sql.Result, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT EXISTS (SELECT 1 FROM %s WHERE id = ?)`, r.table), id,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check expense: %w", err)
+	}
+	if !exists {
+		return errNotProjected
 	}
-	return expenses, nil
+	return nil
 }
@@ -0,0 +1,176 @@
+package expense
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SplitMethod is how an expense's amount is shared between people.
+type SplitMethod string
+
+const (
+	// SplitEqual shares the amount equally.
+	SplitEqual SplitMethod = "equal"
+	// SplitRatio shares the amount in proportion to each share's Ratio.
+	SplitRatio SplitMethod = "ratio"
+	// SplitFixed takes each share's Amount as given.
+	SplitFixed SplitMethod = "fixed"
+)
+
+const (
+	maxShares = 20
+	maxRatio  = 1000
+)
+
+// ErrInvalidSplit is returned when a split cannot be resolved against an
+// expense's amount.
+var ErrInvalidSplit = errors.New("invalid split")
+
+// Split records who paid for an expense in a shared book and how it is
+// shared. In a command only the fields the method needs are read; in a
+// payload every share carries its resolved Amount, and the amounts sum to
+// the expense's.
+type Split struct {
+	PaidBy string      `json:"paid_by"`
+	Method SplitMethod `json:"method"`
+	Shares []Share     `json:"shares"`
+}
+
+// Share is one person's part of a split expense.
+type Share struct {
+	UserID string `json:"user_id"`
+	// Ratio is the share's weight, for SplitRatio.
+	Ratio int64 `json:"ratio,omitempty"`
+	// Amount is the share in yen. It is given for SplitFixed and worked out
+	// for the other methods.
+	Amount int64 `json:"amount"`
+}
+
+// Users returns the payer followed by every sharer, who must all be members
+// of the expense's book.
+func (s Split) Users() []string {
+	users := []string{s.PaidBy}
+	for _, share := range s.Shares {
+		users = append(users, share.UserID)
+	}
+	return users
+}
+
+// Validate checks the split's fields, apart from how they add up to an
+// amount, which Resolve checks.
+func (s Split) Validate() error {
+	var errs []error
+
+	if s.PaidBy == "" {
+		errs = append(errs, fmt.Errorf("split paid_by is required"))
+	}
+	if s.Method != SplitEqual && s.Method != SplitRatio && s.Method != SplitFixed {
+		errs = append(errs, fmt.Errorf("split method must be equal, ratio or fixed"))
+	}
+	if len(s.Shares) == 0 || len(s.Shares) > maxShares {
+		errs = append(errs, fmt.Errorf("split must have 1 to %d shares", maxShares))
+	}
+	seen := make(map[string]bool, len(s.Shares))
+	for _, share := range s.Shares {
+		if share.UserID == "" {
+			errs = append(errs, fmt.Errorf("split share user_id is required"))
+			break
+		}
+		if seen[share.UserID] {
+			errs = append(errs, fmt.Errorf("duplicate split share for %q", share.UserID))
+			break
+		}
+		seen[share.UserID] = true
+	}
+	switch s.Method {
+	case SplitRatio:
+		for _, share := range s.Shares {
+			if share.Ratio <= 0 || share.Ratio > maxRatio {
+				errs = append(errs, fmt.Errorf("split ratios must be 1 to %d", maxRatio))
+				break
+			}
+		}
+	case SplitFixed:
+		for _, share := range s.Shares {
+			if share.Amount < 0 {
+				errs = append(errs, fmt.Errorf("split amounts must not be negative"))
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Resolve returns the split with every share's Amount worked out so that
+// they sum to amount. Yen left over from an equal or ratio split go one
+// each to the earliest shares, largest remainder first for ratios, so the
+// result is the same every time. A fixed split must already sum to amount.
+func (s Split) Resolve(amount int64) (Split, error) {
+	if err := s.Validate(); err != nil {
+		return Split{}, fmt.Errorf("%w: %w", ErrInvalidSplit, err)
+	}
+
+	resolved := Split{PaidBy: s.PaidBy, Method: s.Method, Shares: make([]Share, len(s.Shares))}
+	switch s.Method {
+	case SplitEqual:
+		n := int64(len(s.Shares))
+		for i, share := range s.Shares {
+			resolved.Shares[i] = Share{UserID: share.UserID, Amount: amount / n}
+			if int64(i) < amount%n {
+				resolved.Shares[i].Amount++
+			}
+		}
+	case SplitRatio:
+		var total int64
+		for _, share := range s.Shares {
+			total += share.Ratio
+		}
+		remainders := make([]int64, len(s.Shares))
+		left := amount
+		for i, share := range s.Shares {
+			// Amounts are at most MaxAmount and ratios at most maxRatio, so
+			// amount*ratio cannot overflow.
+			resolved.Shares[i] = Share{UserID: share.UserID, Ratio: share.Ratio, Amount: amount * share.Ratio / total}
+			remainders[i] = amount * share.Ratio % total
+			left -= resolved.Shares[i].Amount
+		}
+		for ; left > 0; left-- {
+			largest := 0
+			for i := range remainders {
+				if remainders[i] > remainders[largest] {
+					largest = i
+				}
+			}
+			resolved.Shares[largest].Amount++
+			remainders[largest] = -1
+		}
+	case SplitFixed:
+		var total int64
+		for i, share := range s.Shares {
+			resolved.Shares[i] = Share{UserID: share.UserID, Amount: share.Amount}
+			total += share.Amount
+		}
+		if total != amount {
+			return Split{}, fmt.Errorf("%w: shares sum to %d, want %d", ErrInvalidSplit, total, amount)
+		}
+	}
+	return resolved, nil
+}
+
+// equalSplits reports whether a and b, either of which may be nil, are the
+// same split.
+func equalSplits(a, b *Split) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.PaidBy != b.PaidBy || a.Method != b.Method || len(a.Shares) != len(b.Shares) {
+		return false
+	}
+	for i := range a.Shares {
+		if a.Shares[i] != b.Shares[i] {
+			return false
+		}
+	}
+	return true
+}
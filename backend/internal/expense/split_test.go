@@ -0,0 +1,135 @@
+package expense
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func TestSplit_Resolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		split  Split
+		amount int64
+		want   []Share
+	}{
+		{
+			"equal with remainder",
+			Split{PaidBy: "alice", Method: SplitEqual, Shares: []Share{{UserID: "alice"}, {UserID: "bob"}, {UserID: "carol"}}},
+			1000,
+			[]Share{{UserID: "alice", Amount: 334}, {UserID: "bob", Amount: 333}, {UserID: "carol", Amount: 333}},
+		},
+		{
+			"equal ignores given amounts",
+			Split{PaidBy: "bob", Method: SplitEqual, Shares: []Share{{UserID: "alice", Amount: 1}, {UserID: "bob", Ratio: 3}}},
+			600,
+			[]Share{{UserID: "alice", Amount: 300}, {UserID: "bob", Amount: 300}},
+		},
+		{
+			"ratio",
+			Split{PaidBy: "alice", Method: SplitRatio, Shares: []Share{{UserID: "alice", Ratio: 2}, {UserID: "bob", Ratio: 1}}},
+			900,
+			[]Share{{UserID: "alice", Ratio: 2, Amount: 600}, {UserID: "bob", Ratio: 1, Amount: 300}},
+		},
+		{
+			"ratio gives leftovers to the largest remainders",
+			Split{PaidBy: "alice", Method: SplitRatio, Shares: []Share{{UserID: "alice", Ratio: 1}, {UserID: "bob", Ratio: 2}, {UserID: "carol", Ratio: 4}}},
+			100,
+			// 14.28..., 28.57... and 57.14...
+			[]Share{{UserID: "alice", Ratio: 1, Amount: 14}, {UserID: "bob", Ratio: 2, Amount: 29}, {UserID: "carol", Ratio: 4, Amount: 57}},
+		},
+		{
+			"ratio of the largest amount",
+			Split{PaidBy: "alice", Method: SplitRatio, Shares: []Share{{UserID: "alice", Ratio: maxRatio}, {UserID: "bob", Ratio: 1}}},
+			MaxAmount,
+			[]Share{{UserID: "alice", Ratio: maxRatio, Amount: 999_000_999_001}, {UserID: "bob", Ratio: 1, Amount: 999_000_999}},
+		},
+		{
+			"fixed",
+			Split{PaidBy: "carol", Method: SplitFixed, Shares: []Share{{UserID: "alice", Amount: 700}, {UserID: "bob", Ratio: 9, Amount: 0}}},
+			700,
+			[]Share{{UserID: "alice", Amount: 700}, {UserID: "bob", Amount: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.split.Resolve(tt.amount)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if got.PaidBy != tt.split.PaidBy || got.Method != tt.split.Method || !reflect.DeepEqual(got.Shares, tt.want) {
+				t.Errorf("Resolve(%d) = %+v, want shares %+v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit_ResolveInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		split Split
+	}{
+		{"no payer", Split{Method: SplitEqual, Shares: []Share{{UserID: "alice"}}}},
+		{"unknown method", Split{PaidBy: "alice", Method: "half", Shares: []Share{{UserID: "alice"}}}},
+		{"no shares", Split{PaidBy: "alice", Method: SplitEqual}},
+		{"duplicate share", Split{PaidBy: "alice", Method: SplitEqual, Shares: []Share{{UserID: "bob"}, {UserID: "bob"}}}},
+		{"zero ratio", Split{PaidBy: "alice", Method: SplitRatio, Shares: []Share{{UserID: "alice", Ratio: 1}, {UserID: "bob"}}}},
+		{"negative amount", Split{PaidBy: "alice", Method: SplitFixed, Shares: []Share{{UserID: "alice", Amount: 1100}, {UserID: "bob", Amount: -100}}}},
+		{"fixed sum differs", Split{PaidBy: "alice", Method: SplitFixed, Shares: []Share{{UserID: "alice", Amount: 500}, {UserID: "bob", Amount: 400}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.split.Resolve(1000); !errors.Is(err, ErrInvalidSplit) {
+				t.Errorf("Resolve = %v, want ErrInvalidSplit", err)
+			}
+		})
+	}
+}
+
+func TestCorrect_Split(t *testing.T) {
+	event, err := RecordExpense("exp-1", RecordExpenseCommand{
+		BookID:   "household-1",
+		Amount:   3000,
+		Category: "外食",
+		Date:     "2026-03-07",
+		Split:    &Split{PaidBy: "alice", Method: SplitRatio, Shares: []Share{{UserID: "alice", Ratio: 1}, {UserID: "bob", Ratio: 2}}},
+	})
+	if err != nil {
+		t.Fatalf("RecordExpense: %v", err)
+	}
+	exp, err := LoadExpense([]eventstore.Event{event})
+	if err != nil {
+		t.Fatalf("LoadExpense: %v", err)
+	}
+
+	// A new amount is shared out again by the same ratio.
+	event, err = exp.Correct(CorrectExpenseCommand{Amount: ptr(int64(6000))})
+	if err != nil {
+		t.Fatalf("Correct amount: %v", err)
+	}
+	if err := exp.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if want := []Share{{UserID: "alice", Ratio: 1, Amount: 2000}, {UserID: "bob", Ratio: 2, Amount: 4000}}; !reflect.DeepEqual(exp.Split.Shares, want) {
+		t.Errorf("shares = %+v, want %+v", exp.Split.Shares, want)
+	}
+
+	fixed := &Split{PaidBy: "bob", Method: SplitFixed, Shares: []Share{{UserID: "alice", Amount: 6000}, {UserID: "bob"}}}
+	event, err = exp.Correct(CorrectExpenseCommand{Split: fixed})
+	if err != nil {
+		t.Fatalf("Correct split: %v", err)
+	}
+	if err := exp.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if _, err := exp.Correct(CorrectExpenseCommand{Split: fixed}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("same split = %v, want ErrNoChanges", err)
+	}
+
+	// Fixed shares no longer add up once the amount changes.
+	if _, err := exp.Correct(CorrectExpenseCommand{Amount: ptr(int64(5000))}); !errors.Is(err, ErrInvalidSplit) {
+		t.Errorf("new amount with a fixed split = %v, want ErrInvalidSplit", err)
+	}
+}
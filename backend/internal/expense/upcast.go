@@ -0,0 +1,65 @@
+package expense
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// RegisterUpcasters adds the upcasters for every historical expense payload
+// schema to u. Stores handed to Projector or used to load an Expense must be
+// wrapped with them.
+func RegisterUpcasters(u *eventstore.Upcasters) {
+	u.Register(eventTypeRecorded, 1, addEmptyTags)
+	u.Register(eventTypeCorrected, 1, addEmptyTags)
+	u.Register(eventTypeRecorded, 2, addPersonalBook)
+	u.Register(eventTypeRecorded, 3, addNoSplit)
+	u.Register(eventTypeCorrected, 2, addNoSplit)
+	u.Register(eventTypeRecorded, 4, addNoAccount)
+	u.Register(eventTypeCorrected, 3, addNoAccount)
+}
+
+// addEmptyTags lifts a v1 ExpenseRecorded or ExpenseCorrected payload, which
+// predates tags, to v2.
+func addEmptyTags(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal v1 payload: %w", err)
+	}
+	fields["tags"] = json.RawMessage(`[]`)
+	return json.Marshal(fields)
+}
+
+// addPersonalBook lifts a v2 ExpenseRecorded payload, which predates books,
+// to v3. The empty book_id puts the expense in the recorder's personal book.
+func addPersonalBook(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal v2 payload: %w", err)
+	}
+	fields["book_id"] = json.RawMessage(`""`)
+	return json.Marshal(fields)
+}
+
+// addNoSplit lifts a v3 ExpenseRecorded or v2 ExpenseCorrected payload,
+// which predates splits, to the next version with no split.
+func addNoSplit(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+	fields["split"] = json.RawMessage(`null`)
+	return json.Marshal(fields)
+}
+
+// addNoAccount lifts a v4 ExpenseRecorded or v3 ExpenseCorrected payload,
+// which predates accounts, to the next version with no account.
+func addNoAccount(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+	fields["account_id"] = json.RawMessage(`""`)
+	return json.Marshal(fields)
+}
@@ -0,0 +1,157 @@
+package expense_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+func upcastingStore(store eventstore.Store) eventstore.Store {
+	upcasters := eventstore.NewUpcasters()
+	expense.RegisterUpcasters(upcasters)
+	return eventstore.NewUpcastingStore(store, upcasters)
+}
+
+// loadFixtureLog appends testdata/expense_events.json, which holds events of
+// every historical payload schema as they were written at the time.
+func loadFixtureLog(t *testing.T, store eventstore.Store) {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/expense_events.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var fixtures []struct {
+		AggregateID   string          `json:"aggregate_id"`
+		Version       int             `json:"version"`
+		EventType     string          `json:"event_type"`
+		SchemaVersion int             `json:"schema_version"`
+		Payload       json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+
+	for _, f := range fixtures {
+		event := eventstore.Event{
+			AggregateID:   f.AggregateID,
+			AggregateType: "expense",
+			Version:       f.Version,
+			EventType:     f.EventType,
+			Payload:       f.Payload,
+			Metadata:      eventstore.Metadata{SchemaVersion: f.SchemaVersion},
+		}
+		if err := store.Append(context.Background(), []eventstore.Event{event}, f.Version-1); err != nil {
+			t.Fatalf("append %s v%d: %v", f.AggregateID, f.Version, err)
+		}
+	}
+}
+
+func TestUpcasters_LoadFixtureLog(t *testing.T) {
+	raw := eventstore.NewMemoryStore()
+	loadFixtureLog(t, raw)
+	store := upcastingStore(raw)
+
+	tests := []struct {
+		id   string
+		want expense.Expense
+	}{
+		{"legacy", expense.Expense{ID: "legacy", Amount: 1500, Category: "食費", Memo: "ランチ", Date: "2025-11-03", Tags: []string{}, Version: 2, Book: "anonymous"}},
+		{"v1", expense.Expense{ID: "v1", Amount: 880, Category: "交通費", Memo: "バス", Date: "2025-12-01", Tags: []string{}, Version: 2, Book: "anonymous"}},
+		{"voided", expense.Expense{ID: "voided", Amount: 300, Category: "雑費", Date: "2025-12-02", Tags: []string{}, Voided: true, Version: 2, Book: "anonymous"}},
+		{"mixed", expense.Expense{ID: "mixed", Amount: 5000, Category: "娯楽", Memo: "映画", Date: "2026-01-10", Tags: []string{"家族"}, Version: 2, Book: "anonymous"}},
+		{"v2", expense.Expense{ID: "v2", Amount: 42000, Category: "旅行", Memo: "宿", Date: "2026-02-14", Tags: []string{"旅行", "家族"}, Version: 1, Book: "anonymous"}},
+		{"v3", expense.Expense{ID: "v3", Amount: 6000, Category: "日用品", Memo: "洗剤と柔軟剤", Date: "2026-03-01", Tags: []string{}, Version: 2, Book: "household-1"}},
+		{"v4", expense.Expense{ID: "v4", Amount: 9000, Category: "外食", Memo: "夕食と二次会", Date: "2026-03-07", Tags: []string{}, Version: 2, Book: "household-1",
+			Split: &expense.Split{PaidBy: "alice", Method: expense.SplitEqual, Shares: []expense.Share{{UserID: "alice", Amount: 4500}, {UserID: "bob", Amount: 4500}}}}},
+		{"v5", expense.Expense{ID: "v5", Amount: 2400, Category: "交通費", Memo: "Suicaで", Date: "2026-03-09", Tags: []string{}, Version: 1, Book: "alice", AccountID: "suica"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			events, err := store.Load(context.Background(), "expense", tt.id)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			got, err := expense.LoadExpense(events)
+			if err != nil {
+				t.Fatalf("LoadExpense: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("expense = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpcasters_ProjectFixtureLog(t *testing.T) {
+	raw := eventstore.NewMemoryStore()
+	loadFixtureLog(t, raw)
+	store := upcastingStore(raw)
+
+	ctx := context.Background()
+	repo := expense.NewMemoryRepository()
+	projector := expense.NewProjector(repo)
+
+	events, err := store.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	for _, e := range events {
+		if err := projector.Apply(ctx, e); err != nil {
+			t.Fatalf("apply %s v%d: %v", e.AggregateID, e.Version, err)
+		}
+	}
+
+	page, err := repo.List(ctx, expense.ListQuery{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	got := make(map[string][]string, len(page.Expenses))
+	books := make(map[string]string, len(page.Expenses))
+	for _, row := range page.Expenses {
+		got[row.ID] = row.Tags
+		books[row.ID] = row.BookID
+	}
+	want := map[string][]string{
+		"legacy": {},
+		"v1":     {},
+		"mixed":  {"家族"},
+		"v2":     {"旅行", "家族"},
+		"v3":     {},
+		"v4":     {},
+		"v5":     {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tags by id = %v, want %v", got, want)
+	}
+	wantBooks := map[string]string{
+		"legacy": "anonymous",
+		"v1":     "anonymous",
+		"mixed":  "anonymous",
+		"v2":     "anonymous",
+		"v3":     "household-1",
+		"v4":     "household-1",
+		"v5":     "alice",
+	}
+	if !reflect.DeepEqual(books, wantBooks) {
+		t.Errorf("books by id = %v, want %v", books, wantBooks)
+	}
+}
+
+func TestLoadExpense_RejectsStalePayloadSchema(t *testing.T) {
+	store := eventstore.NewMemoryStore()
+	loadFixtureLog(t, store)
+
+	events, err := store.Load(context.Background(), "expense", "v1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := expense.LoadExpense(events); err == nil {
+		t.Fatal("LoadExpense of v1 payloads without upcasting succeeded, want error")
+	}
+}
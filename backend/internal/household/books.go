@@ -0,0 +1,41 @@
+package household
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+)
+
+// snapshotEvery is how many events a household may grow by before it is
+// snapshotted again.
+const snapshotEvery = 20
+
+// Books tells what a user may do with a book.
+type Books struct {
+	households *snapshot.Loader[*Household]
+}
+
+// NewBooks creates a new Books.
+func NewBooks(store eventstore.Store, snapshots snapshot.Store) *Books {
+	return &Books{households: snapshot.NewLoader(store, snapshots, Aggregate, snapshot.Every(snapshotEvery))}
+}
+
+// Role returns userID's role in bookID. Users own their personal book,
+// whose ID is their own. It returns ErrNotFound if the book is neither
+// theirs nor a household they are a member of.
+func (b *Books) Role(ctx context.Context, userID, bookID string) (Role, error) {
+	if bookID == userID {
+		return RoleOwner, nil
+	}
+	h, err := b.households.Load(ctx, bookID)
+	if err != nil {
+		return "", fmt.Errorf("load household: %w", err)
+	}
+	role := h.Role(userID)
+	if role == "" {
+		return "", ErrNotFound
+	}
+	return role, nil
+}
@@ -0,0 +1,38 @@
+package household
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// codeSecretBytes is how much randomness an invitation code carries.
+const codeSecretBytes = 16
+
+// NewInvitationCode returns a new one-time code for joining householdID.
+// The code names the household, so joining needs nothing else, and is
+// only ever stored as a hash.
+func NewInvitationCode(householdID string) (string, error) {
+	secret := make([]byte, codeSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate invitation code: %w", err)
+	}
+	return householdID + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// householdOf returns the household an invitation code is for.
+func householdOf(code string) (string, error) {
+	id, secret, ok := strings.Cut(code, ".")
+	if !ok || id == "" || secret == "" {
+		return "", ErrInvalidInvitation
+	}
+	return id, nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,370 @@
+package household
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+)
+
+// Handler handles HTTP requests for the household domain. Every route must
+// be behind middleware.Authenticate. Households a user is not a member of
+// are reported as not found. The list of a user's households is read from
+// the memberships read model, which Projector updates asynchronously, so it
+// can lag a household just created or joined for a moment.
+type Handler struct {
+	store       eventstore.Store
+	households  *snapshot.Loader[*Household]
+	memberships Reader
+	now         func() time.Time
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store, snapshots snapshot.Store, memberships Reader) *Handler {
+	return &Handler{
+		store:       store,
+		households:  snapshot.NewLoader(store, snapshots, Aggregate, snapshot.Every(snapshotEvery)),
+		memberships: memberships,
+		now:         time.Now,
+	}
+}
+
+// Register adds household routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /households", h.CreateHousehold)
+	mux.HandleFunc("GET /households", h.ListHouseholds)
+	mux.HandleFunc("GET /households/{id}", h.GetHousehold)
+	mux.HandleFunc("POST /households/{id}/invitations", h.InviteMember)
+	mux.HandleFunc("POST /households/join", h.JoinHousehold)
+	mux.HandleFunc("PUT /households/{id}/members/{userID}", h.ChangeMemberRole)
+	mux.HandleFunc("POST /households/{id}/settlements", h.RecordSettlement)
+}
+
+type householdResponse struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	Role    Role             `json:"role"`
+	Members []memberResponse `json:"members,omitempty"`
+	Version int              `json:"version"`
+}
+
+type memberResponse struct {
+	UserID string `json:"user_id"`
+	Role   Role   `json:"role"`
+}
+
+func newHouseholdResponse(hh *Household, userID string, withMembers bool) householdResponse {
+	resp := householdResponse{ID: hh.ID, Name: hh.Name, Role: hh.Role(userID), Version: hh.Version}
+	if withMembers {
+		resp.Members = make([]memberResponse, 0, len(hh.Members))
+		for id, role := range hh.Members {
+			resp.Members = append(resp.Members, memberResponse{UserID: id, Role: role})
+		}
+		slices.SortFunc(resp.Members, func(a, b memberResponse) int {
+			return cmp.Compare(a.UserID, b.UserID)
+		})
+	}
+	return resp
+}
+
+// CreateHousehold handles POST /households. The user creating it becomes
+// its owner.
+func (h *Handler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	var cmd CreateCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	event, err := Create(uuid.New().String(), userID, cmd)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, 0); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, householdResponse{
+		ID:      event.AggregateID,
+		Name:    cmd.Name,
+		Role:    RoleOwner,
+		Version: event.Version,
+	})
+}
+
+// ListHouseholds handles GET /households, returning the households the
+// user is a member of in the order they joined.
+func (h *Handler) ListHouseholds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	ids, err := h.memberships.HouseholdsOf(ctx, userID)
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("list memberships: %w", err))
+		return
+	}
+
+	households := make([]householdResponse, 0, len(ids))
+	for _, id := range ids {
+		hh, err := h.households.Load(ctx, id)
+		if err != nil {
+			writeCommandError(w, fmt.Errorf("load household: %w", err))
+			return
+		}
+		households = append(households, newHouseholdResponse(hh, userID, false))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"households": households})
+}
+
+// GetHousehold handles GET /households/{id}.
+func (h *Handler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	hh, err := h.loadHousehold(ctx, r.PathValue("id"), userID)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHouseholdResponse(hh, userID, true))
+}
+
+type invitationResponse struct {
+	Code      string    `json:"code"`
+	Role      Role      `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// InviteMember handles POST /households/{id}/invitations. The code in the
+// response is shown only once; pass it to the invitee to join with.
+func (h *Handler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	var cmd InviteCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	hh, err := h.loadHousehold(ctx, r.PathValue("id"), userID)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	code, err := NewInvitationCode(hh.ID)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	now := h.now()
+	event, err := hh.Invite(userID, code, cmd, now)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, hh.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, invitationResponse{
+		Code:      code,
+		Role:      cmd.Role,
+		ExpiresAt: now.Add(InvitationTTL).UTC(),
+	})
+}
+
+type joinRequest struct {
+	Code string `json:"code"`
+}
+
+// JoinHousehold handles POST /households/join.
+func (h *Handler) JoinHousehold(w http.ResponseWriter, r *http.Request) {
+	var req joinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	id, err := householdOf(req.Code)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	hh, err := h.households.Load(ctx, id)
+	if err != nil {
+		writeCommandError(w, fmt.Errorf("load household: %w", err))
+		return
+	}
+
+	event, err := hh.Join(userID, req.Code, h.now())
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	// A conflict here may be another use of the same code, so it is
+	// reported like any other concurrent change and the retry fails as
+	// invalid if the code is gone.
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, hh.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := hh.apply(event); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newHouseholdResponse(hh, userID, false))
+}
+
+type changeRoleResponse struct {
+	UserID  string `json:"user_id"`
+	Role    Role   `json:"role"`
+	Version int    `json:"version"`
+}
+
+// ChangeMemberRole handles PUT /households/{id}/members/{userID}.
+// Giving a member the role they already have succeeds without appending an
+// event.
+func (h *Handler) ChangeMemberRole(w http.ResponseWriter, r *http.Request) {
+	var cmd ChangeRoleCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	hh, err := h.loadHousehold(ctx, r.PathValue("id"), userID)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	member := r.PathValue("userID")
+	event, err := hh.ChangeRole(userID, member, cmd)
+	if errors.Is(err, ErrNoChanges) {
+		writeJSON(w, http.StatusOK, changeRoleResponse{UserID: member, Role: cmd.Role, Version: hh.Version})
+		return
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, hh.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, changeRoleResponse{UserID: member, Role: cmd.Role, Version: event.Version})
+}
+
+type recordSettlementResponse struct {
+	Version int `json:"version"`
+}
+
+// RecordSettlement handles POST /households/{id}/settlements, marking a
+// debt between two members as paid.
+func (h *Handler) RecordSettlement(w http.ResponseWriter, r *http.Request) {
+	var cmd RecordSettlementCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	hh, err := h.loadHousehold(ctx, r.PathValue("id"), userID)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := hh.RecordSettlement(userID, cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, hh.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, recordSettlementResponse{Version: event.Version})
+}
+
+// loadHousehold loads a household, returning ErrNotFound unless userID is
+// one of its members.
+func (h *Handler) loadHousehold(ctx context.Context, id, userID string) (*Household, error) {
+	hh, err := h.households.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("load household: %w", err)
+	}
+	if hh.Role(userID) == "" {
+		return nil, ErrNotFound
+	}
+	return hh, nil
+}
+
+// writeCommandError maps domain errors to HTTP responses.
+func writeCommandError(w http.ResponseWriter, err error) {
+	var conflict *eventstore.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":           "household was modified concurrently; reload and retry",
+			"current_version": conflict.Actual,
+		})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrMemberNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrAlreadyMember), errors.Is(err, ErrLastOwner):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidInvitation):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("household: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
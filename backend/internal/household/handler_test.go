@@ -0,0 +1,224 @@
+package household
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+type testServer struct {
+	*httptest.Server
+	store     *eventstore.MemoryStore
+	snapshots snapshot.Store
+	now       *time.Time
+}
+
+// newTestServer serves the household routes in memory at a clock the test
+// can move.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	clock := now
+	srv := &testServer{store: eventstore.NewMemoryStore(), snapshots: snapshot.NewMemoryStore(), now: &clock}
+	// Apply memberships as events are appended, so lists reflect them
+	// without waiting for a projection runner.
+	memberships := NewMemoryRepository()
+	projector := NewProjector(memberships)
+	srv.store.OnAppend = func(events []eventstore.Event) error {
+		for _, event := range events {
+			if err := projector.Apply(context.Background(), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	h := NewHandler(srv.store, srv.snapshots, memberships)
+	h.now = func() time.Time { return *srv.now }
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+	srv.Server = httptest.NewServer(testhelper.AsUser(mux))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// do sends a request as userID and decodes a successful response into v.
+func (s *testServer) do(t *testing.T, userID, method, path, body string, v any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set(testhelper.UserHeader, userID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// create has alice create a household, returning its ID.
+func (s *testServer) create(t *testing.T, name string) string {
+	t.Helper()
+	var created householdResponse
+	if status := s.do(t, "alice", http.MethodPost, "/households", `{"name":"`+name+`"}`, &created); status != http.StatusCreated {
+		t.Fatalf("POST /households status = %d, want %d", status, http.StatusCreated)
+	}
+	return created.ID
+}
+
+// invite has alice invite a member with role to id, returning the code.
+func (s *testServer) invite(t *testing.T, id string, role Role) string {
+	t.Helper()
+	var inv invitationResponse
+	if status := s.do(t, "alice", http.MethodPost, "/households/"+id+"/invitations", `{"role":"`+string(role)+`"}`, &inv); status != http.StatusCreated {
+		t.Fatalf("POST invitations status = %d, want %d", status, http.StatusCreated)
+	}
+	if inv.Role != role || !inv.ExpiresAt.Equal(now.Add(InvitationTTL)) {
+		t.Errorf("invitation = %+v, want %s expiring in %s", inv, role, InvitationTTL)
+	}
+	return inv.Code
+}
+
+func TestCreateInviteAndJoin(t *testing.T) {
+	srv := newTestServer(t)
+	id := srv.create(t, "我が家")
+	code := srv.invite(t, id, RoleViewer)
+
+	var joined householdResponse
+	if status := srv.do(t, "bob", http.MethodPost, "/households/join", `{"code":"`+code+`"}`, &joined); status != http.StatusOK {
+		t.Fatalf("POST /households/join status = %d, want %d", status, http.StatusOK)
+	}
+	if joined.ID != id || joined.Name != "我が家" || joined.Role != RoleViewer {
+		t.Errorf("joined = %+v, want 我が家 as viewer", joined)
+	}
+
+	var got householdResponse
+	if status := srv.do(t, "bob", http.MethodGet, "/households/"+id, "", &got); status != http.StatusOK {
+		t.Fatalf("GET /households/%s status = %d, want %d", id, status, http.StatusOK)
+	}
+	want := []memberResponse{{UserID: "alice", Role: RoleOwner}, {UserID: "bob", Role: RoleViewer}}
+	if len(got.Members) != len(want) || got.Members[0] != want[0] || got.Members[1] != want[1] {
+		t.Errorf("members = %+v, want %+v", got.Members, want)
+	}
+
+	var list struct {
+		Households []householdResponse `json:"households"`
+	}
+	srv.create(t, "実家")
+	if status := srv.do(t, "bob", http.MethodGet, "/households", "", &list); status != http.StatusOK {
+		t.Fatalf("GET /households status = %d, want %d", status, http.StatusOK)
+	}
+	if len(list.Households) != 1 || list.Households[0].ID != id || list.Households[0].Role != RoleViewer {
+		t.Errorf("bob's households = %+v, want only %s", list.Households, id)
+	}
+	if srv.do(t, "alice", http.MethodGet, "/households", "", &list); len(list.Households) != 2 {
+		t.Errorf("alice's households = %+v, want both", list.Households)
+	}
+
+	for _, tt := range []struct {
+		name, user, body string
+		want             int
+	}{
+		{"used code", "carol", `{"code":"` + code + `"}`, http.StatusBadRequest},
+		{"malformed code", "carol", `{"code":"nonsense"}`, http.StatusBadRequest},
+		{"unknown household", "carol", `{"code":"00000000-0000-0000-0000-000000000000.secret"}`, http.StatusBadRequest},
+		{"already a member", "alice", `{"code":"` + srv.invite(t, id, RoleEditor) + `"}`, http.StatusConflict},
+	} {
+		if status := srv.do(t, tt.user, http.MethodPost, "/households/join", tt.body, nil); status != tt.want {
+			t.Errorf("%s: join status = %d, want %d", tt.name, status, tt.want)
+		}
+	}
+}
+
+func TestJoin_Expired(t *testing.T) {
+	srv := newTestServer(t)
+	id := srv.create(t, "我が家")
+	code := srv.invite(t, id, RoleEditor)
+
+	*srv.now = now.Add(InvitationTTL)
+	if status := srv.do(t, "bob", http.MethodPost, "/households/join", `{"code":"`+code+`"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("join status = %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestHousehold_Permissions(t *testing.T) {
+	srv := newTestServer(t)
+	id := srv.create(t, "我が家")
+	code := srv.invite(t, id, RoleEditor)
+	if status := srv.do(t, "bob", http.MethodPost, "/households/join", `{"code":"`+code+`"}`, nil); status != http.StatusOK {
+		t.Fatalf("join status = %d, want %d", status, http.StatusOK)
+	}
+
+	for _, tt := range []struct {
+		name, user, method, path, body string
+		want                           int
+	}{
+		{"stranger reads", "carol", http.MethodGet, "/households/" + id, "", http.StatusNotFound},
+		{"stranger invites", "carol", http.MethodPost, "/households/" + id + "/invitations", `{"role":"viewer"}`, http.StatusNotFound},
+		{"editor invites", "bob", http.MethodPost, "/households/" + id + "/invitations", `{"role":"viewer"}`, http.StatusForbidden},
+		{"editor changes roles", "bob", http.MethodPut, "/households/" + id + "/members/bob", `{"role":"owner"}`, http.StatusForbidden},
+		{"invite as owner", "alice", http.MethodPost, "/households/" + id + "/invitations", `{"role":"owner"}`, http.StatusBadRequest},
+		{"invalid name", "alice", http.MethodPost, "/households", `{"name":""}`, http.StatusBadRequest},
+		{"last owner steps down", "alice", http.MethodPut, "/households/" + id + "/members/alice", `{"role":"editor"}`, http.StatusConflict},
+		{"non-member role", "alice", http.MethodPut, "/households/" + id + "/members/carol", `{"role":"editor"}`, http.StatusNotFound},
+		{"invalid role", "alice", http.MethodPut, "/households/" + id + "/members/bob", `{"role":"admin"}`, http.StatusBadRequest},
+	} {
+		if status := srv.do(t, tt.user, tt.method, tt.path, tt.body, nil); status != tt.want {
+			t.Errorf("%s: %s %s status = %d, want %d", tt.name, tt.method, tt.path, status, tt.want)
+		}
+	}
+
+	var changed changeRoleResponse
+	for i, wantVersion := range []int{4, 4} {
+		if status := srv.do(t, "alice", http.MethodPut, "/households/"+id+"/members/bob", `{"role":"viewer"}`, &changed); status != http.StatusOK {
+			t.Fatalf("PUT #%d status = %d, want %d", i+1, status, http.StatusOK)
+		}
+		if changed.Role != RoleViewer || changed.Version != wantVersion {
+			t.Errorf("PUT #%d = %+v, want viewer at version %d", i+1, changed, wantVersion)
+		}
+	}
+}
+
+func TestBooks_Role(t *testing.T) {
+	srv := newTestServer(t)
+	id := srv.create(t, "我が家")
+	code := srv.invite(t, id, RoleViewer)
+	if status := srv.do(t, "bob", http.MethodPost, "/households/join", `{"code":"`+code+`"}`, nil); status != http.StatusOK {
+		t.Fatalf("join status = %d, want %d", status, http.StatusOK)
+	}
+
+	books := NewBooks(srv.store, srv.snapshots)
+	for _, tt := range []struct {
+		user, book string
+		want       Role
+		wantErr    error
+	}{
+		{"bob", "bob", RoleOwner, nil},
+		{"alice", id, RoleOwner, nil},
+		{"bob", id, RoleViewer, nil},
+		{"carol", id, "", ErrNotFound},
+		{"bob", "alice", "", ErrNotFound},
+	} {
+		role, err := books.Role(context.Background(), tt.user, tt.book)
+		if role != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Role(%s, %s) = %q, %v, want %q, %v", tt.user, tt.book, role, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+// Package household is the event-sourced household: a book of expenses
+// shared by its members, who join with one-time invitation codes and act on
+// it according to their roles.
+//
+// Every expense belongs to a book. A household's ID is the ID of its book;
+// a user's own ID is the ID of their personal book.
+package household
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+)
+
+const aggregateType = "household"
+
+const (
+	eventTypeCreated     = "HouseholdCreated"
+	eventTypeInvited     = "MemberInvited"
+	eventTypeJoined      = "MemberJoined"
+	eventTypeRoleChanged = "MemberRoleChanged"
+	eventTypeSettled     = "SettlementRecorded"
+)
+
+// schemaVersions holds the payload schema version of each household event.
+var schemaVersions = map[string]int{
+	eventTypeCreated:     1,
+	eventTypeInvited:     1,
+	eventTypeJoined:      1,
+	eventTypeRoleChanged: 1,
+	eventTypeSettled:     1,
+}
+
+const (
+	maxNameLength = 64
+	// InvitationTTL is how long an invitation code can be used.
+	InvitationTTL = 7 * 24 * time.Hour
+)
+
+// Role is what a member may do with the household's book.
+type Role string
+
+const (
+	// RoleOwner may also invite members and change their roles.
+	RoleOwner Role = "owner"
+	// RoleEditor may record, correct and void expenses.
+	RoleEditor Role = "editor"
+	// RoleViewer may only read.
+	RoleViewer Role = "viewer"
+)
+
+// CanWrite reports whether the role may change the book's expenses.
+func (r Role) CanWrite() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+func (r Role) valid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+var (
+	// ErrNotFound is returned when a household has no events, or the user
+	// is not one of its members.
+	ErrNotFound = errors.New("household not found")
+	// ErrForbidden is returned when a member's role does not allow a
+	// command.
+	ErrForbidden = errors.New("your role does not allow this")
+	// ErrInvalidInvitation is returned for a code that is unknown, used or
+	// expired.
+	ErrInvalidInvitation = errors.New("invitation code is invalid or expired")
+	// ErrAlreadyMember is returned when joining a household twice.
+	ErrAlreadyMember = errors.New("already a member of this household")
+	// ErrMemberNotFound is returned when changing the role of a non-member.
+	ErrMemberNotFound = errors.New("member not found")
+	// ErrLastOwner is returned when a change would leave no owner.
+	ErrLastOwner = errors.New("a household needs at least one owner")
+	// ErrNoChanges is returned when a member already has the role.
+	ErrNoChanges = errors.New("member already has this role")
+)
+
+// CreateCommand holds the data needed to create a household.
+type CreateCommand struct {
+	Name string `json:"name"`
+}
+
+// InviteCommand holds the role an invitation grants.
+type InviteCommand struct {
+	Role Role `json:"role"`
+}
+
+// ChangeRoleCommand holds a member's new role.
+type ChangeRoleCommand struct {
+	Role Role `json:"role"`
+}
+
+// HouseholdCreatedPayload is the event payload for a new household. Owner
+// is the user that created it.
+type HouseholdCreatedPayload struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+}
+
+// MemberInvitedPayload is the event payload for an invitation. The code is
+// only stored as a hash, so the event log does not hold usable codes.
+type MemberInvitedPayload struct {
+	CodeHash  string    `json:"code_hash"`
+	Role      Role      `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MemberJoinedPayload is the event payload for a user joining with the
+// invitation whose code hashes to CodeHash.
+type MemberJoinedPayload struct {
+	UserID   string `json:"user_id"`
+	Role     Role   `json:"role"`
+	CodeHash string `json:"code_hash"`
+}
+
+// MemberRoleChangedPayload is the event payload for a member's new role.
+type MemberRoleChangedPayload struct {
+	UserID string `json:"user_id"`
+	Role   Role   `json:"role"`
+}
+
+// RecordSettlementCommand records that From paid Amount yen to To on Date
+// to settle what they owed.
+type RecordSettlementCommand struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+	Date   string `json:"date"`
+}
+
+// SettlementRecordedPayload is the event payload for a settlement.
+type SettlementRecordedPayload struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+	Date   string `json:"date"`
+}
+
+// Validate checks that the command fields are valid.
+func (c CreateCommand) Validate() error {
+	if c.Name == "" || len([]rune(c.Name)) > maxNameLength {
+		return fmt.Errorf("name must be 1 to %d characters", maxNameLength)
+	}
+	return nil
+}
+
+// Validate checks that the command fields are valid. Owners are made by
+// changing a member's role, not by invitation.
+func (c InviteCommand) Validate() error {
+	if c.Role != RoleEditor && c.Role != RoleViewer {
+		return fmt.Errorf("role must be %q or %q", RoleEditor, RoleViewer)
+	}
+	return nil
+}
+
+// Validate checks that the command fields are valid.
+func (c ChangeRoleCommand) Validate() error {
+	if !c.Role.valid() {
+		return fmt.Errorf("role must be %q, %q or %q", RoleOwner, RoleEditor, RoleViewer)
+	}
+	return nil
+}
+
+// Validate checks that the command fields are valid.
+func (c RecordSettlementCommand) Validate() error {
+	var errs []error
+
+	if c.From == "" || c.To == "" {
+		errs = append(errs, fmt.Errorf("from and to are required"))
+	} else if c.From == c.To {
+		errs = append(errs, fmt.Errorf("from and to must differ"))
+	}
+	if c.Amount <= 0 {
+		errs = append(errs, fmt.Errorf("amount must be positive"))
+	}
+	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+		errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Create creates the event for a new household owned by owner.
+// This is a pure function that performs no I/O.
+func Create(id, owner string, cmd CreateCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	h := &Household{ID: id}
+	return h.newEvent(eventTypeCreated, HouseholdCreatedPayload{Name: cmd.Name, Owner: owner})
+}
+
+// Invitation is an unused invitation.
+type Invitation struct {
+	Role      Role      `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Household is the household aggregate, rebuilt from its event stream. Its
+// fields are exported so it can be snapshotted.
+type Household struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Members map[string]Role `json:"members"`
+	// Invitations holds the unused invitations by code hash. Expired ones
+	// are dropped when the next invitation is made.
+	Invitations map[string]Invitation `json:"invitations"`
+	Version     int                   `json:"version"`
+}
+
+// Aggregate describes the household aggregate to snapshot.Loader. Bump
+// StateVersion whenever Household's JSON shape changes.
+var Aggregate = snapshot.Aggregate[*Household]{
+	Type:         aggregateType,
+	StateVersion: 1,
+	New:          func(id string) *Household { return &Household{ID: id} },
+	Apply:        (*Household).apply,
+	Version:      func(h *Household) int { return h.Version },
+}
+
+// Exists reports whether the household has been created.
+func (h *Household) Exists() bool {
+	return h.Version > 0
+}
+
+// Role returns userID's role, or "" if they are not a member.
+func (h *Household) Role(userID string) Role {
+	return h.Members[userID]
+}
+
+func (h *Household) apply(event eventstore.Event) error {
+	if event.Version != h.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, h.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeCreated:
+		var payload HouseholdCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		h.Name = payload.Name
+		h.Members = map[string]Role{payload.Owner: RoleOwner}
+		h.Invitations = make(map[string]Invitation)
+	case eventTypeInvited:
+		var payload MemberInvitedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		maps.DeleteFunc(h.Invitations, func(_ string, inv Invitation) bool {
+			return !event.OccurredAt.Before(inv.ExpiresAt)
+		})
+		h.Invitations[payload.CodeHash] = Invitation{Role: payload.Role, ExpiresAt: payload.ExpiresAt}
+	case eventTypeJoined:
+		var payload MemberJoinedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		h.Members[payload.UserID] = payload.Role
+		delete(h.Invitations, payload.CodeHash)
+	case eventTypeRoleChanged:
+		var payload MemberRoleChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		h.Members[payload.UserID] = payload.Role
+	case eventTypeSettled:
+		// Settlements only move balances, which the settlement projection
+		// keeps.
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	h.Version = event.Version
+	return nil
+}
+
+// Invite creates a MemberInvited event for a code made by
+// NewInvitationCode. Only owners may invite.
+// This is a pure function that performs no I/O and does not modify h.
+func (h *Household) Invite(by, code string, cmd InviteCommand, now time.Time) (eventstore.Event, error) {
+	if err := h.authorizeOwner(by); err != nil {
+		return eventstore.Event{}, err
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	return h.newEvent(eventTypeInvited, MemberInvitedPayload{
+		CodeHash:  hashCode(code),
+		Role:      cmd.Role,
+		ExpiresAt: now.Add(InvitationTTL).UTC(),
+	})
+}
+
+// Join creates a MemberJoined event for userID, using up the invitation
+// for code.
+// This is a pure function that performs no I/O and does not modify h.
+func (h *Household) Join(userID, code string, now time.Time) (eventstore.Event, error) {
+	if !h.Exists() {
+		return eventstore.Event{}, ErrInvalidInvitation
+	}
+	if h.Role(userID) != "" {
+		return eventstore.Event{}, ErrAlreadyMember
+	}
+	hash := hashCode(code)
+	inv, ok := h.Invitations[hash]
+	if !ok || !now.Before(inv.ExpiresAt) {
+		return eventstore.Event{}, ErrInvalidInvitation
+	}
+	return h.newEvent(eventTypeJoined, MemberJoinedPayload{UserID: userID, Role: inv.Role, CodeHash: hash})
+}
+
+// ChangeRole creates a MemberRoleChanged event giving userID a new role.
+// Only owners may change roles, and the last owner may not step down.
+// This is a pure function that performs no I/O and does not modify h.
+func (h *Household) ChangeRole(by, userID string, cmd ChangeRoleCommand) (eventstore.Event, error) {
+	if err := h.authorizeOwner(by); err != nil {
+		return eventstore.Event{}, err
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	current := h.Role(userID)
+	switch {
+	case current == "":
+		return eventstore.Event{}, ErrMemberNotFound
+	case current == cmd.Role:
+		return eventstore.Event{}, ErrNoChanges
+	case current == RoleOwner && h.owners() == 1:
+		return eventstore.Event{}, ErrLastOwner
+	}
+	return h.newEvent(eventTypeRoleChanged, MemberRoleChangedPayload{UserID: userID, Role: cmd.Role})
+}
+
+// RecordSettlement creates a SettlementRecorded event for a payment
+// between two members. Owners and editors may record settlements.
+// This is a pure function that performs no I/O and does not modify h.
+func (h *Household) RecordSettlement(by string, cmd RecordSettlementCommand) (eventstore.Event, error) {
+	switch role := h.Role(by); {
+	case role == "":
+		return eventstore.Event{}, ErrNotFound
+	case !role.CanWrite():
+		return eventstore.Event{}, ErrForbidden
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	if h.Role(cmd.From) == "" || h.Role(cmd.To) == "" {
+		return eventstore.Event{}, ErrMemberNotFound
+	}
+	return h.newEvent(eventTypeSettled, SettlementRecordedPayload(cmd))
+}
+
+// authorizeOwner returns ErrNotFound unless userID is a member, and
+// ErrForbidden unless they are an owner.
+func (h *Household) authorizeOwner(userID string) error {
+	switch h.Role(userID) {
+	case "":
+		return ErrNotFound
+	case RoleOwner:
+		return nil
+	default:
+		return ErrForbidden
+	}
+}
+
+func (h *Household) owners() int {
+	n := 0
+	for _, role := range h.Members {
+		if role == RoleOwner {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *Household) newEvent(eventType string, payload any) (eventstore.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   h.ID,
+		AggregateType: aggregateType,
+		Version:       h.Version + 1,
+		EventType:     eventType,
+		Payload:       data,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventType]},
+	}, nil
+}
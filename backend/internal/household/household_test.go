@@ -0,0 +1,271 @@
+package household
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newHousehold returns alice's household after applying events.
+func newHousehold(t *testing.T) *Household {
+	t.Helper()
+	event, err := Create("h1", "alice", CreateCommand{Name: "我が家"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	h := &Household{ID: "h1"}
+	mustApply(t, h, event)
+	return h
+}
+
+func mustApply(t *testing.T, h *Household, event eventstore.Event) {
+	t.Helper()
+	if err := h.apply(event); err != nil {
+		t.Fatalf("apply %s: %v", event.EventType, err)
+	}
+}
+
+// invite has alice invite a new member with role, returning the code.
+func invite(t *testing.T, h *Household, role Role) string {
+	t.Helper()
+	return inviteAt(t, h, role, now)
+}
+
+// inviteAt is invite at the given time, which the event is stamped with as
+// a store would.
+func inviteAt(t *testing.T, h *Household, role Role, at time.Time) string {
+	t.Helper()
+	code, err := NewInvitationCode(h.ID)
+	if err != nil {
+		t.Fatalf("NewInvitationCode: %v", err)
+	}
+	event, err := h.Invite("alice", code, InviteCommand{Role: role}, at)
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	event.OccurredAt = at
+	if strings.Contains(string(event.Payload), code) {
+		t.Error("MemberInvited payload contains the plain code")
+	}
+	mustApply(t, h, event)
+	return code
+}
+
+func TestCreate(t *testing.T) {
+	h := newHousehold(t)
+	if !h.Exists() || h.Name != "我が家" || h.Role("alice") != RoleOwner || h.Version != 1 {
+		t.Errorf("household = %+v, want 我が家 owned by alice at version 1", h)
+	}
+
+	for _, name := range []string{"", strings.Repeat("家", maxNameLength+1)} {
+		if _, err := Create("h2", "alice", CreateCommand{Name: name}); err == nil {
+			t.Errorf("Create(%q) succeeded", name)
+		}
+	}
+}
+
+func TestInviteAndJoin(t *testing.T) {
+	h := newHousehold(t)
+	code := invite(t, h, RoleEditor)
+
+	if id, err := householdOf(code); err != nil || id != h.ID {
+		t.Errorf("householdOf(code) = %q, %v, want %q", id, err, h.ID)
+	}
+
+	event, err := h.Join("bob", code, now.Add(InvitationTTL-time.Second))
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	mustApply(t, h, event)
+	if h.Role("bob") != RoleEditor {
+		t.Errorf("bob's role = %q, want editor", h.Role("bob"))
+	}
+
+	if _, err := h.Join("carol", code, now); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("Join with a used code = %v, want ErrInvalidInvitation", err)
+	}
+	if _, err := h.Join("bob", invite(t, h, RoleViewer), now); !errors.Is(err, ErrAlreadyMember) {
+		t.Errorf("Join as a member = %v, want ErrAlreadyMember", err)
+	}
+}
+
+func TestJoin_InvalidCode(t *testing.T) {
+	h := newHousehold(t)
+	code := invite(t, h, RoleViewer)
+
+	tests := []struct {
+		name string
+		code string
+		at   time.Time
+	}{
+		{"unknown", code + "x", now},
+		{"expired", code, now.Add(InvitationTTL)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Join("bob", tt.code, tt.at); !errors.Is(err, ErrInvalidInvitation) {
+				t.Errorf("Join = %v, want ErrInvalidInvitation", err)
+			}
+		})
+	}
+
+	if _, err := (&Household{ID: "none"}).Join("bob", code, now); !errors.Is(err, ErrInvalidInvitation) {
+		t.Errorf("Join of a missing household = %v, want ErrInvalidInvitation", err)
+	}
+	for _, bad := range []string{"", "no-secret", ".secret", "id."} {
+		if _, err := householdOf(bad); !errors.Is(err, ErrInvalidInvitation) {
+			t.Errorf("householdOf(%q) = %v, want ErrInvalidInvitation", bad, err)
+		}
+	}
+}
+
+func TestInvite_DropsExpiredInvitations(t *testing.T) {
+	h := newHousehold(t)
+	expired := inviteAt(t, h, RoleViewer, now)
+	pending := inviteAt(t, h, RoleViewer, now.Add(time.Hour))
+	inviteAt(t, h, RoleEditor, now.Add(InvitationTTL))
+
+	if _, ok := h.Invitations[hashCode(expired)]; ok {
+		t.Error("expired invitation kept after the next invitation")
+	}
+	if _, ok := h.Invitations[hashCode(pending)]; !ok {
+		t.Error("unexpired invitation dropped")
+	}
+	if len(h.Invitations) != 2 {
+		t.Errorf("%d invitations, want the unexpired one and the new one", len(h.Invitations))
+	}
+}
+
+func TestInvite_Errors(t *testing.T) {
+	h := newHousehold(t)
+	event, err := h.Join("bob", invite(t, h, RoleEditor), now)
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	mustApply(t, h, event)
+
+	tests := []struct {
+		name string
+		by   string
+		role Role
+		want error
+	}{
+		{"editor", "bob", RoleViewer, ErrForbidden},
+		{"stranger", "carol", RoleViewer, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.Invite(tt.by, "h1.code", InviteCommand{Role: tt.role}, now); !errors.Is(err, tt.want) {
+				t.Errorf("Invite = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for _, role := range []Role{RoleOwner, "admin"} {
+		if _, err := h.Invite("alice", "h1.code", InviteCommand{Role: role}, now); err == nil {
+			t.Errorf("Invite as %q succeeded", role)
+		}
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	h := newHousehold(t)
+	event, err := h.Join("bob", invite(t, h, RoleViewer), now)
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	mustApply(t, h, event)
+
+	if _, err := h.ChangeRole("alice", "alice", ChangeRoleCommand{Role: RoleEditor}); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("demoting the last owner = %v, want ErrLastOwner", err)
+	}
+	if _, err := h.ChangeRole("bob", "bob", ChangeRoleCommand{Role: RoleOwner}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("viewer promoting themselves = %v, want ErrForbidden", err)
+	}
+	if _, err := h.ChangeRole("alice", "carol", ChangeRoleCommand{Role: RoleEditor}); !errors.Is(err, ErrMemberNotFound) {
+		t.Errorf("changing a non-member = %v, want ErrMemberNotFound", err)
+	}
+	if _, err := h.ChangeRole("alice", "bob", ChangeRoleCommand{Role: RoleViewer}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("same role = %v, want ErrNoChanges", err)
+	}
+
+	event, err = h.ChangeRole("alice", "bob", ChangeRoleCommand{Role: RoleOwner})
+	if err != nil {
+		t.Fatalf("ChangeRole: %v", err)
+	}
+	mustApply(t, h, event)
+
+	// With two owners, alice may step down.
+	event, err = h.ChangeRole("alice", "alice", ChangeRoleCommand{Role: RoleViewer})
+	if err != nil {
+		t.Fatalf("ChangeRole: %v", err)
+	}
+	mustApply(t, h, event)
+	if h.Role("alice") != RoleViewer || h.Role("bob") != RoleOwner {
+		t.Errorf("members = %v, want alice viewer and bob owner", h.Members)
+	}
+}
+
+func TestRole_CanWrite(t *testing.T) {
+	for role, want := range map[Role]bool{RoleOwner: true, RoleEditor: true, RoleViewer: false, "": false} {
+		if got := role.CanWrite(); got != want {
+			t.Errorf("%q.CanWrite() = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestRecordSettlement(t *testing.T) {
+	h := newHousehold(t)
+	for user, role := range map[string]Role{"bob": RoleEditor, "carol": RoleViewer} {
+		event, err := h.Join(user, invite(t, h, role), now)
+		if err != nil {
+			t.Fatalf("Join: %v", err)
+		}
+		mustApply(t, h, event)
+	}
+
+	cmd := RecordSettlementCommand{From: "carol", To: "alice", Amount: 1200, Date: "2026-03-01"}
+	event, err := h.RecordSettlement("bob", cmd)
+	if err != nil {
+		t.Fatalf("RecordSettlement: %v", err)
+	}
+	mustApply(t, h, event)
+	settlement, ok, err := DecodeSettlement(event)
+	want := Settlement{HouseholdID: "h1", Version: h.Version, From: "carol", To: "alice", Amount: 1200, Date: "2026-03-01"}
+	if err != nil || !ok || settlement != want {
+		t.Errorf("DecodeSettlement = %+v, %v, %v, want %+v", settlement, ok, err, want)
+	}
+
+	tests := []struct {
+		name string
+		by   string
+		cmd  RecordSettlementCommand
+		want error
+	}{
+		{"viewer", "carol", cmd, ErrForbidden},
+		{"stranger", "dave", cmd, ErrNotFound},
+		{"non-member", "alice", RecordSettlementCommand{From: "dave", To: "alice", Amount: 1, Date: "2026-03-01"}, ErrMemberNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := h.RecordSettlement(tt.by, tt.cmd); !errors.Is(err, tt.want) {
+				t.Errorf("RecordSettlement = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []RecordSettlementCommand{
+		{From: "alice", To: "alice", Amount: 1, Date: "2026-03-01"},
+		{From: "carol", To: "alice", Amount: 0, Date: "2026-03-01"},
+		{From: "carol", To: "alice", Amount: 1, Date: "2026/03/01"},
+	} {
+		if _, err := h.RecordSettlement("alice", bad); err == nil {
+			t.Errorf("RecordSettlement(%+v) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package household
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Membership records that a user became a member of a household, either by
+// creating it or by joining it.
+type Membership struct {
+	HouseholdID string
+	UserID      string
+	// EventID is the position of the event that made them a member, which
+	// orders a user's households by when they joined.
+	EventID uint64
+}
+
+// decodeMembership decodes a HouseholdCreated or MemberJoined event. ok is
+// false for every other event.
+func decodeMembership(event eventstore.Event) (m Membership, ok bool, err error) {
+	if event.AggregateType != aggregateType {
+		return Membership{}, false, nil
+	}
+	if event.EventType != eventTypeCreated && event.EventType != eventTypeJoined {
+		return Membership{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return Membership{}, false, err
+	}
+
+	m = Membership{HouseholdID: event.AggregateID, EventID: uint64(event.Position())}
+	switch event.EventType {
+	case eventTypeCreated:
+		var payload HouseholdCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return Membership{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		m.UserID = payload.Owner
+	case eventTypeJoined:
+		var payload MemberJoinedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return Membership{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		m.UserID = payload.UserID
+	}
+	return m, true, nil
+}
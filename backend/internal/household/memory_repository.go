@@ -0,0 +1,53 @@
+package household
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// MemoryRepository keeps the memberships of each user in a map.
+type MemoryRepository struct {
+	mu          sync.RWMutex
+	memberships map[string]map[string]Membership
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{memberships: make(map[string]map[string]Membership)}
+}
+
+// HouseholdsOf returns the IDs of userID's households in the order they
+// joined.
+func (r *MemoryRepository) HouseholdsOf(_ context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	memberships := slices.Collect(maps.Values(r.memberships[userID]))
+	r.mu.RUnlock()
+
+	slices.SortFunc(memberships, func(a, b Membership) int {
+		return cmp.Compare(a.EventID, b.EventID)
+	})
+	var ids []string
+	for _, m := range memberships {
+		ids = append(ids, m.HouseholdID)
+	}
+	return ids, nil
+}
+
+// AddMember stores m unless it is stored.
+func (r *MemoryRepository) AddMember(_ context.Context, m Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	households, ok := r.memberships[m.UserID]
+	if !ok {
+		households = make(map[string]Membership)
+		r.memberships[m.UserID] = households
+	}
+	if _, ok := households[m.HouseholdID]; !ok {
+		households[m.HouseholdID] = m
+	}
+	return nil
+}
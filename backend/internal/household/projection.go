@@ -0,0 +1,58 @@
+package household
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the memberships read model's checkpoint.
+	projectionName = "household_memberships"
+	// membershipsTable maps users to the households they are members of.
+	membershipsTable = "household_memberships"
+)
+
+// Projector records the owner of each new household and every member who
+// joins one in the memberships read model.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL memberships projection for
+// rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{membershipsTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{db: db, memberships: tables[membershipsTable]})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply records the membership a HouseholdCreated or MemberJoined event
+// makes. Other events are ignored, since members are never removed.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	m, ok, err := decodeMembership(event)
+	if err != nil || !ok {
+		return err
+	}
+	if err := p.w.AddMember(ctx, m); err != nil {
+		return fmt.Errorf("apply %s to %s: %w", event.EventType, event.AggregateID, err)
+	}
+	return nil
+}
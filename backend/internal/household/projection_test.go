@@ -0,0 +1,82 @@
+package household
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() ReadModel { return NewMemoryRepository() },
+	func(db *sql.DB) ReadModel { return NewRepository(db) },
+)
+
+func TestProjector(t *testing.T) {
+	// alice creates h1 and bob joins it, then bob creates h2.
+	h1 := &Household{ID: "h1"}
+	var events []eventstore.Event
+	add := func(h *Household, event eventstore.Event, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("command: %v", err)
+		}
+		if h != nil {
+			mustApply(t, h, event)
+		}
+		event.ID = uint64(len(events) + 1)
+		events = append(events, event)
+	}
+	event, err := Create("h1", "alice", CreateCommand{Name: "我が家"})
+	add(h1, event, err)
+	code, err := NewInvitationCode(h1.ID)
+	if err != nil {
+		t.Fatalf("NewInvitationCode: %v", err)
+	}
+	event, err = h1.Invite("alice", code, InviteCommand{Role: RoleEditor}, now)
+	add(h1, event, err)
+	event, err = h1.Join("bob", code, now)
+	add(h1, event, err)
+	event, err = Create("h2", "bob", CreateCommand{Name: "実家"})
+	add(nil, event, err)
+
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := NewProjector(repo)
+			ctx := context.Background()
+			// Every event is applied twice, as after a crash between applying
+			// and saving the checkpoint.
+			for range 2 {
+				for _, event := range events {
+					if err := projector.Apply(ctx, event); err != nil {
+						t.Fatalf("apply %s: %v", event.EventType, err)
+					}
+				}
+			}
+
+			want := map[string][]string{"alice": {"h1"}, "bob": {"h1", "h2"}, "carol": nil}
+			for userID, ids := range want {
+				got, err := repo.HouseholdsOf(ctx, userID)
+				if err != nil {
+					t.Fatalf("HouseholdsOf(%s): %v", userID, err)
+				}
+				if !slices.Equal(got, ids) {
+					t.Errorf("HouseholdsOf(%s) = %v, want %v", userID, got, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestProjector_RejectsStaleSchema(t *testing.T) {
+	event := eventstore.Event{ID: 1, AggregateID: "h1", AggregateType: aggregateType, Version: 1,
+		EventType: eventTypeCreated, Payload: []byte(`{"name":"我が家","owner":"alice"}`),
+		Metadata: eventstore.Metadata{SchemaVersion: 0}}
+	if err := NewProjector(NewMemoryRepository()).Apply(context.Background(), event); err == nil {
+		t.Error("Apply: expected error for a payload that was not upcast, got nil")
+	}
+}
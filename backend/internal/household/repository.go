@@ -0,0 +1,75 @@
+package household
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Reader is the query side of the memberships read model.
+type Reader interface {
+	// HouseholdsOf returns the IDs of the households userID is a member
+	// of, in the order they joined.
+	HouseholdsOf(ctx context.Context, userID string) ([]string, error)
+}
+
+// Writer is the projection side of the memberships read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// AddMember stores m unless it is stored.
+	AddMember(ctx context.Context, m Membership) error
+}
+
+// ReadModel combines both sides of the memberships read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// Repository implements ReadModel on top of MySQL.
+type Repository struct {
+	db          *sql.DB
+	memberships string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, memberships: membershipsTable}
+}
+
+// HouseholdsOf returns the IDs of userID's households in the order they
+// joined.
+func (r *Repository) HouseholdsOf(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT household_id FROM %s WHERE user_id = ? ORDER BY event_id`, r.memberships), userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan membership: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate memberships: %w", err)
+	}
+	return ids, nil
+}
+
+// AddMember stores m unless it is stored.
+func (r *Repository) AddMember(ctx context.Context, m Membership) error {
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (household_id, user_id, event_id) VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE household_id = household_id`, r.memberships),
+		m.HouseholdID, m.UserID, m.EventID,
+	); err != nil {
+		return fmt.Errorf("add member: %w", err)
+	}
+	return nil
+}
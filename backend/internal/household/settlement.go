@@ -0,0 +1,49 @@
+package household
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// AggregateType is the aggregate type of household events, for readers of
+// the event log that only want households.
+const AggregateType = aggregateType
+
+// Settlement is a SettlementRecorded event decoded for read models kept
+// outside this package.
+type Settlement struct {
+	HouseholdID string
+	// Version is the household's version after the settlement, which
+	// tells settlements in a household apart.
+	Version int
+	From    string
+	To      string
+	Amount  int64
+	Date    string
+}
+
+// DecodeSettlement decodes a SettlementRecorded event. ok is false for
+// every other event.
+func DecodeSettlement(event eventstore.Event) (settlement Settlement, ok bool, err error) {
+	if event.AggregateType != aggregateType || event.EventType != eventTypeSettled {
+		return Settlement{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return Settlement{}, false, err
+	}
+
+	var payload SettlementRecordedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return Settlement{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+	}
+	return Settlement{
+		HouseholdID: event.AggregateID,
+		Version:     event.Version,
+		From:        payload.From,
+		To:          payload.To,
+		Amount:      payload.Amount,
+		Date:        payload.Date,
+	}, true, nil
+}
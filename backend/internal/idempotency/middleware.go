@@ -0,0 +1,181 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// KeyHeader carries the client-chosen idempotency key.
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	// maxKeyLength is the length of the idempotency_keys.idem_key column.
+	maxKeyLength = 255
+	// maxBodyBytes bounds the request body read for hashing.
+	maxBodyBytes = 1 << 20
+)
+
+// Middleware makes unsafe requests that carry an Idempotency-Key header
+// idempotent for ttl.
+//
+// The first request with a key runs normally and its response is stored,
+// unless it failed with a 5xx status, in which case the key is released so a
+// retry runs the request again. A later request with the same key gets the
+// stored response if it is the same request (method, path and body), 422 if
+// it is a different one, and 409 while the first is still in flight.
+//
+// A key that has been in flight for staleAfter is presumed abandoned by a
+// crash and handed to a retry. staleAfter must exceed the longest a request
+// can run, such as the server's write timeout plus a margin, or a slow
+// request runs twice.
+//
+// scope, if not nil, names the key space of a request, such as its user,
+// so clients in different scopes cannot collide on or replay each other's
+// keys. Scoped keys are stored hashed with their scope.
+//
+// Requests without the header, and GET, HEAD and OPTIONS requests, pass
+// through untouched.
+func Middleware(store Store, ttl, staleAfter time.Duration, scope func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(KeyHeader)
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				writeError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+			if scope != nil {
+				key = scopedKey(scope(r), key)
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+					return
+				}
+				writeError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now().UTC()
+			existing, reserved, err := store.Reserve(r.Context(), Record{
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}, now.Add(-staleAfter))
+			if err != nil {
+				log.Printf("reserve idempotency key: %v", err)
+				writeError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			if !reserved {
+				replay(w, r, body, existing)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// The request's context may be cancelled once the handler returns,
+			// but the outcome must be recorded regardless.
+			ctx := context.WithoutCancel(r.Context())
+			if rec.status >= http.StatusInternalServerError {
+				if err := store.Release(ctx, key); err != nil {
+					log.Printf("release idempotency key: %v", err)
+				}
+				return
+			}
+			if err := store.Complete(ctx, key, Response{
+				Status:      rec.status,
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			}); err != nil {
+				log.Printf("complete idempotency key: %v", err)
+			}
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, body []byte, existing Record) {
+	switch {
+	case existing.RequestHash != requestHash(r, body):
+		writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+	case existing.Response == nil:
+		writeError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+	default:
+		if existing.Response.ContentType != "" {
+			w.Header().Set("Content-Type", existing.Response.ContentType)
+		}
+		w.Header().Set(ReplayedHeader, "true")
+		w.WriteHeader(existing.Response.Status)
+		if _, err := w.Write(existing.Response.Body); err != nil {
+			log.Printf("failed to write response: %v", err)
+		}
+	}
+}
+
+// scopedKey derives the stored key for key within scope. The hash keeps it
+// within maxKeyLength however long the scope is.
+func scopedKey(scope, key string) string {
+	h := sha256.Sum256([]byte(scope + "\x00" + key))
+	return hex.EncodeToString(h[:])
+}
+
+// requestHash identifies a request by method, path and body, so a key
+// reused for another endpoint counts as a different request.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder passes a response through while keeping a copy to store.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": msg}); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
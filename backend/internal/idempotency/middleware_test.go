@@ -0,0 +1,210 @@
+package idempotency_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/idempotency"
+)
+
+// countingHandler answers 201 with a body naming the call number, or the
+// status in the "status" query parameter.
+type countingHandler struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.calls.Add(1)
+	if h.release != nil {
+		<-h.release
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Query().Get("status") {
+	case "500":
+		w.WriteHeader(http.StatusInternalServerError)
+	case "400":
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+	fmt.Fprintf(w, `{"call":%d}`, n)
+}
+
+func do(t *testing.T, h http.Handler, method, target, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.KeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      [3]string // method, target, body
+		retry      [3]string
+		wantStatus int
+		wantBody   string
+		wantCalls  int32
+	}{
+		{"replays the stored response",
+			[3]string{"POST", "/expenses", `{"a":1}`}, [3]string{"POST", "/expenses", `{"a":1}`},
+			http.StatusCreated, `{"call":1}`, 1},
+		{"replays client errors",
+			[3]string{"POST", "/expenses?status=400", `{}`}, [3]string{"POST", "/expenses?status=400", `{}`},
+			http.StatusBadRequest, `{"call":1}`, 1},
+		{"rejects a different body",
+			[3]string{"POST", "/expenses", `{"a":1}`}, [3]string{"POST", "/expenses", `{"a":2}`},
+			http.StatusUnprocessableEntity, "", 1},
+		{"rejects a different path",
+			[3]string{"POST", "/expenses", `{}`}, [3]string{"PATCH", "/expenses/1", `{}`},
+			http.StatusUnprocessableEntity, "", 1},
+		{"runs again after a server error",
+			[3]string{"POST", "/expenses?status=500", `{}`}, [3]string{"POST", "/expenses", `{}`},
+			http.StatusCreated, `{"call":2}`, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &countingHandler{}
+			mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Minute, nil)(h)
+
+			do(t, mw, tt.first[0], tt.first[1], "key-1", tt.first[2])
+			rec := do(t, mw, tt.retry[0], tt.retry[1], "key-1", tt.retry[2])
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", rec.Body, tt.wantBody)
+			}
+			if got := h.calls.Load(); got != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ReplayHeaders(t *testing.T) {
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Minute, nil)(&countingHandler{})
+
+	first := do(t, mw, "POST", "/expenses", "key-1", `{}`)
+	if first.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Error("first response is marked as replayed")
+	}
+	replayed := do(t, mw, "POST", "/expenses", "key-1", `{}`)
+	if replayed.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Error("replayed response is not marked as replayed")
+	}
+	if got := replayed.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestMiddleware_WithoutKey(t *testing.T) {
+	h := &countingHandler{}
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Minute, nil)(h)
+
+	do(t, mw, "POST", "/expenses", "", `{}`)
+	do(t, mw, "POST", "/expenses", "", `{}`)
+	do(t, mw, "GET", "/expenses", "key-1", "")
+	do(t, mw, "GET", "/expenses", "key-1", "")
+
+	if got := h.calls.Load(); got != 4 {
+		t.Errorf("handler called %d times, want 4", got)
+	}
+}
+
+func TestMiddleware_InFlight(t *testing.T) {
+	h := &countingHandler{release: make(chan struct{})}
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Minute, nil)(h)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do(t, mw, "POST", "/expenses", "key-1", `{}`)
+	}()
+	for h.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if rec := do(t, mw, "POST", "/expenses", "key-1", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("status while in flight = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	close(h.release)
+	<-done
+}
+
+func TestMiddleware_StaleInFlight(t *testing.T) {
+	h := &countingHandler{release: make(chan struct{})}
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Nanosecond, nil)(h)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do(t, mw, "POST", "/expenses", "key-1", `{}`)
+		}()
+		time.Sleep(time.Millisecond)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for h.calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(h.release)
+	wg.Wait()
+
+	if got := h.calls.Load(); got != 2 {
+		t.Errorf("handler called %d times, want a retry to take over the stale key", got)
+	}
+}
+
+func TestMiddleware_Expiry(t *testing.T) {
+	h := &countingHandler{}
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Nanosecond, time.Minute, nil)(h)
+
+	do(t, mw, "POST", "/expenses", "key-1", `{"a":1}`)
+	time.Sleep(time.Millisecond)
+	rec := do(t, mw, "POST", "/expenses", "key-1", `{"a":2}`)
+
+	if rec.Code != http.StatusCreated || h.calls.Load() != 2 {
+		t.Errorf("after expiry: status %d after %d calls, want a fresh 201", rec.Code, h.calls.Load())
+	}
+}
+
+func TestMiddleware_Scope(t *testing.T) {
+	h := &countingHandler{}
+	scope := func(r *http.Request) string { return r.Header.Get("X-User") }
+	mw := idempotency.Middleware(idempotency.NewMemoryStore(), time.Hour, time.Minute, scope)(h)
+
+	doAs := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/expenses", strings.NewReader(`{}`))
+		req.Header.Set(idempotency.KeyHeader, "key-1")
+		req.Header.Set("X-User", user)
+		rec := httptest.NewRecorder()
+		mw.ServeHTTP(rec, req)
+		return rec
+	}
+
+	doAs("alice")
+	if rec := doAs("bob"); rec.Header().Get(idempotency.ReplayedHeader) != "" {
+		t.Error("bob was replayed alice's response")
+	}
+	if rec := doAs("alice"); rec.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Error("alice's retry was not replayed")
+	}
+	if got := h.calls.Load(); got != 2 {
+		t.Errorf("handler called %d times, want 2", got)
+	}
+}
@@ -0,0 +1,257 @@
+// Package idempotency lets clients retry unsafe requests without repeating
+// their effect, by replaying the stored response of an earlier request that
+// carried the same Idempotency-Key.
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDuplicateEntryCode is the MySQL error code for duplicate key violations.
+const mysqlDuplicateEntryCode = 1062
+
+// Record is what is stored for an idempotency key.
+type Record struct {
+	Key string
+	// RequestHash identifies the request the key was first used with.
+	RequestHash string
+	// Response is nil while the first request is still being handled.
+	Response  *Response
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Response is a stored response to replay.
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Store persists idempotency records.
+type Store interface {
+	// Reserve stores rec, whose Response must be nil, unless the key is
+	// already taken. A key is taken by a record that has not expired as of
+	// rec.CreatedAt, except an in-flight record created before staleBefore,
+	// which is presumed abandoned by a crashed request. If the key is taken,
+	// Reserve returns the existing record and reserved is false.
+	Reserve(ctx context.Context, rec Record, staleBefore time.Time) (existing Record, reserved bool, err error)
+
+	// Complete stores the response for a reserved key.
+	Complete(ctx context.Context, key string, resp Response) error
+
+	// Release deletes a reserved key, so a retry runs the request again.
+	Release(ctx context.Context, key string) error
+
+	// DeleteExpired deletes records that expired as of now and returns how
+	// many were deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// MySQLStore implements Store backed by the idempotency_keys table.
+type MySQLStore struct {
+	db *sql.DB
+}
+
+// NewMySQLStore creates a new MySQLStore.
+func NewMySQLStore(db *sql.DB) *MySQLStore {
+	return &MySQLStore{db: db}
+}
+
+// Reserve stores rec unless the key is taken.
+func (s *MySQLStore) Reserve(ctx context.Context, rec Record, staleBefore time.Time) (Record, bool, error) {
+	// The existing record can disappear between a failed insert and reading
+	// it, if its request fails and releases the key; then try again.
+	for range 3 {
+		existing, reserved, err := s.reserve(ctx, rec, staleBefore)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return existing, reserved, err
+		}
+	}
+	return Record{}, false, fmt.Errorf("reserve idempotency key %q: key keeps being released", rec.Key)
+}
+
+func (s *MySQLStore) reserve(ctx context.Context, rec Record, staleBefore time.Time) (Record, bool, error) {
+	// Free the key first if its record no longer counts. If a concurrent
+	// request reserves it in between, the insert below fails and that
+	// request's record is returned.
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys
+		 WHERE idem_key = ? AND (expires_at <= ? OR (status = 0 AND created_at < ?))`,
+		rec.Key, rec.CreatedAt, staleBefore,
+	); err != nil {
+		return Record{}, false, fmt.Errorf("delete stale idempotency key: %w", err)
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (idem_key, request_hash, created_at, expires_at)
+		 VALUES (?, ?, ?, ?)`,
+		rec.Key, rec.RequestHash, rec.CreatedAt, rec.ExpiresAt,
+	)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntryCode {
+		existing, err := s.load(ctx, rec.Key)
+		return existing, false, err
+	}
+	if err != nil {
+		return Record{}, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	return rec, true, nil
+}
+
+func (s *MySQLStore) load(ctx context.Context, key string) (Record, error) {
+	rec := Record{Key: key}
+	var (
+		status      int
+		contentType string
+		body        []byte
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT request_hash, status, content_type, response_body, created_at, expires_at
+		 FROM idempotency_keys WHERE idem_key = ?`, key,
+	).Scan(&rec.RequestHash, &status, &contentType, &body, &rec.CreatedAt, &rec.ExpiresAt)
+	if err != nil {
+		return Record{}, fmt.Errorf("load idempotency key: %w", err)
+	}
+	if status != 0 {
+		rec.Response = &Response{Status: status, ContentType: contentType, Body: body}
+	}
+	return rec, nil
+}
+
+// Complete stores the response for a reserved key.
+func (s *MySQLStore) Complete(ctx context.Context, key string, resp Response) error {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET status = ?, content_type = ?, response_body = ?
+		 WHERE idem_key = ?`,
+		resp.Status, resp.ContentType, resp.Body, key,
+	); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes a reserved key.
+func (s *MySQLStore) Release(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE idem_key = ? AND status = 0`, key,
+	); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes records that expired as of now.
+func (s *MySQLStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// MemoryStore implements Store in memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Reserve stores rec unless the key is taken.
+func (s *MemoryStore) Reserve(_ context.Context, rec Record, staleBefore time.Time) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[rec.Key]
+	stale := existing.Response == nil && existing.CreatedAt.Before(staleBefore)
+	if ok && existing.ExpiresAt.After(rec.CreatedAt) && !stale {
+		return cloneRecord(existing), false, nil
+	}
+	s.records[rec.Key] = rec
+	return rec, true, nil
+}
+
+// Complete stores the response for a reserved key.
+func (s *MemoryStore) Complete(_ context.Context, key string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	resp.Body = slices.Clone(resp.Body)
+	rec.Response = &resp
+	s.records[key] = rec
+	return nil
+}
+
+// Release deletes a reserved key.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[key]; ok && rec.Response == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// DeleteExpired deletes records that expired as of now.
+func (s *MemoryStore) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func cloneRecord(rec Record) Record {
+	if rec.Response != nil {
+		resp := *rec.Response
+		resp.Body = slices.Clone(resp.Body)
+		rec.Response = &resp
+	}
+	return rec
+}
+
+// RunCleaner deletes expired records every interval until ctx is cancelled.
+// Expired records are ignored anyway; this only keeps the store small.
+func RunCleaner(ctx context.Context, store Store, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if _, err := store.DeleteExpired(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			log.Printf("idempotency: delete expired keys: %v", err)
+		}
+	}
+}
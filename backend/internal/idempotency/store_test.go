@@ -0,0 +1,123 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/idempotency"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+var stores = []struct {
+	name string
+	open func(t *testing.T) idempotency.Store
+}{
+	{"memory", func(*testing.T) idempotency.Store { return idempotency.NewMemoryStore() }},
+	{"mysql", func(t *testing.T) idempotency.Store {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		return idempotency.NewMySQLStore(db)
+	}},
+}
+
+var t0 = time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+
+func record(key, hash string, at time.Time) idempotency.Record {
+	return idempotency.Record{Key: key, RequestHash: hash, CreatedAt: at, ExpiresAt: at.Add(time.Hour)}
+}
+
+func TestStore_Lifecycle(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.open(t)
+			ctx := context.Background()
+			stale := t0.Add(-time.Minute)
+
+			if _, reserved, err := store.Reserve(ctx, record("k", "h1", t0), stale); err != nil || !reserved {
+				t.Fatalf("first reserve: reserved = %v, err = %v", reserved, err)
+			}
+
+			existing, reserved, err := store.Reserve(ctx, record("k", "h2", t0.Add(time.Second)), stale)
+			if err != nil || reserved {
+				t.Fatalf("second reserve: reserved = %v, err = %v", reserved, err)
+			}
+			if existing.RequestHash != "h1" || existing.Response != nil {
+				t.Errorf("existing = %+v, want the in-flight h1 record", existing)
+			}
+
+			resp := idempotency.Response{Status: 201, ContentType: "application/json", Body: []byte(`{"id":"x"}`)}
+			if err := store.Complete(ctx, "k", resp); err != nil {
+				t.Fatalf("complete: %v", err)
+			}
+			existing, _, err = store.Reserve(ctx, record("k", "h1", t0.Add(2*time.Second)), stale)
+			if err != nil {
+				t.Fatalf("reserve after complete: %v", err)
+			}
+			if existing.Response == nil || existing.Response.Status != 201 || string(existing.Response.Body) != `{"id":"x"}` {
+				t.Errorf("existing response = %+v, want the completed response", existing.Response)
+			}
+
+			// Completed keys are not released.
+			if err := store.Release(ctx, "k"); err != nil {
+				t.Fatalf("release: %v", err)
+			}
+			if _, reserved, _ := store.Reserve(ctx, record("k", "h1", t0.Add(3*time.Second)), stale); reserved {
+				t.Error("completed key was released")
+			}
+		})
+	}
+}
+
+func TestStore_ReleaseAndStale(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.open(t)
+			ctx := context.Background()
+
+			store.Reserve(ctx, record("released", "h", t0), t0.Add(-time.Minute))
+			if err := store.Release(ctx, "released"); err != nil {
+				t.Fatalf("release: %v", err)
+			}
+			if _, reserved, _ := store.Reserve(ctx, record("released", "h", t0), t0.Add(-time.Minute)); !reserved {
+				t.Error("released key could not be reserved again")
+			}
+
+			store.Reserve(ctx, record("abandoned", "h", t0), t0.Add(-time.Minute))
+			later := t0.Add(2 * time.Minute)
+			if _, reserved, _ := store.Reserve(ctx, record("abandoned", "h", later), later.Add(-time.Minute)); !reserved {
+				t.Error("stale in-flight key could not be taken over")
+			}
+		})
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.open(t)
+			ctx := context.Background()
+
+			store.Reserve(ctx, record("old", "h1", t0), t0.Add(-time.Minute))
+			store.Complete(ctx, "old", idempotency.Response{Status: 201})
+			store.Reserve(ctx, record("new", "h1", t0.Add(30*time.Minute)), t0)
+			store.Complete(ctx, "new", idempotency.Response{Status: 201})
+
+			expiredAt := t0.Add(time.Hour)
+			if _, reserved, _ := store.Reserve(ctx, record("old", "h2", expiredAt), expiredAt.Add(-time.Minute)); !reserved {
+				t.Error("expired key could not be reused")
+			}
+
+			n, err := store.DeleteExpired(ctx, t0.Add(95*time.Minute))
+			if err != nil {
+				t.Fatalf("delete expired: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("deleted %d records, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package income
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// AggregateType is the aggregate type of income events, for readers of the
+// event log that only want income.
+const AggregateType = aggregateType
+
+// Change is an income event decoded for read models and readers kept
+// outside this package, so they need not know the event types and payloads.
+type Change struct {
+	ID      string
+	Version int
+	// Voided is set for IncomeVoided, whose change carries no fields.
+	Voided bool
+	// The income's fields after the change, for IncomeRecorded and
+	// IncomeCorrected. A correction carries every field, changed or not.
+	Amount int64
+	Source Source
+	Memo   string
+	Date   string
+	// AccountID is the account the income was paid into, if any.
+	AccountID string
+
+	OccurredAt time.Time
+	// RecordedBy is the user that made the change.
+	RecordedBy string
+	// Book is the book the income belongs to, set on the change that
+	// recorded it.
+	Book string
+}
+
+// Recorded reports whether the change recorded the income.
+func (c Change) Recorded() bool {
+	return c.Version == 1
+}
+
+// DecodeChange decodes an income event, which must have been read through a
+// store wrapped with RegisterUpcasters. ok is false for events of other
+// aggregate types.
+func DecodeChange(event eventstore.Event) (change Change, ok bool, err error) {
+	if event.AggregateType != aggregateType {
+		return Change{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return Change{}, false, err
+	}
+
+	change = Change{
+		ID:         event.AggregateID,
+		Version:    event.Version,
+		OccurredAt: event.OccurredAt,
+		RecordedBy: event.RecordedBy,
+	}
+	var payload IncomeCorrectedPayload
+	switch event.EventType {
+	case eventTypeRecorded:
+		var recorded IncomeRecordedPayload
+		if err := json.Unmarshal(event.Payload, &recorded); err != nil {
+			return Change{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		change.Book = recorded.BookID
+		payload = IncomeCorrectedPayload{
+			Amount:    recorded.Amount,
+			Source:    recorded.Source,
+			Memo:      recorded.Memo,
+			Date:      recorded.Date,
+			AccountID: recorded.AccountID,
+		}
+	case eventTypeCorrected:
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return Change{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+	case eventTypeVoided:
+		change.Voided = true
+		return change, true, nil
+	default:
+		return Change{}, false, fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	change.Amount = payload.Amount
+	change.Source = payload.Source
+	change.Memo = payload.Memo
+	change.Date = payload.Date
+	change.AccountID = payload.AccountID
+	return change, true, nil
+}
@@ -0,0 +1,311 @@
+package income
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// Handler handles HTTP requests for the income domain.
+// Commands only append events; the read model is updated asynchronously by
+// Projector running under a projection.Runner.
+//
+// Every route must be behind middleware.Authenticate. Books are shared the
+// same way as for expenses: users only see the income of books they may
+// read, and only change that of books they may write.
+type Handler struct {
+	store    eventstore.Store
+	repo     Reader
+	books    expense.Books
+	accounts expense.Accounts
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store, repo Reader, books expense.Books, accounts expense.Accounts) *Handler {
+	return &Handler{
+		store:    store,
+		repo:     repo,
+		books:    books,
+		accounts: accounts,
+	}
+}
+
+// Register adds income routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /incomes", h.RecordIncome)
+	mux.HandleFunc("GET /incomes", h.ListIncomes)
+	mux.HandleFunc("GET /incomes/{id}", h.GetIncome)
+	mux.HandleFunc("PATCH /incomes/{id}", h.CorrectIncome)
+	mux.HandleFunc("DELETE /incomes/{id}", h.VoidIncome)
+}
+
+type recordIncomeResponse struct {
+	ID string `json:"id"`
+}
+
+// RecordIncome handles POST /incomes. The income goes in the user's
+// personal book unless the body names another book_id.
+func (h *Handler) RecordIncome(w http.ResponseWriter, r *http.Request) {
+	var cmd RecordIncomeCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	ctx := r.Context()
+	if cmd.BookID == "" {
+		cmd.BookID = middleware.UserID(ctx)
+	}
+	id := uuid.New().String()
+	event, err := RecordIncome(id, cmd)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	role, err := h.bookRole(ctx, cmd.BookID)
+	if err == nil && !role.CanWrite() {
+		err = ErrForbidden
+	}
+	if err == nil {
+		err = h.checkAccount(ctx, cmd.BookID, cmd.AccountID)
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, 0); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, recordIncomeResponse{ID: id})
+}
+
+type correctIncomeResponse struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+// CorrectIncome handles PATCH /incomes/{id}.
+func (h *Handler) CorrectIncome(w http.ResponseWriter, r *http.Request) {
+	var cmd CorrectIncomeCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := cmd.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	ctx := r.Context()
+	i, err := h.loadWritableIncome(ctx, r.PathValue("id"))
+	if err == nil && cmd.AccountID != nil {
+		err = h.checkAccount(ctx, i.Book, *cmd.AccountID)
+	}
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := i.Correct(cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, i.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, correctIncomeResponse{ID: i.ID, Version: event.Version})
+}
+
+// VoidIncome handles DELETE /incomes/{id}.
+// The request body is optional and may carry a reason.
+func (h *Handler) VoidIncome(w http.ResponseWriter, r *http.Request) {
+	var cmd VoidIncomeCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	ctx := r.Context()
+	i, err := h.loadWritableIncome(ctx, r.PathValue("id"))
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	event, err := i.Void(cmd)
+	if err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	if err := h.store.Append(ctx, []eventstore.Event{middleware.Stamp(ctx, event)}, i.Version); err != nil {
+		writeCommandError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadWritableIncome loads income in a book the request's user may write.
+// It returns ErrNotFound if they may not read the book either.
+func (h *Handler) loadWritableIncome(ctx context.Context, id string) (*Income, error) {
+	events, err := h.store.Load(ctx, aggregateType, id)
+	if err != nil {
+		return nil, fmt.Errorf("load events: %w", err)
+	}
+	i, err := LoadIncome(events)
+	if err != nil {
+		return nil, err
+	}
+	role, err := h.bookRole(ctx, i.Book)
+	if errors.Is(err, ErrBookNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !role.CanWrite() {
+		return nil, ErrForbidden
+	}
+	return i, nil
+}
+
+// checkAccount checks that accountID, which may be empty, is an account in
+// bookID. Income are always in yen, so the account must be too.
+func (h *Handler) checkAccount(ctx context.Context, bookID, accountID string) error {
+	if accountID == "" {
+		return nil
+	}
+	acc, err := h.accounts.Load(ctx, accountID)
+	if errors.Is(err, account.ErrNotFound) || err == nil && acc.Book != bookID {
+		return fmt.Errorf("%w: %s is not an account of the book", ErrInvalidAccount, accountID)
+	}
+	if err != nil {
+		return fmt.Errorf("look up account %s: %w", accountID, err)
+	}
+	if acc.Currency != account.DefaultCurrency {
+		return fmt.Errorf("%w: accounts must have the same currency, but %s is in %s", ErrInvalidAccount, accountID, acc.Currency)
+	}
+	return nil
+}
+
+// bookRole returns the request user's role in bookID, or ErrBookNotFound
+// if they have none.
+func (h *Handler) bookRole(ctx context.Context, bookID string) (household.Role, error) {
+	role, err := h.books.Role(ctx, middleware.UserID(ctx), bookID)
+	if errors.Is(err, household.ErrNotFound) {
+		return "", ErrBookNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up role in book %s: %w", bookID, err)
+	}
+	return role, nil
+}
+
+// writeCommandError maps domain errors from command handling to HTTP responses.
+func writeCommandError(w http.ResponseWriter, err error) {
+	var conflict *eventstore.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"error":           "income was modified concurrently; reload and retry",
+			"current_version": conflict.Actual,
+		})
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrBookNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrForbidden):
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrVoided):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrNoChanges), errors.Is(err, ErrInvalidAccount):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("income command: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+// ListIncomes handles GET /incomes. It accepts book, which defaults to the
+// user's personal book, and from and to, inclusive dates as YYYY-MM-DD.
+func (h *Handler) ListIncomes(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	q := ListQuery{BookID: v.Get("book"), From: v.Get("from"), To: v.Get("to")}
+	for _, date := range []string{q.From, q.To} {
+		if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "from and to must be in YYYY-MM-DD format"})
+			return
+		}
+	}
+
+	ctx := r.Context()
+	if q.BookID == "" {
+		q.BookID = middleware.UserID(ctx)
+	}
+	if _, err := h.bookRole(ctx, q.BookID); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	incomes, err := h.repo.List(ctx, q)
+	if err != nil {
+		writeQueryError(w, fmt.Errorf("list incomes: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, incomes)
+}
+
+// GetIncome handles GET /incomes/{id}. It reads the read model, so income
+// that was just recorded can be missing for a moment.
+func (h *Handler) GetIncome(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	i, err := h.repo.Get(ctx, r.PathValue("id"))
+	if err == nil {
+		_, err = h.bookRole(ctx, i.BookID)
+	}
+	if errors.Is(err, ErrBookNotFound) {
+		err = ErrNotFound
+	}
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, i)
+}
+
+// writeQueryError maps errors from reads to HTTP responses.
+func writeQueryError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrBookNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	log.Printf("income query: %v", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
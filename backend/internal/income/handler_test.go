@@ -0,0 +1,283 @@
+package income_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+type backend struct {
+	name string
+	open func(t *testing.T) (eventstore.Store, projection.CheckpointStore, income.ReadModel)
+}
+
+// backends lists every storage the handler tests run against. The MySQL
+// backend is skipped unless TEST_DATABASE_URL is set.
+var backends = []backend{
+	{"memory", func(*testing.T) (eventstore.Store, projection.CheckpointStore, income.ReadModel) {
+		return eventstore.NewMemoryStore(), projection.NewMemoryCheckpointStore(), income.NewMemoryRepository()
+	}},
+	{"mysql", func(t *testing.T) (eventstore.Store, projection.CheckpointStore, income.ReadModel) {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		return eventstore.NewMySQLStore(db), projection.NewMySQLCheckpointStore(db), income.NewRepository(db)
+	}},
+}
+
+// forEachBackend runs fn once per backend with the income routes and the
+// projection runner running in the background. catchUp blocks until the
+// read model reflects every appended event; readModel is handed over for
+// the queries the routes do not expose.
+func forEachBackend(t *testing.T, fn func(t *testing.T, srvURL string, readModel income.Reader, catchUp func())) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			store, checkpoints, readModel := b.open(t)
+
+			projector := income.NewProjector(readModel)
+			startRunner(t, projection.NewRunner(store, checkpoints, projector))
+
+			mux := http.NewServeMux()
+			income.NewHandler(store, readModel, testBooks, testAccounts).Register(mux)
+			srv := httptest.NewServer(testhelper.AsUser(mux))
+			t.Cleanup(srv.Close)
+
+			catchUp := func() {
+				t.Helper()
+				deadline := time.Now().Add(5 * time.Second)
+				for {
+					pos, err := checkpoints.Load(context.Background(), projector.Name())
+					if err != nil {
+						t.Fatalf("load checkpoint: %v", err)
+					}
+					pending, err := store.ReadAll(context.Background(), pos, 1)
+					if err != nil {
+						t.Fatalf("read events: %v", err)
+					}
+					if len(pending) == 0 {
+						return
+					}
+					if time.Now().After(deadline) {
+						t.Fatal("timed out waiting for the projection")
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+			fn(t, srv.URL, readModel, catchUp)
+		})
+	}
+}
+
+// sharedBook is a household book in testBooks.
+const sharedBook = "household-1"
+
+// testBooks gives every user their personal book, and alice and bob the
+// roles of owner and viewer in sharedBook.
+var testBooks = fakeBooks{
+	sharedBook: {"alice": household.RoleOwner, "bob": household.RoleViewer},
+}
+
+type fakeBooks map[string]map[string]household.Role
+
+func (b fakeBooks) Role(_ context.Context, userID, bookID string) (household.Role, error) {
+	if bookID == userID {
+		return household.RoleOwner, nil
+	}
+	role, ok := b[bookID][userID]
+	if !ok {
+		return "", household.ErrNotFound
+	}
+	return role, nil
+}
+
+// testAccounts holds the accounts tests may name.
+var testAccounts = fakeAccounts{
+	"wallet":  {ID: "wallet", Book: "alice", Currency: "JPY"},
+	"joint":   {ID: "joint", Book: sharedBook, Currency: "JPY"},
+	"dollars": {ID: "dollars", Book: "alice", Currency: "USD"},
+}
+
+type fakeAccounts map[string]*account.Account
+
+func (a fakeAccounts) Load(_ context.Context, accountID string) (*account.Account, error) {
+	acc, ok := a[accountID]
+	if !ok {
+		return nil, account.ErrNotFound
+	}
+	return acc, nil
+}
+
+func startRunner(t *testing.T, runner *projection.Runner) {
+	t.Helper()
+
+	runner.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// doRequestAs makes a request as userID, or as alice if it is empty.
+func doRequestAs(t *testing.T, userID, method, url, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set(testhelper.UserHeader, userID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+func recordViaAPI(t *testing.T, srvURL, body string) string {
+	t.Helper()
+
+	resp := doRequestAs(t, "", http.MethodPost, srvURL+"/incomes", body)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /incomes status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return created.ID
+}
+
+func listViaAPI(t *testing.T, srvURL, query string) []income.IncomeRow {
+	t.Helper()
+
+	resp := doRequestAs(t, "", http.MethodGet, srvURL+"/incomes?"+query, "")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /incomes?%s status = %d, want %d", query, resp.StatusCode, http.StatusOK)
+	}
+
+	var incomes []income.IncomeRow
+	if err := json.NewDecoder(resp.Body).Decode(&incomes); err != nil {
+		t.Fatalf("decode incomes: %v", err)
+	}
+	return incomes
+}
+
+func TestIncomes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, srvURL string, readModel income.Reader, catchUp func()) {
+		salary := recordViaAPI(t, srvURL, `{"amount":300000,"source":"salary","memo":"2月分","date":"2026-02-25"}`)
+		refund := recordViaAPI(t, srvURL, `{"amount":1200,"source":"refund","date":"2026-03-02"}`)
+		side := recordViaAPI(t, srvURL, `{"amount":5000,"source":"side","date":"2026-03-02"}`)
+		voided := recordViaAPI(t, srvURL, `{"amount":9999,"source":"bonus","date":"2026-03-05"}`)
+		for _, req := range []struct{ method, path, body string }{
+			{http.MethodPatch, "/incomes/" + refund, `{"amount":1500}`},
+			{http.MethodDelete, "/incomes/" + voided, ""},
+		} {
+			resp := doRequestAs(t, "", req.method, srvURL+req.path, req.body)
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				t.Fatalf("%s %s status = %d", req.method, req.path, resp.StatusCode)
+			}
+		}
+		catchUp()
+
+		got := listViaAPI(t, srvURL, "")
+		var ids []string
+		for _, row := range got {
+			ids = append(ids, row.ID)
+		}
+		if want := []string{side, refund, salary}; strings.Join(ids, ",") != strings.Join(want, ",") {
+			t.Errorf("listed = %v, want %v", ids, want)
+		}
+		if len(got) == 3 && (got[1].Amount != 1500 || got[1].Version != 2 || got[2].Memo != "2月分" || got[2].BookID != "alice") {
+			t.Errorf("listed rows = %+v", got)
+		}
+		if got := listViaAPI(t, srvURL, "from=2026-03-01&to=2026-03-31"); len(got) != 2 {
+			t.Errorf("March income = %+v, want 2 rows", got)
+		}
+
+		totals, err := readModel.DailyTotals(context.Background(), "alice", "2026-03-01", "2026-03-31")
+		if err != nil {
+			t.Fatalf("DailyTotals: %v", err)
+		}
+		want := []income.DailyTotal{
+			{Date: "2026-03-02", Source: income.SourceRefund, Total: 1500, Count: 1},
+			{Date: "2026-03-02", Source: income.SourceSide, Total: 5000, Count: 1},
+		}
+		if len(totals) != len(want) || totals[0] != want[0] || totals[1] != want[1] {
+			t.Errorf("DailyTotals = %+v, want %+v", totals, want)
+		}
+
+		resp := doRequestAs(t, "", http.MethodGet, srvURL+"/incomes/"+voided, "")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET voided income status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+	})
+}
+
+func TestIncomes_Errors(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, srvURL string, _ income.Reader, catchUp func()) {
+		id := recordViaAPI(t, srvURL, `{"book_id":"`+sharedBook+`","amount":300000,"source":"salary","date":"2026-02-25"}`)
+		catchUp()
+
+		for _, req := range []struct {
+			user, method, path, body string
+			want                     int
+		}{
+			{"alice", http.MethodPost, "/incomes", `{"amount":0,"source":"salary","date":"2026-02-25"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/incomes", `{"amount":1000,"source":"gift","date":"2026-02-25"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/incomes", `{invalid}`, http.StatusBadRequest},
+			{"alice", http.MethodGet, "/incomes?from=March", "", http.StatusBadRequest},
+			{"alice", http.MethodPatch, "/incomes/" + id, `{"source":"salary"}`, http.StatusBadRequest},
+			{"alice", http.MethodPatch, "/incomes/missing", `{"amount":1}`, http.StatusNotFound},
+			{"bob", http.MethodGet, "/incomes?book=" + sharedBook, "", http.StatusOK},
+			{"bob", http.MethodGet, "/incomes/" + id, "", http.StatusOK},
+			{"bob", http.MethodPatch, "/incomes/" + id, `{"amount":1}`, http.StatusForbidden},
+			{"alice", http.MethodPatch, "/incomes/" + id, `{"account_id":"joint"}`, http.StatusOK},
+			{"alice", http.MethodPatch, "/incomes/" + id, `{"account_id":"wallet"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/incomes", `{"amount":1000,"source":"side","date":"2026-02-25","account_id":"wallet"}`, http.StatusCreated},
+			{"alice", http.MethodPost, "/incomes", `{"amount":1000,"source":"side","date":"2026-02-25","account_id":"joint"}`, http.StatusBadRequest},
+			{"alice", http.MethodPost, "/incomes", `{"amount":1000,"source":"side","date":"2026-02-25","account_id":"dollars"}`, http.StatusBadRequest},
+			{"bob", http.MethodPost, "/incomes", `{"book_id":"` + sharedBook + `","amount":1,"source":"side","date":"2026-02-25"}`, http.StatusForbidden},
+			{"dave", http.MethodGet, "/incomes?book=" + sharedBook, "", http.StatusNotFound},
+			{"dave", http.MethodGet, "/incomes/" + id, "", http.StatusNotFound},
+			{"dave", http.MethodDelete, "/incomes/" + id, "", http.StatusNotFound},
+			{"alice", http.MethodDelete, "/incomes/" + id, "", http.StatusNoContent},
+			{"alice", http.MethodDelete, "/incomes/" + id, "", http.StatusConflict},
+		} {
+			resp := doRequestAs(t, req.user, req.method, srvURL+req.path, req.body)
+			resp.Body.Close()
+			if resp.StatusCode != req.want {
+				t.Errorf("%s: %s %s status = %d, want %d", req.user, req.method, req.path, resp.StatusCode, req.want)
+			}
+		}
+	})
+}
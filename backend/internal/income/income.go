@@ -0,0 +1,306 @@
+// Package income is the event-sourced income slice: salaries, bonuses, side
+// income and refunds recorded in a book, and the read model that lists and
+// totals them so summaries can set them against spending.
+package income
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+const aggregateType = "income"
+
+const (
+	eventTypeRecorded  = "IncomeRecorded"
+	eventTypeCorrected = "IncomeCorrected"
+	eventTypeVoided    = "IncomeVoided"
+)
+
+// schemaVersions holds the payload schema version of each income event.
+// Upcasters for older versions live in upcast.go.
+var schemaVersions = map[string]int{
+	eventTypeRecorded:  2,
+	eventTypeCorrected: 2,
+	eventTypeVoided:    1,
+}
+
+// Source is where income came from.
+type Source string
+
+const (
+	SourceSalary Source = "salary"
+	SourceBonus  Source = "bonus"
+	// SourceSide is income from side jobs.
+	SourceSide Source = "side"
+	// SourceRefund is money given back, such as a returned purchase.
+	SourceRefund Source = "refund"
+)
+
+func (s Source) valid() bool {
+	return s == SourceSalary || s == SourceBonus || s == SourceSide || s == SourceRefund
+}
+
+var (
+	// ErrNotFound is returned when an income has no events.
+	ErrNotFound = errors.New("income not found")
+	// ErrVoided is returned when a command targets a voided income.
+	ErrVoided = errors.New("income is voided")
+	// ErrNoChanges is returned when a correction would not change anything.
+	ErrNoChanges = errors.New("correction does not change the income")
+	// ErrBookNotFound is returned when the user has no role in a book.
+	ErrBookNotFound = errors.New("book not found")
+	// ErrForbidden is returned when the user's role in a book does not
+	// allow changing its income.
+	ErrForbidden = errors.New("your role in this book does not allow changes")
+	// ErrInvalidAccount is returned when income names an account that is
+	// not in its book.
+	ErrInvalidAccount = errors.New("invalid account")
+
+	errInvalidSource = fmt.Errorf("source must be %q, %q, %q or %q",
+		SourceSalary, SourceBonus, SourceSide, SourceRefund)
+)
+
+// RecordIncomeCommand holds the data needed to record new income.
+type RecordIncomeCommand struct {
+	// BookID is the book to record the income in. The handler fills in the
+	// recorder's personal book when it is empty.
+	BookID string `json:"book_id"`
+	Amount int64  `json:"amount"`
+	Source Source `json:"source"`
+	Memo   string `json:"memo"`
+	Date   string `json:"date"`
+	// AccountID is the account the income was paid into, which must be in
+	// the same book. Empty leaves it unassigned.
+	AccountID string `json:"account_id"`
+}
+
+// IncomeRecordedPayload is the event payload stored in the event store.
+// Schema v2 added AccountID.
+type IncomeRecordedPayload struct {
+	BookID    string `json:"book_id"`
+	Amount    int64  `json:"amount"`
+	Source    Source `json:"source"`
+	Memo      string `json:"memo"`
+	Date      string `json:"date"`
+	AccountID string `json:"account_id"`
+}
+
+// CorrectIncomeCommand holds the fields to change on existing income.
+// Nil fields are left as they are.
+type CorrectIncomeCommand struct {
+	Amount *int64  `json:"amount"`
+	Source *Source `json:"source"`
+	Memo   *string `json:"memo"`
+	Date   *string `json:"date"`
+	// AccountID replaces the account the income was paid into. An empty
+	// string unassigns it.
+	AccountID *string `json:"account_id"`
+}
+
+// IncomeCorrectedPayload carries the full state of the income after the
+// correction, so projections can apply it without knowing the prior state.
+// Schema v2 added AccountID.
+type IncomeCorrectedPayload struct {
+	Amount    int64  `json:"amount"`
+	Source    Source `json:"source"`
+	Memo      string `json:"memo"`
+	Date      string `json:"date"`
+	AccountID string `json:"account_id"`
+}
+
+// VoidIncomeCommand holds the data needed to void income.
+type VoidIncomeCommand struct {
+	Reason string `json:"reason"`
+}
+
+// IncomeVoidedPayload is the event payload for voided income.
+type IncomeVoidedPayload struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks that the command fields are valid.
+func (c RecordIncomeCommand) Validate() error {
+	var errs []error
+
+	if c.BookID == "" {
+		errs = append(errs, fmt.Errorf("book_id is required"))
+	}
+	if c.Amount <= 0 || c.Amount > expense.MaxAmount {
+		errs = append(errs, fmt.Errorf("amount must be 1 to %d", expense.MaxAmount))
+	}
+	if !c.Source.valid() {
+		errs = append(errs, errInvalidSource)
+	}
+	if _, err := time.Parse(time.DateOnly, c.Date); err != nil {
+		errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the fields present in the command are valid.
+func (c CorrectIncomeCommand) Validate() error {
+	var errs []error
+
+	if c.Amount == nil && c.Source == nil && c.Memo == nil && c.Date == nil && c.AccountID == nil {
+		errs = append(errs, fmt.Errorf("at least one field must be provided"))
+	}
+	if c.Amount != nil && (*c.Amount <= 0 || *c.Amount > expense.MaxAmount) {
+		errs = append(errs, fmt.Errorf("amount must be 1 to %d", expense.MaxAmount))
+	}
+	if c.Source != nil && !c.Source.valid() {
+		errs = append(errs, errInvalidSource)
+	}
+	if c.Date != nil {
+		if _, err := time.Parse(time.DateOnly, *c.Date); err != nil {
+			errs = append(errs, fmt.Errorf("date must be in YYYY-MM-DD format"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RecordIncome creates an event for recording new income.
+// This is a pure function that performs no I/O.
+func RecordIncome(id string, cmd RecordIncomeCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+	i := &Income{ID: id}
+	return i.newEvent(eventTypeRecorded, IncomeRecordedPayload(cmd))
+}
+
+// Income is the income aggregate, rebuilt from its event stream.
+type Income struct {
+	ID      string
+	Book    string
+	Amount  int64
+	Source  Source
+	Memo    string
+	Date    string
+	Voided  bool
+	Version int
+	// AccountID is the account the income was paid into, if any.
+	AccountID string
+}
+
+// LoadIncome rebuilds an Income from its events, which must be ordered by
+// version. Returns ErrNotFound when events is empty.
+func LoadIncome(events []eventstore.Event) (*Income, error) {
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	i := &Income{ID: events[0].AggregateID}
+	for _, event := range events {
+		if err := i.apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func (i *Income) apply(event eventstore.Event) error {
+	if event.Version != i.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, i.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeRecorded:
+		var payload IncomeRecordedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		i.Book = payload.BookID
+		i.Amount = payload.Amount
+		i.Source = payload.Source
+		i.Memo = payload.Memo
+		i.Date = payload.Date
+		i.AccountID = payload.AccountID
+	case eventTypeCorrected:
+		var payload IncomeCorrectedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		i.Amount = payload.Amount
+		i.Source = payload.Source
+		i.Memo = payload.Memo
+		i.Date = payload.Date
+		i.AccountID = payload.AccountID
+	case eventTypeVoided:
+		i.Voided = true
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	i.Version = event.Version
+	return nil
+}
+
+// Correct creates an IncomeCorrected event at the next version.
+// This is a pure function that performs no I/O and does not modify i.
+func (i *Income) Correct(cmd CorrectIncomeCommand) (eventstore.Event, error) {
+	if i.Voided {
+		return eventstore.Event{}, ErrVoided
+	}
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+
+	current := IncomeCorrectedPayload{Amount: i.Amount, Source: i.Source, Memo: i.Memo, Date: i.Date, AccountID: i.AccountID}
+	corrected := current
+	if cmd.Amount != nil {
+		corrected.Amount = *cmd.Amount
+	}
+	if cmd.Source != nil {
+		corrected.Source = *cmd.Source
+	}
+	if cmd.Memo != nil {
+		corrected.Memo = *cmd.Memo
+	}
+	if cmd.Date != nil {
+		corrected.Date = *cmd.Date
+	}
+	if cmd.AccountID != nil {
+		corrected.AccountID = *cmd.AccountID
+	}
+
+	if corrected == current {
+		return eventstore.Event{}, ErrNoChanges
+	}
+	return i.newEvent(eventTypeCorrected, corrected)
+}
+
+// Void creates an IncomeVoided event at the next version.
+// This is a pure function that performs no I/O and does not modify i.
+func (i *Income) Void(cmd VoidIncomeCommand) (eventstore.Event, error) {
+	if i.Voided {
+		return eventstore.Event{}, ErrVoided
+	}
+	return i.newEvent(eventTypeVoided, IncomeVoidedPayload(cmd))
+}
+
+func (i *Income) newEvent(eventType string, payload any) (eventstore.Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   i.ID,
+		AggregateType: aggregateType,
+		Version:       i.Version + 1,
+		EventType:     eventType,
+		Payload:       data,
+		Metadata:      eventstore.Metadata{SchemaVersion: schemaVersions[eventType]},
+	}, nil
+}
@@ -0,0 +1,166 @@
+package income
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+func recordedIncome(t *testing.T) *Income {
+	t.Helper()
+
+	event, err := RecordIncome("inc-1", RecordIncomeCommand{
+		BookID: "book-1",
+		Amount: 300000,
+		Source: SourceSalary,
+		Memo:   "2月分",
+		Date:   "2026-02-25",
+	})
+	if err != nil {
+		t.Fatalf("RecordIncome: %v", err)
+	}
+	i, err := LoadIncome([]eventstore.Event{event})
+	if err != nil {
+		t.Fatalf("LoadIncome: %v", err)
+	}
+	return i
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRecordIncome(t *testing.T) {
+	i := recordedIncome(t)
+
+	want := Income{ID: "inc-1", Book: "book-1", Amount: 300000, Source: SourceSalary, Memo: "2月分", Date: "2026-02-25", Version: 1}
+	if *i != want {
+		t.Errorf("income = %+v, want %+v", *i, want)
+	}
+}
+
+func TestRecordIncome_Invalid(t *testing.T) {
+	valid := RecordIncomeCommand{BookID: "book-1", Amount: 1000, Source: SourceRefund, Date: "2026-02-25"}
+	tests := []struct {
+		name   string
+		modify func(*RecordIncomeCommand)
+	}{
+		{"no book", func(c *RecordIncomeCommand) { c.BookID = "" }},
+		{"zero amount", func(c *RecordIncomeCommand) { c.Amount = 0 }},
+		{"amount above the maximum", func(c *RecordIncomeCommand) { c.Amount = expense.MaxAmount + 1 }},
+		{"unknown source", func(c *RecordIncomeCommand) { c.Source = "lottery" }},
+		{"invalid date", func(c *RecordIncomeCommand) { c.Date = "2026/02/25" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := valid
+			tt.modify(&cmd)
+			if _, err := RecordIncome("inc-1", cmd); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	i := recordedIncome(t)
+
+	event, err := i.Correct(CorrectIncomeCommand{Amount: ptr(int64(320000)), Source: ptr(SourceBonus)})
+	if err != nil {
+		t.Fatalf("Correct: %v", err)
+	}
+	if event.Version != 2 || event.EventType != "IncomeCorrected" {
+		t.Errorf("event = %s v%d, want IncomeCorrected v2", event.EventType, event.Version)
+	}
+
+	var payload IncomeCorrectedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	want := IncomeCorrectedPayload{Amount: 320000, Source: SourceBonus, Memo: "2月分", Date: "2026-02-25"}
+	if payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+	if i.Amount != 300000 || i.Version != 1 {
+		t.Error("Correct must not modify the aggregate")
+	}
+
+	if _, err := i.Correct(CorrectIncomeCommand{Source: ptr(SourceSalary)}); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("Correct to the same source = %v, want ErrNoChanges", err)
+	}
+	if _, err := i.Correct(CorrectIncomeCommand{}); err == nil {
+		t.Error("Correct with no fields: expected error, got nil")
+	}
+	if _, err := i.Correct(CorrectIncomeCommand{Amount: ptr(int64(expense.MaxAmount + 1))}); err == nil {
+		t.Error("Correct above the maximum amount: expected error, got nil")
+	}
+}
+
+func TestVoid(t *testing.T) {
+	i := recordedIncome(t)
+
+	event, err := i.Void(VoidIncomeCommand{Reason: "duplicate"})
+	if err != nil {
+		t.Fatalf("Void: %v", err)
+	}
+	if err := i.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !i.Voided {
+		t.Error("Voided = false, want true")
+	}
+
+	if _, err := i.Correct(CorrectIncomeCommand{Amount: ptr(int64(1))}); !errors.Is(err, ErrVoided) {
+		t.Errorf("Correct err = %v, want ErrVoided", err)
+	}
+	if _, err := i.Void(VoidIncomeCommand{}); !errors.Is(err, ErrVoided) {
+		t.Errorf("Void err = %v, want ErrVoided", err)
+	}
+}
+
+func TestRegisterUpcasters(t *testing.T) {
+	upcasters := eventstore.NewUpcasters()
+	RegisterUpcasters(upcasters)
+	store := eventstore.NewUpcastingStore(eventstore.NewMemoryStore(), upcasters)
+
+	// Income recorded before accounts, at schema v1.
+	v1 := []eventstore.Event{
+		{AggregateID: "inc-1", AggregateType: aggregateType, Version: 1, EventType: eventTypeRecorded,
+			Payload:  []byte(`{"book_id":"book-1","amount":300000,"source":"salary","memo":"","date":"2026-02-25"}`),
+			Metadata: eventstore.Metadata{SchemaVersion: 1}},
+		{AggregateID: "inc-1", AggregateType: aggregateType, Version: 2, EventType: eventTypeCorrected,
+			Payload:  []byte(`{"amount":310000,"source":"salary","memo":"","date":"2026-02-25"}`),
+			Metadata: eventstore.Metadata{SchemaVersion: 1}},
+	}
+	ctx := context.Background()
+	if err := store.Append(ctx, v1, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	events, err := store.Load(ctx, aggregateType, "inc-1")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	i, err := LoadIncome(events)
+	if err != nil {
+		t.Fatalf("LoadIncome: %v", err)
+	}
+	want := Income{ID: "inc-1", Book: "book-1", Amount: 310000, Source: SourceSalary, Date: "2026-02-25", Version: 2}
+	if *i != want {
+		t.Errorf("income = %+v, want %+v", *i, want)
+	}
+
+	event, err := i.Correct(CorrectIncomeCommand{AccountID: ptr("bank")})
+	if err != nil {
+		t.Fatalf("Correct: %v", err)
+	}
+	if err := i.apply(event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if i.AccountID != "bank" {
+		t.Errorf("AccountID = %q, want %q", i.AccountID, "bank")
+	}
+}
@@ -0,0 +1,140 @@
+package income
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryRepository keeps income rows in a map, marking voided ones rather
+// than deleting them, and lists them newest first like Repository.
+type MemoryRepository struct {
+	mu   sync.RWMutex
+	rows map[string]memoryRow
+}
+
+type memoryRow struct {
+	IncomeRow
+	voided bool
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{rows: make(map[string]memoryRow)}
+}
+
+// List returns the newest income matching q. Voided income is excluded.
+func (r *MemoryRepository) List(_ context.Context, q ListQuery) ([]IncomeRow, error) {
+	r.mu.RLock()
+	incomes := []IncomeRow{}
+	for _, row := range r.rows {
+		// Dates are YYYY-MM-DD, so string order is chronological order.
+		if !row.voided && row.BookID == q.BookID &&
+			(q.From == "" || row.Date >= q.From) && (q.To == "" || row.Date <= q.To) {
+			incomes = append(incomes, row.IncomeRow)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(incomes, func(a, b IncomeRow) int {
+		return cmp.Or(cmp.Compare(b.Date, a.Date), b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return incomes[:min(len(incomes), maxListed)], nil
+}
+
+// Get returns non-voided income by ID.
+func (r *MemoryRepository) Get(_ context.Context, id string) (IncomeRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.rows[id]
+	if !ok || row.voided {
+		return IncomeRow{}, ErrNotFound
+	}
+	return row.IncomeRow, nil
+}
+
+// DailyTotals sums the book's income between from and to per day and
+// source.
+func (r *MemoryRepository) DailyTotals(_ context.Context, bookID, from, to string) ([]DailyTotal, error) {
+	type daySource struct {
+		date   string
+		source Source
+	}
+
+	r.mu.RLock()
+	sums := make(map[daySource]DailyTotal)
+	for _, row := range r.rows {
+		if row.voided || row.BookID != bookID || row.Date < from || row.Date > to {
+			continue
+		}
+		key := daySource{row.Date, row.Source}
+		t := sums[key]
+		t.Date, t.Source = row.Date, row.Source
+		t.Total += row.Amount
+		t.Count++
+		sums[key] = t
+	}
+	r.mu.RUnlock()
+
+	var totals []DailyTotal
+	for _, t := range sums {
+		totals = append(totals, t)
+	}
+	slices.SortFunc(totals, func(a, b DailyTotal) int {
+		return cmp.Or(cmp.Compare(a.Date, b.Date), cmp.Compare(a.Source, b.Source))
+	})
+	return totals, nil
+}
+
+// InsertIncome adds a row unless one with the same ID already exists.
+func (r *MemoryRepository) InsertIncome(_ context.Context, row IncomeRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rows[row.ID]; !ok {
+		r.rows[row.ID] = memoryRow{IncomeRow: row}
+	}
+	return nil
+}
+
+// UpdateIncome overwrites a row with a newer version.
+func (r *MemoryRepository) UpdateIncome(_ context.Context, row IncomeRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rows[row.ID]
+	if !ok {
+		return errNotProjected
+	}
+	if stored.Version >= row.Version {
+		return nil
+	}
+	stored.Amount = row.Amount
+	stored.Source = row.Source
+	stored.Memo = row.Memo
+	stored.Date = row.Date
+	stored.Version = row.Version
+	r.rows[row.ID] = stored
+	return nil
+}
+
+// VoidIncome soft-deletes a row at a newer version.
+func (r *MemoryRepository) VoidIncome(_ context.Context, id string, version int, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rows[id]
+	if !ok {
+		return errNotProjected
+	}
+	if stored.Version >= version {
+		return nil
+	}
+	stored.voided = true
+	stored.Version = version
+	r.rows[id] = stored
+	return nil
+}
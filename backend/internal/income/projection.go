@@ -0,0 +1,77 @@
+package income
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the incomes read model's checkpoint.
+	projectionName = "incomes"
+	// incomesTable is the read-model table the projection writes to.
+	incomesTable = "incomes"
+)
+
+// Projector applies income events to the read model, keeping each income at
+// the latest version it has seen.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL incomes projection for rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{incomesTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{db: db, table: tables[incomesTable]})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply processes an event and updates the read model accordingly.
+// Events of other aggregate types are ignored.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	c, ok, err := DecodeChange(event)
+	if err != nil || !ok {
+		return err
+	}
+
+	row := IncomeRow{
+		ID:      c.ID,
+		Amount:  c.Amount,
+		Source:  c.Source,
+		Memo:    c.Memo,
+		Date:    c.Date,
+		Version: c.Version,
+	}
+	switch {
+	case c.Voided:
+		err = p.w.VoidIncome(ctx, c.ID, c.Version, c.OccurredAt)
+	case c.Recorded():
+		row.UserID = c.RecordedBy
+		row.BookID = c.Book
+		row.CreatedAt = c.OccurredAt
+		err = p.w.InsertIncome(ctx, row)
+	default:
+		err = p.w.UpdateIncome(ctx, row)
+	}
+	if err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
+}
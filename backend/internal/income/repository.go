@@ -0,0 +1,253 @@
+package income
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxListed bounds how many rows List returns.
+const maxListed = 200
+
+// errNotProjected is returned by Writer when an update targets income whose
+// IncomeRecorded event has not been applied yet.
+var errNotProjected = errors.New("income is not projected yet")
+
+// IncomeRow represents a row from the incomes read model.
+type IncomeRow struct {
+	ID string `json:"id"`
+	// UserID is the user that recorded the income.
+	UserID    string    `json:"-"`
+	BookID    string    `json:"book_id"`
+	Amount    int64     `json:"amount"`
+	Source    Source    `json:"source"`
+	Memo      string    `json:"memo"`
+	Date      string    `json:"date"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListQuery selects the income List returns.
+type ListQuery struct {
+	BookID string
+	// From and To bound the dates, inclusive, as YYYY-MM-DD. Empty means
+	// unbounded.
+	From string
+	To   string
+}
+
+// DailyTotal is the sum of one day's income from one source.
+type DailyTotal struct {
+	Date   string
+	Source Source
+	Total  int64
+	Count  int
+}
+
+// Reader is the query side of the incomes read model.
+type Reader interface {
+	// List returns up to maxListed of the non-voided income matching q,
+	// newest date first.
+	List(ctx context.Context, q ListQuery) ([]IncomeRow, error)
+
+	// Get returns the income with the given ID, or ErrNotFound if it does
+	// not exist, is voided, or has not been projected yet.
+	Get(ctx context.Context, id string) (IncomeRow, error)
+
+	// DailyTotals returns the daily totals per source of the non-voided
+	// income in bookID dated from from to to, inclusive, as YYYY-MM-DD,
+	// ordered by date and source.
+	DailyTotals(ctx context.Context, bookID, from, to string) ([]DailyTotal, error)
+}
+
+// Writer is the projection side of the incomes read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// InsertIncome adds a row. It does nothing if the row already exists.
+	InsertIncome(ctx context.Context, row IncomeRow) error
+
+	// UpdateIncome overwrites the row's fields if row.Version is newer than
+	// the stored version. Returns errNotProjected if the row does not exist.
+	UpdateIncome(ctx context.Context, row IncomeRow) error
+
+	// VoidIncome marks the row voided at the given version if it is newer
+	// than the stored version. Returns errNotProjected if the row does not
+	// exist.
+	VoidIncome(ctx context.Context, id string, version int, voidedAt time.Time) error
+}
+
+// ReadModel combines both sides of the incomes read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// Repository implements ReadModel on top of the MySQL incomes table.
+type Repository struct {
+	db    *sql.DB
+	table string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, table: incomesTable}
+}
+
+// List returns the newest income matching q. Voided income is excluded.
+func (r *Repository) List(ctx context.Context, q ListQuery) ([]IncomeRow, error) {
+	conds := []string{"voided_at IS NULL", "book_id = ?"}
+	args := []any{q.BookID}
+	if q.From != "" {
+		conds = append(conds, "date >= ?")
+		args = append(args, q.From)
+	}
+	if q.To != "" {
+		conds = append(conds, "date <= ?")
+		args = append(args, q.To)
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s
+		 FROM %s
+		 WHERE %s
+		 ORDER BY date DESC, created_at DESC, id DESC
+		 LIMIT ?`, incomeColumns, r.table, strings.Join(conds, " AND ")),
+		append(args, maxListed)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query incomes: %w", err)
+	}
+	defer rows.Close()
+
+	incomes := []IncomeRow{}
+	for rows.Next() {
+		i, err := scanIncome(rows)
+		if err != nil {
+			return nil, err
+		}
+		incomes = append(incomes, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate incomes: %w", err)
+	}
+	return incomes, nil
+}
+
+// Get returns non-voided income by ID.
+func (r *Repository) Get(ctx context.Context, id string) (IncomeRow, error) {
+	row := r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM %s WHERE id = ? AND voided_at IS NULL`, incomeColumns, r.table), id)
+	i, err := scanIncome(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IncomeRow{}, ErrNotFound
+	}
+	return i, err
+}
+
+// DailyTotals sums the book's income between from and to per day and
+// source.
+func (r *Repository) DailyTotals(ctx context.Context, bookID, from, to string) ([]DailyTotal, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DATE_FORMAT(date, '%%Y-%%m-%%d'), source, SUM(amount), COUNT(*)
+		 FROM %s
+		 WHERE book_id = ? AND voided_at IS NULL AND date BETWEEN ? AND ?
+		 GROUP BY date, source
+		 ORDER BY date, source`, r.table),
+		bookID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query income totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []DailyTotal
+	for rows.Next() {
+		var t DailyTotal
+		if err := rows.Scan(&t.Date, &t.Source, &t.Total, &t.Count); err != nil {
+			return nil, fmt.Errorf("scan income total: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate income totals: %w", err)
+	}
+	return totals, nil
+}
+
+// incomeColumns are the columns scanIncome reads, in order.
+const incomeColumns = `id, user_id, book_id, amount, source, memo, DATE_FORMAT(date, '%Y-%m-%d'), version, created_at`
+
+func scanIncome(row interface{ Scan(...any) error }) (IncomeRow, error) {
+	var i IncomeRow
+	if err := row.Scan(&i.ID, &i.UserID, &i.BookID, &i.Amount, &i.Source, &i.Memo, &i.Date, &i.Version, &i.CreatedAt); err != nil {
+		return IncomeRow{}, fmt.Errorf("scan income: %w", err)
+	}
+	return i, nil
+}
+
+// InsertIncome adds a row unless one with the same ID already exists.
+func (r *Repository) InsertIncome(ctx context.Context, row IncomeRow) error {
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, user_id, book_id, amount, source, memo, date, version, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE id = id`, r.table),
+		row.ID, row.UserID, row.BookID, row.Amount, row.Source, row.Memo, row.Date, row.Version, row.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert income: %w", err)
+	}
+	return nil
+}
+
+// UpdateIncome overwrites a row with a newer version.
+func (r *Repository) UpdateIncome(ctx context.Context, row IncomeRow) error {
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE %s SET amount = ?, source = ?, memo = ?, date = ?, version = ?
+		 WHERE id = ? AND version < ?`, r.table),
+		row.Amount, row.Source, row.Memo, row.Date, row.Version, row.ID, row.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("update income: %w", err)
+	}
+	return r.checkApplied(ctx, res, row.ID)
+}
+
+// VoidIncome soft-deletes a row at a newer version.
+func (r *Repository) VoidIncome(ctx context.Context, id string, version int, voidedAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`UPDATE %s SET voided_at = ?, version = ?
+		 WHERE id = ? AND version < ?`, r.table),
+		voidedAt, version, id, version,
+	)
+	if err != nil {
+		return fmt.Errorf("void income: %w", err)
+	}
+	return r.checkApplied(ctx, res, id)
+}
+
+// checkApplied tells a replayed update, which matches no row because the
+// version guard filters it out, apart from an update to a row that does not
+// exist yet.
+func (r *Repository) checkApplied(ctx context.Context, res sql.Result, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT EXISTS (SELECT 1 FROM %s WHERE id = ?)`, r.table), id,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check income: %w", err)
+	}
+	if !exists {
+		return errNotProjected
+	}
+	return nil
+}
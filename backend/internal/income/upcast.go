@@ -0,0 +1,27 @@
+package income
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// RegisterUpcasters adds the upcasters for every historical income payload
+// schema to u. Stores handed to Projector or used to load an Income must be
+// wrapped with them.
+func RegisterUpcasters(u *eventstore.Upcasters) {
+	u.Register(eventTypeRecorded, 1, addNoAccount)
+	u.Register(eventTypeCorrected, 1, addNoAccount)
+}
+
+// addNoAccount lifts a v1 IncomeRecorded or IncomeCorrected payload, which
+// predates accounts, to v2 with no account.
+func addNoAccount(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal v1 payload: %w", err)
+	}
+	fields["account_id"] = json.RawMessage(`""`)
+	return json.Marshal(fields)
+}
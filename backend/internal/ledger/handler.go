@@ -0,0 +1,107 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// Handler handles HTTP requests for account balances and ledgers.
+// The ledger is updated asynchronously by Projector running under a
+// projection.Runner, so it can lag just-recorded changes for a moment.
+// Every route must be behind middleware.Authenticate. Accounts in books the
+// user may not read are reported as not found.
+type Handler struct {
+	repo  Reader
+	books expense.Books
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(repo Reader, books expense.Books) *Handler {
+	return &Handler{repo: repo, books: books}
+}
+
+// Register adds ledger routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /accounts", h.ListAccounts)
+	mux.HandleFunc("GET /accounts/{id}/ledger", h.GetLedger)
+}
+
+// ListAccounts handles GET /accounts. It accepts book, which defaults to
+// the user's personal book, and returns its accounts with their balances
+// and latest reconciliations.
+func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	bookID := r.URL.Query().Get("book")
+	if bookID == "" {
+		bookID = middleware.UserID(ctx)
+	}
+	if err := h.checkRole(ctx, bookID); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	balances, err := h.repo.Balances(ctx, bookID)
+	if err != nil {
+		writeQueryError(w, fmt.Errorf("list balances: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"accounts": balances})
+}
+
+// GetLedger handles GET /accounts/{id}/ledger, returning the account's
+// entries with their running balance and how each reconciliation compares
+// with it.
+func (h *Handler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l, err := h.repo.Ledger(ctx, r.PathValue("id"))
+	if err == nil {
+		err = h.checkRole(ctx, l.Account.BookID)
+	}
+	if errors.Is(err, ErrBookNotFound) {
+		err = ErrNotFound
+	}
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, l)
+}
+
+// checkRole returns ErrBookNotFound if the request user has no role in
+// bookID.
+func (h *Handler) checkRole(ctx context.Context, bookID string) error {
+	_, err := h.books.Role(ctx, middleware.UserID(ctx), bookID)
+	if errors.Is(err, household.ErrNotFound) {
+		return ErrBookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("look up role in book %s: %w", bookID, err)
+	}
+	return nil
+}
+
+// writeQueryError maps errors from reads to HTTP responses.
+func writeQueryError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrBookNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	log.Printf("ledger query: %v", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
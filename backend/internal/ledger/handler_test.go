@@ -0,0 +1,186 @@
+package ledger_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/ledger"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+// sharedBook is a household alice owns and bob may only read.
+const sharedBook = "household-1"
+
+type members struct{}
+
+func (members) Role(_ context.Context, userID, bookID string) (household.Role, error) {
+	switch {
+	case bookID == userID:
+		return household.RoleOwner, nil
+	case bookID == sharedBook && userID == "alice":
+		return household.RoleOwner, nil
+	case bookID == sharedBook && userID == "bob":
+		return household.RoleViewer, nil
+	}
+	return "", household.ErrNotFound
+}
+
+// newServer serves the account and ledger routes over a memory event store
+// and read model, as testhelper.AsUser. Call the returned function to bring
+// the read model up to date.
+func newServer(t *testing.T) (*httptest.Server, func()) {
+	t.Helper()
+
+	store := eventstore.NewMemoryStore()
+	repo := ledger.NewMemoryRepository()
+	projector := ledger.NewProjector(repo)
+	catchUp := func() {
+		t.Helper()
+		// Replaying from the start is safe, because the projector is
+		// idempotent.
+		events, err := store.ReadAll(context.Background(), 0, 0)
+		if err != nil {
+			t.Fatalf("read events: %v", err)
+		}
+		for _, event := range events {
+			if err := projector.Apply(context.Background(), event); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	account.NewHandler(store, members{}).Register(mux)
+	ledger.NewHandler(repo, members{}).Register(mux)
+	srv := httptest.NewServer(testhelper.AsUser(mux))
+	t.Cleanup(srv.Close)
+	return srv, catchUp
+}
+
+// do makes a request as userID and decodes a successful response into v,
+// which may be nil.
+func do(t *testing.T, srv *httptest.Server, userID, method, path, body string, v any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set(testhelper.UserHeader, userID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+type created struct {
+	ID string `json:"id"`
+}
+
+func open(t *testing.T, srv *httptest.Server, body string) string {
+	t.Helper()
+	var c created
+	if status := do(t, srv, "alice", http.MethodPost, "/accounts", body, &c); status != http.StatusCreated {
+		t.Fatalf("POST /accounts %s status = %d, want %d", body, status, http.StatusCreated)
+	}
+	return c.ID
+}
+
+type accountResponse struct {
+	ledger.Account
+	Balance        int64                  `json:"balance"`
+	Reconciliation *ledger.Reconciliation `json:"reconciliation"`
+}
+
+func TestAccounts(t *testing.T) {
+	srv, catchUp := newServer(t)
+
+	wallet := open(t, srv, `{"name":"財布","type":"cash","opening_balance":12000,"date":"2026-03-01"}`)
+	suica := open(t, srv, `{"name":"Suica","type":"emoney","opening_balance":800,"date":"2026-03-01"}`)
+	if status := do(t, srv, "alice", http.MethodPost, "/transfers",
+		`{"from":"`+wallet+`","to":"`+suica+`","amount":3000,"date":"2026-03-02","memo":"チャージ"}`, nil); status != http.StatusCreated {
+		t.Fatalf("POST /transfers status = %d, want %d", status, http.StatusCreated)
+	}
+	if status := do(t, srv, "alice", http.MethodPost, "/accounts/"+wallet+"/reconciliations",
+		`{"actual":8500,"date":"2026-03-03"}`, nil); status != http.StatusCreated {
+		t.Fatalf("POST reconciliations status = %d, want %d", status, http.StatusCreated)
+	}
+	catchUp()
+
+	var list struct {
+		Accounts []accountResponse `json:"accounts"`
+	}
+	if status := do(t, srv, "alice", http.MethodGet, "/accounts", "", &list); status != http.StatusOK {
+		t.Fatalf("GET /accounts status = %d, want %d", status, http.StatusOK)
+	}
+	if len(list.Accounts) != 2 {
+		t.Fatalf("accounts = %+v, want Suica and 財布", list.Accounts)
+	}
+	s, w := list.Accounts[0], list.Accounts[1]
+	if s.ID != suica || s.Balance != 3800 || s.Currency != "JPY" || s.Reconciliation != nil {
+		t.Errorf("Suica = %+v, want balance 3800 in JPY and no reconciliation", s)
+	}
+	wantReconciliation := ledger.Reconciliation{Date: "2026-03-03", Actual: 8500, Expected: 9000, Difference: -500}
+	if w.ID != wallet || w.Balance != 9000 || w.Reconciliation == nil || *w.Reconciliation != wantReconciliation {
+		t.Errorf("財布 = %+v, want balance 9000 reconciled %+v", w, wantReconciliation)
+	}
+
+	var l ledger.Ledger
+	if status := do(t, srv, "alice", http.MethodGet, "/accounts/"+suica+"/ledger", "", &l); status != http.StatusOK {
+		t.Fatalf("GET ledger status = %d, want %d", status, http.StatusOK)
+	}
+	if len(l.Entries) != 1 || l.Entries[0].Label != wallet || l.Entries[0].Memo != "チャージ" || l.Entries[0].Balance != 3800 {
+		t.Errorf("Suica entries = %+v, want the charge from 財布", l.Entries)
+	}
+}
+
+func TestAccounts_Errors(t *testing.T) {
+	srv, catchUp := newServer(t)
+
+	wallet := open(t, srv, `{"name":"財布","type":"cash","date":"2026-03-01"}`)
+	shared := open(t, srv, `{"book_id":"`+sharedBook+`","name":"共通口座","type":"bank","opening_balance":100000,"date":"2026-03-01"}`)
+	dollars := open(t, srv, `{"name":"USD","type":"bank","currency":"USD","date":"2026-03-01"}`)
+	catchUp()
+
+	for _, tt := range []struct {
+		user, method, path, body string
+		want                     int
+	}{
+		{"alice", http.MethodPost, "/accounts", `{"name":"","type":"cash","date":"2026-03-01"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/accounts", `{"name":"ポイント","type":"points","date":"2026-03-01"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/accounts", `{invalid}`, http.StatusBadRequest},
+		{"bob", http.MethodPost, "/accounts", `{"book_id":"` + sharedBook + `","name":"財布","type":"cash","date":"2026-03-01"}`, http.StatusForbidden},
+		{"dave", http.MethodPost, "/accounts", `{"book_id":"` + sharedBook + `","name":"財布","type":"cash","date":"2026-03-01"}`, http.StatusNotFound},
+		{"alice", http.MethodPost, "/accounts/" + wallet + "/reconciliations", `{"actual":0,"date":"2026-02-28"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/accounts/missing/reconciliations", `{"actual":0,"date":"2026-03-01"}`, http.StatusNotFound},
+		{"bob", http.MethodPost, "/accounts/" + shared + "/reconciliations", `{"actual":0,"date":"2026-03-01"}`, http.StatusForbidden},
+		{"bob", http.MethodPost, "/accounts/" + wallet + "/reconciliations", `{"actual":0,"date":"2026-03-01"}`, http.StatusNotFound},
+		{"alice", http.MethodPost, "/transfers", `{"from":"` + wallet + `","to":"` + shared + `","amount":1,"date":"2026-03-01"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/transfers", `{"from":"` + wallet + `","to":"` + dollars + `","amount":1,"date":"2026-03-01"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/transfers", `{"from":"` + wallet + `","to":"` + wallet + `","amount":1,"date":"2026-03-01"}`, http.StatusBadRequest},
+		{"alice", http.MethodPost, "/transfers", `{"from":"` + wallet + `","to":"missing","amount":1,"date":"2026-03-01"}`, http.StatusNotFound},
+		{"bob", http.MethodGet, "/accounts?book=" + sharedBook, "", http.StatusOK},
+		{"bob", http.MethodGet, "/accounts/" + shared + "/ledger", "", http.StatusOK},
+		{"bob", http.MethodGet, "/accounts/" + wallet + "/ledger", "", http.StatusNotFound},
+		{"dave", http.MethodGet, "/accounts?book=" + sharedBook, "", http.StatusNotFound},
+		{"alice", http.MethodGet, "/accounts/missing/ledger", "", http.StatusNotFound},
+	} {
+		if status := do(t, srv, tt.user, tt.method, tt.path, tt.body, nil); status != tt.want {
+			t.Errorf("%s: %s %s status = %d, want %d", tt.user, tt.method, tt.path, status, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package ledger keeps the running balance of every account, projected from
+// opened accounts, transfers between them, and the expenses and income
+// recorded against them, and checks it against the balances users
+// reconcile it with.
+package ledger
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+)
+
+var (
+	// ErrNotFound is returned when an account is not in the read model, or
+	// the user has no role in its book.
+	ErrNotFound = errors.New("account not found")
+	// ErrBookNotFound is returned when the user has no role in a book.
+	ErrBookNotFound = errors.New("book not found")
+)
+
+// Kind is what moved an account's balance.
+type Kind string
+
+const (
+	KindExpense  Kind = "expense"
+	KindIncome   Kind = "income"
+	KindTransfer Kind = "transfer"
+)
+
+// Account is an account as it was opened.
+type Account struct {
+	ID             string       `json:"id"`
+	BookID         string       `json:"book_id"`
+	Name           string       `json:"name"`
+	Type           account.Type `json:"type"`
+	Currency       string       `json:"currency"`
+	OpeningBalance int64        `json:"opening_balance"`
+	// OpenedOn is the date OpeningBalance was the balance on.
+	OpenedOn string `json:"opened_on"`
+}
+
+// Entry is one movement of an account's balance.
+type Entry struct {
+	Date string `json:"date"`
+	Kind Kind   `json:"kind"`
+	// RefID is the ID of the expense, income or transfer.
+	RefID string `json:"ref_id"`
+	// Label is the expense's category, the income's source, or the other
+	// account's ID for a transfer.
+	Label string `json:"label"`
+	Memo  string `json:"memo"`
+	// Amount is negative for money paid out of the account.
+	Amount int64 `json:"amount"`
+	// Balance is the account's balance after the entry.
+	Balance int64 `json:"balance"`
+}
+
+// Reconciliation compares a balance the user saw with the ledger's.
+type Reconciliation struct {
+	Date   string `json:"date"`
+	Actual int64  `json:"actual"`
+	// Expected is the ledger's balance at the end of Date.
+	Expected int64 `json:"expected"`
+	// Difference is Actual minus Expected: money the ledger is missing,
+	// such as an unrecorded refund when positive or an unrecorded expense
+	// when negative.
+	Difference int64 `json:"difference"`
+}
+
+// AccountBalance is an account with its current balance.
+type AccountBalance struct {
+	Account
+	// Balance is the balance after every entry, as in Ledger.
+	Balance int64 `json:"balance"`
+	// Reconciliation is the latest-dated reconciliation, or nil if the
+	// account was never reconciled.
+	Reconciliation *Reconciliation `json:"reconciliation"`
+}
+
+// Ledger is an account's entries with their running balance.
+type Ledger struct {
+	Account Account `json:"account"`
+	// Balance is the balance after every entry.
+	Balance int64 `json:"balance"`
+	// Entries are ordered by date, oldest first. Entries dated before the
+	// account was opened are left out, because the opening balance
+	// already includes them.
+	Entries []Entry `json:"entries"`
+	// Reconciliations are ordered by date, oldest first.
+	Reconciliations []Reconciliation `json:"reconciliations"`
+}
+
+// buildLedger works out a's running balance from its entries, whose Balance
+// is ignored, and checks each reconciliation, of which only Date and Actual
+// are read, against it. Entries with the same date are ordered by kind and
+// RefID so the result is stable.
+func buildLedger(a Account, entries []Entry, reconciliations []Reconciliation) Ledger {
+	l := Ledger{Account: a, Balance: a.OpeningBalance, Entries: []Entry{}, Reconciliations: []Reconciliation{}}
+
+	slices.SortFunc(entries, func(x, y Entry) int {
+		return cmp.Or(cmp.Compare(x.Date, y.Date), cmp.Compare(x.Kind, y.Kind), cmp.Compare(x.RefID, y.RefID))
+	})
+	for _, e := range entries {
+		// Dates are YYYY-MM-DD, so string order is chronological order.
+		if e.Date < a.OpenedOn {
+			continue
+		}
+		l.Balance += e.Amount
+		e.Balance = l.Balance
+		l.Entries = append(l.Entries, e)
+	}
+
+	for _, r := range reconciliations {
+		// The balance at the end of the day is the one after the day's last
+		// entry, which comes just before the first entry of a later day.
+		i, _ := slices.BinarySearchFunc(l.Entries, r.Date, func(e Entry, date string) int {
+			if e.Date <= date {
+				return -1
+			}
+			return 1
+		})
+		r.Expected = a.OpeningBalance
+		if i > 0 {
+			r.Expected = l.Entries[i-1].Balance
+		}
+		r.Difference = r.Actual - r.Expected
+		l.Reconciliations = append(l.Reconciliations, r)
+	}
+	slices.SortStableFunc(l.Reconciliations, func(x, y Reconciliation) int {
+		return cmp.Compare(x.Date, y.Date)
+	})
+	return l
+}
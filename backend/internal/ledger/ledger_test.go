@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildLedger(t *testing.T) {
+	a := Account{ID: "wallet", BookID: "book-1", Name: "財布", Type: "cash", Currency: "JPY", OpeningBalance: 10000, OpenedOn: "2026-03-01"}
+	entries := []Entry{
+		{Date: "2026-03-03", Kind: KindExpense, RefID: "lunch", Label: "食費", Amount: -800},
+		{Date: "2026-03-02", Kind: KindTransfer, RefID: "charge", Label: "suica", Amount: -3000},
+		{Date: "2026-03-03", Kind: KindExpense, RefID: "bread", Label: "食費", Amount: -300},
+		// Already counted in the opening balance.
+		{Date: "2026-02-27", Kind: KindExpense, RefID: "old", Label: "雑費", Amount: -100},
+		{Date: "2026-03-05", Kind: KindIncome, RefID: "refund", Label: "refund", Amount: 1200},
+	}
+	reconciliations := []Reconciliation{
+		{Date: "2026-03-04", Actual: 5800},
+		{Date: "2026-03-01", Actual: 10000},
+		{Date: "2026-03-05", Actual: 7100},
+	}
+
+	l := buildLedger(a, entries, reconciliations)
+
+	wantEntries := []Entry{
+		{Date: "2026-03-02", Kind: KindTransfer, RefID: "charge", Label: "suica", Amount: -3000, Balance: 7000},
+		{Date: "2026-03-03", Kind: KindExpense, RefID: "bread", Label: "食費", Amount: -300, Balance: 6700},
+		{Date: "2026-03-03", Kind: KindExpense, RefID: "lunch", Label: "食費", Amount: -800, Balance: 5900},
+		{Date: "2026-03-05", Kind: KindIncome, RefID: "refund", Label: "refund", Amount: 1200, Balance: 7100},
+	}
+	if !slices.Equal(l.Entries, wantEntries) {
+		t.Errorf("entries = %+v, want %+v", l.Entries, wantEntries)
+	}
+	if l.Balance != 7100 || l.Account != a {
+		t.Errorf("ledger = %+v with balance %d, want %+v with balance 7100", l.Account, l.Balance, a)
+	}
+
+	wantReconciliations := []Reconciliation{
+		{Date: "2026-03-01", Actual: 10000, Expected: 10000},
+		// A 100 yen coin the ledger does not know was spent.
+		{Date: "2026-03-04", Actual: 5800, Expected: 5900, Difference: -100},
+		{Date: "2026-03-05", Actual: 7100, Expected: 7100},
+	}
+	if !slices.Equal(l.Reconciliations, wantReconciliations) {
+		t.Errorf("reconciliations = %+v, want %+v", l.Reconciliations, wantReconciliations)
+	}
+}
+
+func TestBuildLedger_Empty(t *testing.T) {
+	a := Account{ID: "card", OpeningBalance: -35000, OpenedOn: "2026-03-01"}
+	l := buildLedger(a, nil, nil)
+	if l.Balance != -35000 || l.Entries == nil || len(l.Entries) != 0 || l.Reconciliations == nil {
+		t.Errorf("ledger = %+v, want the opening balance and empty lists", l)
+	}
+}
@@ -0,0 +1,186 @@
+package ledger
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// MemoryRepository keeps accounts, ledger entries and reconciliations in maps,
+// with the last applied version of every expense, income and transfer.
+type MemoryRepository struct {
+	mu              sync.RWMutex
+	accounts        map[string]Account
+	entries         map[entryKey]Entry
+	versions        map[changeKey]int
+	reconciliations map[string]map[int]Reconciliation
+}
+
+type changeKey struct {
+	kind Kind
+	id   string
+}
+
+type entryKey struct {
+	changeKey
+	accountID string
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		accounts:        make(map[string]Account),
+		entries:         make(map[entryKey]Entry),
+		versions:        make(map[changeKey]int),
+		reconciliations: make(map[string]map[int]Reconciliation),
+	}
+}
+
+// Balances sums each of the book's accounts' entries, and those up to its
+// latest reconciliation.
+func (r *MemoryRepository) Balances(_ context.Context, bookID string) ([]AccountBalance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balances := []AccountBalance{}
+	for _, a := range r.accounts {
+		if a.BookID != bookID {
+			continue
+		}
+		b := AccountBalance{Account: a, Balance: a.OpeningBalance}
+		var latest *Reconciliation
+		for _, version := range slices.Sorted(maps.Keys(r.reconciliations[a.ID])) {
+			if rec := r.reconciliations[a.ID][version]; latest == nil || rec.Date >= latest.Date {
+				latest = &rec
+			}
+		}
+		if latest != nil {
+			latest.Expected = a.OpeningBalance
+		}
+		for key, e := range r.entries {
+			if key.accountID != a.ID || e.Date < a.OpenedOn {
+				continue
+			}
+			b.Balance += e.Amount
+			if latest != nil && e.Date <= latest.Date {
+				latest.Expected += e.Amount
+			}
+		}
+		if latest != nil {
+			latest.Difference = latest.Actual - latest.Expected
+			b.Reconciliation = latest
+		}
+		balances = append(balances, b)
+	}
+	slices.SortFunc(balances, func(a, b AccountBalance) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return balances, nil
+}
+
+// Ledger works out the account's running balance.
+func (r *MemoryRepository) Ledger(_ context.Context, accountID string) (Ledger, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.accounts[accountID]
+	if !ok {
+		return Ledger{}, ErrNotFound
+	}
+	var entries []Entry
+	for key, e := range r.entries {
+		if key.accountID == accountID {
+			entries = append(entries, e)
+		}
+	}
+	versions := r.reconciliations[accountID]
+	var reconciliations []Reconciliation
+	for _, version := range slices.Sorted(maps.Keys(versions)) {
+		reconciliations = append(reconciliations, versions[version])
+	}
+	return buildLedger(a, entries, reconciliations), nil
+}
+
+// OpenAccount adds the account, or leaves it as it is when replayed.
+func (r *MemoryRepository) OpenAccount(_ context.Context, o account.Opening) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[o.AccountID]; !ok {
+		r.accounts[o.AccountID] = Account{
+			ID:             o.AccountID,
+			BookID:         o.Book,
+			Name:           o.Name,
+			Type:           o.Type,
+			Currency:       o.Currency,
+			OpeningBalance: o.OpeningBalance,
+			OpenedOn:       o.Date,
+		}
+	}
+	return nil
+}
+
+// ApplyReconciliation adds the reconciliation.
+func (r *MemoryRepository) ApplyReconciliation(_ context.Context, rec account.Reconciliation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reconciliations[rec.AccountID] == nil {
+		r.reconciliations[rec.AccountID] = make(map[int]Reconciliation)
+	}
+	r.reconciliations[rec.AccountID][rec.Version] = Reconciliation{Date: rec.Date, Actual: rec.Actual}
+	return nil
+}
+
+// ApplyTransfer adds both of the transfer's entries.
+func (r *MemoryRepository) ApplyTransfer(_ context.Context, t account.Transfer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, to := transferEntries(t)
+	key := changeKey{KindTransfer, t.ID}
+	r.entries[entryKey{key, t.From}] = from
+	r.entries[entryKey{key, t.To}] = to
+	return nil
+}
+
+// ApplyExpense replaces the expense's entry.
+func (r *MemoryRepository) ApplyExpense(_ context.Context, c expense.Change) error {
+	return r.applyChange(expenseChange(c))
+}
+
+// ApplyIncome replaces the income's entry.
+func (r *MemoryRepository) ApplyIncome(_ context.Context, c income.Change) error {
+	return r.applyChange(incomeChange(c))
+}
+
+func (r *MemoryRepository) applyChange(c change) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := changeKey{c.kind, c.id}
+	version, found := r.versions[key]
+	switch {
+	case !found && !c.recorded:
+		return errNotProjected
+	case found && version >= c.version:
+		return nil
+	}
+
+	for k := range r.entries {
+		if k.changeKey == key {
+			delete(r.entries, k)
+		}
+	}
+	if c.entry != nil {
+		r.entries[entryKey{key, c.accountID}] = *c.entry
+	}
+	r.versions[key] = c.version
+	return nil
+}
@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the ledger read model's checkpoint.
+	projectionName = "ledger"
+	// accountsTable holds every account as it was opened.
+	accountsTable = "ledger_accounts"
+	// entriesTable holds every balance movement.
+	entriesTable = "ledger_entries"
+	// changesTable holds the last applied version of each expense and
+	// income.
+	changesTable = "ledger_changes"
+	// reconciliationsTable holds the balances users reconciled with.
+	reconciliationsTable = "ledger_reconciliations"
+)
+
+// Projector applies account, transfer, expense and income events to the
+// ledger read model.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL ledger projection for rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{accountsTable, entriesTable, changesTable, reconciliationsTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{
+				db:                   db,
+				accountsTable:        tables[accountsTable],
+				entriesTable:         tables[entriesTable],
+				changesTable:         tables[changesTable],
+				reconciliationsTable: tables[reconciliationsTable],
+			})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply processes an account, transfer, expense or income event. Other
+// events are ignored.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	if err := p.apply(ctx, event); err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
+}
+
+func (p *Projector) apply(ctx context.Context, event eventstore.Event) error {
+	switch event.AggregateType {
+	case expense.AggregateType:
+		c, _, err := expense.DecodeChange(event)
+		if err != nil {
+			return err
+		}
+		return p.w.ApplyExpense(ctx, c)
+	case income.AggregateType:
+		c, _, err := income.DecodeChange(event)
+		if err != nil {
+			return err
+		}
+		return p.w.ApplyIncome(ctx, c)
+	case account.TransferAggregateType:
+		t, ok, err := account.DecodeTransfer(event)
+		if err != nil || !ok {
+			return err
+		}
+		return p.w.ApplyTransfer(ctx, t)
+	case account.AggregateType:
+		o, ok, err := account.DecodeOpening(event)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return p.w.OpenAccount(ctx, o)
+		}
+		r, ok, err := account.DecodeReconciliation(event)
+		if err != nil || !ok {
+			return err
+		}
+		return p.w.ApplyReconciliation(ctx, r)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package ledger_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/ledger"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() ledger.ReadModel { return ledger.NewMemoryRepository() },
+	func(db *sql.DB) ledger.ReadModel { return ledger.NewRepository(db) },
+)
+
+const book = "alice"
+
+// bookEvents builds the events of book through the account, expense and
+// income aggregates, so the projector sees the events the handlers would
+// append.
+type bookEvents struct {
+	t        *testing.T
+	events   []eventstore.Event
+	accounts map[string]*account.Account
+}
+
+func newBookEvents(t *testing.T) *bookEvents {
+	return &bookEvents{t: t, accounts: make(map[string]*account.Account)}
+}
+
+func (e *bookEvents) add(event eventstore.Event, err error) {
+	e.t.Helper()
+	if err != nil {
+		e.t.Fatalf("build event: %v", err)
+	}
+	event.RecordedBy = "alice"
+	e.events = append(e.events, event)
+}
+
+func (e *bookEvents) open(id string, typ account.Type, balance int64) {
+	e.t.Helper()
+	event, err := account.Open(id, account.OpenCommand{
+		BookID: book, Name: id, Type: typ, OpeningBalance: balance, Currency: "JPY", Date: "2026-03-01",
+	})
+	e.add(event, err)
+	a, err := account.LoadAccount([]eventstore.Event{event})
+	if err != nil {
+		e.t.Fatalf("load %s: %v", id, err)
+	}
+	e.accounts[id] = a
+}
+
+func (e *bookEvents) reconcile(id string, actual int64, date string) {
+	e.t.Helper()
+	a := e.accounts[id]
+	event, err := a.Reconcile(account.ReconcileCommand{Actual: actual, Date: date})
+	e.add(event, err)
+	a.Version = event.Version
+}
+
+func (e *bookEvents) transfer(id, from, to string, amount int64, date string) {
+	e.t.Helper()
+	e.add(account.RecordTransfer(id, e.accounts[from], e.accounts[to], account.TransferCommand{
+		From: from, To: to, Amount: amount, Date: date,
+	}))
+}
+
+func (e *bookEvents) spend(id, accountID string, amount int64, date string) *expense.Expense {
+	e.t.Helper()
+	event, err := expense.RecordExpense(id, expense.RecordExpenseCommand{
+		BookID: book, Amount: amount, Category: "食費", Date: date, AccountID: accountID,
+	})
+	e.add(event, err)
+	exp, err := expense.LoadExpense([]eventstore.Event{event})
+	if err != nil {
+		e.t.Fatalf("load %s: %v", id, err)
+	}
+	return exp
+}
+
+func (e *bookEvents) earn(id, accountID string, amount int64, date string) {
+	e.t.Helper()
+	e.add(income.RecordIncome(id, income.RecordIncomeCommand{
+		BookID: book, Amount: amount, Source: income.SourceSalary, Date: date, AccountID: accountID,
+	}))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestProjector(t *testing.T) {
+	e := newBookEvents(t)
+	e.open("wallet", account.TypeCash, 10000)
+	e.open("suica", account.TypeEMoney, 500)
+	e.open("card", account.TypeCredit, 0)
+	e.transfer("charge", "wallet", "suica", 3000, "2026-03-02")
+	e.spend("train", "suica", 420, "2026-03-03")
+	e.spend("unassigned", "", 999, "2026-03-03")
+	lunch := e.spend("lunch", "wallet", 1200, "2026-03-03")
+	moved, err := lunch.Correct(expense.CorrectExpenseCommand{AccountID: ptr("card")})
+	e.add(moved, err)
+	dinner := e.spend("dinner", "card", 4000, "2026-03-04")
+	voided, err := dinner.Void(expense.VoidExpenseCommand{})
+	e.add(voided, err)
+	e.earn("salary", "wallet", 250000, "2026-03-25")
+	e.reconcile("wallet", 7000, "2026-03-24")
+	e.reconcile("wallet", 256500, "2026-03-25")
+
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := ledger.NewProjector(repo)
+			ctx := context.Background()
+			// Every event is applied twice, as after a crash between applying
+			// and saving the checkpoint.
+			for range 2 {
+				for _, event := range e.events {
+					if err := projector.Apply(ctx, event); err != nil {
+						t.Fatalf("apply: %v", err)
+					}
+				}
+			}
+
+			accounts, err := repo.Balances(ctx, book)
+			if err != nil {
+				t.Fatalf("Balances: %v", err)
+			}
+			var names []string
+			balances := make(map[string]int64)
+			for _, a := range accounts {
+				names = append(names, a.Name)
+				balances[a.ID] = a.Balance
+
+				l, err := repo.Ledger(ctx, a.ID)
+				if err != nil {
+					t.Fatalf("Ledger(%s): %v", a.ID, err)
+				}
+				if l.Balance != a.Balance {
+					t.Errorf("%s ledger balance = %d, want %d as in Balances", a.ID, l.Balance, a.Balance)
+				}
+			}
+			if want := []string{"card", "suica", "wallet"}; !slices.Equal(names, want) {
+				t.Errorf("accounts = %v, want %v", names, want)
+			}
+			want := map[string]int64{"wallet": 257000, "suica": 3080, "card": -1200}
+			for id, balance := range want {
+				if balances[id] != balance {
+					t.Errorf("%s balance = %d, want %d", id, balances[id], balance)
+				}
+			}
+			wantLatest := ledger.Reconciliation{Date: "2026-03-25", Actual: 256500, Expected: 257000, Difference: -500}
+			for _, a := range accounts {
+				switch {
+				case a.ID == "wallet" && (a.Reconciliation == nil || *a.Reconciliation != wantLatest):
+					t.Errorf("wallet reconciliation = %+v, want %+v", a.Reconciliation, wantLatest)
+				case a.ID != "wallet" && a.Reconciliation != nil:
+					t.Errorf("%s reconciliation = %+v, want nil", a.ID, a.Reconciliation)
+				}
+			}
+
+			l, err := repo.Ledger(ctx, "wallet")
+			if err != nil {
+				t.Fatalf("Ledger: %v", err)
+			}
+			wantEntries := []ledger.Entry{
+				{Date: "2026-03-02", Kind: ledger.KindTransfer, RefID: "charge", Label: "suica", Amount: -3000, Balance: 7000},
+				{Date: "2026-03-25", Kind: ledger.KindIncome, RefID: "salary", Label: "salary", Amount: 250000, Balance: 257000},
+			}
+			if !slices.Equal(l.Entries, wantEntries) {
+				t.Errorf("wallet entries = %+v, want %+v", l.Entries, wantEntries)
+			}
+			wantReconciliations := []ledger.Reconciliation{
+				{Date: "2026-03-24", Actual: 7000, Expected: 7000},
+				{Date: "2026-03-25", Actual: 256500, Expected: 257000, Difference: -500},
+			}
+			if !slices.Equal(l.Reconciliations, wantReconciliations) {
+				t.Errorf("wallet reconciliations = %+v, want %+v", l.Reconciliations, wantReconciliations)
+			}
+
+			if _, err := repo.Ledger(ctx, "missing"); !errors.Is(err, ledger.ErrNotFound) {
+				t.Errorf("Ledger(missing) = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
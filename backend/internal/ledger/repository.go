@@ -0,0 +1,355 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/account"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// errNotProjected is returned by Writer when a correction or void targets an
+// expense or income whose recording has not been applied yet.
+var errNotProjected = errors.New("change is not projected yet")
+
+// Reader is the query side of the ledger read model.
+type Reader interface {
+	// Balances returns the accounts of bookID with their balances,
+	// ordered by name.
+	Balances(ctx context.Context, bookID string) ([]AccountBalance, error)
+	// Ledger returns the ledger of accountID, or ErrNotFound if there is
+	// no such account.
+	Ledger(ctx context.Context, accountID string) (Ledger, error)
+}
+
+// Writer is the projection side of the ledger read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	OpenAccount(ctx context.Context, o account.Opening) error
+	ApplyReconciliation(ctx context.Context, r account.Reconciliation) error
+	// ApplyTransfer adds the transfer's entries to both accounts.
+	ApplyTransfer(ctx context.Context, t account.Transfer) error
+	// ApplyExpense replaces the expense's entry with the one after c, if
+	// c.Version is newer than the last version applied. Returns
+	// errNotProjected if c is not a recording and the expense is unknown.
+	ApplyExpense(ctx context.Context, c expense.Change) error
+	// ApplyIncome is ApplyExpense for income.
+	ApplyIncome(ctx context.Context, c income.Change) error
+}
+
+// ReadModel combines both sides of the ledger read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// change is an expense or income change, reduced to the entry it leaves.
+type change struct {
+	kind     Kind
+	id       string
+	version  int
+	recorded bool
+	// entry is nil when the change leaves no entry, because it voided the
+	// expense or income or left it without an account.
+	entry     *Entry
+	accountID string
+}
+
+func expenseChange(c expense.Change) change {
+	ch := change{kind: KindExpense, id: c.ID, version: c.Version, recorded: c.Recorded(), accountID: c.AccountID}
+	if !c.Voided && c.AccountID != "" {
+		ch.entry = &Entry{Date: c.Date, Kind: KindExpense, RefID: c.ID, Label: c.Category, Memo: c.Memo, Amount: -c.Amount}
+	}
+	return ch
+}
+
+func incomeChange(c income.Change) change {
+	ch := change{kind: KindIncome, id: c.ID, version: c.Version, recorded: c.Recorded(), accountID: c.AccountID}
+	if !c.Voided && c.AccountID != "" {
+		ch.entry = &Entry{Date: c.Date, Kind: KindIncome, RefID: c.ID, Label: string(c.Source), Memo: c.Memo, Amount: c.Amount}
+	}
+	return ch
+}
+
+// transferEntries returns the entries t adds to the account it is from and
+// the account it is to.
+func transferEntries(t account.Transfer) (from, to Entry) {
+	from = Entry{Date: t.Date, Kind: KindTransfer, RefID: t.ID, Label: t.To, Memo: t.Memo, Amount: -t.Amount}
+	to = Entry{Date: t.Date, Kind: KindTransfer, RefID: t.ID, Label: t.From, Memo: t.Memo, Amount: t.Amount}
+	return from, to
+}
+
+// Repository implements ReadModel on top of MySQL. Entries are keyed by the
+// expense, income or transfer they came from, so an expense's or income's
+// entry can be replaced when it is corrected or voided.
+type Repository struct {
+	db                   *sql.DB
+	accountsTable        string
+	entriesTable         string
+	changesTable         string
+	reconciliationsTable string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{
+		db:                   db,
+		accountsTable:        accountsTable,
+		entriesTable:         entriesTable,
+		changesTable:         changesTable,
+		reconciliationsTable: reconciliationsTable,
+	}
+}
+
+const accountColumns = `id, book_id, name, type, currency, opening_balance, DATE_FORMAT(opened_on, '%Y-%m-%d')`
+
+func scanAccount(row interface{ Scan(...any) error }) (Account, error) {
+	var a Account
+	err := row.Scan(&a.ID, &a.BookID, &a.Name, &a.Type, &a.Currency, &a.OpeningBalance, &a.OpenedOn)
+	return a, err
+}
+
+// Balances sums each of the book's accounts' entries, and those up to its
+// latest reconciliation, in one query rather than reading every ledger.
+func (r *Repository) Balances(ctx context.Context, bookID string) ([]AccountBalance, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT a.id, a.book_id, a.name, a.type, a.currency, a.opening_balance,
+		        DATE_FORMAT(a.opened_on, '%%Y-%%m-%%d'),
+		        a.opening_balance + COALESCE((
+		            SELECT SUM(e.amount) FROM %[2]s e
+		            WHERE e.account_id = a.id AND e.date >= a.opened_on), 0),
+		        DATE_FORMAT(r.date, '%%Y-%%m-%%d'), r.actual,
+		        a.opening_balance + COALESCE((
+		            SELECT SUM(e.amount) FROM %[2]s e
+		            WHERE e.account_id = a.id AND e.date >= a.opened_on AND e.date <= r.date), 0)
+		 FROM %[1]s a
+		 LEFT JOIN %[3]s r ON r.account_id = a.id AND r.version = (
+		     SELECT l.version FROM %[3]s l WHERE l.account_id = a.id
+		     ORDER BY l.date DESC, l.version DESC LIMIT 1)
+		 WHERE a.book_id = ?
+		 ORDER BY a.name, a.id`, r.accountsTable, r.entriesTable, r.reconciliationsTable),
+		bookID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := []AccountBalance{}
+	for rows.Next() {
+		var (
+			b        AccountBalance
+			date     sql.NullString
+			actual   sql.NullInt64
+			expected int64
+		)
+		if err := rows.Scan(&b.ID, &b.BookID, &b.Name, &b.Type, &b.Currency, &b.OpeningBalance, &b.OpenedOn,
+			&b.Balance, &date, &actual, &expected); err != nil {
+			return nil, fmt.Errorf("scan balance: %w", err)
+		}
+		if date.Valid {
+			b.Reconciliation = &Reconciliation{Date: date.String, Actual: actual.Int64, Expected: expected,
+				Difference: actual.Int64 - expected}
+		}
+		balances = append(balances, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate balances: %w", err)
+	}
+	return balances, nil
+}
+
+// Ledger reads the account's entries and reconciliations and works out its
+// running balance.
+func (r *Repository) Ledger(ctx context.Context, accountID string) (Ledger, error) {
+	a, err := scanAccount(r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM %s WHERE id = ?`, accountColumns, r.accountsTable), accountID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Ledger{}, ErrNotFound
+	}
+	if err != nil {
+		return Ledger{}, fmt.Errorf("query account: %w", err)
+	}
+
+	entries, err := r.entries(ctx, accountID)
+	if err != nil {
+		return Ledger{}, err
+	}
+	reconciliations, err := r.reconciliations(ctx, accountID)
+	if err != nil {
+		return Ledger{}, err
+	}
+	return buildLedger(a, entries, reconciliations), nil
+}
+
+func (r *Repository) entries(ctx context.Context, accountID string) ([]Entry, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DATE_FORMAT(date, '%%Y-%%m-%%d'), kind, ref_id, label, memo, amount
+		 FROM %s WHERE account_id = ?`, r.entriesTable),
+		accountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Date, &e.Kind, &e.RefID, &e.Label, &e.Memo, &e.Amount); err != nil {
+			return nil, fmt.Errorf("scan entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *Repository) reconciliations(ctx context.Context, accountID string) ([]Reconciliation, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DATE_FORMAT(date, '%%Y-%%m-%%d'), actual
+		 FROM %s WHERE account_id = ? ORDER BY version`, r.reconciliationsTable),
+		accountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query reconciliations: %w", err)
+	}
+	defer rows.Close()
+
+	var reconciliations []Reconciliation
+	for rows.Next() {
+		var rec Reconciliation
+		if err := rows.Scan(&rec.Date, &rec.Actual); err != nil {
+			return nil, fmt.Errorf("scan reconciliation: %w", err)
+		}
+		reconciliations = append(reconciliations, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reconciliations: %w", err)
+	}
+	return reconciliations, nil
+}
+
+// OpenAccount inserts the account, or leaves it as it is when replayed.
+func (r *Repository) OpenAccount(ctx context.Context, o account.Opening) error {
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, book_id, name, type, currency, opening_balance, opened_on)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE id = id`, r.accountsTable),
+		o.AccountID, o.Book, o.Name, o.Type, o.Currency, o.OpeningBalance, o.Date,
+	); err != nil {
+		return fmt.Errorf("insert account: %w", err)
+	}
+	return nil
+}
+
+// ApplyReconciliation inserts the reconciliation, or leaves it as it is
+// when replayed.
+func (r *Repository) ApplyReconciliation(ctx context.Context, rec account.Reconciliation) error {
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (account_id, version, date, actual)
+		 VALUES (?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE account_id = account_id`, r.reconciliationsTable),
+		rec.AccountID, rec.Version, rec.Date, rec.Actual,
+	); err != nil {
+		return fmt.Errorf("insert reconciliation: %w", err)
+	}
+	return nil
+}
+
+// ApplyTransfer adds both of the transfer's entries in one transaction.
+func (r *Repository) ApplyTransfer(ctx context.Context, t account.Transfer) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	from, to := transferEntries(t)
+	if err := r.addEntry(ctx, tx, t.From, from); err != nil {
+		return err
+	}
+	if err := r.addEntry(ctx, tx, t.To, to); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// ApplyExpense replaces the expense's entry in one transaction.
+func (r *Repository) ApplyExpense(ctx context.Context, c expense.Change) error {
+	return r.applyChange(ctx, expenseChange(c))
+}
+
+// ApplyIncome replaces the income's entry in one transaction.
+func (r *Repository) ApplyIncome(ctx context.Context, c income.Change) error {
+	return r.applyChange(ctx, incomeChange(c))
+}
+
+func (r *Repository) applyChange(ctx context.Context, c change) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT version FROM %s WHERE kind = ? AND ref_id = ? FOR UPDATE`, r.changesTable), c.kind, c.id,
+	).Scan(&version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if !c.recorded {
+			return errNotProjected
+		}
+	case err != nil:
+		return fmt.Errorf("load %s version: %w", c.kind, err)
+	case version >= c.version:
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE kind = ? AND ref_id = ?`, r.entriesTable), c.kind, c.id,
+	); err != nil {
+		return fmt.Errorf("delete entries: %w", err)
+	}
+	if c.entry != nil {
+		if err := r.addEntry(ctx, tx, c.accountID, *c.entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (kind, ref_id, version)
+		 VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE version = ?`, r.changesTable),
+		c.kind, c.id, c.version, c.version,
+	); err != nil {
+		return fmt.Errorf("save %s version: %w", c.kind, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) addEntry(ctx context.Context, tx *sql.Tx, accountID string, e Entry) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (kind, ref_id, account_id, date, label, memo, amount)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE amount = ?`, r.entriesTable),
+		e.Kind, e.RefID, accountID, e.Date, e.Label, e.Memo, e.Amount, e.Amount,
+	); err != nil {
+		return fmt.Errorf("save entry: %w", err)
+	}
+	return nil
+}
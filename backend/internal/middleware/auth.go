@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// TokenVerifier verifies bearer tokens.
+type TokenVerifier interface {
+	// Verify returns the ID of the user token was issued to, or an error if
+	// the token is not valid.
+	Verify(token string) (userID string, err error)
+}
+
+type userKey struct{}
+
+// Authenticate rejects requests without a valid "Authorization: Bearer"
+// token with 401, and records the token's user in the request context for
+// the rest.
+func Authenticate(tokens TokenVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				unauthorized(w, `{"error":"authentication required"}`)
+				return
+			}
+			userID, err := tokens.Verify(token)
+			if err != nil {
+				unauthorized(w, `{"error":"invalid or expired token"}`)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), userID)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	if _, err := w.Write([]byte(body)); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user's ID, as
+// Authenticate does. Tests use it to call handlers directly.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserID returns the ID of the authenticated user, or "" outside
+// Authenticate.
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userKey{}).(string)
+	return id
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// tokenMap verifies the tokens it maps to user IDs.
+type tokenMap map[string]string
+
+func (m tokenMap) Verify(token string) (string, error) {
+	if id, ok := m[token]; ok {
+		return id, nil
+	}
+	return "", errors.New("unknown token")
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantUser      string
+	}{
+		{"valid token", "Bearer good", http.StatusOK, "user-1"},
+		{"no header", "", http.StatusUnauthorized, ""},
+		{"other scheme", "Basic good", http.StatusUnauthorized, ""},
+		{"invalid token", "Bearer bad", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotMetadataUser string
+			h := Authenticate(tokenMap{"good": "user-1"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser = UserID(r.Context())
+				gotMetadataUser = EventMetadata(r.Context()).UserID
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+			if gotUser != tt.wantUser || gotMetadataUser != tt.wantUser {
+				t.Errorf("user = %q, metadata user = %q, want %q", gotUser, gotMetadataUser, tt.wantUser)
+			}
+		})
+	}
+}
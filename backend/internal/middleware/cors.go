@@ -1,14 +1,20 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/idempotency"
+)
 
 // CORS wraps an http.Handler to allow cross-origin requests from any origin.
 // This is intentionally permissive for MVP development.
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers",
+			"Authorization, Content-Type, "+RequestIDHeader+", "+ClientHeader+", "+idempotency.KeyHeader)
+		w.Header().Set("Access-Control-Expose-Headers", RequestIDHeader+", "+idempotency.ReplayedHeader+", Link")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
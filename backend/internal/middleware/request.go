@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const (
+	// RequestIDHeader carries the caller's request ID and is echoed back.
+	RequestIDHeader = "X-Request-ID"
+	// ClientHeader names the kind of client sending the request.
+	ClientHeader = "X-Client"
+)
+
+// Known client types accepted in the X-Client header.
+const (
+	ClientWeb    = "web"
+	ClientMobile = "mobile"
+	ClientImport = "import"
+)
+
+// requestIDPattern limits caller-supplied request IDs to something safe to
+// log and store in event metadata.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type requestInfoKey struct{}
+
+type requestInfo struct {
+	requestID string
+	client    string
+}
+
+// RequestContext records the request ID and client type in the request
+// context. A missing or malformed X-Request-ID is replaced by a new UUID,
+// and the ID in use is echoed in the response. An unknown X-Client value is
+// rejected with 400 so a misconfigured client is noticed early.
+func RequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := requestInfo{
+			requestID: r.Header.Get(RequestIDHeader),
+			client:    r.Header.Get(ClientHeader),
+		}
+		if !requestIDPattern.MatchString(info.requestID) {
+			info.requestID = uuid.New().String()
+		}
+		w.Header().Set(RequestIDHeader, info.requestID)
+
+		switch info.client {
+		case "", ClientWeb, ClientMobile, ClientImport:
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			if _, err := w.Write([]byte(`{"error":"X-Client must be one of web, mobile, import"}`)); err != nil {
+				log.Printf("failed to write response: %v", err)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), requestInfoKey{}, info)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns the request ID stored by RequestContext, or "".
+func RequestID(ctx context.Context) string {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return info.requestID
+}
+
+// Client returns the client type stored by RequestContext, or "".
+func Client(ctx context.Context) string {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return info.client
+}
+
+// EventMetadata returns the metadata for events written while handling the
+// request in ctx. The request is both the correlation and the causation of
+// such events, and UserID is the authenticated user. SchemaVersion is left
+// for the caller to set.
+func EventMetadata(ctx context.Context) eventstore.Metadata {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return eventstore.Metadata{
+		CorrelationID: info.requestID,
+		CausationID:   info.requestID,
+		UserID:        UserID(ctx),
+		Client:        info.client,
+	}
+}
+
+// Stamp returns event with the metadata of the request in ctx, keeping the
+// event's SchemaVersion. The authenticated user, if any, also becomes the
+// event's UserID and RecordedBy; otherwise the event keeps its own.
+func Stamp(ctx context.Context, event eventstore.Event) eventstore.Event {
+	md := EventMetadata(ctx)
+	md.SchemaVersion = event.Metadata.SchemaVersion
+	if md.UserID == "" {
+		md.UserID = event.Metadata.UserID
+	} else {
+		event.RecordedBy = md.UserID
+	}
+	event.Metadata = md
+	return event
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func TestRequestContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		requestID  string
+		client     string
+		wantStatus int
+		wantID     string
+		wantClient string
+	}{
+		{"caller request ID", "req-123", "mobile", http.StatusOK, "req-123", "mobile"},
+		{"no headers", "", "", http.StatusOK, "", ""},
+		{"malformed request ID", "bad id\n", "web", http.StatusOK, "", "web"},
+		{"unknown client", "req-123", "desktop", http.StatusBadRequest, "req-123", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotID, gotClient string
+			h := RequestContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				md := EventMetadata(r.Context())
+				if md.CorrelationID != RequestID(r.Context()) || md.CausationID != RequestID(r.Context()) {
+					t.Errorf("metadata IDs = %q/%q, want request ID", md.CorrelationID, md.CausationID)
+				}
+				gotID = RequestID(r.Context())
+				gotClient = md.Client
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/expenses", nil)
+			req.Header.Set(RequestIDHeader, tt.requestID)
+			req.Header.Set(ClientHeader, tt.client)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			echoed := rec.Header().Get(RequestIDHeader)
+			if echoed == "" {
+				t.Error("response is missing X-Request-ID")
+			}
+			if tt.wantID != "" && echoed != tt.wantID {
+				t.Errorf("echoed request ID = %q, want %q", echoed, tt.wantID)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if gotID != echoed {
+				t.Errorf("context request ID = %q, want %q", gotID, echoed)
+			}
+			if gotClient != tt.wantClient {
+				t.Errorf("client = %q, want %q", gotClient, tt.wantClient)
+			}
+		})
+	}
+}
+
+func TestStamp(t *testing.T) {
+	event := eventstore.Event{
+		EventType:  "ThingHappened",
+		RecordedBy: "user-2",
+		Metadata:   eventstore.Metadata{UserID: "user-2", SchemaVersion: 3},
+	}
+
+	tests := []struct {
+		name        string
+		auth        bool
+		wantUser    string
+		wantRecords string
+	}{
+		{"authenticated", true, "user-1", "user-1"},
+		{"anonymous keeps the event's user", false, "user-2", "user-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got eventstore.Event
+			var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = Stamp(r.Context(), event)
+			})
+			if tt.auth {
+				h = Authenticate(tokenMap{"good": "user-1"})(h)
+			}
+			h = RequestContext(h)
+
+			req := httptest.NewRequest(http.MethodPost, "/things", nil)
+			req.Header.Set(RequestIDHeader, "req-123")
+			req.Header.Set(ClientHeader, ClientWeb)
+			req.Header.Set("Authorization", "Bearer good")
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			want := eventstore.Metadata{CorrelationID: "req-123", CausationID: "req-123",
+				UserID: tt.wantUser, Client: ClientWeb, SchemaVersion: 3}
+			if got.Metadata != want || got.RecordedBy != tt.wantRecords {
+				t.Errorf("stamped = %+v recorded by %q, want %+v recorded by %q",
+					got.Metadata, got.RecordedBy, want, tt.wantRecords)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = 200 * time.Millisecond
+	defaultLease        = 30 * time.Second
+	defaultMaxAttempts  = 10
+	defaultMinBackoff   = time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+)
+
+// Dispatcher delivers outbox messages to their subscribers in the background.
+//
+// A failed delivery is retried with exponential backoff; once a message has
+// failed MaxAttempts times, or cannot be delivered at all because its event
+// or subscriber no longer exists, it is dead-lettered. A subscriber that
+// returns ErrNotReady is not failing, so its message is retried every
+// MinBackoff for as long as it takes.
+type Dispatcher struct {
+	store       Store
+	events      eventstore.Store
+	subscribers map[string]Subscriber
+	now         func() time.Time
+
+	// BatchSize is the number of messages claimed at a time.
+	BatchSize int
+	// PollInterval is how long to wait when nothing is deliverable.
+	PollInterval time.Duration
+	// Lease is how long a claimed batch is reserved. It must comfortably
+	// exceed the time needed to deliver a batch, or messages are delivered
+	// twice.
+	Lease time.Duration
+	// MaxAttempts is the number of failed deliveries after which a message
+	// is dead-lettered.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the delay before a failed message is
+	// retried.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// NewDispatcher creates a Dispatcher with default tuning. Events are read
+// from events, which should upcast payloads, so subscribers see the same
+// schema as every other reader.
+func NewDispatcher(store Store, events eventstore.Store, subscribers ...Subscriber) *Dispatcher {
+	byName := make(map[string]Subscriber, len(subscribers))
+	for _, s := range subscribers {
+		byName[s.Name()] = s
+	}
+	return &Dispatcher{
+		store:        store,
+		events:       events,
+		subscribers:  byName,
+		now:          time.Now,
+		BatchSize:    defaultBatchSize,
+		PollInterval: defaultPollInterval,
+		Lease:        defaultLease,
+		MaxAttempts:  defaultMaxAttempts,
+		MinBackoff:   defaultMinBackoff,
+		MaxBackoff:   defaultMaxBackoff,
+	}
+}
+
+// Run delivers messages until ctx is cancelled. A message being delivered
+// when ctx is cancelled is left leased and redelivered after the lease
+// expires.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		now := d.now().UTC()
+		messages, err := d.store.Claim(ctx, now, now.Add(d.Lease), d.BatchSize)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox: claim: %v", err)
+		}
+
+		for _, m := range messages {
+			if ctx.Err() != nil {
+				return
+			}
+			d.deliver(ctx, m)
+		}
+
+		if len(messages) == 0 && !sleep(ctx, d.PollInterval) {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, m Message) {
+	sub, ok := d.subscribers[m.Subscriber]
+	if !ok {
+		d.bury(ctx, m, fmt.Errorf("no subscriber named %q", m.Subscriber))
+		return
+	}
+
+	event, err := d.loadEvent(ctx, m.EventID)
+	if err == nil {
+		err = sub.Handle(ctx, event)
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease will expire and the message is redelivered.
+		return
+	}
+
+	switch {
+	case errors.Is(err, errEventMissing):
+		d.bury(ctx, m, err)
+	case errors.Is(err, ErrNotReady):
+		if err := d.store.Postpone(ctx, m, d.now().UTC().Add(d.MinBackoff)); err != nil {
+			log.Printf("outbox: postpone message %d: %v", m.ID, err)
+		}
+	case err != nil && m.Attempts+1 >= d.MaxAttempts:
+		d.bury(ctx, m, err)
+	case err != nil:
+		retryAt := d.now().UTC().Add(d.backoff(m.Attempts))
+		log.Printf("outbox: %s: event %d: %v (attempt %d, retrying at %s)",
+			m.Subscriber, m.EventID, err, m.Attempts+1, retryAt.Format(time.RFC3339))
+		if err := d.store.Retry(ctx, m, retryAt, err.Error()); err != nil {
+			log.Printf("outbox: retry message %d: %v", m.ID, err)
+		}
+	default:
+		if err := d.store.Ack(ctx, m); err != nil {
+			log.Printf("outbox: ack message %d: %v", m.ID, err)
+		}
+	}
+}
+
+func (d *Dispatcher) bury(ctx context.Context, m Message, cause error) {
+	log.Printf("outbox: %s: event %d: dead-lettered after %d attempts: %v",
+		m.Subscriber, m.EventID, m.Attempts+1, cause)
+	if err := d.store.Bury(ctx, m, d.now().UTC(), cause.Error()); err != nil {
+		log.Printf("outbox: bury message %d: %v", m.ID, err)
+	}
+}
+
+// backoff returns the delay before retrying a message that has already
+// failed attempts times before its latest failure.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.MinBackoff
+	for range attempts {
+		if delay >= d.MaxBackoff {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, d.MaxBackoff)
+}
+
+var errEventMissing = errors.New("event not found")
+
+func (d *Dispatcher) loadEvent(ctx context.Context, id eventstore.Position) (eventstore.Event, error) {
+	events, err := d.events.ReadAll(ctx, id-1, 1)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("load event: %w", err)
+	}
+	if len(events) == 0 || events[0].Position() != id {
+		return eventstore.Event{}, fmt.Errorf("%w: %d", errEventMissing, id)
+	}
+	return events[0], nil
+}
+
+// sleep waits for d and returns false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
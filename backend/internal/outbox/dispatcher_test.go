@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// recordingSubscriber records handled positions. It fails the first
+// failures calls to Handle, and reports itself not ready for the first
+// notReady calls after those.
+type recordingSubscriber struct {
+	name     string
+	mu       sync.Mutex
+	handled  []eventstore.Position
+	failures int
+	notReady int
+}
+
+func (s *recordingSubscriber) Name() string { return s.name }
+
+func (s *recordingSubscriber) Handle(_ context.Context, e eventstore.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("transient failure")
+	}
+	if s.notReady > 0 {
+		s.notReady--
+		return fmt.Errorf("projection behind: %w", ErrNotReady)
+	}
+	s.handled = append(s.handled, e.Position())
+	return nil
+}
+
+func (s *recordingSubscriber) handledPositions() []eventstore.Position {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]eventstore.Position(nil), s.handled...)
+}
+
+func newTestDispatcher(t *testing.T, subscribers ...Subscriber) (*Dispatcher, *eventstore.MemoryStore, *MemoryStore) {
+	t.Helper()
+
+	events := eventstore.NewMemoryStore()
+	store := NewMemoryStore(Names(subscribers...))
+	events.OnAppend = store.Enqueue
+
+	d := NewDispatcher(store, events, subscribers...)
+	d.PollInterval = time.Millisecond
+	d.MinBackoff = time.Millisecond
+	d.MaxBackoff = 5 * time.Millisecond
+	return d, events, store
+}
+
+func appendTestEvents(t *testing.T, store eventstore.Store, n int) {
+	t.Helper()
+	for v := 1; v <= n; v++ {
+		e := eventstore.Event{
+			AggregateID: "a", AggregateType: "test", Version: v,
+			EventType: "TestHappened", Payload: []byte(`{}`),
+		}
+		if err := store.Append(context.Background(), []eventstore.Event{e}, v-1); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+}
+
+// runUntil runs the dispatcher until cond holds, then stops it and waits
+// for it to return.
+func runUntil(t *testing.T, d *Dispatcher, cond func() bool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			cancel()
+			<-done
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestDispatcher_DeliversToEverySubscriber(t *testing.T) {
+	mailer := &recordingSubscriber{name: "mailer"}
+	scores := &recordingSubscriber{name: "scores", failures: 2}
+	d, events, store := newTestDispatcher(t, mailer, scores)
+	appendTestEvents(t, events, 3)
+
+	runUntil(t, d, func() bool {
+		return len(mailer.handledPositions()) == 3 && len(scores.handledPositions()) == 3
+	})
+
+	if left, _ := store.Claim(context.Background(), time.Now().Add(time.Hour), time.Now().Add(2*time.Hour), 10); len(left) != 0 {
+		t.Errorf("%d messages left after delivery", len(left))
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	broken := &recordingSubscriber{name: "broken", failures: 1000}
+	d, events, store := newTestDispatcher(t, broken)
+	d.MaxAttempts = 3
+	appendTestEvents(t, events, 1)
+
+	var dead []DeadLetter
+	runUntil(t, d, func() bool {
+		dead, _ = store.DeadLetters(context.Background(), 10)
+		return len(dead) == 1
+	})
+
+	if dead[0].Attempts != 3 || dead[0].LastError != "transient failure" {
+		t.Errorf("dead letter = %+v, want 3 attempts with the last error", dead[0])
+	}
+}
+
+func TestDispatcher_WaitsForLaggingDependencyWithoutCountingAttempts(t *testing.T) {
+	lagging := &recordingSubscriber{name: "achievements", failures: 1, notReady: 20}
+	d, events, store := newTestDispatcher(t, lagging)
+	d.MaxAttempts = 3
+	appendTestEvents(t, events, 1)
+
+	runUntil(t, d, func() bool {
+		return len(lagging.handledPositions()) == 1
+	})
+
+	if dead, _ := store.DeadLetters(context.Background(), 10); len(dead) != 0 {
+		t.Errorf("dead letters = %+v, want none", dead)
+	}
+}
+
+func TestDispatcher_DeadLettersUnknownSubscriber(t *testing.T) {
+	events := eventstore.NewMemoryStore()
+	store := NewMemoryStore([]string{"removed"})
+	events.OnAppend = store.Enqueue
+	appendTestEvents(t, events, 1)
+
+	d := NewDispatcher(store, events)
+	d.PollInterval = time.Millisecond
+	runUntil(t, d, func() bool {
+		dead, _ := store.DeadLetters(context.Background(), 10)
+		return len(dead) == 1
+	})
+}
+
+func TestDispatcher_RedeliversAfterLeaseExpires(t *testing.T) {
+	mailer := &recordingSubscriber{name: "mailer"}
+	d, events, store := newTestDispatcher(t, mailer)
+	appendTestEvents(t, events, 1)
+
+	// A dispatcher that crashed after claiming, without acknowledging.
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	if _, err := store.Claim(context.Background(), now, now.Add(d.Lease), 10); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	d.now = func() time.Time { return now.Add(d.Lease) }
+	runUntil(t, d, func() bool { return len(mailer.handledPositions()) == 1 })
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := &Dispatcher{MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package outbox delivers appended events to in-process subscribers.
+//
+// Outbox rows are written in the same transaction as the events they refer
+// to, one per event and subscriber, so an event is committed if and only if
+// its deliveries are scheduled. A Dispatcher then delivers them in the
+// background with at-least-once semantics: a message is removed only after
+// its subscriber handled it, is retried with backoff when the subscriber
+// fails, and is dead-lettered after too many attempts.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Subscriber reacts to events delivered through the outbox.
+//
+// Handle receives every event appended after the subscriber was registered
+// and must ignore events it is not interested in. Delivery is at-least-once
+// and not ordered: a message is redelivered if the dispatcher stops before
+// acknowledging it, and a retried message may arrive after later events.
+// Handle must therefore be idempotent and must not assume ordering.
+type Subscriber interface {
+	// Name identifies the subscriber's outbox rows. It must be stable.
+	Name() string
+
+	// Handle processes a single event.
+	Handle(ctx context.Context, event eventstore.Event) error
+}
+
+// Names returns the names of subscribers, as needed to create a store.
+func Names(subscribers ...Subscriber) []string {
+	names := make([]string, len(subscribers))
+	for i, s := range subscribers {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// ErrNotReady is returned, wrapped, by a subscriber that cannot handle an
+// event yet because something it reads, such as a projection, has not
+// caught up with the event. The message is retried without counting as a
+// failed attempt, so a lagging dependency never dead-letters it.
+var ErrNotReady = errors.New("subscriber not ready for event")
+
+// ErrLeaseLost is returned when a message is acknowledged, retried or
+// buried after its lease expired and another dispatcher may have claimed it.
+var ErrLeaseLost = errors.New("outbox lease lost")
+
+// Message is a pending delivery of one event to one subscriber.
+type Message struct {
+	ID         uint64
+	EventID    eventstore.Position
+	Subscriber string
+	// Attempts is the number of earlier deliveries that failed.
+	Attempts int
+
+	claimToken string
+}
+
+// DeadLetter is a message that was given up on.
+type DeadLetter struct {
+	Message
+	LastError string
+	DeadAt    time.Time
+}
+
+// Store persists outbox messages.
+type Store interface {
+	// Claim leases up to limit deliverable messages, oldest first, until
+	// leaseUntil. A message is deliverable if it is not dead and neither
+	// waiting for a retry nor leased as of now. A message whose lease
+	// expires without being acknowledged becomes deliverable again, which
+	// is how messages of a crashed dispatcher are redelivered.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Message, error)
+
+	// Ack removes a delivered message.
+	Ack(ctx context.Context, m Message) error
+
+	// Retry releases the message for another attempt at retryAt.
+	Retry(ctx context.Context, m Message, retryAt time.Time, cause string) error
+
+	// Postpone releases the message for delivery at retryAt without
+	// counting an attempt.
+	Postpone(ctx context.Context, m Message, retryAt time.Time) error
+
+	// Bury dead-letters the message; it is never claimed again unless
+	// requeued.
+	Bury(ctx context.Context, m Message, deadAt time.Time, cause string) error
+
+	// DeadLetters returns up to limit dead-lettered messages, oldest first.
+	DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+
+	// Requeue makes a dead-lettered message deliverable again with its
+	// attempts reset. It returns false if no dead message has the ID.
+	Requeue(ctx context.Context, id uint64) (bool, error)
+}
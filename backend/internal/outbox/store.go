@@ -0,0 +1,338 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Both stores use available_at for retries and leases alike: claiming a
+// message pushes it to the end of the lease, so it is claimable again only
+// if the dispatcher neither acknowledges, retries nor buries it in time.
+
+// MySQLStore implements Store backed by the outbox table.
+type MySQLStore struct {
+	db          *sql.DB
+	subscribers []string
+}
+
+// NewMySQLStore creates a MySQLStore that enqueues every event for each of
+// the named subscribers.
+func NewMySQLStore(db *sql.DB, subscribers []string) *MySQLStore {
+	return &MySQLStore{db: db, subscribers: subscribers}
+}
+
+// Enqueue writes one message per event and subscriber in tx. It matches
+// eventstore.MySQLStore.OnAppend, so messages commit with their events.
+func (s *MySQLStore) Enqueue(ctx context.Context, tx *sql.Tx, events []eventstore.Event) error {
+	if len(s.subscribers) == 0 || len(events) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(events)*len(s.subscribers))
+	args := make([]any, 0, 2*cap(values))
+	for _, e := range events {
+		for _, name := range s.subscribers {
+			values = append(values, "(?, ?)")
+			args = append(args, e.ID, name)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO outbox (event_id, subscriber) VALUES `+strings.Join(values, ", "), args...,
+	); err != nil {
+		return fmt.Errorf("enqueue outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Claim leases up to limit deliverable messages until leaseUntil.
+func (s *MySQLStore) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Message, error) {
+	token := uuid.NewString()
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE outbox SET claim_token = ?, available_at = ?
+		 WHERE dead_at IS NULL AND available_at <= ?
+		 ORDER BY id
+		 LIMIT ?`,
+		token, leaseUntil, now, limit,
+	); err != nil {
+		return nil, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, subscriber, attempts FROM outbox
+		 WHERE claim_token = ?
+		 ORDER BY id`, token)
+	if err != nil {
+		return nil, fmt.Errorf("query claimed messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		m := Message{claimToken: token}
+		if err := rows.Scan(&m.ID, &m.EventID, &m.Subscriber, &m.Attempts); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+	return messages, nil
+}
+
+// Ack removes a delivered message.
+func (s *MySQLStore) Ack(ctx context.Context, m Message) error {
+	return s.execClaimed(ctx, "ack", m,
+		`DELETE FROM outbox WHERE id = ? AND claim_token = ?`, m.ID, m.claimToken)
+}
+
+// Retry releases the message for another attempt at retryAt.
+func (s *MySQLStore) Retry(ctx context.Context, m Message, retryAt time.Time, cause string) error {
+	return s.execClaimed(ctx, "retry", m,
+		`UPDATE outbox SET attempts = attempts + 1, available_at = ?, claim_token = NULL, last_error = ?
+		 WHERE id = ? AND claim_token = ?`,
+		retryAt, cause, m.ID, m.claimToken)
+}
+
+// Postpone releases the message for delivery at retryAt without counting an
+// attempt.
+func (s *MySQLStore) Postpone(ctx context.Context, m Message, retryAt time.Time) error {
+	return s.execClaimed(ctx, "postpone", m,
+		`UPDATE outbox SET available_at = ?, claim_token = NULL WHERE id = ? AND claim_token = ?`,
+		retryAt, m.ID, m.claimToken)
+}
+
+// Bury dead-letters the message.
+func (s *MySQLStore) Bury(ctx context.Context, m Message, deadAt time.Time, cause string) error {
+	return s.execClaimed(ctx, "bury", m,
+		`UPDATE outbox SET attempts = attempts + 1, dead_at = ?, claim_token = NULL, last_error = ?
+		 WHERE id = ? AND claim_token = ?`,
+		deadAt, cause, m.ID, m.claimToken)
+}
+
+func (s *MySQLStore) execClaimed(ctx context.Context, op string, m Message, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s outbox message %d: %w", op, m.ID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s outbox message %d: %w", op, m.ID, ErrLeaseLost)
+	}
+	return nil
+}
+
+// DeadLetters returns up to limit dead-lettered messages, oldest first.
+func (s *MySQLStore) DeadLetters(ctx context.Context, limit int) ([]DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, subscriber, attempts, COALESCE(last_error, ''), dead_at FROM outbox
+		 WHERE dead_at IS NOT NULL
+		 ORDER BY id
+		 LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []DeadLetter
+	for rows.Next() {
+		var d DeadLetter
+		if err := rows.Scan(&d.ID, &d.EventID, &d.Subscriber, &d.Attempts, &d.LastError, &d.DeadAt); err != nil {
+			return nil, fmt.Errorf("scan dead letter: %w", err)
+		}
+		letters = append(letters, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// Requeue makes a dead-lettered message deliverable again.
+func (s *MySQLStore) Requeue(ctx context.Context, id uint64) (bool, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE outbox SET dead_at = NULL, attempts = 0, available_at = UTC_TIMESTAMP(6)
+		 WHERE id = ? AND dead_at IS NOT NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("requeue outbox message %d: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// MemoryStore implements Store in memory with the same semantics as
+// MySQLStore.
+type MemoryStore struct {
+	mu          sync.Mutex
+	subscribers []string
+	nextID      uint64
+	messages    map[uint64]*memoryMessage
+}
+
+// memoryMessage is deliverable from availableAt on; the zero time means
+// right away.
+type memoryMessage struct {
+	DeadLetter
+	availableAt time.Time
+}
+
+// NewMemoryStore creates a MemoryStore that enqueues every event for each
+// of the named subscribers.
+func NewMemoryStore(subscribers []string) *MemoryStore {
+	return &MemoryStore{
+		subscribers: subscribers,
+		messages:    make(map[uint64]*memoryMessage),
+	}
+}
+
+// Enqueue adds one message per event and subscriber. It matches
+// eventstore.MemoryStore.OnAppend.
+func (s *MemoryStore) Enqueue(events []eventstore.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		for _, name := range s.subscribers {
+			s.nextID++
+			m := &memoryMessage{}
+			m.ID, m.EventID, m.Subscriber = s.nextID, e.Position(), name
+			s.messages[m.ID] = m
+		}
+	}
+	return nil
+}
+
+// Claim leases up to limit deliverable messages until leaseUntil.
+func (s *MemoryStore) Claim(_ context.Context, now, leaseUntil time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimable []*memoryMessage
+	for _, m := range s.messages {
+		if m.DeadAt.IsZero() && !m.availableAt.After(now) {
+			claimable = append(claimable, m)
+		}
+	}
+	sort.Slice(claimable, func(i, j int) bool { return claimable[i].ID < claimable[j].ID })
+
+	token := uuid.NewString()
+	var messages []Message
+	for _, m := range claimable[:min(limit, len(claimable))] {
+		m.claimToken = token
+		m.availableAt = leaseUntil
+		messages = append(messages, m.Message)
+	}
+	return messages, nil
+}
+
+// Ack removes a delivered message.
+func (s *MemoryStore) Ack(_ context.Context, m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.claimed("ack", m); err != nil {
+		return err
+	}
+	delete(s.messages, m.ID)
+	return nil
+}
+
+// Retry releases the message for another attempt at retryAt.
+func (s *MemoryStore) Retry(_ context.Context, m Message, retryAt time.Time, cause string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.claimed("retry", m)
+	if err != nil {
+		return err
+	}
+	stored.Attempts++
+	stored.availableAt = retryAt
+	stored.claimToken = ""
+	stored.LastError = cause
+	return nil
+}
+
+// Postpone releases the message for delivery at retryAt without counting an
+// attempt.
+func (s *MemoryStore) Postpone(_ context.Context, m Message, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.claimed("postpone", m)
+	if err != nil {
+		return err
+	}
+	stored.availableAt = retryAt
+	stored.claimToken = ""
+	return nil
+}
+
+// Bury dead-letters the message.
+func (s *MemoryStore) Bury(_ context.Context, m Message, deadAt time.Time, cause string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.claimed("bury", m)
+	if err != nil {
+		return err
+	}
+	stored.Attempts++
+	stored.DeadAt = deadAt
+	stored.claimToken = ""
+	stored.LastError = cause
+	return nil
+}
+
+func (s *MemoryStore) claimed(op string, m Message) (*memoryMessage, error) {
+	stored, ok := s.messages[m.ID]
+	if !ok || stored.claimToken != m.claimToken {
+		return nil, fmt.Errorf("%s outbox message %d: %w", op, m.ID, ErrLeaseLost)
+	}
+	return stored, nil
+}
+
+// DeadLetters returns up to limit dead-lettered messages, oldest first.
+func (s *MemoryStore) DeadLetters(_ context.Context, limit int) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var letters []DeadLetter
+	for _, m := range s.messages {
+		if !m.DeadAt.IsZero() {
+			letters = append(letters, m.DeadLetter)
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].ID < letters[j].ID })
+	return letters[:min(limit, len(letters))], nil
+}
+
+// Requeue makes a dead-lettered message deliverable again.
+func (s *MemoryStore) Requeue(_ context.Context, id uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.messages[id]
+	if !ok || m.DeadAt.IsZero() {
+		return false, nil
+	}
+	m.DeadAt = time.Time{}
+	m.Attempts = 0
+	m.availableAt = time.Time{}
+	return true, nil
+}
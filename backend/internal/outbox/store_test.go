@@ -0,0 +1,235 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/outbox"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+var subscribers = []string{"mailer", "scores"}
+
+// backends returns an event store whose appends enqueue outbox messages for
+// subscribers, and the outbox store they are enqueued in.
+var backends = []struct {
+	name string
+	open func(t *testing.T) (eventstore.Store, outbox.Store)
+}{
+	{"memory", func(*testing.T) (eventstore.Store, outbox.Store) {
+		events := eventstore.NewMemoryStore()
+		store := outbox.NewMemoryStore(subscribers)
+		events.OnAppend = store.Enqueue
+		return events, store
+	}},
+	{"mysql", func(t *testing.T) (eventstore.Store, outbox.Store) {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		events := eventstore.NewMySQLStore(db)
+		store := outbox.NewMySQLStore(db, subscribers)
+		events.OnAppend = store.Enqueue
+		return events, store
+	}},
+}
+
+func appendEvents(t *testing.T, store eventstore.Store, id string, n int) {
+	t.Helper()
+	var events []eventstore.Event
+	for v := 1; v <= n; v++ {
+		events = append(events, eventstore.Event{
+			AggregateID: id, AggregateType: "test", Version: v,
+			EventType: "TestHappened", Payload: []byte(`{}`),
+		})
+	}
+	if err := store.Append(context.Background(), events, 0); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+}
+
+// base is in the future so that rows enqueued with the database clock are
+// always available to it.
+var base = time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+func TestStore_EnqueueAndClaim(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			events, store := b.open(t)
+			ctx := context.Background()
+			appendEvents(t, events, "a", 2)
+
+			first, err := store.Claim(ctx, base, base.Add(time.Minute), 3)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if len(first) != 3 {
+				t.Fatalf("claimed %d messages, want 3", len(first))
+			}
+			if first[0].Subscriber != "mailer" || first[1].Subscriber != "scores" ||
+				first[0].EventID != first[1].EventID || first[2].EventID <= first[0].EventID {
+				t.Errorf("claimed %+v, want one message per event and subscriber in event order", first)
+			}
+
+			second, err := store.Claim(ctx, base, base.Add(time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if len(second) != 1 {
+				t.Fatalf("second claim got %d messages, want the 1 not leased", len(second))
+			}
+
+			// After the lease expires, unacknowledged messages are claimable again.
+			for _, m := range second {
+				if err := store.Ack(ctx, m); err != nil {
+					t.Fatalf("ack: %v", err)
+				}
+			}
+			expired, err := store.Claim(ctx, base.Add(2*time.Minute), base.Add(3*time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if len(expired) != 3 {
+				t.Errorf("claim after lease expiry got %d messages, want 3", len(expired))
+			}
+
+			// The expired claim no longer owns its messages.
+			if err := store.Ack(ctx, first[0]); !errors.Is(err, outbox.ErrLeaseLost) {
+				t.Errorf("ack with an expired lease: err = %v, want ErrLeaseLost", err)
+			}
+		})
+	}
+}
+
+func TestStore_RetryAndBury(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			events, store := b.open(t)
+			ctx := context.Background()
+			appendEvents(t, events, "a", 1)
+
+			claimed, err := store.Claim(ctx, base, base.Add(time.Minute), 10)
+			if err != nil || len(claimed) != 2 {
+				t.Fatalf("claim: %d messages, err = %v", len(claimed), err)
+			}
+			retried, buried := claimed[0], claimed[1]
+			if err := store.Retry(ctx, retried, base.Add(time.Hour), "smtp down"); err != nil {
+				t.Fatalf("retry: %v", err)
+			}
+			if err := store.Bury(ctx, buried, base, "bad payload"); err != nil {
+				t.Fatalf("bury: %v", err)
+			}
+
+			if got, _ := store.Claim(ctx, base.Add(30*time.Minute), base.Add(31*time.Minute), 10); len(got) != 0 {
+				t.Errorf("claimed %+v before the retry time", got)
+			}
+			got, err := store.Claim(ctx, base.Add(time.Hour), base.Add(61*time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if len(got) != 1 || got[0].ID != retried.ID || got[0].Attempts != 1 {
+				t.Errorf("claimed %+v at the retry time, want message %d with 1 attempt", got, retried.ID)
+			}
+
+			dead, err := store.DeadLetters(ctx, 10)
+			if err != nil {
+				t.Fatalf("dead letters: %v", err)
+			}
+			if len(dead) != 1 || dead[0].ID != buried.ID || dead[0].LastError != "bad payload" || dead[0].Attempts != 1 {
+				t.Fatalf("dead letters = %+v, want message %d", dead, buried.ID)
+			}
+
+			ok, err := store.Requeue(ctx, buried.ID)
+			if err != nil || !ok {
+				t.Fatalf("requeue: ok = %v, err = %v", ok, err)
+			}
+			if ok, _ := store.Requeue(ctx, buried.ID); ok {
+				t.Error("requeue of a live message reported success")
+			}
+			got, err = store.Claim(ctx, base.Add(2*time.Hour), base.Add(121*time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			var requeued bool
+			for _, m := range got {
+				requeued = requeued || (m.ID == buried.ID && m.Attempts == 0)
+			}
+			if !requeued {
+				t.Errorf("claimed %+v after requeue, want message %d with attempts reset", got, buried.ID)
+			}
+		})
+	}
+}
+
+func TestStore_Postpone(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			events, store := b.open(t)
+			ctx := context.Background()
+			appendEvents(t, events, "a", 1)
+
+			claimed, err := store.Claim(ctx, base, base.Add(time.Minute), 1)
+			if err != nil || len(claimed) != 1 {
+				t.Fatalf("claim: %d messages, err = %v", len(claimed), err)
+			}
+			if err := store.Postpone(ctx, claimed[0], base.Add(time.Hour)); err != nil {
+				t.Fatalf("postpone: %v", err)
+			}
+			if err := store.Postpone(ctx, claimed[0], base.Add(time.Hour)); !errors.Is(err, outbox.ErrLeaseLost) {
+				t.Errorf("postpone of a released message: err = %v, want ErrLeaseLost", err)
+			}
+
+			got, err := store.Claim(ctx, base.Add(30*time.Minute), base.Add(31*time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			for _, m := range got {
+				if m.ID == claimed[0].ID {
+					t.Errorf("claimed message %d before the postponed time", m.ID)
+				}
+			}
+			got, err = store.Claim(ctx, base.Add(time.Hour), base.Add(61*time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			var postponed bool
+			for _, m := range got {
+				postponed = postponed || (m.ID == claimed[0].ID && m.Attempts == 0)
+			}
+			if !postponed {
+				t.Errorf("claimed %+v at the postponed time, want message %d with 0 attempts", got, claimed[0].ID)
+			}
+		})
+	}
+}
+
+func TestStore_RolledBackAppendEnqueuesNothing(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			events, store := b.open(t)
+			ctx := context.Background()
+			appendEvents(t, events, "a", 1)
+
+			// Conflicts with the first append, so it must leave no messages behind.
+			conflicting := eventstore.Event{
+				AggregateID: "a", AggregateType: "test", Version: 1,
+				EventType: "TestHappened", Payload: []byte(`{}`),
+			}
+			if err := events.Append(ctx, []eventstore.Event{conflicting}, 0); err == nil {
+				t.Fatal("conflicting append succeeded")
+			}
+
+			got, err := store.Claim(ctx, base, base.Add(time.Minute), 10)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if len(got) != 2 {
+				t.Errorf("claimed %d messages, want 2 for the single committed event", len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// MySQLCheckpointStore implements CheckpointStore backed by the
+// projection_checkpoints table.
+type MySQLCheckpointStore struct {
+	db *sql.DB
+}
+
+// NewMySQLCheckpointStore creates a new MySQLCheckpointStore.
+func NewMySQLCheckpointStore(db *sql.DB) *MySQLCheckpointStore {
+	return &MySQLCheckpointStore{db: db}
+}
+
+// Load returns the saved position for the named projection.
+func (s *MySQLCheckpointStore) Load(ctx context.Context, name string) (eventstore.Position, error) {
+	var pos eventstore.Position
+	err := s.db.QueryRowContext(ctx,
+		`SELECT position FROM projection_checkpoints WHERE name = ?`, name,
+	).Scan(&pos)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load checkpoint %s: %w", name, err)
+	}
+	return pos, nil
+}
+
+// Save upserts the position for the named projection.
+func (s *MySQLCheckpointStore) Save(ctx context.Context, name string, pos eventstore.Position) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO projection_checkpoints (name, position) VALUES (?, ?)
+		 ON DUPLICATE KEY UPDATE position = VALUES(position), updated_at = UTC_TIMESTAMP(6)`,
+		name, pos,
+	)
+	if err != nil {
+		return fmt.Errorf("save checkpoint %s: %w", name, err)
+	}
+	return nil
+}
+
+// MemoryCheckpointStore implements CheckpointStore in memory.
+type MemoryCheckpointStore struct {
+	mu        sync.Mutex
+	positions map[string]eventstore.Position
+}
+
+// NewMemoryCheckpointStore creates an empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{positions: make(map[string]eventstore.Position)}
+}
+
+// Load returns the saved position for the named projection.
+func (s *MemoryCheckpointStore) Load(_ context.Context, name string) (eventstore.Position, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.positions[name], nil
+}
+
+// Save records the position for the named projection.
+func (s *MemoryCheckpointStore) Save(_ context.Context, name string, pos eventstore.Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions[name] = pos
+	return nil
+}
@@ -0,0 +1,29 @@
+package projection
+
+import (
+	"context"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Projection builds a read model from the global event log.
+//
+// Apply receives every event in the log, in commit order, and must ignore
+// events it is not interested in. Delivery is at-least-once: after a crash or
+// a failed checkpoint write, events are replayed, so Apply must be idempotent.
+type Projection interface {
+	// Name identifies the projection's checkpoint. It must be stable.
+	Name() string
+
+	// Apply updates the read model for a single event.
+	Apply(ctx context.Context, event eventstore.Event) error
+}
+
+// CheckpointStore persists how far each projection has read the event log.
+type CheckpointStore interface {
+	// Load returns the saved position, or the zero Position if none exists.
+	Load(ctx context.Context, name string) (eventstore.Position, error)
+
+	// Save records that every event up to and including pos has been applied.
+	Save(ctx context.Context, name string, pos eventstore.Position) error
+}
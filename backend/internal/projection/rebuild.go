@@ -0,0 +1,147 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const (
+	shadowSuffix  = "_rebuild"
+	retiredSuffix = "_retired"
+	rebuildBatch  = eventstore.MaxReadLimit
+)
+
+var tableNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Definition describes a projection that can be rebuilt from the event log.
+type Definition struct {
+	// Name is the projection's checkpoint name.
+	Name string
+	// Tables lists the read-model tables the projection writes to.
+	Tables []string
+	// New builds the projection against the given tables, which map every
+	// name in Tables to the table that should actually be written.
+	New func(tables map[string]string) Projection
+}
+
+// Rebuilder regenerates a projection's read model from the event log.
+type Rebuilder struct {
+	db          *sql.DB
+	store       eventstore.Store
+	checkpoints CheckpointStore
+}
+
+// NewRebuilder creates a new Rebuilder.
+func NewRebuilder(db *sql.DB, store eventstore.Store, checkpoints CheckpointStore) *Rebuilder {
+	return &Rebuilder{db: db, store: store, checkpoints: checkpoints}
+}
+
+// Rebuild replays the whole event log into empty shadow copies of the
+// projection's tables, swaps them in with a single RENAME TABLE, and resets
+// the checkpoint to the last replayed position.
+//
+// The server may keep running: events appended while the shadow tables are
+// built are replayed into the live tables right after the swap. A running
+// Runner applies the same events again, which idempotent projections absorb.
+func (r *Rebuilder) Rebuild(ctx context.Context, def Definition) (eventstore.Position, error) {
+	for _, table := range def.Tables {
+		if !tableNamePattern.MatchString(table) {
+			return 0, fmt.Errorf("invalid table name %q", table)
+		}
+	}
+
+	shadows := make(map[string]string, len(def.Tables))
+	for _, table := range def.Tables {
+		shadow := table + shadowSuffix
+		if err := r.exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS `%s`", shadow)); err != nil {
+			return 0, err
+		}
+		if err := r.exec(ctx, fmt.Sprintf("CREATE TABLE `%s` LIKE `%s`", shadow, table)); err != nil {
+			return 0, err
+		}
+		shadows[table] = shadow
+	}
+
+	pos, err := r.replay(ctx, def.New(shadows), 0)
+	if err != nil {
+		return 0, fmt.Errorf("replay into shadow tables: %w", err)
+	}
+	log.Printf("rebuild %s: replayed up to position %d", def.Name, pos)
+
+	if err := r.swap(ctx, def.Tables); err != nil {
+		return 0, err
+	}
+
+	live := make(map[string]string, len(def.Tables))
+	for _, table := range def.Tables {
+		live[table] = table
+	}
+	pos, err = r.replay(ctx, def.New(live), pos)
+	if err != nil {
+		return 0, fmt.Errorf("catch up live tables: %w", err)
+	}
+
+	if err := r.checkpoints.Save(ctx, def.Name, pos); err != nil {
+		return 0, err
+	}
+	return pos, nil
+}
+
+// replay applies every event after the given position and returns the
+// position of the last event applied.
+func (r *Rebuilder) replay(ctx context.Context, p Projection, after eventstore.Position) (eventstore.Position, error) {
+	pos := after
+	for {
+		events, err := r.store.ReadAll(ctx, pos, rebuildBatch)
+		if err != nil {
+			return 0, err
+		}
+		if len(events) == 0 {
+			return pos, nil
+		}
+		for _, event := range events {
+			if err := p.Apply(ctx, event); err != nil {
+				return 0, fmt.Errorf("apply event at position %d: %w", event.Position(), err)
+			}
+			pos = event.Position()
+		}
+	}
+}
+
+// swap atomically replaces each table with its shadow and drops the old ones.
+func (r *Rebuilder) swap(ctx context.Context, tables []string) error {
+	renames := make([]string, 0, 2*len(tables))
+	for _, table := range tables {
+		retired := table + retiredSuffix
+		if err := r.exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS `%s`", retired)); err != nil {
+			return err
+		}
+		renames = append(renames,
+			fmt.Sprintf("`%s` TO `%s`", table, retired),
+			fmt.Sprintf("`%s` TO `%s`", table+shadowSuffix, table))
+	}
+
+	if err := r.exec(ctx, "RENAME TABLE "+strings.Join(renames, ", ")); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if err := r.exec(ctx, fmt.Sprintf("DROP TABLE `%s`", table+retiredSuffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Rebuilder) exec(ctx context.Context, stmt string) error {
+	if _, err := r.db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("%s: %w", stmt, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package projection_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+func TestRebuild_ReplacesReadModel(t *testing.T) {
+	db := testhelper.OpenTestDB(t)
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	ctx := context.Background()
+	store := eventstore.NewMySQLStore(db)
+	checkpoints := projection.NewMySQLCheckpointStore(db)
+
+	for _, id := range []string{"expense-1", "expense-2"} {
+		event, err := expense.RecordExpense(id, expense.RecordExpenseCommand{
+			Amount: 1000, Category: "食費", Date: "2026-02-20",
+		})
+		if err != nil {
+			t.Fatalf("record expense: %v", err)
+		}
+		if err := store.Append(ctx, []eventstore.Event{event}, 0); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	// A stale row that no event accounts for, as left behind by a projector bug.
+	if _, err := db.Exec(`INSERT INTO expenses (id, amount, category, date) VALUES ('stale', 1, 'x', '2026-01-01')`); err != nil {
+		t.Fatalf("insert stale row: %v", err)
+	}
+
+	rebuilder := projection.NewRebuilder(db, store, checkpoints)
+	pos, err := rebuilder.Rebuild(ctx, expense.ProjectionDefinition(db))
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+
+	var ids []string
+	rows, err := db.Query(`SELECT id FROM expenses ORDER BY id`)
+	if err != nil {
+		t.Fatalf("query expenses: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 || ids[0] != "expense-1" || ids[1] != "expense-2" {
+		t.Errorf("ids = %v, want [expense-1 expense-2]", ids)
+	}
+
+	events, err := store.ReadAll(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("read all: %v", err)
+	}
+	if want := events[len(events)-1].Position(); pos != want {
+		t.Errorf("position = %d, want %d", pos, want)
+	}
+	if saved, err := checkpoints.Load(ctx, "expenses"); err != nil || saved != pos {
+		t.Errorf("checkpoint = %d (err %v), want %d", saved, err, pos)
+	}
+}
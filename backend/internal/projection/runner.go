@@ -0,0 +1,150 @@
+package projection
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = 200 * time.Millisecond
+	defaultMinBackoff   = 100 * time.Millisecond
+	defaultMaxBackoff   = 30 * time.Second
+)
+
+// Runner tails the global event log in the background and feeds each
+// projection from its own checkpoint.
+//
+// Every projection runs in its own goroutine, so a failing projection only
+// stalls itself. Failed reads, applies and checkpoint writes are retried with
+// exponential backoff until they succeed or the context is cancelled; the
+// checkpoint never advances past an event that was not applied.
+type Runner struct {
+	store       eventstore.Store
+	checkpoints CheckpointStore
+	projections []Projection
+
+	// BatchSize is the number of events read per page.
+	BatchSize int
+	// PollInterval is how long to wait after catching up with the log.
+	PollInterval time.Duration
+	// MinBackoff and MaxBackoff bound the retry delay after a failure.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// NewRunner creates a Runner with default tuning.
+func NewRunner(store eventstore.Store, checkpoints CheckpointStore, projections ...Projection) *Runner {
+	return &Runner{
+		store:        store,
+		checkpoints:  checkpoints,
+		projections:  projections,
+		BatchSize:    defaultBatchSize,
+		PollInterval: defaultPollInterval,
+		MinBackoff:   defaultMinBackoff,
+		MaxBackoff:   defaultMaxBackoff,
+	}
+}
+
+// Run processes events until ctx is cancelled, then waits for every
+// projection to finish its current event and returns.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, p := range r.projections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.run(ctx, p)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *Runner) run(ctx context.Context, p Projection) {
+	name := p.Name()
+
+	var pos eventstore.Position
+	ok := r.retry(ctx, name, "load checkpoint", func() error {
+		var err error
+		pos, err = r.checkpoints.Load(ctx, name)
+		return err
+	})
+	if !ok {
+		return
+	}
+	log.Printf("projection %s: starting after position %d", name, pos)
+
+	for {
+		var events []eventstore.Event
+		ok := r.retry(ctx, name, "read events", func() error {
+			var err error
+			events, err = r.store.ReadAll(ctx, pos, r.BatchSize)
+			return err
+		})
+		if !ok {
+			return
+		}
+
+		if len(events) == 0 {
+			if !sleep(ctx, r.PollInterval) {
+				return
+			}
+			continue
+		}
+
+		for _, event := range events {
+			ok := r.retry(ctx, name, "apply event", func() error {
+				return p.Apply(ctx, event)
+			})
+			if !ok {
+				return
+			}
+			pos = event.Position()
+		}
+
+		ok = r.retry(ctx, name, "save checkpoint", func() error {
+			return r.checkpoints.Save(ctx, name, pos)
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
+// retry calls fn until it succeeds, doubling the delay between attempts.
+// It returns false if ctx is cancelled first.
+func (r *Runner) retry(ctx context.Context, name, op string, fn func() error) bool {
+	backoff := r.MinBackoff
+	for {
+		err := fn()
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		log.Printf("projection %s: %s: %v (retrying in %s)", name, op, err, backoff)
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = min(backoff*2, r.MaxBackoff)
+	}
+}
+
+// sleep waits for d and returns false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
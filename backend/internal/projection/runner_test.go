@@ -0,0 +1,153 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// fakeStore serves ReadAll from a fixed slice of events.
+type fakeStore struct {
+	eventstore.Store
+	events []eventstore.Event
+}
+
+func (s *fakeStore) ReadAll(_ context.Context, after eventstore.Position, limit int) ([]eventstore.Event, error) {
+	var page []eventstore.Event
+	for _, e := range s.events {
+		if e.Position() > after && len(page) < limit {
+			page = append(page, e)
+		}
+	}
+	return page, nil
+}
+
+// recordingProjection records applied positions and fails the first
+// failures calls to Apply.
+type recordingProjection struct {
+	mu       sync.Mutex
+	applied  []eventstore.Position
+	failures int
+}
+
+func (p *recordingProjection) Name() string { return "recording" }
+
+func (p *recordingProjection) Apply(_ context.Context, e eventstore.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("transient failure")
+	}
+	p.applied = append(p.applied, e.Position())
+	return nil
+}
+
+func (p *recordingProjection) appliedPositions() []eventstore.Position {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]eventstore.Position(nil), p.applied...)
+}
+
+// runUntil runs the runner until cond holds, then stops it and waits for it
+// to return.
+func runUntil(t *testing.T, r *Runner, cond func() bool) {
+	t.Helper()
+
+	r.PollInterval = time.Millisecond
+	r.MinBackoff = time.Millisecond
+	r.BatchSize = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatal("timed out waiting for runner")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("runner did not stop after cancel")
+	}
+}
+
+func eventsAt(positions ...uint64) []eventstore.Event {
+	events := make([]eventstore.Event, len(positions))
+	for i, pos := range positions {
+		events[i] = eventstore.Event{ID: pos}
+	}
+	return events
+}
+
+func TestRunner_AppliesInOrderFromCheckpoint(t *testing.T) {
+	// Positions are not contiguous, as with MySQL auto-increment gaps.
+	store := &fakeStore{events: eventsAt(1, 2, 5, 9, 10)}
+	checkpoints := NewMemoryCheckpointStore()
+	if err := checkpoints.Save(context.Background(), "recording", 2); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	p := &recordingProjection{}
+
+	runUntil(t, NewRunner(store, checkpoints, p), func() bool {
+		pos, _ := checkpoints.Load(context.Background(), "recording")
+		return pos == 10
+	})
+
+	got := p.appliedPositions()
+	want := []eventstore.Position{5, 9, 10}
+	if len(got) != len(want) {
+		t.Fatalf("applied = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("applied = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRunner_RetriesFailedApply(t *testing.T) {
+	store := &fakeStore{events: eventsAt(1, 2, 3)}
+	checkpoints := NewMemoryCheckpointStore()
+	p := &recordingProjection{failures: 3}
+
+	runUntil(t, NewRunner(store, checkpoints, p), func() bool {
+		pos, _ := checkpoints.Load(context.Background(), "recording")
+		return pos == 3
+	})
+
+	if got := p.appliedPositions(); len(got) != 3 {
+		t.Errorf("applied = %v, want every event exactly once", got)
+	}
+}
+
+func TestRunner_StopsWhileFailing(t *testing.T) {
+	store := &fakeStore{events: eventsAt(1)}
+	checkpoints := NewMemoryCheckpointStore()
+	p := &recordingProjection{failures: 1 << 30}
+
+	r := NewRunner(store, checkpoints, p)
+	r.MinBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r.Run(ctx)
+
+	if pos, _ := checkpoints.Load(context.Background(), "recording"); pos != 0 {
+		t.Errorf("checkpoint = %d, want 0", pos)
+	}
+}
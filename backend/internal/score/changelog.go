@@ -0,0 +1,46 @@
+package score
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/outbox"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+// ErrBehind is returned by ChangeLog when the score read model has not
+// applied the event asked for yet. It wraps outbox.ErrNotReady, so outbox
+// subscribers that return it have the event redelivered once the read
+// model catches up, however long that takes.
+var ErrBehind = fmt.Errorf("score read model is behind: %w", outbox.ErrNotReady)
+
+// ChangeLog reads a book's changes from the score read model as they stood
+// at a given event, for outbox subscribers that judge an event by the log
+// up to it. Subscribers run independently of projections, so ChangeLog
+// refuses to answer until the read model has caught up with the event.
+type ChangeLog struct {
+	changes     Reader
+	checkpoints projection.CheckpointStore
+}
+
+// NewChangeLog creates a ChangeLog. checkpoints must be the store the score
+// projector saves its checkpoint in.
+func NewChangeLog(changes Reader, checkpoints projection.CheckpointStore) *ChangeLog {
+	return &ChangeLog{changes: changes, checkpoints: checkpoints}
+}
+
+// ExpensesThrough returns the changes to the expenses in bookID up to and
+// including the event at last, in log order, or ErrBehind if the read
+// model has not applied that event yet.
+func (l *ChangeLog) ExpensesThrough(ctx context.Context, bookID string, last eventstore.Position) ([]expense.Change, error) {
+	applied, err := l.checkpoints.Load(ctx, projectionName)
+	if err != nil {
+		return nil, fmt.Errorf("load checkpoint: %w", err)
+	}
+	if applied < last {
+		return nil, fmt.Errorf("expenses through %d, applied %d: %w", last, applied, ErrBehind)
+	}
+	return l.changes.ExpensesThrough(ctx, bookID, last)
+}
@@ -0,0 +1,152 @@
+package score
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+const (
+	defaultGhosts = 3
+	maxGhosts     = 12
+	defaultMonths = 12
+	maxMonths     = 24
+)
+
+// Handler handles HTTP requests for the score board.
+// Scores are computed on every request from the book's expense and income
+// changes rather than from totals, so they can be recomputed as of any past
+// day. The changes come from a read model that is updated asynchronously,
+// so an expense recorded a moment ago may not count yet.
+//
+// Every route must be behind middleware.Authenticate; scores only count the
+// expenses and income in the request user's personal book.
+type Handler struct {
+	changes Reader
+	// now returns the current time; today is its date in its location.
+	now func() time.Time
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(changes Reader) *Handler {
+	return &Handler{changes: changes, now: time.Now}
+}
+
+// Register adds score routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /scores/current", h.CurrentScore)
+	mux.HandleFunc("GET /scores/history", h.ScoreHistory)
+}
+
+// CurrentScore handles GET /scores/current?as_of=YYYY-MM-DD&ghosts=N.
+// as_of defaults to today and ghosts, the number of previous months the
+// ghost averages, to 3.
+func (h *Handler) CurrentScore(w http.ResponseWriter, r *http.Request) {
+	asOf, err := h.asOf(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	ghosts, err := queryInt(r, "ghosts", defaultGhosts, maxGhosts)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	changes, incomes, err := h.changesUntil(r.Context(), asOf)
+	if err != nil {
+		log.Printf("current score: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, Current(changes, incomes, asOf, ghosts, h.now().Location()))
+}
+
+// ScoreHistory handles GET /scores/history?as_of=YYYY-MM-DD&months=N&ghosts=N.
+// It returns the final result of each of the last months months, 12 by
+// default, oldest first.
+func (h *Handler) ScoreHistory(w http.ResponseWriter, r *http.Request) {
+	asOf, err := h.asOf(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	months, err := queryInt(r, "months", defaultMonths, maxMonths)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	ghosts, err := queryInt(r, "ghosts", defaultGhosts, maxGhosts)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	changes, incomes, err := h.changesUntil(r.Context(), asOf)
+	if err != nil {
+		log.Printf("score history: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, History(changes, incomes, asOf, months, ghosts, h.now().Location()))
+}
+
+// changesUntil reads the changes to the request user's expenses and income
+// up to the end of asOf, each in log order.
+func (h *Handler) changesUntil(ctx context.Context, asOf time.Time) ([]expense.Change, []income.Change, error) {
+	changes, incomes, err := h.changes.Changes(ctx, middleware.UserID(ctx), endOfDay(asOf, h.now().Location()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read changes: %w", err)
+	}
+	return changes, incomes, nil
+}
+
+// asOf reads the optional as_of query parameter as a date at midnight UTC,
+// defaulting to today. Days after today are rejected.
+func (h *Handler) asOf(r *http.Request) (time.Time, error) {
+	y, m, d := h.now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	s := r.URL.Query().Get("as_of")
+	if s == "" {
+		return today, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, errors.New("as_of must be in YYYY-MM-DD format")
+	}
+	if t.After(today) {
+		return time.Time{}, errors.New("as_of must not be in the future")
+	}
+	return t, nil
+}
+
+// queryInt reads an optional integer query parameter between 1 and limit.
+func queryInt(r *http.Request, key string, fallback, limit int) (int, error) {
+	s := r.URL.Query().Get(key)
+	if s == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > limit {
+		return 0, fmt.Errorf("%s must be an integer between 1 and %d", key, limit)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
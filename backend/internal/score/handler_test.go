@@ -0,0 +1,163 @@
+package score_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+)
+
+// newServer serves the score routes to alice over a score read model
+// projecting the given expenses of hers, recorded now, her salary of 3000 on
+// the first of this month, and an expense and income of bob's that must
+// never count.
+func newServer(t *testing.T, expenses ...expense.RecordExpenseCommand) *httptest.Server {
+	t.Helper()
+
+	changes := score.NewMemoryRepository()
+	projector := score.NewProjector(changes)
+	store := eventstore.NewMemoryStore()
+	store.OnAppend = func(events []eventstore.Event) error {
+		for _, event := range events {
+			if err := projector.Apply(context.Background(), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	appendAs := func(userID, id string, cmd expense.RecordExpenseCommand) {
+		event, err := expense.RecordExpense(id, cmd)
+		if err != nil {
+			t.Fatalf("record expense: %v", err)
+		}
+		event.RecordedBy = userID
+		if err := store.Append(context.Background(), []eventstore.Event{event}, 0); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	for i, cmd := range expenses {
+		appendAs("alice", string(rune('a'+i)), cmd)
+	}
+	appendAs("bob", "bob", expense.RecordExpenseCommand{Amount: 99999, Category: "食費", Date: time.Now().Format(time.DateOnly)})
+
+	now := time.Now()
+	for id, cmd := range map[string]income.RecordIncomeCommand{
+		"alice-salary": {BookID: "alice", Amount: 3000, Source: income.SourceSalary, Date: now.AddDate(0, 0, 1-now.Day()).Format(time.DateOnly)},
+		"bob-salary":   {BookID: "bob", Amount: 99999, Source: income.SourceSalary, Date: now.Format(time.DateOnly)},
+	} {
+		event, err := income.RecordIncome(id, cmd)
+		if err != nil {
+			t.Fatalf("record income: %v", err)
+		}
+		if err := store.Append(context.Background(), []eventstore.Event{event}, 0); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	score.NewHandler(changes).Register(mux)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(middleware.WithUser(r.Context(), "alice")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestCurrentScore(t *testing.T) {
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	lastMonth := thisMonth.AddDate(0, -1, 0)
+	srv := newServer(t,
+		expense.RecordExpenseCommand{Amount: 2000, Category: "食費", Date: lastMonth.Format(time.DateOnly)},
+		expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: thisMonth.Format(time.DateOnly)},
+	)
+
+	var b score.Board
+	if status := getJSON(t, srv.URL+"/scores/current", &b); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if b.AsOf != now.Format(time.DateOnly) || len(b.GhostMonths) != 1 || b.GhostMonths[0] != lastMonth.Format("2006-01") {
+		t.Errorf("board as of %s racing %v, want as of today racing last month", b.AsOf, b.GhostMonths)
+	}
+	if b.Spent != 1000 || b.GhostSpent != 2000 || b.Lead != 1000 || b.Score == nil || *b.Score != 750 {
+		t.Errorf("result = %+v, want 1000 under a 2000 ghost scoring 750", b.Result)
+	}
+	if b.Income != 3000 || b.Saved != 2000 || b.SavedMore == nil || !*b.SavedMore {
+		t.Errorf("savings = %+v, want 2000 of 3000 saved, more than last month", b.Savings)
+	}
+
+	// Yesterday nothing had been recorded yet.
+	yesterday := now.AddDate(0, 0, -1).Format(time.DateOnly)
+	var before score.Board
+	if status := getJSON(t, srv.URL+"/scores/current?as_of="+yesterday, &before); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if before.Spent != 0 || len(before.GhostMonths) != 0 || before.Score != nil {
+		t.Errorf("board as of yesterday = %+v, want nothing recorded", before.Result)
+	}
+}
+
+func TestScoreHistory(t *testing.T) {
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	srv := newServer(t,
+		expense.RecordExpenseCommand{Amount: 2000, Category: "食費", Date: thisMonth.AddDate(0, -2, 0).Format(time.DateOnly)},
+		expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: thisMonth.Format(time.DateOnly)},
+	)
+
+	var history []score.MonthResult
+	if status := getJSON(t, srv.URL+"/scores/history?months=2", &history); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if len(history) != 2 || history[0].Month != thisMonth.AddDate(0, -1, 0).Format("2006-01") ||
+		history[1].Month != thisMonth.Format("2006-01") {
+		t.Fatalf("history = %+v, want last month and this month", history)
+	}
+	// Last month raced the month before, which had 2000 by its end.
+	if r := history[0]; r.Spent != 0 || r.GhostSpent != 2000 || r.Score == nil || *r.Score != 1000 {
+		t.Errorf("%s = %+v, want nothing spent against a 2000 ghost", r.Month, r.Result)
+	}
+}
+
+func TestScore_InvalidQuery(t *testing.T) {
+	srv := newServer(t)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+
+	for _, path := range []string{
+		"/scores/current?as_of=2026-02",
+		"/scores/current?as_of=" + tomorrow,
+		"/scores/current?ghosts=0",
+		"/scores/current?ghosts=13",
+		"/scores/history?months=25",
+		"/scores/history?months=x",
+	} {
+		var v any
+		if status := getJSON(t, srv.URL+path, &v); status != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want %d", path, status, http.StatusBadRequest)
+		}
+	}
+}
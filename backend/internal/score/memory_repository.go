@@ -0,0 +1,93 @@
+package score
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// MemoryRepository keeps score changes in a map keyed by kind, ID and
+// version.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	changes map[changeKey]row
+}
+
+type changeKey struct {
+	kind    string
+	id      string
+	version int
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{changes: make(map[changeKey]row)}
+}
+
+// Changes returns the book's changes before cutoff in log order.
+func (r *MemoryRepository) Changes(_ context.Context, bookID string, cutoff time.Time) ([]expense.Change, []income.Change, error) {
+	expenses, incomes := split(r.query(func(c row) bool {
+		return c.book == bookID && c.occurredAt.Before(cutoff)
+	}))
+	return expenses, incomes, nil
+}
+
+// ExpensesThrough returns the book's expense changes up to last in log
+// order.
+func (r *MemoryRepository) ExpensesThrough(_ context.Context, bookID string, last eventstore.Position) ([]expense.Change, error) {
+	expenses, _ := split(r.query(func(c row) bool {
+		return c.book == bookID && c.kind == kindExpense && c.eventID <= uint64(last)
+	}))
+	return expenses, nil
+}
+
+// query returns the changes matching keep in log order.
+func (r *MemoryRepository) query(keep func(row) bool) []row {
+	r.mu.RLock()
+	var changes []row
+	for _, c := range r.changes {
+		if keep(c) {
+			changes = append(changes, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(changes, func(a, b row) int {
+		return cmp.Compare(a.eventID, b.eventID)
+	})
+	return changes
+}
+
+// AddExpense stores c in the expense's book.
+func (r *MemoryRepository) AddExpense(_ context.Context, eventID uint64, c expense.Change) error {
+	return r.add(expenseRow(eventID, c))
+}
+
+// AddIncome stores c in the income's book.
+func (r *MemoryRepository) AddIncome(_ context.Context, eventID uint64, c income.Change) error {
+	return r.add(incomeRow(eventID, c))
+}
+
+func (r *MemoryRepository) add(c row) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !c.recorded {
+		recording, ok := r.changes[changeKey{c.kind, c.id, 1}]
+		if !ok {
+			return errNotProjected
+		}
+		c.book = recording.book
+	}
+	key := changeKey{c.kind, c.id, c.version}
+	if _, ok := r.changes[key]; !ok {
+		r.changes[key] = c
+	}
+	return nil
+}
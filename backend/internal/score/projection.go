@@ -0,0 +1,74 @@
+package score
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the score read model's checkpoint.
+	projectionName = "score"
+	// changesTable holds every change to an expense or income, keyed by
+	// book.
+	changesTable = "score_changes"
+)
+
+// Projector logs every version of each expense and income in the score read
+// model, which compares books against their own past.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL score projection for rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{changesTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{db: db, changes: tables[changesTable]})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply adds the change an expense or income event makes to its book's
+// change log. Other events are ignored.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	if err := p.apply(ctx, event); err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
+}
+
+func (p *Projector) apply(ctx context.Context, event eventstore.Event) error {
+	switch event.AggregateType {
+	case expense.AggregateType:
+		c, _, err := expense.DecodeChange(event)
+		if err != nil {
+			return err
+		}
+		return p.w.AddExpense(ctx, event.ID, c)
+	case income.AggregateType:
+		c, _, err := income.DecodeChange(event)
+		if err != nil {
+			return err
+		}
+		return p.w.AddIncome(ctx, event.ID, c)
+	}
+	return nil
+}
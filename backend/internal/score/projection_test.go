@@ -0,0 +1,197 @@
+package score_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/outbox"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() score.ReadModel { return score.NewMemoryRepository() },
+	func(db *sql.DB) score.ReadModel { return score.NewRepository(db) },
+)
+
+// day returns the noon of the given day in March 2026, when the test's
+// changes are recorded.
+func day(d int) time.Time {
+	return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestProjector(t *testing.T) {
+	var events []eventstore.Event
+	add := func(by string, at time.Time, event eventstore.Event, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("build event: %v", err)
+		}
+		event.ID = uint64(len(events) + 1)
+		event.RecordedBy = by
+		event.OccurredAt = at
+		events = append(events, event)
+	}
+	spend := func(by, id, bookID string, amount int64, at time.Time) *expense.Expense {
+		t.Helper()
+		event, err := expense.RecordExpense(id, expense.RecordExpenseCommand{
+			BookID: bookID, Amount: amount, Category: "食費", Date: at.Format(time.DateOnly),
+		})
+		add(by, at, event, err)
+		exp, err := expense.LoadExpense([]eventstore.Event{event})
+		if err != nil {
+			t.Fatalf("load %s: %v", id, err)
+		}
+		return exp
+	}
+
+	lunch := spend("alice", "lunch", "", 1000, day(3))
+	dinner := spend("alice", "dinner", "", 4000, day(4))
+	spend("bob", "bob", "", 9999, day(4))
+	spend("alice", "shared", "h1", 3000, day(4))
+	event, err := income.RecordIncome("salary", income.RecordIncomeCommand{
+		BookID: "alice", Amount: 250000, Source: income.SourceSalary, Date: "2026-03-05",
+	})
+	add("alice", day(5), event, err)
+	event, err = lunch.Correct(expense.CorrectExpenseCommand{Amount: ptr[int64](1200)})
+	add("alice", day(6), event, err)
+	event, err = dinner.Void(expense.VoidExpenseCommand{})
+	add("alice", day(7), event, err)
+
+	tests := []struct {
+		cutoff   time.Time
+		expenses []string
+		incomes  []string
+	}{
+		{day(6), []string{"lunch v1 1000 2026-03-03", "dinner v1 4000 2026-03-04"}, []string{"salary v1 250000 2026-03-05"}},
+		{day(8), []string{"lunch v1 1000 2026-03-03", "dinner v1 4000 2026-03-04", "lunch v2 1200 2026-03-03", "dinner v2 voided"},
+			[]string{"salary v1 250000 2026-03-05"}},
+	}
+
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := score.NewProjector(repo)
+			ctx := context.Background()
+			// Every event is applied twice, as after a crash between applying
+			// and saving the checkpoint.
+			for range 2 {
+				for _, event := range events {
+					if err := projector.Apply(ctx, event); err != nil {
+						t.Fatalf("apply %s: %v", event.EventType, err)
+					}
+				}
+			}
+
+			for _, tt := range tests {
+				expenses, incomes, err := repo.Changes(ctx, "alice", tt.cutoff)
+				if err != nil {
+					t.Fatalf("Changes: %v", err)
+				}
+				var gotExpenses, gotIncomes []string
+				for _, c := range expenses {
+					gotExpenses = append(gotExpenses, describe(c.ID, c.Version, c.Voided, c.Amount, c.Date, c.OccurredAt, c.Book))
+				}
+				for _, c := range incomes {
+					gotIncomes = append(gotIncomes, describe(c.ID, c.Version, c.Voided, c.Amount, c.Date, c.OccurredAt, c.Book))
+				}
+				if !slices.Equal(gotExpenses, tt.expenses) || !slices.Equal(gotIncomes, tt.incomes) {
+					t.Errorf("Changes before %s = %v and %v, want %v and %v",
+						tt.cutoff, gotExpenses, gotIncomes, tt.expenses, tt.incomes)
+				}
+			}
+
+			// The expenses up to lunch's correction, which is event 6.
+			expenses, err := repo.ExpensesThrough(ctx, "alice", 6)
+			if err != nil {
+				t.Fatalf("ExpensesThrough: %v", err)
+			}
+			var got []string
+			for _, c := range expenses {
+				got = append(got, describe(c.ID, c.Version, c.Voided, c.Amount, c.Date, c.OccurredAt, c.Book)+" "+c.Category)
+			}
+			want := []string{"lunch v1 1000 2026-03-03 食費", "dinner v1 4000 2026-03-04 食費", "lunch v2 1200 2026-03-03 食費"}
+			if !slices.Equal(got, want) {
+				t.Errorf("ExpensesThrough(6) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestProjector_VoidBeforeRecording(t *testing.T) {
+	recorded, err := expense.RecordExpense("lunch", expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-03-03"})
+	if err != nil {
+		t.Fatalf("RecordExpense: %v", err)
+	}
+	exp, err := expense.LoadExpense([]eventstore.Event{recorded})
+	if err != nil {
+		t.Fatalf("LoadExpense: %v", err)
+	}
+	voided, err := exp.Void(expense.VoidExpenseCommand{})
+	if err != nil {
+		t.Fatalf("Void: %v", err)
+	}
+	voided.ID = 2
+
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			if err := score.NewProjector(b.Open(t)).Apply(context.Background(), voided); err == nil {
+				t.Error("Apply succeeded, want an error for a void of an unknown expense")
+			}
+		})
+	}
+}
+
+func TestChangeLog_Behind(t *testing.T) {
+	ctx := context.Background()
+	repo := score.NewMemoryRepository()
+	checkpoints := projection.NewMemoryCheckpointStore()
+	event, err := expense.RecordExpense("lunch", expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-03-03"})
+	if err != nil {
+		t.Fatalf("RecordExpense: %v", err)
+	}
+	event.ID = 1
+	event.RecordedBy = "alice"
+	if err := score.NewProjector(repo).Apply(ctx, event); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	log := score.NewChangeLog(repo, checkpoints)
+
+	// The event is applied, but the runner has not saved its checkpoint.
+	if _, err := log.ExpensesThrough(ctx, "alice", 1); !errors.Is(err, score.ErrBehind) || !errors.Is(err, outbox.ErrNotReady) {
+		t.Errorf("ExpensesThrough before the checkpoint: err = %v, want ErrBehind and outbox.ErrNotReady", err)
+	}
+
+	if err := checkpoints.Save(ctx, score.NewProjector(repo).Name(), 1); err != nil {
+		t.Fatalf("save checkpoint: %v", err)
+	}
+	got, err := log.ExpensesThrough(ctx, "alice", 1)
+	if err != nil || len(got) != 1 || got[0].ID != "lunch" {
+		t.Errorf("ExpensesThrough after the checkpoint = %+v, %v, want lunch", got, err)
+	}
+}
+
+// describe formats a change with its book left out when it is alice's, and
+// its time checked to be the noon of its day.
+func describe(id string, version int, voided bool, amount int64, date string, at time.Time, book string) string {
+	if book != "alice" || !at.Equal(day(at.Day())) {
+		return fmt.Sprintf("%s v%d in %s at %s", id, version, book, at)
+	}
+	if voided {
+		return fmt.Sprintf("%s v%d voided", id, version)
+	}
+	return fmt.Sprintf("%s v%d %d %s", id, version, amount, date)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
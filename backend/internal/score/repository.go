@@ -0,0 +1,205 @@
+package score
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// errNotProjected is returned by Writer when a correction or void targets an
+// expense or income whose recording has not been applied yet.
+var errNotProjected = errors.New("change is not projected yet")
+
+// Reader is the query side of the score read model.
+type Reader interface {
+	// Changes returns the changes to the expenses and income in bookID
+	// that occurred before cutoff, each in log order. Only the fields
+	// scores read are set.
+	Changes(ctx context.Context, bookID string, cutoff time.Time) ([]expense.Change, []income.Change, error)
+	// ExpensesThrough returns the changes to the expenses in bookID up to
+	// and including the event at last, in log order. Only the fields
+	// scores read and Category are set.
+	ExpensesThrough(ctx context.Context, bookID string, last eventstore.Position) ([]expense.Change, error)
+}
+
+// Writer is the projection side of the score read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// AddExpense stores c, the change eventID made, in the expense's book.
+	// Returns errNotProjected if c is not a recording and the expense is
+	// unknown.
+	AddExpense(ctx context.Context, eventID uint64, c expense.Change) error
+	// AddIncome is AddExpense for income.
+	AddIncome(ctx context.Context, eventID uint64, c income.Change) error
+}
+
+// ReadModel combines both sides of the score read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+const (
+	kindExpense = "expense"
+	kindIncome  = "income"
+)
+
+// row is an expense or income change, reduced to what the change log
+// keeps.
+type row struct {
+	kind     string
+	id       string
+	version  int
+	recorded bool
+	book     string
+	eventID  uint64
+	voided   bool
+	amount   int64
+	// category is empty for income.
+	category   string
+	date       string
+	occurredAt time.Time
+}
+
+func expenseRow(eventID uint64, c expense.Change) row {
+	return row{kind: kindExpense, id: c.ID, version: c.Version, recorded: c.Recorded(), book: c.Book,
+		eventID: eventID, voided: c.Voided, amount: c.Amount, category: c.Category, date: c.Date,
+		occurredAt: c.OccurredAt}
+}
+
+func incomeRow(eventID uint64, c income.Change) row {
+	return row{kind: kindIncome, id: c.ID, version: c.Version, recorded: c.Recorded(), book: c.Book,
+		eventID: eventID, voided: c.Voided, amount: c.Amount, date: c.Date, occurredAt: c.OccurredAt}
+}
+
+func (r row) expense() expense.Change {
+	return expense.Change{ID: r.id, Version: r.version, Voided: r.voided, Amount: r.amount, Category: r.category,
+		Date: r.date, OccurredAt: r.occurredAt, Book: r.book}
+}
+
+func (r row) income() income.Change {
+	return income.Change{ID: r.id, Version: r.version, Voided: r.voided, Amount: r.amount, Date: r.date,
+		OccurredAt: r.occurredAt, Book: r.book}
+}
+
+// split sorts rows, which must be in log order, into expense and income
+// changes.
+func split(rows []row) ([]expense.Change, []income.Change) {
+	var expenses []expense.Change
+	var incomes []income.Change
+	for _, r := range rows {
+		if r.kind == kindExpense {
+			expenses = append(expenses, r.expense())
+		} else {
+			incomes = append(incomes, r.income())
+		}
+	}
+	return expenses, incomes
+}
+
+// Repository implements ReadModel on top of MySQL. Every change is stored
+// with the book of the expense or income it changed, so a book's changes
+// are read without the rest of the log.
+type Repository struct {
+	db      *sql.DB
+	changes string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, changes: changesTable}
+}
+
+// Changes returns the book's changes before cutoff in log order.
+func (r *Repository) Changes(ctx context.Context, bookID string, cutoff time.Time) ([]expense.Change, []income.Change, error) {
+	rows, err := r.query(ctx, `book_id = ? AND occurred_at < ?`, bookID, cutoff.UTC())
+	if err != nil {
+		return nil, nil, err
+	}
+	expenses, incomes := split(rows)
+	return expenses, incomes, nil
+}
+
+// ExpensesThrough returns the book's expense changes up to last in log
+// order.
+func (r *Repository) ExpensesThrough(ctx context.Context, bookID string, last eventstore.Position) ([]expense.Change, error) {
+	rows, err := r.query(ctx, `book_id = ? AND kind = ? AND event_id <= ?`, bookID, kindExpense, uint64(last))
+	if err != nil {
+		return nil, err
+	}
+	expenses, _ := split(rows)
+	return expenses, nil
+}
+
+// query returns the changes matching where in log order.
+func (r *Repository) query(ctx context.Context, where string, args ...any) ([]row, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT kind, ref_id, version, book_id, event_id, voided, amount, category,
+		        COALESCE(DATE_FORMAT(date, '%%Y-%%m-%%d'), ''), occurred_at
+		 FROM %s WHERE %s
+		 ORDER BY event_id`, r.changes, where),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []row
+	for rows.Next() {
+		var c row
+		if err := rows.Scan(&c.kind, &c.id, &c.version, &c.book, &c.eventID, &c.voided, &c.amount, &c.category,
+			&c.date, &c.occurredAt); err != nil {
+			return nil, fmt.Errorf("scan change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate changes: %w", err)
+	}
+	return changes, nil
+}
+
+// AddExpense stores c in the expense's book.
+func (r *Repository) AddExpense(ctx context.Context, eventID uint64, c expense.Change) error {
+	return r.add(ctx, expenseRow(eventID, c))
+}
+
+// AddIncome stores c in the income's book.
+func (r *Repository) AddIncome(ctx context.Context, eventID uint64, c income.Change) error {
+	return r.add(ctx, incomeRow(eventID, c))
+}
+
+func (r *Repository) add(ctx context.Context, c row) error {
+	if !c.recorded {
+		err := r.db.QueryRowContext(ctx, fmt.Sprintf(
+			`SELECT book_id FROM %s WHERE kind = ? AND ref_id = ? AND version = 1`, r.changes), c.kind, c.id,
+		).Scan(&c.book)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errNotProjected
+		case err != nil:
+			return fmt.Errorf("load %s book: %w", c.kind, err)
+		}
+	}
+
+	var date any
+	if c.date != "" {
+		date = c.date
+	}
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (kind, ref_id, version, book_id, event_id, voided, amount, category, date, occurred_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE version = version`, r.changes),
+		c.kind, c.id, c.version, c.book, c.eventID, c.voided, c.amount, c.category, date, c.occurredAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("save %s change: %w", c.kind, err)
+	}
+	return nil
+}
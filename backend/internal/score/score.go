@@ -0,0 +1,292 @@
+package score
+
+import (
+	"math"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+const monthLayout = "2006-01"
+
+// Board races a month's spending against its ghost: the average cumulative
+// spending of the months before it, day by day.
+type Board struct {
+	Month string `json:"month"`
+	// AsOf is the day the board was computed for. Only expenses recorded by
+	// the end of that day count, so a board for a past day is reproducible.
+	AsOf        string `json:"as_of"`
+	Day         int    `json:"day"`
+	DaysInMonth int    `json:"days_in_month"`
+	// GhostMonths are the months averaged into the ghost, newest first.
+	// Months before the first expense are left out, so it may be empty.
+	GhostMonths []string `json:"ghost_months"`
+	Result
+	// Days covers the whole month so the ghost can be drawn ahead of today.
+	Days []DayPoint `json:"days"`
+}
+
+// Result is how a month stands against its ghost on a given day.
+type Result struct {
+	// Spent is the month's cumulative spending up to and including the day.
+	Spent int64 `json:"spent"`
+	// GhostSpent is the ghost's cumulative spending by the same day.
+	GhostSpent int64 `json:"ghost_spent"`
+	// Lead is how far under the ghost the month is; negative when over.
+	Lead int64 `json:"lead"`
+	// Score is nil when there is no ghost to race.
+	Score *int `json:"score"`
+	Savings
+}
+
+// Savings is how much of its income a month kept, against the month before.
+type Savings struct {
+	// Income is the month's cumulative income up to and including the day.
+	Income int64 `json:"income"`
+	// Saved is Income minus Spent; negative when the month spent more than
+	// it earned.
+	Saved int64 `json:"saved"`
+	// PreviousSaved is what the previous month had saved by the same day,
+	// or by its end on the last day of this month.
+	PreviousSaved int64 `json:"previous_saved"`
+	// SavedMore reports whether Saved beats PreviousSaved. It is nil when
+	// the previous month is before the first expense.
+	SavedMore *bool `json:"saved_more"`
+}
+
+// DayPoint is the cumulative spending at one day of the month.
+type DayPoint struct {
+	Day  int    `json:"day"`
+	Date string `json:"date"`
+	// Spent is nil for days after AsOf.
+	Spent *int64 `json:"spent"`
+	// Ghost is nil when there is no ghost.
+	Ghost *int64 `json:"ghost"`
+}
+
+// MonthResult is a month's final standing in the score history.
+type MonthResult struct {
+	Month string `json:"month"`
+	// AsOf is the last day of the month, or the requested day for the
+	// month still in progress.
+	AsOf string `json:"as_of"`
+	Result
+}
+
+// ledger holds the spending and income per date of the expenses and income
+// known at some moment.
+type ledger struct {
+	daily  map[string]int64
+	income map[string]int64
+	// first is the first day of the month of the earliest expense, or the
+	// zero time if there are none.
+	first time.Time
+}
+
+// change is the part of an expense or income change that ledgers need.
+type change struct {
+	id         string
+	voided     bool
+	amount     int64
+	date       string
+	occurredAt time.Time
+}
+
+// replay folds expense and income changes, each in log order, into the
+// ledger as it stood just before cutoff. Changes that occurred at or after
+// cutoff are ignored.
+func replay(expenses []expense.Change, incomes []income.Change, cutoff time.Time) ledger {
+	l := ledger{
+		daily: fold(expenses, cutoff, func(c expense.Change) change {
+			return change{c.ID, c.Voided, c.Amount, c.Date, c.OccurredAt}
+		}),
+		income: fold(incomes, cutoff, func(c income.Change) change {
+			return change{c.ID, c.Voided, c.Amount, c.Date, c.OccurredAt}
+		}),
+	}
+	var earliest string
+	for date := range l.daily {
+		if earliest == "" || date < earliest {
+			earliest = date
+		}
+	}
+	if earliest != "" {
+		d, _ := time.Parse(time.DateOnly, earliest)
+		l.first = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return l
+}
+
+// fold returns the amounts per date of the entries left after the changes
+// that occurred before cutoff.
+func fold[C any](changes []C, cutoff time.Time, view func(C) change) map[string]int64 {
+	type state struct {
+		amount int64
+		date   string
+	}
+	entries := make(map[string]state)
+	for _, c := range changes {
+		c := view(c)
+		if !c.occurredAt.Before(cutoff) {
+			continue
+		}
+		if c.voided {
+			delete(entries, c.id)
+			continue
+		}
+		entries[c.id] = state{amount: c.amount, date: c.date}
+	}
+
+	daily := make(map[string]int64)
+	for _, e := range entries {
+		daily[e.date] += e.amount
+	}
+	return daily
+}
+
+// curve returns the cumulative amounts in daily of the month starting at
+// start for days 1 through days. Days past the end of a shorter month carry
+// its total.
+func curve(daily map[string]int64, start time.Time, days int) []int64 {
+	curve := make([]int64, days)
+	var sum int64
+	for i := range days {
+		if d := start.AddDate(0, 0, i); d.Month() == start.Month() {
+			sum += daily[d.Format(time.DateOnly)]
+		}
+		curve[i] = sum
+	}
+	return curve
+}
+
+// savings compares what the month starting at start had saved by day,
+// having spent spent, with what the previous month had saved by then.
+func (l ledger) savings(start time.Time, day int, spent int64) Savings {
+	prev := start.AddDate(0, -1, 0)
+	// On its last day the month is over, so it is set against the whole of
+	// the previous month even if that was longer.
+	if days := start.AddDate(0, 1, -1).Day(); day == days {
+		day = max(days, prev.AddDate(0, 1, -1).Day())
+	}
+
+	s := Savings{Income: curve(l.income, start, day)[day-1]}
+	s.Saved = s.Income - spent
+	if l.first.IsZero() || prev.Before(l.first) {
+		return s
+	}
+	s.PreviousSaved = curve(l.income, prev, day)[day-1] - curve(l.daily, prev, day)[day-1]
+	more := s.Saved > s.PreviousSaved
+	s.SavedMore = &more
+	return s
+}
+
+// board races asOf's month against the ghost of up to ghosts months before
+// it, using the expenses in l. asOf must be a date at midnight UTC.
+func (l ledger) board(asOf time.Time, ghosts int) Board {
+	start := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	days := start.AddDate(0, 1, -1).Day()
+
+	var ghostMonths []time.Time
+	for i := 1; i <= ghosts; i++ {
+		m := start.AddDate(0, -i, 0)
+		if l.first.IsZero() || m.Before(l.first) {
+			break
+		}
+		ghostMonths = append(ghostMonths, m)
+	}
+
+	var ghost []int64
+	if len(ghostMonths) > 0 {
+		ghost = make([]int64, days)
+		for _, m := range ghostMonths {
+			for i, v := range curve(l.daily, m, days) {
+				ghost[i] += v
+			}
+		}
+		for i := range ghost {
+			ghost[i] = int64(math.Round(float64(ghost[i]) / float64(len(ghostMonths))))
+		}
+	}
+
+	b := Board{
+		Month:       start.Format(monthLayout),
+		AsOf:        asOf.Format(time.DateOnly),
+		Day:         asOf.Day(),
+		DaysInMonth: days,
+		GhostMonths: []string{},
+		Days:        make([]DayPoint, days),
+	}
+	for _, m := range ghostMonths {
+		b.GhostMonths = append(b.GhostMonths, m.Format(monthLayout))
+	}
+
+	spent := curve(l.daily, start, days)
+	for i := range b.Days {
+		p := DayPoint{Day: i + 1, Date: start.AddDate(0, 0, i).Format(time.DateOnly)}
+		if i < b.Day {
+			p.Spent = &spent[i]
+		}
+		if ghost != nil {
+			p.Ghost = &ghost[i]
+		}
+		b.Days[i] = p
+	}
+
+	b.Spent = spent[b.Day-1]
+	if ghost != nil {
+		b.GhostSpent = ghost[b.Day-1]
+		b.Lead = b.GhostSpent - b.Spent
+		s := score(b.Spent, b.GhostSpent)
+		b.Score = &s
+	}
+	b.Savings = l.savings(start, b.Day, b.Spent)
+	return b
+}
+
+// score rates spending against the ghost's from 0 to 1000: 500 for keeping
+// pace, 1000 for spending nothing and 0 for spending twice as much or more.
+func score(spent, ghost int64) int {
+	if ghost == 0 {
+		if spent == 0 {
+			return 500
+		}
+		return 0
+	}
+	s := 500 + 500*float64(ghost-spent)/float64(ghost)
+	return int(math.Round(min(max(s, 0), 1000)))
+}
+
+// Current returns the board for asOf, a date at midnight UTC, from the
+// expense and income changes recorded before the end of that day in loc.
+func Current(changes []expense.Change, incomes []income.Change, asOf time.Time, ghosts int, loc *time.Location) Board {
+	return replay(changes, incomes, endOfDay(asOf, loc)).board(asOf, ghosts)
+}
+
+// History returns the results of up to months months ending with asOf's,
+// oldest first, from the expense and income changes recorded before the end
+// of asOf in loc. Each past month is scored as of its last day. Months before the first
+// expense are left out.
+func History(changes []expense.Change, incomes []income.Change, asOf time.Time, months, ghosts int, loc *time.Location) []MonthResult {
+	l := replay(changes, incomes, endOfDay(asOf, loc))
+	start := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	results := []MonthResult{}
+	for i := months - 1; i >= 0; i-- {
+		m := start.AddDate(0, -i, 0)
+		if l.first.IsZero() || m.Before(l.first) {
+			continue
+		}
+		day := m.AddDate(0, 1, -1)
+		if i == 0 {
+			day = asOf
+		}
+		b := l.board(day, ghosts)
+		results = append(results, MonthResult{Month: b.Month, AsOf: b.AsOf, Result: b.Result})
+	}
+	return results
+}
+
+// endOfDay returns the instant day, a date at midnight UTC, ends in loc.
+func endOfDay(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,212 @@
+package score
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// recordedAt returns the noon of the given day, when the test's changes are
+// recorded.
+func recordedAt(s string) time.Time {
+	return date(s).Add(12 * time.Hour)
+}
+
+// changes is an expense log over three months. January's expense is
+// corrected after the month ends.
+var changes = []expense.Change{
+	{ID: "dec", Version: 1, Amount: 1000, Date: "2025-12-10", OccurredAt: recordedAt("2025-12-10")},
+	{ID: "void", Version: 1, Amount: 9999, Date: "2026-01-02", OccurredAt: recordedAt("2026-01-02")},
+	{ID: "void", Version: 2, Voided: true, OccurredAt: recordedAt("2026-01-03")},
+	{ID: "jan", Version: 1, Amount: 1500, Date: "2026-01-05", OccurredAt: recordedAt("2026-01-05")},
+	{ID: "feb1", Version: 1, Amount: 500, Date: "2026-02-03", OccurredAt: recordedAt("2026-02-03")},
+	{ID: "feb2", Version: 1, Amount: 1000, Date: "2026-02-10", OccurredAt: recordedAt("2026-02-10")},
+	{ID: "jan", Version: 2, Amount: 2500, Date: "2026-01-05", OccurredAt: recordedAt("2026-02-20")},
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCurrent(t *testing.T) {
+	tests := []struct {
+		asOf string
+		want Result
+	}{
+		// The ghost averages January's 1500 and December's 0 by the 7th.
+		{"2026-02-07", Result{Spent: 500, GhostSpent: 750, Lead: 250, Score: ptr(667)}},
+		// By the 28th January's correction is known: (2500 + 1000) / 2.
+		{"2026-02-28", Result{Spent: 1500, GhostSpent: 1750, Lead: 250, Score: ptr(571)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.asOf, func(t *testing.T) {
+			b := Current(changes, nil, date(tt.asOf), 3, time.UTC)
+
+			if b.Month != "2026-02" || b.AsOf != tt.asOf || b.DaysInMonth != 28 || len(b.Days) != 28 {
+				t.Errorf("board = %s as of %s with %d of %d days, want 2026-02 as of %s with 28",
+					b.Month, b.AsOf, len(b.Days), b.DaysInMonth, tt.asOf)
+			}
+			// November comes before the first expense, so it is no ghost.
+			if len(b.GhostMonths) != 2 || b.GhostMonths[0] != "2026-01" || b.GhostMonths[1] != "2025-12" {
+				t.Errorf("ghost months = %v, want [2026-01 2025-12]", b.GhostMonths)
+			}
+			if b.Spent != tt.want.Spent || b.GhostSpent != tt.want.GhostSpent || b.Lead != tt.want.Lead ||
+				b.Score == nil || *b.Score != *tt.want.Score {
+				t.Errorf("result = %+v (score %v), want %+v (score %d)", b.Result, b.Score, tt.want, *tt.want.Score)
+			}
+		})
+	}
+}
+
+func TestCurrent_Days(t *testing.T) {
+	b := Current(changes, nil, date("2026-02-07"), 3, time.UTC)
+
+	tests := []struct {
+		day          int
+		spent, ghost *int64
+	}{
+		{1, ptr[int64](0), ptr[int64](0)},
+		{3, ptr[int64](500), ptr[int64](0)},
+		{7, ptr[int64](500), ptr[int64](750)},
+		{8, nil, ptr[int64](750)},
+		// December's 10th lifts the ghost; February's 10th is still ahead.
+		{10, nil, ptr[int64](1250)},
+	}
+	for _, tt := range tests {
+		p := b.Days[tt.day-1]
+		if p.Day != tt.day || !equal(p.Spent, tt.spent) || !equal(p.Ghost, tt.ghost) {
+			t.Errorf("day %d = %+v, want spent %v and ghost %v", tt.day, p, deref(tt.spent), deref(tt.ghost))
+		}
+	}
+}
+
+func TestCurrent_NoGhost(t *testing.T) {
+	b := Current(changes, nil, date("2025-12-31"), 3, time.UTC)
+
+	if len(b.GhostMonths) != 0 || b.Score != nil || b.Days[0].Ghost != nil {
+		t.Errorf("board = %+v, want no ghost or score", b)
+	}
+	if b.Spent != 1000 {
+		t.Errorf("spent = %d, want 1000", b.Spent)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		asOf string
+		want []MonthResult
+	}{
+		{"2026-02-07", []MonthResult{
+			{Month: "2025-12", AsOf: "2025-12-31", Result: Result{Spent: 1000}},
+			{Month: "2026-01", AsOf: "2026-01-31", Result: Result{Spent: 1500, GhostSpent: 1000, Lead: -500, Score: ptr(250)}},
+			{Month: "2026-02", AsOf: "2026-02-07", Result: Result{Spent: 500, GhostSpent: 750, Lead: 250, Score: ptr(667)}},
+		}},
+		// January is rescored with its correction.
+		{"2026-02-28", []MonthResult{
+			{Month: "2025-12", AsOf: "2025-12-31", Result: Result{Spent: 1000}},
+			{Month: "2026-01", AsOf: "2026-01-31", Result: Result{Spent: 2500, GhostSpent: 1000, Lead: -1500, Score: ptr(0)}},
+			{Month: "2026-02", AsOf: "2026-02-28", Result: Result{Spent: 1500, GhostSpent: 1750, Lead: 250, Score: ptr(571)}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.asOf, func(t *testing.T) {
+			got := History(changes, nil, date(tt.asOf), 12, 3, time.UTC)
+			if len(got) != len(tt.want) {
+				t.Fatalf("history = %+v, want %d months", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Month != w.Month || g.AsOf != w.AsOf || g.Spent != w.Spent || g.GhostSpent != w.GhostSpent ||
+					g.Lead != w.Lead || !equal(g.Score, w.Score) {
+					t.Errorf("history[%d] = %+v (score %v), want %+v (score %v)", i, g, deref(g.Score), w, deref(w.Score))
+				}
+			}
+		})
+	}
+}
+
+// incomes is an income log over the same months as changes.
+var incomes = []income.Change{
+	{ID: "dec", Version: 1, Amount: 3000, Date: "2025-12-25", OccurredAt: recordedAt("2025-12-25")},
+	{ID: "jan", Version: 1, Amount: 3000, Date: "2026-01-25", OccurredAt: recordedAt("2026-01-25")},
+	{ID: "refund", Version: 1, Amount: 200, Date: "2026-02-05", OccurredAt: recordedAt("2026-02-05")},
+	{ID: "void", Version: 1, Amount: 9999, Date: "2026-02-06", OccurredAt: recordedAt("2026-02-06")},
+	{ID: "void", Version: 2, Voided: true, OccurredAt: recordedAt("2026-02-06")},
+	{ID: "feb", Version: 1, Amount: 3000, Date: "2026-02-25", OccurredAt: recordedAt("2026-02-25")},
+}
+
+func TestSavings(t *testing.T) {
+	// By the 7th January had only spent its 1500 as first recorded.
+	b := Current(changes, incomes, date("2026-02-07"), 3, time.UTC)
+	if want := (Savings{Income: 200, Saved: -300, PreviousSaved: -1500, SavedMore: ptr(true)}); !equalSavings(b.Savings, want) {
+		t.Errorf("savings as of 2026-02-07 = %+v, want %+v", b.Savings, want)
+	}
+
+	tests := []struct {
+		month string
+		want  Savings
+	}{
+		// November comes before the first expense, so there is nothing to
+		// beat.
+		{"2025-12", Savings{Income: 3000, Saved: 2000}},
+		{"2026-01", Savings{Income: 3000, Saved: 500, PreviousSaved: 2000, SavedMore: ptr(false)}},
+		// February's last day is set against the whole of January.
+		{"2026-02", Savings{Income: 3200, Saved: 1700, PreviousSaved: 500, SavedMore: ptr(true)}},
+	}
+	got := History(changes, incomes, date("2026-02-28"), 12, 3, time.UTC)
+	if len(got) != len(tests) {
+		t.Fatalf("history = %+v, want %d months", got, len(tests))
+	}
+	for i, tt := range tests {
+		if got[i].Month != tt.month || !equalSavings(got[i].Savings, tt.want) {
+			t.Errorf("history[%d] = %s %+v, want %s %+v", i, got[i].Month, got[i].Savings, tt.month, tt.want)
+		}
+	}
+}
+
+func equalSavings(a, b Savings) bool {
+	return a.Income == b.Income && a.Saved == b.Saved && a.PreviousSaved == b.PreviousSaved && equal(a.SavedMore, b.SavedMore)
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		spent, ghost int64
+		want         int
+	}{
+		{1000, 1000, 500},
+		{0, 1000, 1000},
+		{500, 1000, 750},
+		{2000, 1000, 0},
+		{5000, 1000, 0},
+		{0, 0, 500},
+		{1, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := score(tt.spent, tt.ghost); got != tt.want {
+			t.Errorf("score(%d, %d) = %d, want %d", tt.spent, tt.ghost, got, tt.want)
+		}
+	}
+}
+
+func equal[T comparable](a, b *T) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
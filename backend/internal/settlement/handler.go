@@ -0,0 +1,108 @@
+package settlement
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// firstDate and lastDate bound a period left open on either side. They are
+// the range of MySQL's DATE.
+const (
+	firstDate = "1000-01-01"
+	lastDate  = "9999-12-31"
+)
+
+// Handler handles HTTP requests for household balances.
+// The balances are updated asynchronously by Projector running under a
+// projection.Runner, so they can lag just-recorded expenses for a moment.
+// Every route must be behind middleware.Authenticate. Households the user
+// is not a member of are reported as not found.
+type Handler struct {
+	repo  Reader
+	books expense.Books
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(repo Reader, books expense.Books) *Handler {
+	return &Handler{repo: repo, books: books}
+}
+
+// Register adds settlement routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /households/{id}/balances", h.Balances)
+}
+
+type balancesResponse struct {
+	Balances  []Balance  `json:"balances"`
+	Transfers []Transfer `json:"transfers"`
+}
+
+// Balances handles GET /households/{id}/balances?from=YYYY-MM-DD&to=YYYY-MM-DD.
+// It returns what each member is owed from the split expenses and
+// settlements dated in the period, and the fewest transfers that settle
+// them. Either end of the period may be left out.
+func (h *Handler) Balances(w http.ResponseWriter, r *http.Request) {
+	from, fromErr := parseDate(r, "from", firstDate)
+	to, toErr := parseDate(r, "to", lastDate)
+	if err := errors.Join(fromErr, toErr); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if from > to {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "from must not be after to"})
+		return
+	}
+
+	ctx := r.Context()
+	id := r.PathValue("id")
+	_, err := h.books.Role(ctx, middleware.UserID(ctx), id)
+	if errors.Is(err, household.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("balances: look up role in book %s: %v", id, err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+
+	balances, err := h.repo.Balances(ctx, id, from, to)
+	if err != nil {
+		log.Printf("balances: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	if balances == nil {
+		balances = []Balance{}
+	}
+	writeJSON(w, http.StatusOK, balancesResponse{Balances: balances, Transfers: Transfers(balances)})
+}
+
+// parseDate reads an optional YYYY-MM-DD query parameter, returning def
+// when it is absent.
+func parseDate(r *http.Request, key, def string) (string, error) {
+	s := r.URL.Query().Get(key)
+	if s == "" {
+		return def, nil
+	}
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return "", fmt.Errorf("%s must be in YYYY-MM-DD format", key)
+	}
+	return s, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package settlement_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/settlement"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+// members makes alice, bob and carol members of sharedBook.
+type members struct{}
+
+func (members) Role(_ context.Context, userID, bookID string) (household.Role, error) {
+	if bookID == sharedBook && (userID == "alice" || userID == "bob" || userID == "carol") {
+		return household.RoleEditor, nil
+	}
+	return "", household.ErrNotFound
+}
+
+// newServer serves the settlement routes over a memory read model holding
+// e's events, as testhelper.AsUser.
+func newServer(t *testing.T, e *bookEvents) *httptest.Server {
+	t.Helper()
+
+	repo := settlement.NewMemoryRepository()
+	e.apply(t, settlement.NewProjector(repo))
+
+	mux := http.NewServeMux()
+	settlement.NewHandler(repo, members{}).Register(mux)
+	srv := httptest.NewServer(testhelper.AsUser(mux))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type balancesResponse struct {
+	Balances  []settlement.Balance  `json:"balances"`
+	Transfers []settlement.Transfer `json:"transfers"`
+}
+
+func getBalances(t *testing.T, srv *httptest.Server, userID, query string, v *balancesResponse) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/households/"+sharedBook+"/balances"+query, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set(testhelper.UserHeader, userID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET balances: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestBalances(t *testing.T) {
+	e := &bookEvents{t: t}
+	e.record("feb", 3000, "2026-02-20", equal("bob", "alice", "carol"))
+	e.record("mar", 900, "2026-03-01", equal("alice", "bob", "carol"))
+	srv := newServer(t, e)
+
+	var got balancesResponse
+	if status := getBalances(t, srv, "bob", "?from=2026-03-01&to=2026-03-31", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	wantBalances := []settlement.Balance{{UserID: "alice", Amount: 600}, {UserID: "bob", Amount: -300}, {UserID: "carol", Amount: -300}}
+	wantTransfers := []settlement.Transfer{{From: "bob", To: "alice", Amount: 300}, {From: "carol", To: "alice", Amount: 300}}
+	if !slices.Equal(got.Balances, wantBalances) || !slices.Equal(got.Transfers, wantTransfers) {
+		t.Errorf("March = %+v, want balances %+v and transfers %+v", got, wantBalances, wantTransfers)
+	}
+
+	// Without a period, February's dinner counts too.
+	if status := getBalances(t, srv, "alice", "", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	wantTransfers = []settlement.Transfer{{From: "carol", To: "bob", Amount: 1300}, {From: "alice", To: "bob", Amount: 400}}
+	if !slices.Equal(got.Transfers, wantTransfers) {
+		t.Errorf("all time transfers = %+v, want %+v", got.Transfers, wantTransfers)
+	}
+
+	for _, tt := range []struct {
+		user, query string
+		want        int
+	}{
+		{"dave", "", http.StatusNotFound},
+		{"alice", "?from=2026-03", http.StatusBadRequest},
+		{"alice", "?from=2026-03-02&to=2026-03-01", http.StatusBadRequest},
+	} {
+		if status := getBalances(t, srv, tt.user, tt.query, &got); status != tt.want {
+			t.Errorf("%s GET balances%s status = %d, want %d", tt.user, tt.query, status, tt.want)
+		}
+	}
+}
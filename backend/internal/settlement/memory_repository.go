@@ -0,0 +1,104 @@
+package settlement
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+)
+
+// MemoryRepository keeps each member's settlement entries, and the last
+// version of every split expense, in maps.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	entries  map[sourceUser]entry
+	expenses map[string]expenseState
+}
+
+type sourceUser struct {
+	source, userID string
+}
+
+type entry struct {
+	bookID, date string
+	amount       int64
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		entries:  make(map[sourceUser]entry),
+		expenses: make(map[string]expenseState),
+	}
+}
+
+// Balances sums the book's entries between from and to per member.
+func (r *MemoryRepository) Balances(_ context.Context, bookID, from, to string) ([]Balance, error) {
+	r.mu.RLock()
+	sums := make(map[string]int64)
+	for key, e := range r.entries {
+		// Dates are YYYY-MM-DD, so string order is chronological order.
+		if e.bookID == bookID && e.date >= from && e.date <= to {
+			sums[key.userID] += e.amount
+		}
+	}
+	r.mu.RUnlock()
+
+	var balances []Balance
+	for userID, amount := range sums {
+		if amount != 0 {
+			balances = append(balances, Balance{UserID: userID, Amount: amount})
+		}
+	}
+	slices.SortFunc(balances, func(a, b Balance) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return balances, nil
+}
+
+// ApplyExpense replaces the expense's entries.
+func (r *MemoryRepository) ApplyExpense(_ context.Context, c expense.Change) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, found := r.expenses[c.ID]
+	switch {
+	case !found && !c.Recorded():
+		return errNotProjected
+	case found && prev.Version >= c.Version:
+		return nil
+	}
+
+	next := expenseState{BookID: prev.BookID, Version: c.Version}
+	if c.Recorded() {
+		next.BookID = c.Book
+	}
+
+	source := expenseSource(c.ID)
+	for key := range r.entries {
+		if key.source == source {
+			delete(r.entries, key)
+		}
+	}
+	if !c.Voided {
+		for userID, amount := range splitEntries(c.Split) {
+			r.entries[sourceUser{source, userID}] = entry{bookID: next.BookID, date: c.Date, amount: amount}
+		}
+	}
+	r.expenses[c.ID] = next
+	return nil
+}
+
+// ApplySettlement adds the settlement's entries.
+func (r *MemoryRepository) ApplySettlement(_ context.Context, s household.Settlement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source := settlementSource(s)
+	r.entries[sourceUser{source, s.From}] = entry{bookID: s.HouseholdID, date: s.Date, amount: s.Amount}
+	r.entries[sourceUser{source, s.To}] = entry{bookID: s.HouseholdID, date: s.Date, amount: -s.Amount}
+	return nil
+}
@@ -0,0 +1,77 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the settlement read model's checkpoint.
+	projectionName = "settlements"
+	// entriesTable holds every balance movement.
+	entriesTable = "settlement_entries"
+	// expensesTable holds the book and last applied version of each expense.
+	expensesTable = "settlement_expenses"
+)
+
+// Projector applies split expenses and recorded settlements to the
+// settlement read model.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL settlement projection for
+// rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{entriesTable, expensesTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{
+				db:            db,
+				entriesTable:  tables[entriesTable],
+				expensesTable: tables[expensesTable],
+			})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply processes an expense or SettlementRecorded event. Other events are
+// ignored.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	c, ok, err := expense.DecodeChange(event)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := p.w.ApplyExpense(ctx, c); err != nil {
+			return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+		}
+		return nil
+	}
+
+	s, ok, err := household.DecodeSettlement(event)
+	if err != nil || !ok {
+		return err
+	}
+	if err := p.w.ApplySettlement(ctx, s); err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
+}
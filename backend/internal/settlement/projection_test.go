@@ -0,0 +1,182 @@
+package settlement_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/settlement"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() settlement.ReadModel { return settlement.NewMemoryRepository() },
+	func(db *sql.DB) settlement.ReadModel { return settlement.NewRepository(db) },
+)
+
+const sharedBook = "household-1"
+
+// bookEvents builds the events of sharedBook: expenses through the expense
+// aggregate, so the projector sees the events the handler would append,
+// and settlements.
+type bookEvents struct {
+	t           *testing.T
+	events      []eventstore.Event
+	settlements int
+}
+
+// record records an expense in sharedBook split by split.
+func (e *bookEvents) record(id string, amount int64, date string, split *expense.Split) {
+	e.t.Helper()
+	event, err := expense.RecordExpense(id, expense.RecordExpenseCommand{
+		BookID: sharedBook, Amount: amount, Category: "外食", Date: date, Split: split,
+	})
+	if err != nil {
+		e.t.Fatalf("record %s: %v", id, err)
+	}
+	event.RecordedBy = "alice"
+	e.events = append(e.events, event)
+}
+
+func (e *bookEvents) correct(id string, cmd expense.CorrectExpenseCommand) {
+	e.t.Helper()
+	event, err := e.load(id).Correct(cmd)
+	if err != nil {
+		e.t.Fatalf("correct %s: %v", id, err)
+	}
+	e.events = append(e.events, event)
+}
+
+func (e *bookEvents) void(id string) {
+	e.t.Helper()
+	event, err := e.load(id).Void(expense.VoidExpenseCommand{})
+	if err != nil {
+		e.t.Fatalf("void %s: %v", id, err)
+	}
+	e.events = append(e.events, event)
+}
+
+func (e *bookEvents) load(id string) *expense.Expense {
+	e.t.Helper()
+	var stream []eventstore.Event
+	for _, event := range e.events {
+		if event.AggregateID == id {
+			stream = append(stream, event)
+		}
+	}
+	exp, err := expense.LoadExpense(stream)
+	if err != nil {
+		e.t.Fatalf("load %s: %v", id, err)
+	}
+	return exp
+}
+
+// settle records that from paid to. The household's other events are left
+// out, since the projector only reads settlements.
+func (e *bookEvents) settle(from, to string, amount int64, date string) {
+	e.t.Helper()
+	payload, err := json.Marshal(household.SettlementRecordedPayload{From: from, To: to, Amount: amount, Date: date})
+	if err != nil {
+		e.t.Fatalf("marshal settlement: %v", err)
+	}
+	e.settlements++
+	e.events = append(e.events, eventstore.Event{
+		AggregateType: household.AggregateType,
+		AggregateID:   sharedBook,
+		Version:       e.settlements,
+		EventType:     "SettlementRecorded",
+		Payload:       payload,
+		Metadata:      eventstore.Metadata{SchemaVersion: 1},
+	})
+}
+
+func (e *bookEvents) apply(t *testing.T, projector *settlement.Projector) {
+	t.Helper()
+	for _, event := range e.events {
+		if err := projector.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply %s v%d: %v", event.EventType, event.Version, err)
+		}
+	}
+}
+
+func equal(members ...string) *expense.Split {
+	split := &expense.Split{PaidBy: members[0], Method: expense.SplitEqual}
+	for _, m := range members {
+		split.Shares = append(split.Shares, expense.Share{UserID: m})
+	}
+	return split
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestProjector(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := settlement.NewProjector(repo)
+			ctx := context.Background()
+
+			e := &bookEvents{t: t}
+			// alice pays 900 for three: bob and carol owe her 300 each.
+			e.record("dinner", 900, "2026-03-01", equal("alice", "bob", "carol"))
+			// bob pays 600 for himself and alice, then it is raised to 800.
+			e.record("taxi", 600, "2026-03-02", equal("bob", "alice"))
+			e.correct("taxi", expense.CorrectExpenseCommand{Amount: ptr(int64(800))})
+			// Voided and unsplit expenses move nothing.
+			e.record("mistake", 5000, "2026-03-03", equal("carol", "alice"))
+			e.void("mistake")
+			e.record("groceries", 3000, "2026-03-03", nil)
+			e.settle("carol", "alice", 300, "2026-03-05")
+			// Events of other aggregates are ignored.
+			e.events = append(e.events, eventstore.Event{AggregateType: "budget", AggregateID: "x", Version: 1, EventType: "BudgetSet"})
+
+			// Applying everything twice checks that replays are absorbed.
+			e.apply(t, projector)
+			e.apply(t, projector)
+
+			got, err := repo.Balances(ctx, sharedBook, "2026-03-01", "2026-03-31")
+			if err != nil {
+				t.Fatalf("balances: %v", err)
+			}
+			want := []settlement.Balance{{UserID: "alice", Amount: -100}, {UserID: "bob", Amount: 100}}
+			if !slices.Equal(got, want) {
+				t.Errorf("balances = %+v, want %+v", got, want)
+			}
+
+			// The settlement on the 5th is outside the period.
+			got, err = repo.Balances(ctx, sharedBook, "2026-03-01", "2026-03-02")
+			if err != nil {
+				t.Fatalf("balances: %v", err)
+			}
+			want = []settlement.Balance{{UserID: "alice", Amount: 200}, {UserID: "bob", Amount: 100}, {UserID: "carol", Amount: -300}}
+			if !slices.Equal(got, want) {
+				t.Errorf("balances to 2026-03-02 = %+v, want %+v", got, want)
+			}
+
+			if got, err := repo.Balances(ctx, "household-2", "2026-03-01", "2026-03-31"); err != nil || len(got) != 0 {
+				t.Errorf("other book's balances = %+v, %v, want none", got, err)
+			}
+		})
+	}
+}
+
+func TestProjector_CorrectionBeforeRecording(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			projector := settlement.NewProjector(b.Open(t))
+
+			e := &bookEvents{t: t}
+			e.record("a", 1000, "2026-03-01", equal("alice", "bob"))
+			e.correct("a", expense.CorrectExpenseCommand{Amount: ptr(int64(1200))})
+
+			if err := projector.Apply(context.Background(), e.events[1]); err == nil {
+				t.Error("applying a correction of an unknown expense succeeded")
+			}
+		})
+	}
+}
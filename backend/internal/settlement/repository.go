@@ -0,0 +1,192 @@
+package settlement
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+)
+
+// errNotProjected is returned by Writer when a correction or void targets an
+// expense whose recording has not been applied yet.
+var errNotProjected = errors.New("expense is not projected yet")
+
+// Reader is the query side of the settlement read model.
+type Reader interface {
+	// Balances returns the non-zero balances of bookID's members from the
+	// split expenses and settlements dated from from to to, inclusive, as
+	// YYYY-MM-DD, ordered by user ID.
+	Balances(ctx context.Context, bookID, from, to string) ([]Balance, error)
+}
+
+// Writer is the projection side of the settlement read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// ApplyExpense replaces the expense's balance entries with those of its
+	// split after c, if c.Version is newer than the last version applied.
+	// Returns errNotProjected if c is not a recording and the expense is
+	// unknown.
+	ApplyExpense(ctx context.Context, c expense.Change) error
+	// ApplySettlement adds the settlement's balance entries.
+	ApplySettlement(ctx context.Context, s household.Settlement) error
+}
+
+// ReadModel combines both sides of the settlement read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// expenseState is what the read model remembers of an expense between its
+// changes.
+type expenseState struct {
+	BookID  string
+	Version int
+}
+
+func expenseSource(id string) string {
+	return "expense:" + id
+}
+
+func settlementSource(s household.Settlement) string {
+	return fmt.Sprintf("settlement:%s:%d", s.HouseholdID, s.Version)
+}
+
+// Repository implements ReadModel on top of MySQL. Every balance movement
+// is an entry keyed by the expense or settlement it came from, so an
+// expense's entries can be replaced when it is corrected or voided.
+type Repository struct {
+	db            *sql.DB
+	entriesTable  string
+	expensesTable string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, entriesTable: entriesTable, expensesTable: expensesTable}
+}
+
+// Balances sums the book's entries between from and to per member.
+func (r *Repository) Balances(ctx context.Context, bookID, from, to string) ([]Balance, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT user_id, SUM(amount)
+		 FROM %s
+		 WHERE book_id = ? AND date BETWEEN ? AND ?
+		 GROUP BY user_id
+		 HAVING SUM(amount) <> 0
+		 ORDER BY user_id`, r.entriesTable),
+		bookID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query balances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []Balance
+	for rows.Next() {
+		var b Balance
+		if err := rows.Scan(&b.UserID, &b.Amount); err != nil {
+			return nil, fmt.Errorf("scan balance: %w", err)
+		}
+		balances = append(balances, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate balances: %w", err)
+	}
+	return balances, nil
+}
+
+// ApplyExpense replaces the expense's entries in one transaction.
+func (r *Repository) ApplyExpense(ctx context.Context, c expense.Change) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var prev expenseState
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT book_id, version FROM %s WHERE expense_id = ? FOR UPDATE`, r.expensesTable), c.ID,
+	).Scan(&prev.BookID, &prev.Version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if !c.Recorded() {
+			return errNotProjected
+		}
+	case err != nil:
+		return fmt.Errorf("load expense state: %w", err)
+	case prev.Version >= c.Version:
+		return nil
+	}
+
+	next := expenseState{BookID: prev.BookID, Version: c.Version}
+	if c.Recorded() {
+		next.BookID = c.Book
+	}
+
+	source := expenseSource(c.ID)
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`DELETE FROM %s WHERE source = ?`, r.entriesTable), source,
+	); err != nil {
+		return fmt.Errorf("delete entries: %w", err)
+	}
+	if !c.Voided {
+		for userID, amount := range splitEntries(c.Split) {
+			if err := r.addEntry(ctx, tx, source, userID, next.BookID, c.Date, amount); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (expense_id, book_id, version)
+		 VALUES (?, ?, ?)
+		 ON DUPLICATE KEY UPDATE version = ?`, r.expensesTable),
+		c.ID, next.BookID, next.Version, next.Version,
+	); err != nil {
+		return fmt.Errorf("save expense state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// ApplySettlement adds the settlement's entries in one transaction. The
+// payer is owed what they paid and the receiver is owed that much less.
+func (r *Repository) ApplySettlement(ctx context.Context, s household.Settlement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	source := settlementSource(s)
+	if err := r.addEntry(ctx, tx, source, s.From, s.HouseholdID, s.Date, s.Amount); err != nil {
+		return err
+	}
+	if err := r.addEntry(ctx, tx, source, s.To, s.HouseholdID, s.Date, -s.Amount); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) addEntry(ctx context.Context, tx *sql.Tx, source, userID, bookID, date string, amount int64) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (source, user_id, book_id, date, amount)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE amount = ?`, r.entriesTable),
+		source, userID, bookID, date, amount, amount,
+	); err != nil {
+		return fmt.Errorf("save entry: %w", err)
+	}
+	return nil
+}
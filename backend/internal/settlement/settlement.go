@@ -0,0 +1,172 @@
+// Package settlement keeps who owes whom in each household, projected from
+// split expenses and recorded settlements, and works out the fewest
+// transfers that settle it.
+package settlement
+
+import (
+	"cmp"
+	"math/bits"
+	"slices"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+// Balance is what a member of a household is owed. It is positive when
+// they paid more than their shares, and negative when they owe others.
+type Balance struct {
+	UserID string `json:"user_id"`
+	Amount int64  `json:"amount"`
+}
+
+// Transfer is a payment that settles part of the balances.
+type Transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// maxExactMembers caps the members with a balance for which Transfers
+// searches for the fewest transfers. The search is exponential in the
+// member count, so beyond it balances are settled as one group.
+const maxExactMembers = 16
+
+// Transfers returns the fewest payments that bring every balance to zero,
+// which must sum to zero. Members are split into as many groups that
+// settle among themselves as possible, since a group of k members needs
+// k-1 transfers. Within a group the largest debt is paid to the largest
+// credit, and ties are broken by user ID so the result is stable.
+func Transfers(balances []Balance) []Transfer {
+	var open []Balance
+	for _, b := range balances {
+		if b.Amount != 0 {
+			open = append(open, b)
+		}
+	}
+	slices.SortFunc(open, func(a, b Balance) int { return cmp.Compare(a.UserID, b.UserID) })
+
+	transfers := []Transfer{}
+	for _, group := range zeroSumGroups(open) {
+		transfers = append(transfers, settleGroup(group)...)
+	}
+	return transfers
+}
+
+// zeroSumGroups partitions balances, which sum to zero, into as many
+// groups that each sum to zero as possible. Groups are ordered by their
+// first member, and members keep their order in balances.
+func zeroSumGroups(balances []Balance) [][]Balance {
+	n := len(balances)
+	if n == 0 {
+		return nil
+	}
+	if n > maxExactMembers {
+		return [][]Balance{balances}
+	}
+
+	// groups[mask] is the most zero-sum groups that members in mask can be
+	// removed in, one member at a time, counting each time the remaining
+	// members sum to zero.
+	full := 1<<n - 1
+	sums := make([]int64, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		sums[mask] = sums[mask&(mask-1)] + balances[bits.TrailingZeros(uint(mask))].Amount
+		for rest := mask; rest != 0; rest &= rest - 1 {
+			groups[mask] = max(groups[mask], groups[mask&^(rest&-rest)])
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// Walk back from everyone; each zero-sum mask on the way closes a group.
+	var result [][]Balance
+	mask, closed := full, full
+	for mask != 0 {
+		want := groups[mask]
+		if sums[mask] == 0 {
+			want--
+		}
+		for rest := mask; rest != 0; rest &= rest - 1 {
+			if next := mask &^ (rest & -rest); groups[next] == want {
+				mask = next
+				break
+			}
+		}
+		if sums[mask] == 0 {
+			result = append(result, members(balances, closed&^mask))
+			closed = mask
+		}
+	}
+	slices.SortFunc(result, func(a, b []Balance) int { return cmp.Compare(a[0].UserID, b[0].UserID) })
+	return result
+}
+
+// members returns the balances whose bits are set in mask.
+func members(balances []Balance, mask int) []Balance {
+	var group []Balance
+	for i, b := range balances {
+		if mask&(1<<i) != 0 {
+			group = append(group, b)
+		}
+	}
+	return group
+}
+
+// settleGroup returns the payments that bring a group's balances, which
+// must sum to zero, to zero in fewer transfers than members.
+func settleGroup(balances []Balance) []Transfer {
+	var debtors, creditors []Balance
+	for _, b := range balances {
+		switch {
+		case b.Amount < 0:
+			debtors = append(debtors, Balance{UserID: b.UserID, Amount: -b.Amount})
+		case b.Amount > 0:
+			creditors = append(creditors, b)
+		}
+	}
+
+	var transfers []Transfer
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sortLargestFirst(debtors)
+		sortLargestFirst(creditors)
+
+		amount := min(debtors[0].Amount, creditors[0].Amount)
+		transfers = append(transfers, Transfer{From: debtors[0].UserID, To: creditors[0].UserID, Amount: amount})
+		debtors[0].Amount -= amount
+		creditors[0].Amount -= amount
+		if debtors[0].Amount == 0 {
+			debtors = debtors[1:]
+		}
+		if creditors[0].Amount == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	return transfers
+}
+
+func sortLargestFirst(balances []Balance) {
+	slices.SortFunc(balances, func(a, b Balance) int {
+		return cmp.Or(cmp.Compare(b.Amount, a.Amount), cmp.Compare(a.UserID, b.UserID))
+	})
+}
+
+// splitEntries returns how split moves each member's balance: the payer is
+// owed the whole amount and every sharer owes their share. Users whose
+// balance does not move are left out.
+func splitEntries(split *expense.Split) map[string]int64 {
+	entries := make(map[string]int64)
+	if split == nil {
+		return entries
+	}
+	for _, share := range split.Shares {
+		entries[split.PaidBy] += share.Amount
+		entries[share.UserID] -= share.Amount
+	}
+	for userID, amount := range entries {
+		if amount == 0 {
+			delete(entries, userID)
+		}
+	}
+	return entries
+}
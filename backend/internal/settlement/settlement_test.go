@@ -0,0 +1,73 @@
+package settlement_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/settlement"
+)
+
+func TestTransfers(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances []settlement.Balance
+		want     []settlement.Transfer
+	}{
+		{"settled", nil, []settlement.Transfer{}},
+		{
+			"one debtor",
+			[]settlement.Balance{{UserID: "alice", Amount: 600}, {UserID: "bob", Amount: -300}, {UserID: "carol", Amount: -300}},
+			[]settlement.Transfer{{From: "bob", To: "alice", Amount: 300}, {From: "carol", To: "alice", Amount: 300}},
+		},
+		{
+			// Paying along the chain would take two transfers.
+			"chain",
+			[]settlement.Balance{{UserID: "alice", Amount: 500}, {UserID: "bob", Amount: 0}, {UserID: "carol", Amount: -500}},
+			[]settlement.Transfer{{From: "carol", To: "alice", Amount: 500}},
+		},
+		{
+			"largest first",
+			[]settlement.Balance{
+				{UserID: "alice", Amount: 700}, {UserID: "bob", Amount: 300},
+				{UserID: "carol", Amount: -800}, {UserID: "dave", Amount: -200},
+			},
+			[]settlement.Transfer{
+				{From: "carol", To: "alice", Amount: 700},
+				{From: "dave", To: "bob", Amount: 200},
+				{From: "carol", To: "bob", Amount: 100},
+			},
+		},
+		{
+			"pairs",
+			[]settlement.Balance{
+				{UserID: "alice", Amount: -200}, {UserID: "bob", Amount: 100},
+				{UserID: "carol", Amount: -100}, {UserID: "dave", Amount: 200},
+			},
+			[]settlement.Transfer{
+				{From: "alice", To: "dave", Amount: 200},
+				{From: "carol", To: "bob", Amount: 100},
+			},
+		},
+		{
+			// Paying the largest debt to the largest credit across everyone
+			// would take four transfers.
+			"zero-sum groups",
+			[]settlement.Balance{
+				{UserID: "alice", Amount: 600}, {UserID: "bob", Amount: 400},
+				{UserID: "carol", Amount: -400}, {UserID: "dave", Amount: -300}, {UserID: "erin", Amount: -300},
+			},
+			[]settlement.Transfer{
+				{From: "dave", To: "alice", Amount: 300},
+				{From: "erin", To: "alice", Amount: 300},
+				{From: "carol", To: "bob", Amount: 400},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settlement.Transfers(tt.balances); !slices.Equal(got, tt.want) {
+				t.Errorf("Transfers = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
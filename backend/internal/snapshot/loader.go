@@ -0,0 +1,119 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+// Aggregate describes how to rebuild an aggregate of type T, usually a
+// pointer to a struct, from its events. T is snapshotted with encoding/json,
+// so every field that makes up its state must be exported.
+type Aggregate[T any] struct {
+	// Type is the aggregate type the events are stored under.
+	Type string
+	// StateVersion identifies the JSON shape of T. Bump it whenever a field
+	// is added, removed or changes meaning, so snapshots taken with the old
+	// shape are ignored and replaced instead of decoding into wrong state.
+	StateVersion int
+	// New returns the aggregate before its first event.
+	New func(id string) T
+	// Apply folds one event into the aggregate.
+	Apply func(agg T, event eventstore.Event) error
+	// Version returns the version of the last event applied to agg.
+	Version func(agg T) int
+}
+
+// Loader loads aggregates from the latest usable snapshot plus the events
+// appended after it, and takes new snapshots according to its Policy.
+type Loader[T any] struct {
+	events    eventstore.Store
+	snapshots Store
+	aggregate Aggregate[T]
+	policy    Policy
+	now       func() time.Time
+}
+
+// NewLoader creates a Loader. events should already upcast payloads, since
+// only events after the snapshot are replayed through Aggregate.Apply.
+func NewLoader[T any](events eventstore.Store, snapshots Store, aggregate Aggregate[T], policy Policy) *Loader[T] {
+	return &Loader[T]{
+		events:    events,
+		snapshots: snapshots,
+		aggregate: aggregate,
+		policy:    policy,
+		now:       time.Now,
+	}
+}
+
+// Load returns the aggregate with every committed event applied. An
+// aggregate without events is returned as built by Aggregate.New, at
+// version zero.
+//
+// A snapshot that cannot be used is ignored, and a snapshot that cannot be
+// saved is logged, because neither affects the loaded state.
+func (l *Loader[T]) Load(ctx context.Context, id string) (T, error) {
+	agg, fromVersion, err := l.restore(ctx, id)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	events, err := l.events.LoadFrom(ctx, l.aggregate.Type, id, fromVersion)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("load %s %s after v%d: %w", l.aggregate.Type, id, fromVersion, err)
+	}
+	for _, e := range events {
+		if err := l.aggregate.Apply(agg, e); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	if version := l.aggregate.Version(agg); len(events) > 0 && l.policy(fromVersion, version) {
+		if err := l.save(ctx, id, agg, version); err != nil {
+			log.Printf("snapshot %s %s at v%d: %v", l.aggregate.Type, id, version, err)
+		}
+	}
+	return agg, nil
+}
+
+// restore returns the aggregate decoded from its snapshot and the snapshot's
+// version, or a new aggregate at version zero if there is no usable snapshot.
+func (l *Loader[T]) restore(ctx context.Context, id string) (T, int, error) {
+	snap, ok, err := l.snapshots.Load(ctx, l.aggregate.Type, id)
+	if err != nil {
+		var zero T
+		return zero, 0, err
+	}
+	if !ok || snap.StateVersion != l.aggregate.StateVersion {
+		return l.aggregate.New(id), 0, nil
+	}
+
+	agg := l.aggregate.New(id)
+	if err := json.Unmarshal(snap.State, &agg); err != nil {
+		log.Printf("ignoring snapshot of %s %s at v%d: %v", l.aggregate.Type, id, snap.Version, err)
+		return l.aggregate.New(id), 0, nil
+	}
+	return agg, snap.Version, nil
+}
+
+func (l *Loader[T]) save(ctx context.Context, id string, agg T, version int) error {
+	state, err := json.Marshal(agg)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+	return l.snapshots.Save(ctx, Snapshot{
+		AggregateType: l.aggregate.Type,
+		AggregateID:   id,
+		Version:       version,
+		StateVersion:  l.aggregate.StateVersion,
+		State:         state,
+		TakenAt:       l.now().UTC().Truncate(time.Microsecond),
+	})
+}
@@ -0,0 +1,165 @@
+package snapshot_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+)
+
+// counter is a minimal aggregate whose events each add their payload to Total.
+type counter struct {
+	ID      string `json:"id"`
+	Total   int    `json:"total"`
+	Version int    `json:"version"`
+}
+
+func counterAggregate(stateVersion int) snapshot.Aggregate[*counter] {
+	return snapshot.Aggregate[*counter]{
+		Type:         "counter",
+		StateVersion: stateVersion,
+		New:          func(id string) *counter { return &counter{ID: id} },
+		Apply: func(c *counter, e eventstore.Event) error {
+			var n int
+			if err := json.Unmarshal(e.Payload, &n); err != nil {
+				return err
+			}
+			c.Total += n
+			c.Version = e.Version
+			return nil
+		},
+		Version: func(c *counter) int { return c.Version },
+	}
+}
+
+// countingStore records how many events LoadFrom returned.
+type countingStore struct {
+	eventstore.Store
+	replayed int
+}
+
+func (s *countingStore) LoadFrom(ctx context.Context, aggregateType, aggregateID string, afterVersion int) ([]eventstore.Event, error) {
+	events, err := s.Store.LoadFrom(ctx, aggregateType, aggregateID, afterVersion)
+	s.replayed = len(events)
+	return events, err
+}
+
+func appendAdds(t *testing.T, store eventstore.Store, id string, from, to int) {
+	t.Helper()
+	for v := from; v <= to; v++ {
+		event := eventstore.Event{
+			AggregateID:   id,
+			AggregateType: "counter",
+			Version:       v,
+			EventType:     "Added",
+			Payload:       []byte(strconv.Itoa(v)),
+		}
+		if err := store.Append(context.Background(), []eventstore.Event{event}, v-1); err != nil {
+			t.Fatalf("append v%d: %v", v, err)
+		}
+	}
+}
+
+// sum returns 1 + 2 + ... + n, the total of a counter at version n.
+func sum(n int) int {
+	return n * (n + 1) / 2
+}
+
+func TestLoader_SnapshotsEveryN(t *testing.T) {
+	ctx := context.Background()
+	events := &countingStore{Store: eventstore.NewMemoryStore()}
+	snapshots := snapshot.NewMemoryStore()
+	loader := snapshot.NewLoader(events, snapshots, counterAggregate(1), snapshot.Every(10))
+
+	steps := []struct {
+		appendTo     int
+		wantReplayed int
+		wantSnapshot int
+	}{
+		{9, 9, 0},    // below the threshold: no snapshot yet
+		{12, 12, 12}, // 12 events since none: snapshot at 12
+		{15, 3, 12},  // only events after the snapshot are replayed
+		{22, 10, 22}, // 10 since the last snapshot: snapshot again
+		{22, 0, 22},  // nothing new
+	}
+	last := 0
+	for _, step := range steps {
+		appendAdds(t, events, "a", last+1, step.appendTo)
+		last = step.appendTo
+
+		c, err := loader.Load(ctx, "a")
+		if err != nil {
+			t.Fatalf("load at v%d: %v", step.appendTo, err)
+		}
+		if c.Total != sum(step.appendTo) || c.Version != step.appendTo {
+			t.Errorf("v%d: counter = %+v, want total %d", step.appendTo, *c, sum(step.appendTo))
+		}
+		if events.replayed != step.wantReplayed {
+			t.Errorf("v%d: replayed %d events, want %d", step.appendTo, events.replayed, step.wantReplayed)
+		}
+		snap, _, _ := snapshots.Load(ctx, "counter", "a")
+		if snap.Version != step.wantSnapshot {
+			t.Errorf("v%d: snapshot at v%d, want v%d", step.appendTo, snap.Version, step.wantSnapshot)
+		}
+	}
+}
+
+func TestLoader_NoEvents(t *testing.T) {
+	loader := snapshot.NewLoader(eventstore.NewMemoryStore(), snapshot.NewMemoryStore(),
+		counterAggregate(1), snapshot.Every(1))
+
+	c, err := loader.Load(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if *c != (counter{ID: "missing"}) {
+		t.Errorf("counter = %+v, want a new counter", *c)
+	}
+}
+
+func TestLoader_IgnoresOtherStateVersion(t *testing.T) {
+	ctx := context.Background()
+	events := &countingStore{Store: eventstore.NewMemoryStore()}
+	snapshots := snapshot.NewMemoryStore()
+	appendAdds(t, events, "a", 1, 5)
+
+	// A snapshot in an older shape whose state would decode wrongly.
+	if err := snapshots.Save(ctx, snapshot.Snapshot{
+		AggregateType: "counter", AggregateID: "a", Version: 5, StateVersion: 1,
+		State: []byte(`{"id":"a","total":-1,"version":5}`),
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loader := snapshot.NewLoader(events, snapshots, counterAggregate(2), snapshot.Every(1))
+	c, err := loader.Load(ctx, "a")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if c.Total != sum(5) || events.replayed != 5 {
+		t.Errorf("total = %d after replaying %d events, want %d from the full stream", c.Total, events.replayed, sum(5))
+	}
+
+	snap, _, _ := snapshots.Load(ctx, "counter", "a")
+	if snap.StateVersion != 2 {
+		t.Errorf("snapshot StateVersion = %d, want it replaced with 2", snap.StateVersion)
+	}
+}
+
+func TestLoader_Never(t *testing.T) {
+	ctx := context.Background()
+	events := eventstore.NewMemoryStore()
+	snapshots := snapshot.NewMemoryStore()
+	appendAdds(t, events, "a", 1, 50)
+
+	loader := snapshot.NewLoader(events, snapshots, counterAggregate(1), snapshot.Never)
+	if _, err := loader.Load(ctx, "a"); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, ok, _ := snapshots.Load(ctx, "counter", "a"); ok {
+		t.Error("snapshot taken with the Never policy")
+	}
+}
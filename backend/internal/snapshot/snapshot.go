@@ -0,0 +1,59 @@
+// Package snapshot stores the serialized state of long-lived aggregates so
+// they can be loaded without replaying their whole event stream.
+//
+// Snapshots are a cache: the event stream stays the source of truth, and
+// deleting every snapshot only makes loads slower.
+package snapshot
+
+import (
+	"context"
+	"time"
+)
+
+// Snapshot is an aggregate's serialized state as of a version.
+type Snapshot struct {
+	AggregateType string
+	AggregateID   string
+	// Version is the version of the last event folded into State.
+	Version int
+	// StateVersion identifies the shape State was serialized with. A loader
+	// ignores snapshots whose StateVersion differs from its own.
+	StateVersion int
+	State        []byte
+	TakenAt      time.Time
+}
+
+// Store persists the latest snapshot of each aggregate.
+type Store interface {
+	// Load returns the aggregate's snapshot. ok is false if there is none.
+	Load(ctx context.Context, aggregateType, aggregateID string) (snap Snapshot, ok bool, err error)
+
+	// Save stores snap unless the stored snapshot is at a newer version with
+	// the same StateVersion. A snapshot with a different StateVersion is
+	// always replaced.
+	Save(ctx context.Context, snap Snapshot) error
+
+	// DeleteType removes every snapshot of aggregateType, for use when its
+	// state shape changes in a way StateVersion does not capture or when a
+	// snapshot is suspected to be wrong. It returns the number removed.
+	DeleteType(ctx context.Context, aggregateType string) (int64, error)
+}
+
+// Policy decides whether to take a new snapshot after loading an aggregate
+// at currentVersion whose previous snapshot was at snapshotVersion (zero if
+// it had none).
+type Policy func(snapshotVersion, currentVersion int) bool
+
+// Every returns a Policy that snapshots once n or more events have been
+// appended since the last snapshot.
+func Every(n int) Policy {
+	return func(snapshotVersion, currentVersion int) bool {
+		return currentVersion-snapshotVersion >= n
+	}
+}
+
+// Never is a Policy that never takes snapshots, so loads always read the
+// full stream unless snapshots were taken by another loader.
+func Never(int, int) bool {
+	return false
+}
@@ -0,0 +1,139 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MySQLStore implements Store backed by the snapshots table.
+type MySQLStore struct {
+	db *sql.DB
+}
+
+// NewMySQLStore creates a new MySQLStore.
+func NewMySQLStore(db *sql.DB) *MySQLStore {
+	return &MySQLStore{db: db}
+}
+
+// Load returns the aggregate's snapshot, if any.
+func (s *MySQLStore) Load(ctx context.Context, aggregateType, aggregateID string) (Snapshot, bool, error) {
+	snap := Snapshot{AggregateType: aggregateType, AggregateID: aggregateID}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, state_version, state, taken_at FROM snapshots
+		 WHERE aggregate_type = ? AND aggregate_id = ?`,
+		aggregateType, aggregateID,
+	).Scan(&snap.Version, &snap.StateVersion, &snap.State, &snap.TakenAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("load snapshot %s/%s: %w", aggregateType, aggregateID, err)
+	}
+	return snap, true, nil
+}
+
+// Save stores snap unless a newer snapshot of the same shape exists.
+func (s *MySQLStore) Save(ctx context.Context, snap Snapshot) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE snapshots SET version = ?, state_version = ?, state = ?, taken_at = ?
+		 WHERE aggregate_type = ? AND aggregate_id = ?
+		   AND (version < ? OR state_version <> ?)`,
+		snap.Version, snap.StateVersion, snap.State, snap.TakenAt,
+		snap.AggregateType, snap.AggregateID, snap.Version, snap.StateVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("update snapshot %s/%s: %w", snap.AggregateType, snap.AggregateID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	} else if n > 0 {
+		return nil
+	}
+
+	// Either there is no snapshot yet or the stored one is at least as new.
+	// In the latter case, or if a concurrent loader inserted first, the
+	// insert is a no-op.
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO snapshots (aggregate_type, aggregate_id, version, state_version, state, taken_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE aggregate_id = aggregate_id`,
+		snap.AggregateType, snap.AggregateID, snap.Version, snap.StateVersion, snap.State, snap.TakenAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert snapshot %s/%s: %w", snap.AggregateType, snap.AggregateID, err)
+	}
+	return nil
+}
+
+// DeleteType removes every snapshot of aggregateType.
+func (s *MySQLStore) DeleteType(ctx context.Context, aggregateType string) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM snapshots WHERE aggregate_type = ?`, aggregateType)
+	if err != nil {
+		return 0, fmt.Errorf("delete %s snapshots: %w", aggregateType, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// MemoryStore implements Store in memory.
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[snapshotKey]Snapshot
+}
+
+type snapshotKey struct {
+	aggregateType string
+	aggregateID   string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: make(map[snapshotKey]Snapshot)}
+}
+
+// Load returns the aggregate's snapshot, if any.
+func (s *MemoryStore) Load(_ context.Context, aggregateType, aggregateID string) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap, ok := s.snapshots[snapshotKey{aggregateType, aggregateID}]
+	snap.State = slices.Clone(snap.State)
+	return snap, ok, nil
+}
+
+// Save stores snap unless a newer snapshot of the same shape exists.
+func (s *MemoryStore) Save(_ context.Context, snap Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := snapshotKey{snap.AggregateType, snap.AggregateID}
+	if stored, ok := s.snapshots[key]; ok &&
+		stored.Version >= snap.Version && stored.StateVersion == snap.StateVersion {
+		return nil
+	}
+	snap.State = slices.Clone(snap.State)
+	s.snapshots[key] = snap
+	return nil
+}
+
+// DeleteType removes every snapshot of aggregateType.
+func (s *MemoryStore) DeleteType(_ context.Context, aggregateType string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key := range s.snapshots {
+		if key.aggregateType == aggregateType {
+			delete(s.snapshots, key)
+			n++
+		}
+	}
+	return n, nil
+}
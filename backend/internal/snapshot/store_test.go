@@ -0,0 +1,130 @@
+package snapshot_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/snapshot"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+var stores = []struct {
+	name string
+	open func(t *testing.T) snapshot.Store
+}{
+	{"memory", func(*testing.T) snapshot.Store { return snapshot.NewMemoryStore() }},
+	{"mysql", func(t *testing.T) snapshot.Store {
+		db := testhelper.OpenTestDB(t)
+		if err := migrations.Run(db); err != nil {
+			t.Fatalf("run migrations: %v", err)
+		}
+		return snapshot.NewMySQLStore(db)
+	}},
+}
+
+func newSnapshot(id string, version, stateVersion int, state string) snapshot.Snapshot {
+	return snapshot.Snapshot{
+		AggregateType: "counter",
+		AggregateID:   id,
+		Version:       version,
+		StateVersion:  stateVersion,
+		State:         []byte(state),
+		TakenAt:       time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStore_Save(t *testing.T) {
+	tests := []struct {
+		name      string
+		saves     []snapshot.Snapshot
+		wantState string
+	}{
+		{"first", []snapshot.Snapshot{newSnapshot("a", 5, 1, `5`)}, `5`},
+		{"newer replaces", []snapshot.Snapshot{
+			newSnapshot("a", 5, 1, `5`),
+			newSnapshot("a", 9, 1, `9`),
+		}, `9`},
+		{"older is ignored", []snapshot.Snapshot{
+			newSnapshot("a", 9, 1, `9`),
+			newSnapshot("a", 5, 1, `5`),
+		}, `9`},
+		{"new state version replaces older version", []snapshot.Snapshot{
+			newSnapshot("a", 9, 1, `9`),
+			newSnapshot("a", 5, 2, `"five"`),
+		}, `"five"`},
+	}
+
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					store := s.open(t)
+					ctx := context.Background()
+
+					for _, snap := range tt.saves {
+						if err := store.Save(ctx, snap); err != nil {
+							t.Fatalf("save v%d: %v", snap.Version, err)
+						}
+					}
+
+					got, ok, err := store.Load(ctx, "counter", "a")
+					if err != nil || !ok {
+						t.Fatalf("load: ok = %v, err = %v", ok, err)
+					}
+					if string(got.State) != tt.wantState {
+						t.Errorf("state = %s, want %s", got.State, tt.wantState)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestStore_LoadMissing(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			_, ok, err := s.open(t).Load(context.Background(), "counter", "missing")
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if ok {
+				t.Error("ok = true for a missing snapshot")
+			}
+		})
+	}
+}
+
+func TestStore_DeleteType(t *testing.T) {
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			store := s.open(t)
+			ctx := context.Background()
+
+			other := newSnapshot("c", 3, 1, `{}`)
+			other.AggregateType = "other"
+			for _, snap := range []snapshot.Snapshot{
+				newSnapshot("a", 3, 1, `{}`), newSnapshot("b", 3, 1, `{}`), other,
+			} {
+				if err := store.Save(ctx, snap); err != nil {
+					t.Fatalf("save: %v", err)
+				}
+			}
+
+			n, err := store.DeleteType(ctx, "counter")
+			if err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if n != 2 {
+				t.Errorf("deleted %d snapshots, want 2", n)
+			}
+			if _, ok, _ := store.Load(ctx, "counter", "a"); ok {
+				t.Error("counter snapshot survived DeleteType")
+			}
+			if _, ok, _ := store.Load(ctx, "other", "c"); !ok {
+				t.Error("snapshot of another type was deleted")
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// maxRangeMonths bounds how many months GET /summaries/monthly/range returns.
+const maxRangeMonths = 24
+
+// Handler handles HTTP requests for spending summaries.
+// The totals are updated asynchronously by Projector, and income by
+// income.Projector, running under a projection.Runner, so they can lag
+// just-recorded expenses and income for a moment.
+// Every route must be behind middleware.Authenticate, and summarizes the
+// book given by the book query parameter, which defaults to the user's
+// personal book. Books the user has no role in are reported as not found.
+type Handler struct {
+	repo    Reader
+	incomes IncomeReader
+	books   expense.Books
+}
+
+// IncomeReader reads the income that summaries set against spending.
+// income.Repository implements it.
+type IncomeReader interface {
+	// DailyTotals returns the daily totals per source of the income in
+	// bookID dated from from to to, inclusive, as YYYY-MM-DD.
+	DailyTotals(ctx context.Context, bookID, from, to string) ([]income.DailyTotal, error)
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(repo Reader, incomes IncomeReader, books expense.Books) *Handler {
+	return &Handler{repo: repo, incomes: incomes, books: books}
+}
+
+// Register adds summary routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /summaries/monthly", h.MonthlySummary)
+	mux.HandleFunc("GET /summaries/monthly/range", h.MonthlySummaryRange)
+}
+
+// MonthlySummary handles GET /summaries/monthly?month=YYYY-MM.
+func (h *Handler) MonthlySummary(w http.ResponseWriter, r *http.Request) {
+	month, err := parseMonth(r, "month")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	book, err := h.book(r)
+	if err != nil {
+		writeBookError(w, err)
+		return
+	}
+
+	months, err := h.months(r.Context(), book, month, month)
+	if err != nil {
+		log.Printf("monthly summary: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, months[0])
+}
+
+// MonthlySummaryRange handles GET /summaries/monthly/range?from=YYYY-MM&to=YYYY-MM.
+// It returns the summary of every month from from to to, inclusive, in order.
+func (h *Handler) MonthlySummaryRange(w http.ResponseWriter, r *http.Request) {
+	from, fromErr := parseMonth(r, "from")
+	to, toErr := parseMonth(r, "to")
+	var msg string
+	switch {
+	case fromErr != nil:
+		msg = fromErr.Error()
+	case toErr != nil:
+		msg = toErr.Error()
+	case from.After(to):
+		msg = "from must not be after to"
+	case from.AddDate(0, maxRangeMonths, 0).Before(to.AddDate(0, 1, 0)):
+		msg = fmt.Sprintf("the range must not exceed %d months", maxRangeMonths)
+	}
+	if msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	book, err := h.book(r)
+	if err != nil {
+		writeBookError(w, err)
+		return
+	}
+
+	months, err := h.months(r.Context(), book, from, to)
+	if err != nil {
+		log.Printf("monthly summary range: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, months)
+}
+
+// months summarizes every month of book from from to to, which must be
+// first days of months, with a single read of expenses and one of income
+// that include the month before from.
+func (h *Handler) months(ctx context.Context, book string, from, to time.Time) ([]Monthly, error) {
+	first := from.AddDate(0, -1, 0).Format(time.DateOnly)
+	last := to.AddDate(0, 1, -1).Format(time.DateOnly)
+	totals, err := h.repo.DailyTotals(ctx, book, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("read daily totals: %w", err)
+	}
+	incomes, err := h.incomes.DailyTotals(ctx, book, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("read income totals: %w", err)
+	}
+
+	var months []Monthly
+	for m := from; !m.After(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, buildMonthly(m, totals, incomes))
+	}
+	return months, nil
+}
+
+// parseMonth reads a required YYYY-MM query parameter as the first day of
+// the month.
+func parseMonth(r *http.Request, key string) (time.Time, error) {
+	s := r.URL.Query().Get(key)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%s is required", key)
+	}
+	t, err := time.Parse(monthLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be in YYYY-MM format", key)
+	}
+	return t, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
+
+// book returns the book r asks for, or expense.ErrBookNotFound if the user
+// has no role in it.
+func (h *Handler) book(r *http.Request) (string, error) {
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	book := r.URL.Query().Get("book")
+	if book == "" {
+		return userID, nil
+	}
+	_, err := h.books.Role(ctx, userID, book)
+	if errors.Is(err, household.ErrNotFound) {
+		return "", expense.ErrBookNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up role in book %s: %w", book, err)
+	}
+	return book, nil
+}
+
+// writeBookError maps an error from book to an HTTP response.
+func writeBookError(w http.ResponseWriter, err error) {
+	if errors.Is(err, expense.ErrBookNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	log.Printf("summary book: %v", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+}
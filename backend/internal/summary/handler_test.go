@@ -0,0 +1,250 @@
+package summary_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/household"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+)
+
+// newServer serves the summary routes to alice over memory read models
+// holding her given expenses and income, keyed by ID, an expense and income
+// of bob's that must not show up, and an expense bob recorded in sharedBook,
+// which alice is a member of.
+func newServer(t *testing.T, expenses map[string]expense.RecordExpenseCommand, incomes map[string]income.RecordIncomeCommand) *httptest.Server {
+	t.Helper()
+
+	repo := summary.NewMemoryRepository()
+	projector := summary.NewProjector(repo)
+	e := &expenseEvents{t: t}
+	for id, cmd := range expenses {
+		e.record(id, cmd)
+	}
+	e.recordAs("bob", "bob", expense.RecordExpenseCommand{Amount: 99999, Category: "食費", Date: "2026-02-01"})
+	e.recordAs("bob", "shared", expense.RecordExpenseCommand{BookID: sharedBook, Amount: 4000, Category: "日用品", Date: "2026-02-10"})
+	for _, event := range e.events {
+		if err := projector.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+	}
+
+	incomeRepo := income.NewMemoryRepository()
+	incomeProjector := income.NewProjector(incomeRepo)
+	recordIncome := func(id string, cmd income.RecordIncomeCommand) {
+		event, err := income.RecordIncome(id, cmd)
+		if err != nil {
+			t.Fatalf("record %s: %v", id, err)
+		}
+		if err := incomeProjector.Apply(context.Background(), event); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+	}
+	for id, cmd := range incomes {
+		cmd.BookID = "alice"
+		recordIncome(id, cmd)
+	}
+	recordIncome("bob-salary", income.RecordIncomeCommand{BookID: "bob", Amount: 99999, Source: income.SourceSalary, Date: "2026-02-25"})
+
+	mux := http.NewServeMux()
+	summary.NewHandler(repo, incomeRepo, sharedBooks{}).Register(mux)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(middleware.WithUser(r.Context(), "alice")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const sharedBook = "household-1"
+
+// sharedBooks makes alice a viewer of sharedBook.
+type sharedBooks struct{}
+
+func (sharedBooks) Role(_ context.Context, userID, bookID string) (household.Role, error) {
+	if bookID == userID || bookID == sharedBook && userID == "alice" {
+		return household.RoleViewer, nil
+	}
+	return "", household.ErrNotFound
+}
+
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestMonthlySummary(t *testing.T) {
+	srv := newServer(t, map[string]expense.RecordExpenseCommand{
+		"jan-food":   {Amount: 2000, Category: "食費", Date: "2026-01-15"},
+		"jan-books":  {Amount: 1000, Category: "書籍", Date: "2026-01-20"},
+		"feb-food-1": {Amount: 1000, Category: "食費", Date: "2026-02-01"},
+		"feb-food-2": {Amount: 500, Category: "食費", Date: "2026-02-01"},
+		"feb-travel": {Amount: 1500, Category: "交通費", Date: "2026-02-28"},
+		"mar-food":   {Amount: 9999, Category: "食費", Date: "2026-03-01"},
+	}, map[string]income.RecordIncomeCommand{
+		"jan-salary": {Amount: 2000, Source: income.SourceSalary, Date: "2026-01-25"},
+		"feb-salary": {Amount: 10000, Source: income.SourceSalary, Date: "2026-02-25"},
+		"feb-refund": {Amount: 2000, Source: income.SourceRefund, Date: "2026-02-01"},
+		"mar-salary": {Amount: 9999, Source: income.SourceSalary, Date: "2026-03-25"},
+	})
+
+	var got summary.Monthly
+	if status := getJSON(t, srv.URL+"/summaries/monthly?month=2026-02", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+
+	if got.Month != "2026-02" || got.Total != 3000 || got.Count != 3 {
+		t.Errorf("month = %s total %d count %d, want 2026-02 total 3000 count 3", got.Month, got.Total, got.Count)
+	}
+
+	wantCategories := []summary.CategoryTotal{
+		{Category: "食費", Total: 1500, Count: 2, PreviousTotal: 2000},
+		{Category: "交通費", Total: 1500, Count: 1},
+		{Category: "書籍", PreviousTotal: 1000},
+	}
+	if len(got.Categories) != len(wantCategories) {
+		t.Fatalf("categories = %+v, want %+v", got.Categories, wantCategories)
+	}
+	for i, want := range wantCategories {
+		if got.Categories[i] != want {
+			t.Errorf("categories[%d] = %+v, want %+v", i, got.Categories[i], want)
+		}
+	}
+
+	if len(got.Daily) != 28 {
+		t.Fatalf("len(daily) = %d, want 28", len(got.Daily))
+	}
+	if d := got.Daily[0]; d != (summary.DayTotal{Date: "2026-02-01", Total: 1500, Count: 2}) {
+		t.Errorf("daily[0] = %+v", d)
+	}
+	if d := got.Daily[1]; d != (summary.DayTotal{Date: "2026-02-02"}) {
+		t.Errorf("daily[1] = %+v, want an empty day", d)
+	}
+	if d := got.Daily[27]; d != (summary.DayTotal{Date: "2026-02-28", Total: 1500, Count: 1}) {
+		t.Errorf("daily[27] = %+v", d)
+	}
+
+	prev := got.Previous
+	if prev.Month != "2026-01" || prev.Total != 3000 || prev.Count != 2 || prev.Difference != 0 {
+		t.Errorf("previous = %+v, want 2026-01 total 3000 count 2 difference 0", prev)
+	}
+	if prev.ChangeRate == nil || *prev.ChangeRate != 0 {
+		t.Errorf("change rate = %v, want 0", prev.ChangeRate)
+	}
+
+	if got.Income != 12000 || got.NetSavings != 9000 || got.SavingsRate == nil || *got.SavingsRate != 0.75 {
+		t.Errorf("income %d net savings %d rate %v, want 12000, 9000 and 0.75", got.Income, got.NetSavings, got.SavingsRate)
+	}
+	if prev.Income != 2000 || prev.NetSavings != -1000 {
+		t.Errorf("previous income %d net savings %d, want 2000 and -1000", prev.Income, prev.NetSavings)
+	}
+}
+
+func TestMonthlySummary_Empty(t *testing.T) {
+	srv := newServer(t, nil, nil)
+
+	var got summary.Monthly
+	if status := getJSON(t, srv.URL+"/summaries/monthly?month=2024-02", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if got.Total != 0 || len(got.Categories) != 0 || len(got.Daily) != 29 || got.Previous.ChangeRate != nil || got.SavingsRate != nil {
+		t.Errorf("got %+v, want an empty leap-year February without a change or savings rate", got)
+	}
+}
+
+func TestMonthlySummary_Book(t *testing.T) {
+	srv := newServer(t, map[string]expense.RecordExpenseCommand{
+		"feb-food": {Amount: 1000, Category: "食費", Date: "2026-02-01"},
+	}, nil)
+
+	var got summary.Monthly
+	if status := getJSON(t, srv.URL+"/summaries/monthly?month=2026-02&book="+sharedBook, &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if got.Total != 4000 || got.Count != 1 {
+		t.Errorf("shared book total %d count %d, want 4000 and 1", got.Total, got.Count)
+	}
+
+	for _, path := range []string{
+		"/summaries/monthly?month=2026-02&book=bob",
+		"/summaries/monthly/range?from=2026-01&to=2026-02&book=household-2",
+	} {
+		if status := getJSON(t, srv.URL+path, &got); status != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want %d", path, status, http.StatusNotFound)
+		}
+	}
+}
+
+func TestMonthlySummaryRange(t *testing.T) {
+	srv := newServer(t, map[string]expense.RecordExpenseCommand{
+		"nov": {Amount: 1000, Category: "食費", Date: "2025-11-30"},
+		"dec": {Amount: 1500, Category: "食費", Date: "2025-12-01"},
+		"jan": {Amount: 750, Category: "食費", Date: "2026-01-31"},
+	}, nil)
+
+	var got []summary.Monthly
+	if status := getJSON(t, srv.URL+"/summaries/monthly/range?from=2025-12&to=2026-02", &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+
+	want := []struct {
+		month      string
+		total      int64
+		changeRate float64
+	}{
+		{"2025-12", 1500, 0.5},
+		{"2026-01", 750, -0.5},
+		{"2026-02", 0, -1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("len(got) = %d, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.Month != w.month || m.Total != w.total || m.Previous.ChangeRate == nil || *m.Previous.ChangeRate != w.changeRate {
+			t.Errorf("got[%d] = %s total %d change %v, want %s total %d change %v",
+				i, m.Month, m.Total, m.Previous.ChangeRate, w.month, w.total, w.changeRate)
+		}
+	}
+}
+
+func TestSummary_InvalidQuery(t *testing.T) {
+	srv := newServer(t, nil, nil)
+
+	for _, path := range []string{
+		"/summaries/monthly",
+		"/summaries/monthly?month=2026-2",
+		"/summaries/monthly?month=2026-13",
+		"/summaries/monthly/range?from=2026-01",
+		"/summaries/monthly/range?from=2026-03&to=2026-01",
+		"/summaries/monthly/range?from=2024-01&to=2026-01",
+	} {
+		t.Run(path, func(t *testing.T) {
+			if status := getJSON(t, srv.URL+path, nil); status != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", status, http.StatusBadRequest)
+			}
+		})
+	}
+
+	// Exactly the maximum range is accepted.
+	var got []summary.Monthly
+	if status := getJSON(t, srv.URL+"/summaries/monthly/range?from=2024-02&to=2026-01", &got); status != http.StatusOK || len(got) != 24 {
+		t.Errorf("24-month range: status %d with %d months, want 200 with 24", status, len(got))
+	}
+}
@@ -0,0 +1,81 @@
+package summary
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+// MemoryRepository keeps daily totals per book, day and category in a map,
+// along with the last applied version of each expense.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	totals   map[dayCategory]DailyTotal
+	expenses map[string]expenseState
+}
+
+type dayCategory struct {
+	bookID, date, category string
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		totals:   make(map[dayCategory]DailyTotal),
+		expenses: make(map[string]expenseState),
+	}
+}
+
+// DailyTotals returns the book's non-empty daily totals between from and to.
+func (r *MemoryRepository) DailyTotals(_ context.Context, bookID, from, to string) ([]DailyTotal, error) {
+	r.mu.RLock()
+	var totals []DailyTotal
+	for key, t := range r.totals {
+		// Dates are YYYY-MM-DD, so string order is chronological order.
+		if key.bookID == bookID && t.Count > 0 && t.Date >= from && t.Date <= to {
+			totals = append(totals, t)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(totals, func(a, b DailyTotal) int {
+		return cmp.Or(cmp.Compare(a.Date, b.Date), cmp.Compare(a.Category, b.Category))
+	})
+	return totals, nil
+}
+
+// ApplyExpense moves the expense's share of the totals.
+func (r *MemoryRepository) ApplyExpense(_ context.Context, c expense.Change) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, found := r.expenses[c.ID]
+	switch {
+	case !found && !c.Recorded():
+		return errNotProjected
+	case found && prev.Version >= c.Version:
+		return nil
+	}
+
+	next := prev.next(c)
+	if found && !prev.Voided {
+		r.addTotal(prev, -1)
+	}
+	if !next.Voided {
+		r.addTotal(next, 1)
+	}
+	r.expenses[c.ID] = next
+	return nil
+}
+
+func (r *MemoryRepository) addTotal(s expenseState, sign int) {
+	key := dayCategory{s.BookID, s.Date, s.Category}
+	t := r.totals[key]
+	t.Date, t.Category = s.Date, s.Category
+	t.Total += int64(sign) * s.Amount
+	t.Count += sign
+	r.totals[key] = t
+}
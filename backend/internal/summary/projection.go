@@ -0,0 +1,64 @@
+package summary
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the summary read model's checkpoint.
+	projectionName = "summaries"
+	// totalsTable holds the daily totals per category.
+	totalsTable = "summary_daily_totals"
+	// expensesTable holds the state each expense was last counted with.
+	expensesTable = "summary_expenses"
+)
+
+// Projector applies expense events to the summary read model, moving each
+// expense's amount between daily totals as it is corrected or voided.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL summary projection for rebuilds.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{totalsTable, expensesTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{
+				db:            db,
+				totalsTable:   tables[totalsTable],
+				expensesTable: tables[expensesTable],
+			})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply processes an expense event and updates the totals accordingly.
+// Events of other aggregate types are ignored.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	c, ok, err := expense.DecodeChange(event)
+	if err != nil || !ok {
+		return err
+	}
+	if err := p.w.ApplyExpense(ctx, c); err != nil {
+		return fmt.Errorf("apply %s v%d to %s: %w", event.EventType, event.Version, event.AggregateID, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package summary_test
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/summary"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() summary.ReadModel { return summary.NewMemoryRepository() },
+	func(db *sql.DB) summary.ReadModel { return summary.NewRepository(db) },
+)
+
+// expenseEvents builds expense streams through the expense aggregate, so
+// the projector sees the events the handler would append.
+type expenseEvents struct {
+	t      *testing.T
+	events []eventstore.Event
+}
+
+// record records an expense as alice.
+func (e *expenseEvents) record(id string, cmd expense.RecordExpenseCommand) {
+	e.t.Helper()
+	e.recordAs("alice", id, cmd)
+}
+
+func (e *expenseEvents) recordAs(userID, id string, cmd expense.RecordExpenseCommand) {
+	e.t.Helper()
+	event, err := expense.RecordExpense(id, cmd)
+	if err != nil {
+		e.t.Fatalf("record %s: %v", id, err)
+	}
+	event.RecordedBy = userID
+	e.events = append(e.events, event)
+}
+
+func (e *expenseEvents) correct(id string, cmd expense.CorrectExpenseCommand) {
+	e.t.Helper()
+	event, err := e.load(id).Correct(cmd)
+	if err != nil {
+		e.t.Fatalf("correct %s: %v", id, err)
+	}
+	e.events = append(e.events, event)
+}
+
+func (e *expenseEvents) void(id string) {
+	e.t.Helper()
+	event, err := e.load(id).Void(expense.VoidExpenseCommand{})
+	if err != nil {
+		e.t.Fatalf("void %s: %v", id, err)
+	}
+	e.events = append(e.events, event)
+}
+
+func (e *expenseEvents) load(id string) *expense.Expense {
+	e.t.Helper()
+	var stream []eventstore.Event
+	for _, event := range e.events {
+		if event.AggregateID == id {
+			stream = append(stream, event)
+		}
+	}
+	exp, err := expense.LoadExpense(stream)
+	if err != nil {
+		e.t.Fatalf("load %s: %v", id, err)
+	}
+	return exp
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestProjector(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := summary.NewProjector(repo)
+			ctx := context.Background()
+
+			e := &expenseEvents{t: t}
+			e.record("a", expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-02-01"})
+			e.record("b", expense.RecordExpenseCommand{Amount: 500, Category: "食費", Date: "2026-02-01"})
+			e.record("c", expense.RecordExpenseCommand{Amount: 300, Category: "日用品", Date: "2026-02-02"})
+			// Moves b to another day and category.
+			e.correct("b", expense.CorrectExpenseCommand{Category: ptr("交通費"), Date: ptr("2026-02-03")})
+			e.correct("a", expense.CorrectExpenseCommand{Amount: ptr(int64(1200))})
+			e.void("c")
+			// Other users' expenses are kept apart.
+			e.recordAs("bob", "d", expense.RecordExpenseCommand{Amount: 700, Category: "食費", Date: "2026-02-01"})
+			e.correct("d", expense.CorrectExpenseCommand{Amount: ptr(int64(900))})
+			// Events of other aggregates are ignored.
+			e.events = append(e.events, eventstore.Event{AggregateType: "budget", AggregateID: "x", Version: 1, EventType: "BudgetSet"})
+
+			// Applying everything twice checks that replays are absorbed.
+			for range 2 {
+				for _, event := range e.events {
+					if err := projector.Apply(ctx, event); err != nil {
+						t.Fatalf("apply %s v%d: %v", event.EventType, event.Version, err)
+					}
+				}
+			}
+
+			got, err := repo.DailyTotals(ctx, "alice", "2026-02-01", "2026-02-28")
+			if err != nil {
+				t.Fatalf("daily totals: %v", err)
+			}
+			want := []summary.DailyTotal{
+				{Date: "2026-02-01", Category: "食費", Total: 1200, Count: 1},
+				{Date: "2026-02-03", Category: "交通費", Total: 500, Count: 1},
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("alice's totals = %+v, want %+v", got, want)
+			}
+
+			got, err = repo.DailyTotals(ctx, "bob", "2026-02-01", "2026-02-28")
+			if err != nil {
+				t.Fatalf("daily totals: %v", err)
+			}
+			want = []summary.DailyTotal{{Date: "2026-02-01", Category: "食費", Total: 900, Count: 1}}
+			if !slices.Equal(got, want) {
+				t.Errorf("bob's totals = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestProjector_CorrectionBeforeRecording(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			projector := summary.NewProjector(b.Open(t))
+
+			e := &expenseEvents{t: t}
+			e.record("a", expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2026-02-01"})
+			e.correct("a", expense.CorrectExpenseCommand{Amount: ptr(int64(1200))})
+
+			if err := projector.Apply(context.Background(), e.events[1]); err == nil {
+				t.Error("applying a correction of an unknown expense succeeded")
+			}
+		})
+	}
+}
+
+func TestDailyTotals_Range(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repo := b.Open(t)
+			projector := summary.NewProjector(repo)
+			ctx := context.Background()
+
+			e := &expenseEvents{t: t}
+			for i, date := range []string{"2026-01-31", "2026-02-01", "2026-02-28", "2026-03-01"} {
+				e.record(date, expense.RecordExpenseCommand{Amount: int64(i + 1), Category: "食費", Date: date})
+			}
+			for _, event := range e.events {
+				if err := projector.Apply(ctx, event); err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+			}
+
+			got, err := repo.DailyTotals(ctx, "alice", "2026-02-01", "2026-02-28")
+			if err != nil {
+				t.Fatalf("daily totals: %v", err)
+			}
+			if len(got) != 2 || got[0].Date != "2026-02-01" || got[1].Date != "2026-02-28" {
+				t.Errorf("totals = %+v, want 2026-02-01 and 2026-02-28", got)
+			}
+		})
+	}
+}
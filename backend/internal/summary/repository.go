@@ -0,0 +1,171 @@
+package summary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+// errNotProjected is returned by Writer when a correction or void targets an
+// expense whose recording has not been applied yet.
+var errNotProjected = errors.New("expense is not projected yet")
+
+// Reader is the query side of the summary read model.
+type Reader interface {
+	// DailyTotals returns the non-empty daily totals of the expenses in
+	// bookID dated from from to to, inclusive, as YYYY-MM-DD, ordered by date
+	// and category. A user's ID is the ID of their personal book.
+	DailyTotals(ctx context.Context, bookID, from, to string) ([]DailyTotal, error)
+}
+
+// Writer is the projection side of the summary read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// ApplyExpense moves the expense's share of the totals to its state after
+	// c, if c.Version is newer than the last version applied. Returns
+	// errNotProjected if c is not a recording and the expense is unknown.
+	ApplyExpense(ctx context.Context, c expense.Change) error
+}
+
+// ReadModel combines both sides of the summary read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// expenseState is the part of an expense the totals depend on.
+type expenseState struct {
+	BookID   string
+	Date     string
+	Category string
+	Amount   int64
+	Version  int
+	Voided   bool
+}
+
+// next returns the expense's state after c.
+func (s expenseState) next(c expense.Change) expenseState {
+	if c.Voided {
+		s.Voided = true
+	} else {
+		book := s.BookID
+		if c.Recorded() {
+			book = c.Book
+		}
+		s = expenseState{BookID: book, Date: c.Date, Category: c.Category, Amount: c.Amount}
+	}
+	s.Version = c.Version
+	return s
+}
+
+// Repository implements ReadModel on top of MySQL. It keeps the totals in
+// one table and, to undo an expense's share on corrections and voids, the
+// state each expense was last counted with in another.
+type Repository struct {
+	db            *sql.DB
+	totalsTable   string
+	expensesTable string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, totalsTable: totalsTable, expensesTable: expensesTable}
+}
+
+// DailyTotals returns the user's non-empty daily totals between from and to.
+func (r *Repository) DailyTotals(ctx context.Context, bookID, from, to string) ([]DailyTotal, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DATE_FORMAT(date, '%%Y-%%m-%%d'), category, total, expense_count
+		 FROM %s
+		 WHERE book_id = ? AND date BETWEEN ? AND ? AND expense_count > 0
+		 ORDER BY date, category`, r.totalsTable),
+		bookID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query daily totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []DailyTotal
+	for rows.Next() {
+		var t DailyTotal
+		if err := rows.Scan(&t.Date, &t.Category, &t.Total, &t.Count); err != nil {
+			return nil, fmt.Errorf("scan daily total: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate daily totals: %w", err)
+	}
+	return totals, nil
+}
+
+// ApplyExpense moves the expense's share of the totals in one transaction.
+func (r *Repository) ApplyExpense(ctx context.Context, c expense.Change) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var prev expenseState
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT book_id, DATE_FORMAT(date, '%%Y-%%m-%%d'), category, amount, version, voided
+		 FROM %s WHERE expense_id = ? FOR UPDATE`, r.expensesTable), c.ID,
+	).Scan(&prev.BookID, &prev.Date, &prev.Category, &prev.Amount, &prev.Version, &prev.Voided)
+	found := err == nil
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if !c.Recorded() {
+			return errNotProjected
+		}
+	case err != nil:
+		return fmt.Errorf("load expense state: %w", err)
+	case prev.Version >= c.Version:
+		return nil
+	}
+
+	next := prev.next(c)
+	if found && !prev.Voided {
+		if err := r.addTotal(ctx, tx, prev, -1); err != nil {
+			return err
+		}
+	}
+	if !next.Voided {
+		if err := r.addTotal(ctx, tx, next, 1); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (expense_id, book_id, date, category, amount, version, voided)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE date = ?, category = ?, amount = ?, version = ?, voided = ?`, r.expensesTable),
+		c.ID, next.BookID, next.Date, next.Category, next.Amount, next.Version, next.Voided,
+		next.Date, next.Category, next.Amount, next.Version, next.Voided,
+	); err != nil {
+		return fmt.Errorf("save expense state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// addTotal adds sign times the expense to its day's total.
+func (r *Repository) addTotal(ctx context.Context, tx *sql.Tx, s expenseState, sign int) error {
+	amount := int64(sign) * s.Amount
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (book_id, date, category, total, expense_count)
+		 VALUES (?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE total = total + ?, expense_count = expense_count + ?`, r.totalsTable),
+		s.BookID, s.Date, s.Category, amount, sign, amount, sign,
+	); err != nil {
+		return fmt.Errorf("update daily total: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package summary keeps spending totals per day and category, projected from
+// expense events, and reports them by month alongside the income read from
+// the income read model.
+package summary
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// monthLayout is the format of months in requests and responses.
+const monthLayout = "2006-01"
+
+// DailyTotal is the sum of one day's expenses in one category.
+type DailyTotal struct {
+	Date     string
+	Category string
+	Total    int64
+	Count    int
+}
+
+// Monthly summarizes a month's expenses and income.
+type Monthly struct {
+	Month string `json:"month"`
+	// Total and Count are the month's expenses.
+	Total int64 `json:"total"`
+	Count int   `json:"count"`
+	// Income is the month's income from every source.
+	Income int64 `json:"income"`
+	// NetSavings is Income minus Total. It is negative when the month spent
+	// more than it earned.
+	NetSavings int64 `json:"net_savings"`
+	// SavingsRate is NetSavings relative to Income, or nil if there was no
+	// income.
+	SavingsRate *float64 `json:"savings_rate"`
+	// Categories are ordered by total, then previous month's total,
+	// descending. Categories that only had expenses in the previous month
+	// are listed with a zero total.
+	Categories []CategoryTotal `json:"categories"`
+	// Daily lists every day of the month, including days without expenses.
+	Daily    []DayTotal `json:"daily"`
+	Previous Comparison `json:"previous"`
+}
+
+// CategoryTotal is a month's total in one category.
+type CategoryTotal struct {
+	Category      string `json:"category"`
+	Total         int64  `json:"total"`
+	Count         int    `json:"count"`
+	PreviousTotal int64  `json:"previous_total"`
+}
+
+// DayTotal is one day's total across categories.
+type DayTotal struct {
+	Date  string `json:"date"`
+	Total int64  `json:"total"`
+	Count int    `json:"count"`
+}
+
+// Comparison compares a month with the month before it.
+type Comparison struct {
+	Month string `json:"month"`
+	Total int64  `json:"total"`
+	Count int    `json:"count"`
+	// Income and NetSavings are the previous month's, as in Monthly.
+	Income     int64 `json:"income"`
+	NetSavings int64 `json:"net_savings"`
+	// Difference is the month's total minus the previous month's.
+	Difference int64 `json:"difference"`
+	// ChangeRate is Difference relative to the previous month's total, or
+	// nil if that was zero.
+	ChangeRate *float64 `json:"change_rate"`
+}
+
+// buildMonthly summarizes month, which must be the first day of a month,
+// from expense totals and income covering at least that month and the one
+// before it.
+func buildMonthly(month time.Time, totals []DailyTotal, incomes []income.DailyTotal) Monthly {
+	prevMonth := month.AddDate(0, -1, 0)
+	m := Monthly{
+		Month:      month.Format(monthLayout),
+		Categories: []CategoryTotal{},
+		Previous:   Comparison{Month: prevMonth.Format(monthLayout)},
+	}
+
+	days := make(map[string]*DayTotal)
+	for d := month; d.Month() == month.Month(); d = d.AddDate(0, 0, 1) {
+		m.Daily = append(m.Daily, DayTotal{Date: d.Format(time.DateOnly)})
+	}
+	for i := range m.Daily {
+		days[m.Daily[i].Date] = &m.Daily[i]
+	}
+
+	categories := make(map[string]*CategoryTotal)
+	category := func(name string) *CategoryTotal {
+		c, ok := categories[name]
+		if !ok {
+			c = &CategoryTotal{Category: name}
+			categories[name] = c
+		}
+		return c
+	}
+
+	for _, t := range totals {
+		switch t.Date[:len(monthLayout)] {
+		case m.Month:
+			m.Total += t.Total
+			m.Count += t.Count
+			days[t.Date].Total += t.Total
+			days[t.Date].Count += t.Count
+			c := category(t.Category)
+			c.Total += t.Total
+			c.Count += t.Count
+		case m.Previous.Month:
+			m.Previous.Total += t.Total
+			m.Previous.Count += t.Count
+			category(t.Category).PreviousTotal += t.Total
+		}
+	}
+
+	for _, t := range incomes {
+		switch t.Date[:len(monthLayout)] {
+		case m.Month:
+			m.Income += t.Total
+		case m.Previous.Month:
+			m.Previous.Income += t.Total
+		}
+	}
+	m.NetSavings = m.Income - m.Total
+	m.Previous.NetSavings = m.Previous.Income - m.Previous.Total
+	if m.Income != 0 {
+		rate := float64(m.NetSavings) / float64(m.Income)
+		m.SavingsRate = &rate
+	}
+
+	for _, c := range categories {
+		m.Categories = append(m.Categories, *c)
+	}
+	slices.SortFunc(m.Categories, func(a, b CategoryTotal) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(b.PreviousTotal, a.PreviousTotal),
+			cmp.Compare(a.Category, b.Category))
+	})
+
+	m.Previous.Difference = m.Total - m.Previous.Total
+	if m.Previous.Total != 0 {
+		rate := float64(m.Previous.Difference) / float64(m.Previous.Total)
+		m.Previous.ChangeRate = &rate
+	}
+	return m
+}
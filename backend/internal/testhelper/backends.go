@@ -0,0 +1,31 @@
+package testhelper
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/migrations"
+)
+
+// Backend is one implementation of a store or read model that a test runs
+// against.
+type Backend[T any] struct {
+	Name string
+	Open func(t *testing.T) T
+}
+
+// Backends returns a memory backend, built by memory, and a MySQL backend,
+// built by mysql on the test database with every migration applied. Tests
+// on the MySQL backend are skipped unless TEST_DATABASE_URL is set.
+func Backends[T any](memory func() T, mysql func(db *sql.DB) T) []Backend[T] {
+	return []Backend[T]{
+		{"memory", func(*testing.T) T { return memory() }},
+		{"mysql", func(t *testing.T) T {
+			db := OpenTestDB(t)
+			if err := migrations.Run(db); err != nil {
+				t.Fatalf("run migrations: %v", err)
+			}
+			return mysql(db)
+		}},
+	}
+}
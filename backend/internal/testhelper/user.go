@@ -0,0 +1,23 @@
+package testhelper
+
+import (
+	"net/http"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+// UserHeader names the user a test request is made as, in place of the
+// bearer token middleware.Authenticate would verify.
+const UserHeader = "X-Test-User"
+
+// AsUser serves requests with next as the user named in their UserHeader,
+// or as alice if they have none.
+func AsUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get(UserHeader)
+		if userID == "" {
+			userID = "alice"
+		}
+		next.ServeHTTP(w, r.WithContext(middleware.WithUser(r.Context(), userID)))
+	})
+}
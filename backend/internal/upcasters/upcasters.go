@@ -0,0 +1,19 @@
+// Package upcasters gathers the payload upcasters of every domain, for the
+// binaries that read the event store.
+package upcasters
+
+import (
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+)
+
+// All returns the upcasters of every domain whose payloads have changed
+// shape, so reads through a store wrapped with them always see current
+// payload schemas. A domain that adds RegisterUpcasters must be added here.
+func All() *eventstore.Upcasters {
+	u := eventstore.NewUpcasters()
+	expense.RegisterUpcasters(u)
+	income.RegisterUpcasters(u)
+	return u
+}
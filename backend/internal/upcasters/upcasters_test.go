@@ -0,0 +1,38 @@
+package upcasters_test
+
+import (
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/income"
+	"github.com/kikeda1102/kakei-board/backend/internal/upcasters"
+)
+
+func TestAll(t *testing.T) {
+	u := upcasters.All()
+
+	recorded, err := u.Upcast(eventstore.Event{
+		AggregateID: "e1", AggregateType: expense.AggregateType, Version: 1, EventType: "ExpenseRecorded",
+		Payload:  []byte(`{"amount":800,"category":"交通費","memo":"","date":"2025-12-01"}`),
+		Metadata: eventstore.Metadata{SchemaVersion: 1},
+	})
+	if err != nil {
+		t.Fatalf("upcast expense: %v", err)
+	}
+	if _, _, err := expense.DecodeChange(recorded); err != nil {
+		t.Errorf("decode upcast expense: %v", err)
+	}
+
+	earned, err := u.Upcast(eventstore.Event{
+		AggregateID: "i1", AggregateType: income.AggregateType, Version: 1, EventType: "IncomeRecorded",
+		Payload:  []byte(`{"book_id":"book-1","amount":300000,"source":"salary","memo":"","date":"2026-02-25"}`),
+		Metadata: eventstore.Metadata{SchemaVersion: 1},
+	})
+	if err != nil {
+		t.Fatalf("upcast income: %v", err)
+	}
+	if _, _, err := income.DecodeChange(earned); err != nil {
+		t.Errorf("decode upcast income: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash is checked against when logging in as an unknown user, so the
+// response takes as long as for a wrong password and does not reveal which
+// usernames exist.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("kakei-board"), bcrypt.DefaultCost)
+
+// Handler handles HTTP requests for registration and login. Its routes must
+// be reachable without a token.
+type Handler struct {
+	store  eventstore.Store
+	tokens *Tokens
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(store eventstore.Store, tokens *Tokens) *Handler {
+	return &Handler{store: store, tokens: tokens}
+}
+
+// Register adds user routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /users", h.RegisterUser)
+	mux.HandleFunc("POST /sessions", h.Login)
+}
+
+type userResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// RegisterUser handles POST /users.
+func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	var cmd RegisterCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	event, err := Register(cmd)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	err = h.store.Append(r.Context(), []eventstore.Event{middleware.Stamp(r.Context(), event)}, 0)
+	var conflict *eventstore.VersionConflictError
+	if errors.As(err, &conflict) {
+		err = ErrUsernameTaken
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, userResponse{ID: event.AggregateID, Username: normalizeUsername(cmd.Username)})
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      userResponse `json:"user"`
+}
+
+// Login handles POST /sessions. It returns a bearer token for the
+// Authorization header of every other request.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	events, err := h.store.Load(r.Context(), aggregateType, ID(req.Username))
+	if err != nil {
+		writeError(w, fmt.Errorf("load events: %w", err))
+		return
+	}
+	u, err := LoadUser(events)
+	if errors.Is(err, ErrNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password))
+		writeError(w, ErrInvalidCredentials)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := u.CheckPassword(req.Password); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	token, expires, err := h.tokens.Issue(u.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, loginResponse{
+		Token:     token,
+		ExpiresAt: expires,
+		User:      userResponse{ID: u.ID, Username: u.Username},
+	})
+}
+
+// writeError maps domain errors to HTTP responses.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUsernameTaken):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidCredentials):
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("user: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
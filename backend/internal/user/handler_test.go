@@ -0,0 +1,77 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func post(t *testing.T, url, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return resp
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	tokens := newTestTokens(t, "s")
+	mux := http.NewServeMux()
+	NewHandler(eventstore.NewMemoryStore(), tokens).Register(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp := post(t, srv.URL+"/users", `{"username":"Alice","password":"correct horse"}`)
+	var registered userResponse
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || registered.ID != ID("alice") || registered.Username != "alice" {
+		t.Fatalf("POST /users = %d %+v, want %d for alice", resp.StatusCode, registered, http.StatusCreated)
+	}
+
+	for _, tt := range []struct {
+		name, body string
+		want       int
+	}{
+		{"taken in another case", `{"username":"ALICE","password":"another horse"}`, http.StatusConflict},
+		{"invalid", `{"username":"a","password":"correct horse"}`, http.StatusBadRequest},
+		{"malformed", `{`, http.StatusBadRequest},
+	} {
+		resp := post(t, srv.URL+"/users", tt.body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: POST /users status = %d, want %d", tt.name, resp.StatusCode, tt.want)
+		}
+	}
+
+	resp = post(t, srv.URL+"/sessions", `{"username":"alice","password":"correct horse"}`)
+	var session loginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || session.User.ID != ID("alice") {
+		t.Fatalf("POST /sessions = %d %+v, want %d for alice", resp.StatusCode, session, http.StatusOK)
+	}
+	if userID, err := tokens.Verify(session.Token); err != nil || userID != ID("alice") {
+		t.Errorf("token verifies as %q, %v, want alice", userID, err)
+	}
+
+	for _, body := range []string{
+		`{"username":"alice","password":"wrong horse"}`,
+		`{"username":"nobody","password":"correct horse"}`,
+	} {
+		resp := post(t, srv.URL+"/sessions", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: POST /sessions status = %d, want %d", body, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+}
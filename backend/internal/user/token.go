@@ -0,0 +1,88 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinSecretLength is the shortest secret NewTokens accepts, the size of an
+// HMAC-SHA256 key.
+const MinSecretLength = 32
+
+// ErrInvalidToken is returned for a token that is malformed, was not signed
+// with the secret or has expired.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Tokens issues and verifies bearer tokens. A token is its claims as
+// base64url JSON and their HMAC-SHA256, joined by a dot. Tokens are not
+// stored, so one stays valid until it expires; keep the TTL modest.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokens creates Tokens that sign with secret, which must be at least
+// MinSecretLength bytes, and expire after ttl.
+func NewTokens(secret []byte, ttl time.Duration) (*Tokens, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("token secret must be at least %d bytes", MinSecretLength)
+	}
+	return &Tokens{secret: secret, ttl: ttl, now: time.Now}, nil
+}
+
+type claims struct {
+	// Subject is the user ID.
+	Subject string `json:"sub"`
+	// Expires is the Unix time the token expires at.
+	Expires int64 `json:"exp"`
+}
+
+// Issue returns a token for the user with userID and when it expires.
+func (t *Tokens) Issue(userID string) (string, time.Time, error) {
+	expires := t.now().Add(t.ttl).Truncate(time.Second)
+	data, err := json.Marshal(claims{Subject: userID, Expires: expires.Unix()})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("marshal claims: %w", err)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload)), expires, nil
+}
+
+// Verify returns the ID of the user token was issued to, or ErrInvalidToken.
+// It implements middleware.TokenVerifier.
+func (t *Tokens) Verify(token string) (string, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, t.sign(payload)) {
+		return "", ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(data, &c); err != nil || c.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	if !t.now().Before(time.Unix(c.Expires, 0)) {
+		return "", ErrInvalidToken
+	}
+	return c.Subject, nil
+}
+
+func (t *Tokens) sign(payload string) []byte {
+	h := hmac.New(sha256.New, t.secret)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
@@ -0,0 +1,61 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTokens(t *testing.T, secret string) *Tokens {
+	t.Helper()
+	tokens, err := NewTokens([]byte(strings.Repeat(secret, MinSecretLength)), time.Hour)
+	if err != nil {
+		t.Fatalf("NewTokens: %v", err)
+	}
+	return tokens
+}
+
+func TestNewTokens_ShortSecret(t *testing.T) {
+	if _, err := NewTokens([]byte("short"), time.Hour); err == nil {
+		t.Error("NewTokens with a short secret succeeded")
+	}
+}
+
+func TestTokens(t *testing.T) {
+	tokens := newTestTokens(t, "s")
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	tokens.now = func() time.Time { return now }
+
+	token, expires, err := tokens.Issue("user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("expires = %v, want %v", expires, now.Add(time.Hour))
+	}
+	if userID, err := tokens.Verify(token); err != nil || userID != "user-1" {
+		t.Errorf("Verify = %q, %v, want user-1", userID, err)
+	}
+
+	payload, _, _ := strings.Cut(token, ".")
+	forged, _, _ := newTestTokens(t, "x").Issue("user-1")
+	_, forgedSig, _ := strings.Cut(forged, ".")
+	for name, bad := range map[string]string{
+		"empty":          "",
+		"no signature":   payload,
+		"tampered":       "X" + token[1:],
+		"other secret":   forged,
+		"swapped sig":    payload + "." + forgedSig,
+		"bad base64 sig": payload + ".!!!",
+	} {
+		if _, err := tokens.Verify(bad); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: Verify = %v, want ErrInvalidToken", name, err)
+		}
+	}
+
+	now = expires
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired: Verify = %v, want ErrInvalidToken", err)
+	}
+}
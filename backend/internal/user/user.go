@@ -0,0 +1,174 @@
+// Package user registers users and authenticates them by password.
+//
+// A user is an event-sourced aggregate whose ID is derived from the
+// username, so registering claims the username by appending the aggregate's
+// first event, and logging in needs no lookup table.
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const aggregateType = "user"
+
+const eventTypeRegistered = "UserRegistered"
+
+// schemaVersions holds the payload schema version of each user event.
+var schemaVersions = map[string]int{
+	eventTypeRegistered: 1,
+}
+
+const (
+	minPasswordLength = 8
+	// maxPasswordLength is the most bcrypt hashes; longer passwords would be
+	// silently truncated.
+	maxPasswordLength = 72
+)
+
+// usernamePattern is applied after lowercasing, so usernames are
+// case-insensitive.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_.-]{3,32}$`)
+
+var (
+	// ErrNotFound is returned when a user has no events.
+	ErrNotFound = errors.New("user not found")
+	// ErrUsernameTaken is returned when registering a username in use.
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrInvalidCredentials is returned when a username and password do not
+	// match a user. It does not tell which of the two was wrong.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// idNamespace derives user IDs from usernames.
+var idNamespace = uuid.MustParse("3d1c7a0e-95b2-4f6a-8e43-c0d9b6a15f27")
+
+// ID returns the aggregate ID of the user with username, ignoring case.
+func ID(username string) string {
+	return uuid.NewSHA1(idNamespace, []byte(normalizeUsername(username))).String()
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(username)
+}
+
+// RegisterCommand holds the data needed to register a user.
+type RegisterCommand struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// UserRegisteredPayload is the event payload for a registered user.
+// The password is only stored as a bcrypt hash.
+type UserRegisteredPayload struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+}
+
+// Validate checks that the command fields are valid.
+func (c RegisterCommand) Validate() error {
+	var errs []error
+
+	if !usernamePattern.MatchString(normalizeUsername(c.Username)) {
+		errs = append(errs, fmt.Errorf("username must be 3 to 32 letters, digits, '_', '.' or '-'"))
+	}
+	if len(c.Password) < minPasswordLength || len(c.Password) > maxPasswordLength {
+		errs = append(errs, fmt.Errorf("password must be %d to %d bytes", minPasswordLength, maxPasswordLength))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Register creates the event registering a user. The username is stored
+// lowercased. The event is recorded by the new user.
+// It performs no I/O, but hashing the password takes tens of milliseconds.
+func Register(cmd RegisterCommand) (eventstore.Event, error) {
+	if err := cmd.Validate(); err != nil {
+		return eventstore.Event{}, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(cmd.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("hash password: %w", err)
+	}
+	username := normalizeUsername(cmd.Username)
+	payload, err := json.Marshal(UserRegisteredPayload{Username: username, PasswordHash: string(hash)})
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	id := ID(username)
+	return eventstore.Event{
+		AggregateID:   id,
+		AggregateType: aggregateType,
+		Version:       1,
+		EventType:     eventTypeRegistered,
+		Payload:       payload,
+		Metadata:      eventstore.Metadata{UserID: id, SchemaVersion: schemaVersions[eventTypeRegistered]},
+		RecordedBy:    id,
+	}, nil
+}
+
+// User is the user aggregate, rebuilt from its event stream.
+type User struct {
+	ID           string
+	Username     string
+	passwordHash []byte
+	Version      int
+}
+
+// LoadUser rebuilds a user from its events.
+// Returns ErrNotFound if events is empty.
+func LoadUser(events []eventstore.Event) (*User, error) {
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+
+	u := &User{ID: events[0].AggregateID}
+	for _, event := range events {
+		if err := u.apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func (u *User) apply(event eventstore.Event) error {
+	if event.Version != u.Version+1 {
+		return fmt.Errorf("apply %s: version %d does not follow %d",
+			event.EventType, event.Version, u.Version)
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return err
+	}
+
+	switch event.EventType {
+	case eventTypeRegistered:
+		var payload UserRegisteredPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+		}
+		u.Username = payload.Username
+		u.passwordHash = []byte(payload.PasswordHash)
+	default:
+		return fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	u.Version = event.Version
+	return nil
+}
+
+// CheckPassword returns ErrInvalidCredentials unless password is the user's.
+func (u *User) CheckPassword(password string) error {
+	if err := bcrypt.CompareHashAndPassword(u.passwordHash, []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+func TestRegisterCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     RegisterCommand
+		wantErr string
+	}{
+		{"valid", RegisterCommand{Username: "Alice_01", Password: "correct horse"}, ""},
+		{"short username", RegisterCommand{Username: "al", Password: "correct horse"}, "username"},
+		{"username with spaces", RegisterCommand{Username: "al ice", Password: "correct horse"}, "username"},
+		{"short password", RegisterCommand{Username: "alice", Password: "short"}, "password"},
+		{"long password", RegisterCommand{Username: "alice", Password: strings.Repeat("p", 73)}, "password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	event, err := Register(RegisterCommand{Username: "Alice", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if event.AggregateID != ID("alice") || event.Version != 1 || event.RecordedBy != ID("alice") ||
+		event.Metadata.UserID != ID("alice") {
+		t.Errorf("event = %+v, want version 1 of alice recorded by alice", event)
+	}
+	if strings.Contains(string(event.Payload), "correct horse") {
+		t.Error("payload contains the plain password")
+	}
+
+	u, err := LoadUser([]eventstore.Event{event})
+	if err != nil {
+		t.Fatalf("LoadUser: %v", err)
+	}
+	if u.ID != ID("alice") || u.Username != "alice" || u.Version != 1 {
+		t.Errorf("user = %+v, want alice at version 1", u)
+	}
+	if err := u.CheckPassword("correct horse"); err != nil {
+		t.Errorf("CheckPassword(right) = %v, want nil", err)
+	}
+	if err := u.CheckPassword("wrong horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("CheckPassword(wrong) = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	if _, err := Register(RegisterCommand{Username: "alice", Password: "short"}); err == nil {
+		t.Error("Register with a short password succeeded")
+	}
+}
+
+func TestLoadUser_NotFound(t *testing.T) {
+	if _, err := LoadUser(nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LoadUser(nil) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestID(t *testing.T) {
+	if ID("Alice") != ID("alice") {
+		t.Error("ID depends on case")
+	}
+	if ID("alice") == ID("bob") {
+		t.Error("different usernames share an ID")
+	}
+	if len(ID("alice")) != 36 {
+		t.Errorf("len(ID) = %d, want 36 to fit events.aggregate_id", len(ID("alice")))
+	}
+}
@@ -0,0 +1,176 @@
+package xp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/achievement"
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+)
+
+// Awarder is the outbox subscriber that grants XP in reaction to expense,
+// budget and achievement events, to the user who recorded them.
+//
+// Budgets belong to a user, so XP is only earned in the user's personal
+// book: expenses recorded in a household book earn no same-day logging XP
+// and do not count against a user's budget.
+//
+// Each grant is appended at version 1 of its own aggregate, so a redelivered
+// event cannot grant the same XP twice. Handle fails until the expense
+// change log has caught up with the event, and the outbox delivers it again
+// later.
+type Awarder struct {
+	store    eventstore.Store
+	expenses ExpenseChanges
+	// loc is the time zone that decides which day an expense was recorded on.
+	loc *time.Location
+}
+
+// ExpenseChanges reads the expense changes in a book as they stood at an
+// event. score.ChangeLog implements it.
+type ExpenseChanges interface {
+	// ExpensesThrough returns the changes to the expenses in bookID up to
+	// and including the event at last, in log order.
+	ExpensesThrough(ctx context.Context, bookID string, last eventstore.Position) ([]expense.Change, error)
+}
+
+// NewAwarder creates an Awarder.
+func NewAwarder(store eventstore.Store, expenses ExpenseChanges) *Awarder {
+	return &Awarder{store: store, expenses: expenses, loc: time.Local}
+}
+
+// Name implements outbox.Subscriber.
+func (a *Awarder) Name() string {
+	return "xp"
+}
+
+// grant identifies one grant by its reason and subject.
+type grant struct {
+	reason, subject string
+}
+
+// Handle implements outbox.Subscriber.
+func (a *Awarder) Handle(ctx context.Context, event eventstore.Event) error {
+	var grants []grant
+	switch event.AggregateType {
+	case expense.AggregateType:
+		c, _, err := expense.DecodeChange(event)
+		if err != nil {
+			return err
+		}
+		if c.Recorded() && c.Book == c.RecordedBy && c.Date == c.OccurredAt.In(a.loc).Format(time.DateOnly) {
+			grants = append(grants, grant{ReasonSameDayLogging, c.ID})
+		}
+	case budget.Aggregate.Type:
+	case achievement.AggregateType:
+		u, _, err := achievement.DecodeUnlocked(event)
+		if err != nil {
+			return err
+		}
+		grants = append(grants, grant{ReasonAchievement, u.Achievement})
+	default:
+		return nil
+	}
+
+	// Expense and budget events both settle ended months: the first of them
+	// after a month ends grants the limits it kept.
+	if event.AggregateType != achievement.AggregateType {
+		months, err := a.monthsUnderBudget(ctx, event)
+		if err != nil {
+			return err
+		}
+		grants = append(grants, months...)
+	}
+
+	for _, g := range grants {
+		awarded, err := award(g.reason, g.subject, event)
+		if err != nil {
+			return err
+		}
+		err = a.store.Append(ctx, []eventstore.Event{awarded}, 0)
+		var conflict *eventstore.VersionConflictError
+		if err != nil && !errors.As(err, &conflict) {
+			return fmt.Errorf("award %s for %s: %w", g.reason, g.subject, err)
+		}
+	}
+	return nil
+}
+
+// monthsUnderBudget returns a grant for every category limit kept in a month
+// that had ended by the day trigger occurred, judging the budgets of the
+// user who recorded trigger against the expenses in their personal book, in
+// the log up to trigger. A user's ID is the ID of their personal book.
+// Months without spending earn nothing, so only their budgets are loaded.
+// Grants already awarded are left out.
+func (a *Awarder) monthsUnderBudget(ctx context.Context, trigger eventstore.Event) ([]grant, error) {
+	y, m, _ := trigger.OccurredAt.In(a.loc).Date()
+	thisMonth := fmt.Sprintf("%04d-%02d", y, m)
+
+	changes, err := a.expenses.ExpensesThrough(ctx, trigger.RecordedBy, trigger.Position())
+	if err != nil {
+		return nil, fmt.Errorf("read expenses: %w", err)
+	}
+	// Months are YYYY-MM, the first seven characters of an expense's date.
+	type state struct {
+		month, category string
+		amount          int64
+	}
+	expenses := make(map[string]state)
+	for _, c := range changes {
+		if c.Voided {
+			delete(expenses, c.ID)
+		} else {
+			expenses[c.ID] = state{month: c.Date[:7], category: c.Category, amount: c.Amount}
+		}
+	}
+	spent := make(map[string]map[string]int64)
+	for _, e := range expenses {
+		if spent[e.month] == nil {
+			spent[e.month] = make(map[string]int64)
+		}
+		spent[e.month][e.category] += e.amount
+	}
+
+	var grants []grant
+	for _, month := range slices.Sorted(maps.Keys(spent)) {
+		if month >= thisMonth {
+			continue
+		}
+		b, err := budget.LoadAt(ctx, a.store, trigger.RecordedBy, month, trigger.Position())
+		if err != nil {
+			return nil, err
+		}
+		if !b.Exists() {
+			continue
+		}
+		for _, category := range slices.Sorted(maps.Keys(b.Categories)) {
+			if spent[month][category] > b.Categories[category] {
+				continue
+			}
+			g := grant{ReasonCategoryUnderBudget, month + "/" + category}
+			awarded, err := a.awarded(ctx, trigger.RecordedBy, g)
+			if err != nil {
+				return nil, err
+			}
+			if !awarded {
+				grants = append(grants, g)
+			}
+		}
+	}
+	return grants, nil
+}
+
+// awarded reports whether userID has been granted g.
+func (a *Awarder) awarded(ctx context.Context, userID string, g grant) (bool, error) {
+	events, err := a.store.Load(ctx, aggregateType, grantID(userID, g.reason, g.subject))
+	if err != nil {
+		return false, fmt.Errorf("load grant %s for %s: %w", g.reason, g.subject, err)
+	}
+	return len(events) > 0, nil
+}
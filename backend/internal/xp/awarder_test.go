@@ -0,0 +1,163 @@
+package xp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/achievement"
+	"github.com/kikeda1102/kakei-board/backend/internal/budget"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/expense"
+	"github.com/kikeda1102/kakei-board/backend/internal/score"
+	"github.com/kikeda1102/kakei-board/backend/internal/xp"
+)
+
+// eventLog is an event store the test appends to, as alice, and reads back
+// from, with the IDs and times the store stamps. Expense changes are
+// projected into changes as they are appended.
+type eventLog struct {
+	t       *testing.T
+	store   *eventstore.MemoryStore
+	changes *score.MemoryRepository
+}
+
+func newLog(t *testing.T) *eventLog {
+	l := &eventLog{t: t, store: eventstore.NewMemoryStore(), changes: score.NewMemoryRepository()}
+	projector := score.NewProjector(l.changes)
+	l.store.OnAppend = func(events []eventstore.Event) error {
+		for _, event := range events {
+			if err := projector.Apply(context.Background(), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return l
+}
+
+func (l *eventLog) append(event eventstore.Event, err error) eventstore.Event {
+	l.t.Helper()
+	if err != nil {
+		l.t.Fatalf("create event: %v", err)
+	}
+	if event.RecordedBy == "" {
+		event.RecordedBy = "alice"
+	}
+	if err := l.store.Append(context.Background(), []eventstore.Event{event}, event.Version-1); err != nil {
+		l.t.Fatalf("append: %v", err)
+	}
+	events, _ := l.store.Load(context.Background(), event.AggregateType, event.AggregateID)
+	return events[len(events)-1]
+}
+
+func (l *eventLog) expense(cmd expense.RecordExpenseCommand) eventstore.Event {
+	return l.append(expense.RecordExpense(uuid.NewString(), cmd))
+}
+
+func (l *eventLog) budget(month string, categories map[string]int64) eventstore.Event {
+	return l.append(budget.Aggregate.New(budget.ID("alice", month)).Set(month, budget.SetBudgetCommand{Categories: categories}))
+}
+
+// grants returns the reason and subject of every grant made, in order.
+func (l *eventLog) grants() []xp.XPAwardedPayload {
+	l.t.Helper()
+	events, err := l.store.ReadAllByType(context.Background(), "xp", 0, 0)
+	if err != nil {
+		l.t.Fatalf("read grants: %v", err)
+	}
+	grants := make([]xp.XPAwardedPayload, len(events))
+	for i, e := range events {
+		if err := json.Unmarshal(e.Payload, &grants[i]); err != nil {
+			l.t.Fatalf("unmarshal: %v", err)
+		}
+	}
+	return grants
+}
+
+func handle(t *testing.T, a *xp.Awarder, events ...eventstore.Event) {
+	t.Helper()
+	for _, e := range events {
+		if err := a.Handle(context.Background(), e); err != nil {
+			t.Fatalf("handle %s: %v", e.EventType, err)
+		}
+	}
+}
+
+func TestAwarder_SameDayLogging(t *testing.T) {
+	l := newLog(t)
+	a := xp.NewAwarder(l.store, l.changes)
+
+	today := l.expense(expense.RecordExpenseCommand{Amount: 500, Category: "食費", Date: time.Now().Format(time.DateOnly)})
+	late := l.expense(expense.RecordExpenseCommand{Amount: 500, Category: "食費", Date: "2020-01-01"})
+	handle(t, a, today, late, today)
+
+	grants := l.grants()
+	if len(grants) != 1 || grants[0].Reason != xp.ReasonSameDayLogging ||
+		grants[0].Subject != today.AggregateID || grants[0].CausedBy != today.ID {
+		t.Errorf("grants = %+v, want one same-day grant for %s", grants, today.AggregateID)
+	}
+}
+
+func TestAwarder_HouseholdExpensesEarnNothing(t *testing.T) {
+	l := newLog(t)
+	a := xp.NewAwarder(l.store, l.changes)
+
+	l.budget("2020-01", map[string]int64{"食費": 1000})
+	l.expense(expense.RecordExpenseCommand{BookID: "home", Amount: 500, Category: "食費", Date: "2020-01-10"})
+	today := l.expense(expense.RecordExpenseCommand{BookID: "home", Amount: 500, Category: "食費", Date: time.Now().Format(time.DateOnly)})
+	handle(t, a, today)
+
+	if grants := l.grants(); len(grants) != 0 {
+		t.Errorf("grants = %+v, want none for household expenses", grants)
+	}
+}
+
+func TestAwarder_Achievement(t *testing.T) {
+	l := newLog(t)
+	recorded := l.expense(expense.RecordExpenseCommand{Amount: 500, Category: "食費", Date: "2020-01-01"})
+	if err := achievement.NewEngine(l.store, l.changes).Handle(context.Background(), recorded); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	unlocked, _ := l.store.ReadAllByType(context.Background(), achievement.AggregateType, 0, 0)
+
+	handle(t, xp.NewAwarder(l.store, l.changes), unlocked...)
+
+	grants := l.grants()
+	if len(grants) != 1 || grants[0].Reason != xp.ReasonAchievement || grants[0].Subject != "first-expense" {
+		t.Errorf("grants = %+v, want one for first-expense", grants)
+	}
+}
+
+func TestAwarder_CategoryUnderBudget(t *testing.T) {
+	l := newLog(t)
+	a := xp.NewAwarder(l.store, l.changes)
+
+	// Months long over, so the next event settles them.
+	l.budget("2020-01", map[string]int64{"食費": 1000, "交通費": 1000, "娯楽費": 1000})
+	l.expense(expense.RecordExpenseCommand{Amount: 1000, Category: "食費", Date: "2020-01-10"})
+	l.expense(expense.RecordExpenseCommand{Amount: 1500, Category: "交通費", Date: "2020-01-10"})
+	// Bob's spending does not count against alice's budget.
+	bobs, err := expense.RecordExpense(uuid.NewString(), expense.RecordExpenseCommand{Amount: 5000, Category: "娯楽費", Date: "2020-01-10"})
+	bobs.RecordedBy = "bob"
+	l.append(bobs, err)
+	l.budget("2020-02", map[string]int64{"食費": 1000})
+	trigger := l.budget("2020-03", map[string]int64{"食費": 1000})
+
+	handle(t, a, trigger, trigger)
+
+	got := make(map[string]bool)
+	for _, g := range l.grants() {
+		if g.Reason != xp.ReasonCategoryUnderBudget {
+			t.Errorf("unexpected grant %+v", g)
+		}
+		got[g.Subject] = true
+	}
+	// 交通費 went over, and nothing was spent in February.
+	want := map[string]bool{"2020-01/食費": true, "2020-01/娯楽費": true}
+	if len(got) != len(want) || !got["2020-01/食費"] || !got["2020-01/娯楽費"] {
+		t.Errorf("grants = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package xp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+const (
+	defaultRecent = 10
+	maxRecent     = 50
+)
+
+// Handler handles HTTP requests for XP and levels.
+// Grants are made asynchronously by Awarder through the outbox and priced by
+// Projector under a projection.Runner, so new XP can lag for a moment.
+//
+// Every route must be behind middleware.Authenticate.
+type Handler struct {
+	repo Reader
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(repo Reader) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Register adds XP routes to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /profile/progress", h.Progress)
+}
+
+type progressResponse struct {
+	XP    int64 `json:"xp"`
+	Level int   `json:"level"`
+	// LevelXP and NextLevelXP are the XP at which the current level and the
+	// next one begin.
+	LevelXP     int64   `json:"level_xp"`
+	NextLevelXP int64   `json:"next_level_xp"`
+	Recent      []Grant `json:"recent"`
+}
+
+// Progress handles GET /profile/progress?recent=N. It returns the request
+// user's XP, the level it reaches and the N most recent grants, 10 by
+// default.
+func (h *Handler) Progress(w http.ResponseWriter, r *http.Request) {
+	recent := defaultRecent
+	if s := r.URL.Query().Get("recent"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n > maxRecent {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("recent must be an integer between 0 and %d", maxRecent),
+			})
+			return
+		}
+		recent = n
+	}
+
+	ctx := r.Context()
+	userID := middleware.UserID(ctx)
+	total, err := h.repo.Total(ctx, userID)
+	if err != nil {
+		log.Printf("xp progress: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	grants, err := h.repo.Recent(ctx, userID, recent)
+	if err != nil {
+		log.Printf("xp progress: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+
+	level, floor, next := Level(total)
+	writeJSON(w, http.StatusOK, progressResponse{
+		XP:          total,
+		Level:       level,
+		LevelXP:     floor,
+		NextLevelXP: next,
+		Recent:      grants,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("write response: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package xp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/middleware"
+)
+
+func TestProgress(t *testing.T) {
+	repo := NewMemoryRepository()
+	p := NewProjector(repo)
+	for i, reason := range []string{ReasonAchievement, ReasonAchievement, ReasonSameDayLogging} {
+		if err := p.Apply(context.Background(), awardedEvent(t, uint64(i+1), reason, string(rune('a'+i)))); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	NewHandler(repo).Register(mux)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(middleware.WithUser(r.Context(), "alice")))
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/profile/progress?recent=1")
+	if err != nil {
+		t.Fatalf("GET /profile/progress: %v", err)
+	}
+	defer resp.Body.Close()
+	var got progressResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if got.XP != 210 || got.Level != 2 || got.LevelXP != 100 || got.NextLevelXP != 300 {
+		t.Errorf("progress = %d XP at level %d [%d, %d), want 210 XP at level 2 [100, 300)",
+			got.XP, got.Level, got.LevelXP, got.NextLevelXP)
+	}
+	if len(got.Recent) != 1 || got.Recent[0].Reason != ReasonSameDayLogging {
+		t.Errorf("recent = %+v, want the same-day grant", got.Recent)
+	}
+
+	for _, query := range []string{"recent=-1", "recent=51", "recent=x"} {
+		resp, err := http.Get(srv.URL + "/profile/progress?" + query)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package xp
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryRepository keeps grants in a map keyed by the XPAwarded event ID.
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	grants map[uint64]Grant
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{grants: make(map[uint64]Grant)}
+}
+
+// Total returns the sum of the points of every grant to userID.
+func (r *MemoryRepository) Total(_ context.Context, userID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, g := range r.grants {
+		if g.UserID == userID {
+			total += g.Points
+		}
+	}
+	return total, nil
+}
+
+// Recent returns up to limit grants to userID, newest first.
+func (r *MemoryRepository) Recent(_ context.Context, userID string, limit int) ([]Grant, error) {
+	r.mu.RLock()
+	grants := make([]Grant, 0, len(r.grants))
+	for _, g := range r.grants {
+		if g.UserID == userID {
+			grants = append(grants, g)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(grants, func(a, b Grant) int {
+		return cmp.Compare(b.EventID, a.EventID)
+	})
+	return grants[:min(limit, len(grants))], nil
+}
+
+// SaveGrant stores g unless a grant with its EventID is stored.
+func (r *MemoryRepository) SaveGrant(_ context.Context, g Grant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.grants[g.EventID]; !ok {
+		r.grants[g.EventID] = g
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package xp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/projection"
+)
+
+const (
+	// projectionName identifies the xp read model's checkpoint.
+	projectionName = "xp"
+	// grantsTable holds every grant with the points it is worth.
+	grantsTable = "xp_grants"
+)
+
+// Projector prices XPAwarded events with the points table and applies them
+// to the xp read model.
+type Projector struct {
+	w Writer
+}
+
+// NewProjector creates a new Projector.
+func NewProjector(w Writer) *Projector {
+	return &Projector{w: w}
+}
+
+// ProjectionDefinition describes the MySQL xp projection for rebuilds, which
+// re-price every grant after the points table changes.
+func ProjectionDefinition(db *sql.DB) projection.Definition {
+	return projection.Definition{
+		Name:   projectionName,
+		Tables: []string{grantsTable},
+		New: func(tables map[string]string) projection.Projection {
+			return NewProjector(&Repository{db: db, grants: tables[grantsTable]})
+		},
+	}
+}
+
+// Name returns the projection's checkpoint name.
+func (p *Projector) Name() string {
+	return projectionName
+}
+
+// Apply stores the grant an XPAwarded event makes. Events of other aggregate
+// types are ignored, as are grants for reasons no longer in the points table.
+func (p *Projector) Apply(ctx context.Context, event eventstore.Event) error {
+	payload, ok, err := decodeAwarded(event)
+	if err != nil || !ok {
+		return err
+	}
+	pts, ok := points[payload.Reason]
+	if !ok {
+		return nil
+	}
+
+	if err := p.w.SaveGrant(ctx, Grant{
+		EventID:   event.ID,
+		UserID:    event.RecordedBy,
+		Reason:    payload.Reason,
+		Subject:   payload.Subject,
+		CausedBy:  payload.CausedBy,
+		Points:    pts,
+		AwardedAt: event.OccurredAt,
+	}); err != nil {
+		return fmt.Errorf("apply %s to %s: %w", event.EventType, event.AggregateID, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package xp
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+	"github.com/kikeda1102/kakei-board/backend/internal/testhelper"
+)
+
+var backends = testhelper.Backends(
+	func() ReadModel { return NewMemoryRepository() },
+	func(db *sql.DB) ReadModel { return NewRepository(db) },
+)
+
+// awardedEvent returns an XPAwarded event granted to alice as read back from
+// the store at position id.
+func awardedEvent(t *testing.T, id uint64, reason, subject string) eventstore.Event {
+	t.Helper()
+	event, err := award(reason, subject, eventstore.Event{ID: id - 1, RecordedBy: "alice"})
+	if err != nil {
+		t.Fatalf("award: %v", err)
+	}
+	event.ID = id
+	event.OccurredAt = time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC).Add(time.Duration(id) * time.Minute)
+	return event
+}
+
+func TestProjector(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := b.Open(t)
+			p := NewProjector(repo)
+
+			events := []eventstore.Event{
+				awardedEvent(t, 2, ReasonSameDayLogging, "e1"),
+				awardedEvent(t, 4, ReasonAchievement, "first-expense"),
+				awardedEvent(t, 6, "retired-reason", "x"),
+				awardedEvent(t, 8, ReasonCategoryUnderBudget, "2026-01/食費"),
+			}
+			bobs := awardedEvent(t, 10, ReasonAchievement, "first-expense")
+			bobs.RecordedBy = "bob"
+			events = append(events, bobs)
+			// Replaying must not count a grant twice.
+			for _, e := range append(events, events...) {
+				if err := p.Apply(ctx, e); err != nil {
+					t.Fatalf("apply event %d: %v", e.ID, err)
+				}
+			}
+
+			total, err := repo.Total(ctx, "alice")
+			if err != nil {
+				t.Fatalf("total: %v", err)
+			}
+			if want := points[ReasonSameDayLogging] + points[ReasonAchievement] + points[ReasonCategoryUnderBudget]; total != want {
+				t.Errorf("total = %d, want %d", total, want)
+			}
+
+			recent, err := repo.Recent(ctx, "alice", 2)
+			if err != nil {
+				t.Fatalf("recent: %v", err)
+			}
+			if len(recent) != 2 || recent[0].EventID != 8 || recent[1].EventID != 4 {
+				t.Fatalf("recent = %+v, want events 8 and 4", recent)
+			}
+			got := recent[0]
+			if got.UserID != "alice" || got.Reason != ReasonCategoryUnderBudget || got.Subject != "2026-01/食費" || got.CausedBy != 7 ||
+				got.Points != points[ReasonCategoryUnderBudget] || !got.AwardedAt.Equal(events[3].OccurredAt) {
+				t.Errorf("recent[0] = %+v", got)
+			}
+		})
+	}
+}
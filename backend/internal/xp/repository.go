@@ -0,0 +1,104 @@
+package xp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Grant is an XP grant as priced by the read model.
+type Grant struct {
+	// EventID is the position of the XPAwarded event.
+	EventID uint64 `json:"event_id"`
+	// UserID is the user the XP was granted to.
+	UserID   string `json:"-"`
+	Reason   string `json:"reason"`
+	Subject  string `json:"subject"`
+	CausedBy uint64 `json:"caused_by"`
+	Points   int64  `json:"points"`
+	// AwardedAt is when the grant was recorded.
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+// Reader is the query side of the xp read model.
+type Reader interface {
+	// Total returns the sum of the points of every grant to userID.
+	Total(ctx context.Context, userID string) (int64, error)
+	// Recent returns up to limit grants to userID, newest first.
+	Recent(ctx context.Context, userID string, limit int) ([]Grant, error)
+}
+
+// Writer is the projection side of the xp read model.
+// Every method must be idempotent, because events can be replayed.
+type Writer interface {
+	// SaveGrant stores g unless a grant with its EventID is stored.
+	SaveGrant(ctx context.Context, g Grant) error
+}
+
+// ReadModel combines both sides of the xp read model.
+type ReadModel interface {
+	Reader
+	Writer
+}
+
+// Repository implements ReadModel on top of MySQL.
+type Repository struct {
+	db     *sql.DB
+	grants string
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db, grants: grantsTable}
+}
+
+// Total returns the sum of the points of every grant to userID.
+func (r *Repository) Total(ctx context.Context, userID string) (int64, error) {
+	var total int64
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT COALESCE(SUM(points), 0) FROM %s WHERE user_id = ?`, r.grants), userID,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum xp: %w", err)
+	}
+	return total, nil
+}
+
+// Recent returns up to limit grants to userID, newest first.
+func (r *Repository) Recent(ctx context.Context, userID string, limit int) ([]Grant, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT event_id, user_id, reason, subject, caused_by, points, awarded_at
+		 FROM %s WHERE user_id = ? ORDER BY event_id DESC LIMIT ?`, r.grants), userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query grants: %w", err)
+	}
+	defer rows.Close()
+
+	grants := []Grant{}
+	for rows.Next() {
+		var g Grant
+		if err := rows.Scan(&g.EventID, &g.UserID, &g.Reason, &g.Subject, &g.CausedBy, &g.Points, &g.AwardedAt); err != nil {
+			return nil, fmt.Errorf("scan grant: %w", err)
+		}
+		grants = append(grants, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate grants: %w", err)
+	}
+	return grants, nil
+}
+
+// SaveGrant stores g unless a grant with its EventID is stored.
+func (r *Repository) SaveGrant(ctx context.Context, g Grant) error {
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (event_id, user_id, reason, subject, caused_by, points, awarded_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON DUPLICATE KEY UPDATE event_id = event_id`, r.grants),
+		g.EventID, g.UserID, g.Reason, g.Subject, g.CausedBy, g.Points, g.AwardedAt,
+	); err != nil {
+		return fmt.Errorf("save grant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package xp grants experience points for good habits and levels users up
+// with them.
+//
+// Grants are recorded as XPAwarded events naming their reason, but not their
+// points: the read model prices each grant with the points table when it
+// applies it, so re-tuning the table and rebuilding the projection re-scores
+// the whole ledger.
+package xp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/kikeda1102/kakei-board/backend/internal/eventstore"
+)
+
+const aggregateType = "xp"
+
+const eventTypeAwarded = "XPAwarded"
+
+// schemaVersions holds the payload schema version of each XP event.
+var schemaVersions = map[string]int{
+	eventTypeAwarded: 1,
+}
+
+// Reasons XP is granted for.
+const (
+	// ReasonSameDayLogging is granted for an expense recorded on the day it
+	// was spent. Its subject is the expense ID.
+	ReasonSameDayLogging = "same-day-logging"
+	// ReasonCategoryUnderBudget is granted for a category that ended its
+	// month within its budgeted limit. Its subject is "YYYY-MM/category".
+	ReasonCategoryUnderBudget = "category-under-budget"
+	// ReasonAchievement is granted for an unlocked achievement. Its subject
+	// is the achievement's key.
+	ReasonAchievement = "achievement"
+)
+
+// points prices each reason. Changes apply to past grants once the xp
+// projection is rebuilt.
+var points = map[string]int64{
+	ReasonSameDayLogging:      10,
+	ReasonCategoryUnderBudget: 50,
+	ReasonAchievement:         100,
+}
+
+// levelStep is the XP between levels 1 and 2; each level after that takes
+// levelStep more than the one before it.
+const levelStep = 100
+
+// Level returns the level xp reaches, starting at 1, and the XP at which
+// that level and the next one begin.
+func Level(xp int64) (level int, floor, next int64) {
+	level = 1
+	for next = levelStep; xp >= next; next += int64(level) * levelStep {
+		level++
+		floor = next
+	}
+	return level, floor, next
+}
+
+// idNamespace derives grant IDs from their user, reason and subject. Each
+// grant is an aggregate awarded at most once, at version 1.
+var idNamespace = uuid.MustParse("d3a1e6b2-7c49-4f0a-8e15-9b2c4f6a1d87")
+
+// grantID returns the aggregate ID of userID's grant for reason and subject.
+func grantID(userID, reason, subject string) string {
+	return uuid.NewSHA1(idNamespace, []byte(userID+"\x00"+reason+"\x00"+subject)).String()
+}
+
+// XPAwardedPayload is the event payload for a grant.
+type XPAwardedPayload struct {
+	Reason  string `json:"reason"`
+	Subject string `json:"subject"`
+	// CausedBy is the position of the event the grant was made for.
+	CausedBy uint64 `json:"caused_by"`
+}
+
+// award creates the XPAwarded event for reason and subject in reaction to
+// trigger, whose position it records as the cause. The XP goes to the user
+// who recorded trigger.
+func award(reason, subject string, trigger eventstore.Event) (eventstore.Event, error) {
+	data, err := json.Marshal(XPAwardedPayload{Reason: reason, Subject: subject, CausedBy: trigger.ID})
+	if err != nil {
+		return eventstore.Event{}, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	return eventstore.Event{
+		AggregateID:   grantID(trigger.RecordedBy, reason, subject),
+		AggregateType: aggregateType,
+		Version:       1,
+		EventType:     eventTypeAwarded,
+		Payload:       data,
+		RecordedBy:    trigger.RecordedBy,
+		Metadata: eventstore.Metadata{
+			CorrelationID: trigger.Metadata.CorrelationID,
+			CausationID:   strconv.FormatUint(trigger.ID, 10),
+			UserID:        trigger.RecordedBy,
+			SchemaVersion: schemaVersions[eventTypeAwarded],
+		},
+	}, nil
+}
+
+// decodeAwarded decodes an XPAwarded event. ok is false for events of other
+// aggregate types.
+func decodeAwarded(event eventstore.Event) (payload XPAwardedPayload, ok bool, err error) {
+	if event.AggregateType != aggregateType {
+		return XPAwardedPayload{}, false, nil
+	}
+	if err := eventstore.CheckSchemaVersion(event, schemaVersions); err != nil {
+		return XPAwardedPayload{}, false, err
+	}
+	if event.EventType != eventTypeAwarded {
+		return XPAwardedPayload{}, false, fmt.Errorf("unknown event type: %s", event.EventType)
+	}
+
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return XPAwardedPayload{}, false, fmt.Errorf("unmarshal %s payload: %w", event.EventType, err)
+	}
+	return payload, true, nil
+}
@@ -0,0 +1,37 @@
+package xp
+
+import "testing"
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		xp          int64
+		level       int
+		floor, next int64
+	}{
+		{0, 1, 0, 100},
+		{99, 1, 0, 100},
+		{100, 2, 100, 300},
+		{299, 2, 100, 300},
+		{300, 3, 300, 600},
+		{1000, 5, 1000, 1500},
+	}
+	for _, tt := range tests {
+		level, floor, next := Level(tt.xp)
+		if level != tt.level || floor != tt.floor || next != tt.next {
+			t.Errorf("Level(%d) = %d [%d, %d), want %d [%d, %d)",
+				tt.xp, level, floor, next, tt.level, tt.floor, tt.next)
+		}
+	}
+}
+
+func TestGrantID(t *testing.T) {
+	if grantID("alice", ReasonAchievement, "a") != grantID("alice", ReasonAchievement, "a") {
+		t.Error("grantID is not deterministic")
+	}
+	if grantID("alice", ReasonAchievement, "a") == grantID("alice", ReasonSameDayLogging, "a") {
+		t.Error("reasons with the same subject share an ID")
+	}
+	if grantID("alice", ReasonAchievement, "a") == grantID("bob", ReasonAchievement, "a") {
+		t.Error("users share an ID")
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE expenses
+    ADD COLUMN version   INT UNSIGNED NOT NULL DEFAULT 1,
+    ADD COLUMN voided_at DATETIME(6)  NULL;
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS event_append_lock (
+    id TINYINT UNSIGNED NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE events ADD INDEX idx_type_position (aggregate_type, id);
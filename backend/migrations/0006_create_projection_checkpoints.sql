@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    name       VARCHAR(64)     NOT NULL,
+    position   BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    updated_at DATETIME(6)     NOT NULL DEFAULT (UTC_TIMESTAMP(6)),
+    PRIMARY KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
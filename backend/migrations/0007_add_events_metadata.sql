@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN metadata JSON NULL;
@@ -0,0 +1 @@
+ALTER TABLE expenses ADD COLUMN tags JSON NULL;
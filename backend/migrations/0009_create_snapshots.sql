@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+    aggregate_type VARCHAR(64)  NOT NULL,
+    aggregate_id   VARCHAR(36)  NOT NULL,
+    version        INT UNSIGNED NOT NULL,
+    state_version  INT UNSIGNED NOT NULL,
+    state          JSON         NOT NULL,
+    taken_at       DATETIME(6)  NOT NULL,
+    PRIMARY KEY (aggregate_type, aggregate_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    event_id     BIGINT UNSIGNED NOT NULL,
+    subscriber   VARCHAR(64)     NOT NULL,
+    attempts     INT UNSIGNED    NOT NULL DEFAULT 0,
+    available_at DATETIME(6)     NOT NULL DEFAULT (UTC_TIMESTAMP(6)),
+    claim_token  VARCHAR(36)     NULL,
+    last_error   TEXT            NULL,
+    dead_at      DATETIME(6)     NULL,
+    created_at   DATETIME(6)     NOT NULL DEFAULT (UTC_TIMESTAMP(6)),
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_outbox_event_subscriber (event_id, subscriber),
+    INDEX idx_outbox_available (dead_at, available_at),
+    INDEX idx_outbox_claim (claim_token)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idem_key      VARCHAR(255)      NOT NULL,
+    request_hash  CHAR(64)          NOT NULL,
+    status        SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    content_type  VARCHAR(255)      NOT NULL DEFAULT '',
+    response_body MEDIUMBLOB        NULL,
+    created_at    DATETIME(6)       NOT NULL,
+    expires_at    DATETIME(6)       NOT NULL,
+    PRIMARY KEY (idem_key),
+    INDEX idx_idempotency_keys_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
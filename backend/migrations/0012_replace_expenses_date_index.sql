@@ -0,0 +1,3 @@
+ALTER TABLE expenses
+    DROP INDEX idx_expenses_date,
+    ADD INDEX idx_expenses_listing (voided_at, date, created_at, id);
@@ -0,0 +1,7 @@
+CREATE TABLE summary_daily_totals (
+    date          DATE        NOT NULL,
+    category      VARCHAR(64) NOT NULL,
+    total         BIGINT      NOT NULL,
+    expense_count INT         NOT NULL,
+    PRIMARY KEY (date, category)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
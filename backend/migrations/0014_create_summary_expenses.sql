@@ -0,0 +1,9 @@
+CREATE TABLE summary_expenses (
+    expense_id VARCHAR(36)  NOT NULL,
+    date       DATE         NOT NULL,
+    category   VARCHAR(64)  NOT NULL,
+    amount     BIGINT       NOT NULL,
+    version    INT UNSIGNED NOT NULL,
+    voided     BOOLEAN      NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (expense_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
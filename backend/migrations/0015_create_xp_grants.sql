@@ -0,0 +1,9 @@
+CREATE TABLE xp_grants (
+    event_id   BIGINT UNSIGNED NOT NULL,
+    reason     VARCHAR(64)     NOT NULL,
+    subject    VARCHAR(128)    NOT NULL,
+    caused_by  BIGINT UNSIGNED NOT NULL,
+    points     BIGINT          NOT NULL,
+    awarded_at DATETIME(6)     NOT NULL,
+    PRIMARY KEY (event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
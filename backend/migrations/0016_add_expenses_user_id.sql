@@ -0,0 +1,4 @@
+ALTER TABLE expenses
+    ADD COLUMN user_id VARCHAR(36) NOT NULL DEFAULT 'anonymous' AFTER id,
+    DROP INDEX idx_expenses_listing,
+    ADD INDEX idx_expenses_listing (user_id, voided_at, date, created_at, id);
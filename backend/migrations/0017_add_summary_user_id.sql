@@ -0,0 +1,4 @@
+ALTER TABLE summary_daily_totals
+    ADD COLUMN user_id VARCHAR(36) NOT NULL DEFAULT 'anonymous' FIRST,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (user_id, date, category);
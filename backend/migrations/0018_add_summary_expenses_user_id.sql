@@ -0,0 +1 @@
+ALTER TABLE summary_expenses ADD COLUMN user_id VARCHAR(36) NOT NULL DEFAULT 'anonymous' AFTER expense_id;
@@ -0,0 +1,3 @@
+ALTER TABLE xp_grants
+    ADD COLUMN user_id VARCHAR(36) NOT NULL DEFAULT 'anonymous' AFTER event_id,
+    ADD INDEX idx_xp_grants_user (user_id, event_id);
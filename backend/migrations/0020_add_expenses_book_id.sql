@@ -0,0 +1,4 @@
+ALTER TABLE expenses
+    ADD COLUMN book_id VARCHAR(36) NOT NULL DEFAULT '' AFTER user_id,
+    DROP INDEX idx_expenses_listing,
+    ADD INDEX idx_expenses_listing (book_id, voided_at, date, created_at, id);
@@ -0,0 +1 @@
+UPDATE expenses SET book_id = user_id WHERE book_id = '';
@@ -0,0 +1 @@
+ALTER TABLE summary_daily_totals RENAME COLUMN user_id TO book_id;
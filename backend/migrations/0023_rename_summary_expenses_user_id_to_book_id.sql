@@ -0,0 +1 @@
+ALTER TABLE summary_expenses RENAME COLUMN user_id TO book_id;
@@ -0,0 +1,6 @@
+CREATE TABLE settlement_expenses (
+    expense_id VARCHAR(36)  NOT NULL,
+    book_id    VARCHAR(36)  NOT NULL,
+    version    INT UNSIGNED NOT NULL,
+    PRIMARY KEY (expense_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
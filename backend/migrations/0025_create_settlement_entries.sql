@@ -0,0 +1,9 @@
+CREATE TABLE settlement_entries (
+    source  VARCHAR(100) NOT NULL,
+    user_id VARCHAR(36)  NOT NULL,
+    book_id VARCHAR(36)  NOT NULL,
+    date    DATE         NOT NULL,
+    amount  BIGINT       NOT NULL,
+    PRIMARY KEY (source, user_id),
+    INDEX idx_settlement_entries_book_date (book_id, date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
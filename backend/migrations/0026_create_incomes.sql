@@ -0,0 +1,14 @@
+CREATE TABLE incomes (
+    id         VARCHAR(36)  NOT NULL,
+    user_id    VARCHAR(36)  NOT NULL,
+    book_id    VARCHAR(36)  NOT NULL,
+    amount     BIGINT       NOT NULL,
+    source     VARCHAR(16)  NOT NULL,
+    memo       VARCHAR(512) NOT NULL DEFAULT '',
+    date       DATE         NOT NULL,
+    version    INT UNSIGNED NOT NULL,
+    created_at DATETIME(6)  NOT NULL,
+    voided_at  DATETIME(6)  NULL,
+    PRIMARY KEY (id),
+    INDEX idx_incomes_listing (book_id, voided_at, date, created_at, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
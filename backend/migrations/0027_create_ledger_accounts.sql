@@ -0,0 +1,11 @@
+CREATE TABLE ledger_accounts (
+    id              VARCHAR(36) NOT NULL,
+    book_id         VARCHAR(36) NOT NULL,
+    name            VARCHAR(64) NOT NULL,
+    type            VARCHAR(16) NOT NULL,
+    currency        CHAR(3)     NOT NULL,
+    opening_balance BIGINT      NOT NULL,
+    opened_on       DATE        NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_ledger_accounts_book (book_id, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
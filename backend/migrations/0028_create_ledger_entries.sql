@@ -0,0 +1,11 @@
+CREATE TABLE ledger_entries (
+    kind       VARCHAR(16)  NOT NULL,
+    ref_id     VARCHAR(36)  NOT NULL,
+    account_id VARCHAR(36)  NOT NULL,
+    date       DATE         NOT NULL,
+    label      VARCHAR(64)  NOT NULL DEFAULT '',
+    memo       VARCHAR(512) NOT NULL DEFAULT '',
+    amount     BIGINT       NOT NULL,
+    PRIMARY KEY (kind, ref_id, account_id),
+    INDEX idx_ledger_entries_account_date (account_id, date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+CREATE TABLE ledger_changes (
+    kind    VARCHAR(16)  NOT NULL,
+    ref_id  VARCHAR(36)  NOT NULL,
+    version INT UNSIGNED NOT NULL,
+    PRIMARY KEY (kind, ref_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -0,0 +1,7 @@
+CREATE TABLE ledger_reconciliations (
+    account_id VARCHAR(36)  NOT NULL,
+    version    INT UNSIGNED NOT NULL,
+    date       DATE         NOT NULL,
+    actual     BIGINT       NOT NULL,
+    PRIMARY KEY (account_id, version)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
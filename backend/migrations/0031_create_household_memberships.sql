@@ -0,0 +1,7 @@
+CREATE TABLE household_memberships (
+    household_id VARCHAR(36)     NOT NULL,
+    user_id      VARCHAR(36)     NOT NULL,
+    event_id     BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (household_id, user_id),
+    INDEX idx_household_memberships_user (user_id, event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
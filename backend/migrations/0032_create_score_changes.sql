@@ -0,0 +1,13 @@
+CREATE TABLE score_changes (
+    kind        VARCHAR(16)     NOT NULL,
+    ref_id      VARCHAR(36)     NOT NULL,
+    version     INT UNSIGNED    NOT NULL,
+    book_id     VARCHAR(36)     NOT NULL,
+    event_id    BIGINT UNSIGNED NOT NULL,
+    voided      BOOLEAN         NOT NULL DEFAULT FALSE,
+    amount      BIGINT          NOT NULL DEFAULT 0,
+    date        DATE            NULL,
+    occurred_at DATETIME(6)     NOT NULL,
+    PRIMARY KEY (kind, ref_id, version),
+    INDEX idx_score_changes_book (book_id, event_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
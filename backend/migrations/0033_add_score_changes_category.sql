@@ -0,0 +1 @@
+ALTER TABLE score_changes ADD COLUMN category VARCHAR(64) NOT NULL DEFAULT '' AFTER amount;
@@ -0,0 +1,161 @@
+import { type FormEvent, useState } from "react";
+import styled from "styled-components";
+import { useOpenAccountMutation } from "./accountApi";
+import type { AccountType } from "./types";
+
+export const TYPE_LABELS: Record<AccountType, string> = {
+  cash: "現金",
+  bank: "銀行口座",
+  debit: "デビットカード",
+  credit: "クレジットカード",
+  emoney: "電子マネー",
+};
+
+const TODAY = new Date().toISOString().slice(0, 10);
+
+const Form = styled.form`
+  display: flex;
+  flex-wrap: wrap;
+  gap: 0.75rem;
+  align-items: flex-end;
+  margin-bottom: 1.5rem;
+`;
+
+const Field = styled.label`
+  display: flex;
+  flex-direction: column;
+  gap: 0.25rem;
+  font-size: 0.85rem;
+`;
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Select = styled.select`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const SubmitButton = styled.button`
+  padding: 0.5rem 1.5rem;
+  background: #0f3460;
+  color: #fff;
+  border: none;
+  border-radius: 4px;
+  cursor: pointer;
+  font-size: 0.95rem;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: not-allowed;
+  }
+`;
+
+const ErrorMessage = styled.p`
+  color: #ff6b6b;
+  font-size: 0.85rem;
+  width: 100%;
+  margin: 0;
+`;
+
+interface Props {
+  // bookId is the household to open the account in; the personal book if
+  // omitted.
+  bookId?: string;
+}
+
+export default function AccountForm({ bookId }: Props) {
+  const [name, setName] = useState("");
+  const [type, setType] = useState<AccountType>("bank");
+  const [openingBalance, setOpeningBalance] = useState("0");
+  const [date, setDate] = useState(TODAY);
+
+  const [openAccount, { isLoading, error }] = useOpenAccountMutation();
+
+  const handleSubmit = async (e: FormEvent) => {
+    e.preventDefault();
+
+    // Credit cards open with a negative balance when they carry debt.
+    const parsed = Number(openingBalance);
+    if (!Number.isInteger(parsed)) return;
+
+    await openAccount({
+      book_id: bookId,
+      name: name.trim(),
+      type,
+      opening_balance: parsed,
+      date,
+    }).unwrap();
+
+    setName("");
+    setOpeningBalance("0");
+  };
+
+  return (
+    <Form onSubmit={handleSubmit}>
+      <Field>
+        口座名
+        <Input
+          type="text"
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+          required
+          maxLength={64}
+          placeholder="給与口座"
+        />
+      </Field>
+
+      <Field>
+        種類
+        <Select
+          aria-label="種類"
+          value={type}
+          onChange={(e) => setType(e.target.value as AccountType)}
+        >
+          {Object.entries(TYPE_LABELS).map(([value, label]) => (
+            <option key={value} value={value}>
+              {label}
+            </option>
+          ))}
+        </Select>
+      </Field>
+
+      <Field>
+        開始残高
+        <Input
+          type="number"
+          step="1"
+          value={openingBalance}
+          onChange={(e) => setOpeningBalance(e.target.value)}
+          required
+        />
+      </Field>
+
+      <Field>
+        開始日
+        <Input
+          type="date"
+          value={date}
+          onChange={(e) => setDate(e.target.value)}
+          required
+        />
+      </Field>
+
+      <SubmitButton type="submit" disabled={isLoading}>
+        {isLoading ? "作成中..." : "口座を追加"}
+      </SubmitButton>
+
+      {error && <ErrorMessage>口座の追加に失敗しました</ErrorMessage>}
+    </Form>
+  );
+}
@@ -0,0 +1,109 @@
+import { useState } from "react";
+import styled from "styled-components";
+import AccountForm, { TYPE_LABELS } from "./AccountForm";
+import LedgerPanel from "./LedgerPanel";
+import TransferForm from "./TransferForm";
+import { useListAccountsQuery } from "./accountApi";
+import BookSelect from "../household/BookSelect";
+import { useListHouseholdsQuery } from "../household/householdApi";
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const Button = styled.button`
+  padding: 0.25rem 0.75rem;
+  border: none;
+  border-radius: 4px;
+  background: #646cff;
+  color: #fff;
+  font-size: 0.85rem;
+  cursor: pointer;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+const yen = (amount: number) => `¥${amount.toLocaleString()}`;
+
+export default function AccountPage() {
+  const [book, setBook] = useState("");
+  const [selected, setSelected] = useState<string | null>(null);
+  const { data: households } = useListHouseholdsQuery();
+  const { data: accounts, isLoading, error } = useListAccountsQuery(
+    book || undefined,
+  );
+  const canWrite =
+    households?.find((h) => h.id === book)?.role !== "viewer";
+
+  const changeBook = (b: string) => {
+    setBook(b);
+    setSelected(null);
+  };
+
+  return (
+    <>
+      <h2>口座</h2>
+      <BookSelect value={book} onChange={changeBook} />
+      {canWrite && <AccountForm bookId={book || undefined} />}
+      {canWrite && accounts && accounts.length > 1 && (
+        <TransferForm bookId={book || undefined} />
+      )}
+
+      {isLoading && <Message>読み込み中...</Message>}
+      {error && <Message>口座の取得に失敗しました</Message>}
+      {accounts && accounts.length === 0 && (
+        <Message>口座はまだありません</Message>
+      )}
+      {accounts && accounts.length > 0 && (
+        <Table>
+          <thead>
+            <tr>
+              <Th>名前</Th>
+              <Th>種類</Th>
+              <Th>残高</Th>
+              <Th>最終照合</Th>
+              <Th>入出金</Th>
+            </tr>
+          </thead>
+          <tbody>
+            {accounts.map((a) => (
+              <tr key={a.id}>
+                <Td>{a.name}</Td>
+                <Td>{TYPE_LABELS[a.type]}</Td>
+                <Td>{yen(a.balance)}</Td>
+                <Td>
+                  {a.reconciliation
+                    ? `${a.reconciliation.date}（差額 ${yen(a.reconciliation.difference)}）`
+                    : "未照合"}
+                </Td>
+                <Td>
+                  <Button type="button" onClick={() => setSelected(a.id)}>
+                    明細
+                  </Button>
+                </Td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {selected && <LedgerPanel accountId={selected} canWrite={canWrite} />}
+    </>
+  );
+}
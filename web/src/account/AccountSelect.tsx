@@ -0,0 +1,55 @@
+import styled from "styled-components";
+import { useListAccountsQuery } from "./accountApi";
+
+const Select = styled.select`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+interface Props {
+  // bookId is the book whose accounts are offered; the personal book if
+  // omitted.
+  bookId?: string;
+  // value is an account ID, or "" for none.
+  value: string;
+  onChange: (account: string) => void;
+  // label names the select; 口座 if omitted.
+  label?: string;
+  // allowNone offers "指定なし" as the first option.
+  allowNone?: boolean;
+}
+
+export default function AccountSelect({
+  bookId,
+  value,
+  onChange,
+  label = "口座",
+  allowNone = true,
+}: Props) {
+  const { data: accounts } = useListAccountsQuery(bookId);
+
+  return (
+    <Select
+      aria-label={label}
+      value={value}
+      onChange={(e) => onChange(e.target.value)}
+    >
+      {allowNone ? (
+        <option value="">指定なし</option>
+      ) : (
+        <option value="" disabled>
+          選択してください
+        </option>
+      )}
+      {accounts?.map((a) => (
+        <option key={a.id} value={a.id}>
+          {a.name}
+        </option>
+      ))}
+    </Select>
+  );
+}
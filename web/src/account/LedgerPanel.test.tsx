@@ -0,0 +1,84 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import LedgerPanel from "./LedgerPanel";
+import "@testing-library/jest-dom";
+import * as accountApi from "./accountApi";
+
+jest.mock("./accountApi");
+
+const mockUseGetLedgerQuery =
+  accountApi.useGetLedgerQuery as jest.MockedFunction<
+    typeof accountApi.useGetLedgerQuery
+  >;
+const mockUseReconcileAccountMutation =
+  accountApi.useReconcileAccountMutation as jest.MockedFunction<
+    typeof accountApi.useReconcileAccountMutation
+  >;
+
+let reconcile: jest.Mock;
+
+beforeEach(() => {
+  mockUseGetLedgerQuery.mockReturnValue({
+    data: {
+      account: {
+        id: "acc-1",
+        book_id: "alice",
+        name: "財布",
+        type: "cash",
+        currency: "JPY",
+        opening_balance: 5000,
+        opened_on: "2026-03-01",
+      },
+      balance: 3500,
+      entries: [
+        {
+          date: "2026-03-02",
+          kind: "expense",
+          ref_id: "exp-1",
+          label: "食費",
+          memo: "ランチ",
+          amount: -1500,
+          balance: 3500,
+        },
+      ],
+      reconciliations: [
+        { date: "2026-03-02", actual: 3400, expected: 3500, difference: -100 },
+      ],
+    },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof accountApi.useGetLedgerQuery>);
+  reconcile = jest.fn().mockReturnValue({
+    unwrap: () => Promise.resolve({ id: "acc-1", version: 3 }),
+  });
+  mockUseReconcileAccountMutation.mockReturnValue([
+    reconcile,
+    { isLoading: false },
+  ] as unknown as ReturnType<typeof accountApi.useReconcileAccountMutation>);
+});
+
+test("shows entries with the running balance and the latest difference", () => {
+  render(<LedgerPanel accountId="acc-1" canWrite />);
+
+  expect(screen.getByText("財布: ¥3,500")).toBeInTheDocument();
+  expect(screen.getByText("¥-1,500")).toBeInTheDocument();
+  expect(screen.getByText(/差額 ¥-100/)).toBeInTheDocument();
+});
+
+test("records a reconciliation", async () => {
+  const user = userEvent.setup();
+  render(<LedgerPanel accountId="acc-1" canWrite />);
+
+  await user.type(screen.getByLabelText("実残高"), "3400");
+  await user.click(screen.getByText("残高を照合"));
+
+  expect(reconcile).toHaveBeenCalledWith(
+    expect.objectContaining({ id: "acc-1", actual: 3400 }),
+  );
+});
+
+test("viewers cannot reconcile", () => {
+  render(<LedgerPanel accountId="acc-1" canWrite={false} />);
+
+  expect(screen.queryByText("残高を照合")).not.toBeInTheDocument();
+});
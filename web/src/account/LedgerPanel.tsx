@@ -0,0 +1,180 @@
+import { type FormEvent, useState } from "react";
+import styled from "styled-components";
+import { useGetLedgerQuery, useReconcileAccountMutation } from "./accountApi";
+import type { EntryKind } from "./types";
+
+const KIND_LABELS: Record<EntryKind, string> = {
+  expense: "支出",
+  income: "収入",
+  transfer: "振替",
+};
+
+const TODAY = new Date().toISOString().slice(0, 10);
+
+const Panel = styled.section`
+  margin-top: 1.5rem;
+`;
+
+const Form = styled.form`
+  display: flex;
+  gap: 0.5rem;
+  margin: 1rem 0;
+`;
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Button = styled.button`
+  padding: 0.5rem 1rem;
+  border: none;
+  border-radius: 4px;
+  background: #646cff;
+  color: #fff;
+  font-size: 0.95rem;
+  cursor: pointer;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: default;
+  }
+`;
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const AmountTd = styled(Td)<{ $negative: boolean }>`
+  text-align: right;
+  white-space: nowrap;
+  color: ${({ $negative }) => ($negative ? "#ff6b6b" : "inherit")};
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+const yen = (amount: number) => `¥${amount.toLocaleString()}`;
+
+interface Props {
+  accountId: string;
+  // canWrite allows recording reconciliations.
+  canWrite: boolean;
+}
+
+// LedgerPanel shows an account's entries with the running balance, and
+// compares it with balances the user checked against the bank or wallet.
+export default function LedgerPanel({ accountId, canWrite }: Props) {
+  const { data, isLoading, error } = useGetLedgerQuery(accountId);
+  const [reconcile, { isLoading: isReconciling, error: reconcileError }] =
+    useReconcileAccountMutation();
+  const [actual, setActual] = useState("");
+  const [date, setDate] = useState(TODAY);
+
+  const handleReconcile = async (e: FormEvent) => {
+    e.preventDefault();
+
+    const parsed = Number(actual);
+    if (actual === "" || !Number.isInteger(parsed)) return;
+
+    await reconcile({ id: accountId, actual: parsed, date }).unwrap();
+    setActual("");
+  };
+
+  if (isLoading) return <Message>読み込み中...</Message>;
+  if (error || !data) return <Message>入出金の取得に失敗しました</Message>;
+
+  const latest = data.reconciliations[data.reconciliations.length - 1];
+
+  return (
+    <Panel>
+      <h3>
+        {data.account.name}: {yen(data.balance)}
+      </h3>
+
+      {latest && (
+        <Message>
+          {latest.date}の照合: 実残高 {yen(latest.actual)} / 記録{" "}
+          {yen(latest.expected)}
+          {latest.difference === 0
+            ? "（一致）"
+            : `（差額 ${yen(latest.difference)}）`}
+        </Message>
+      )}
+
+      {canWrite && (
+        <Form onSubmit={handleReconcile}>
+          <Input
+            type="number"
+            step="1"
+            aria-label="実残高"
+            placeholder="実残高"
+            value={actual}
+            onChange={(e) => setActual(e.target.value)}
+            required
+          />
+          <Input
+            type="date"
+            aria-label="照合日"
+            value={date}
+            onChange={(e) => setDate(e.target.value)}
+            required
+          />
+          <Button type="submit" disabled={isReconciling}>
+            残高を照合
+          </Button>
+        </Form>
+      )}
+      {reconcileError && <Message>照合の記録に失敗しました</Message>}
+
+      {data.entries.length === 0 ? (
+        <Message>入出金はまだありません</Message>
+      ) : (
+        <Table>
+          <thead>
+            <tr>
+              <Th>日付</Th>
+              <Th>種類</Th>
+              <Th>内容</Th>
+              <Th>メモ</Th>
+              <Th>金額</Th>
+              <Th>残高</Th>
+            </tr>
+          </thead>
+          <tbody>
+            {data.entries.map((e) => (
+              <tr key={`${e.kind}-${e.ref_id}`}>
+                <Td>{e.date}</Td>
+                <Td>{KIND_LABELS[e.kind]}</Td>
+                <Td>{e.label}</Td>
+                <Td>{e.memo}</Td>
+                <AmountTd $negative={e.amount < 0}>{yen(e.amount)}</AmountTd>
+                <AmountTd $negative={e.balance < 0}>{yen(e.balance)}</AmountTd>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+    </Panel>
+  );
+}
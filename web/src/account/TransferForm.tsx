@@ -0,0 +1,148 @@
+import { type FormEvent, useState } from "react";
+import styled from "styled-components";
+import AccountSelect from "./AccountSelect";
+import { useRecordTransferMutation } from "./accountApi";
+
+const TODAY = new Date().toISOString().slice(0, 10);
+
+const Form = styled.form`
+  display: flex;
+  flex-wrap: wrap;
+  gap: 0.75rem;
+  align-items: flex-end;
+  margin-bottom: 1.5rem;
+`;
+
+const Field = styled.label`
+  display: flex;
+  flex-direction: column;
+  gap: 0.25rem;
+  font-size: 0.85rem;
+`;
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const SubmitButton = styled.button`
+  padding: 0.5rem 1.5rem;
+  background: #0f3460;
+  color: #fff;
+  border: none;
+  border-radius: 4px;
+  cursor: pointer;
+  font-size: 0.95rem;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: not-allowed;
+  }
+`;
+
+const ErrorMessage = styled.p`
+  color: #ff6b6b;
+  font-size: 0.85rem;
+  width: 100%;
+  margin: 0;
+`;
+
+interface Props {
+  // bookId is the book whose accounts can be transferred between; the
+  // personal book if omitted.
+  bookId?: string;
+}
+
+// TransferForm moves money between two accounts of the same book, such as
+// a cash withdrawal or paying off a credit card.
+export default function TransferForm({ bookId }: Props) {
+  const [from, setFrom] = useState("");
+  const [to, setTo] = useState("");
+  const [amount, setAmount] = useState("");
+  const [memo, setMemo] = useState("");
+  const [date, setDate] = useState(TODAY);
+
+  const [recordTransfer, { isLoading, error }] = useRecordTransferMutation();
+
+  const handleSubmit = async (e: FormEvent) => {
+    e.preventDefault();
+
+    const parsed = Number(amount);
+    if (!from || !to || from === to) return;
+    if (!Number.isInteger(parsed) || parsed <= 0) return;
+
+    await recordTransfer({ from, to, amount: parsed, date, memo }).unwrap();
+
+    setAmount("");
+    setMemo("");
+  };
+
+  return (
+    <Form onSubmit={handleSubmit}>
+      <Field>
+        振替元
+        <AccountSelect
+          bookId={bookId}
+          value={from}
+          onChange={setFrom}
+          label="振替元"
+          allowNone={false}
+        />
+      </Field>
+
+      <Field>
+        振替先
+        <AccountSelect
+          bookId={bookId}
+          value={to}
+          onChange={setTo}
+          label="振替先"
+          allowNone={false}
+        />
+      </Field>
+
+      <Field>
+        金額
+        <Input
+          type="number"
+          min="1"
+          step="1"
+          value={amount}
+          onChange={(e) => setAmount(e.target.value)}
+          required
+          placeholder="10000"
+        />
+      </Field>
+
+      <Field>
+        メモ
+        <Input
+          type="text"
+          value={memo}
+          onChange={(e) => setMemo(e.target.value)}
+          placeholder="ATM引き出し"
+        />
+      </Field>
+
+      <Field>
+        日付
+        <Input
+          type="date"
+          value={date}
+          onChange={(e) => setDate(e.target.value)}
+          required
+        />
+      </Field>
+
+      <SubmitButton type="submit" disabled={isLoading}>
+        {isLoading ? "記録中..." : "振替を記録"}
+      </SubmitButton>
+
+      {error && <ErrorMessage>振替の記録に失敗しました</ErrorMessage>}
+    </Form>
+  );
+}
@@ -0,0 +1,58 @@
+import { baseApi } from "../store/baseApi";
+import type {
+  AccountWithBalance,
+  CommandResponse,
+  Ledger,
+  OpenAccountRequest,
+  ReconcileRequest,
+  TransferRequest,
+} from "./types";
+
+export const accountApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    listAccounts: builder.query<AccountWithBalance[], string | void>({
+      query: (book) =>
+        book ? `/accounts?book=${encodeURIComponent(book)}` : "/accounts",
+      transformResponse: (response: { accounts: AccountWithBalance[] }) =>
+        response.accounts,
+      // Expenses and income paid from an account move its balance too.
+      providesTags: ["Account", "Expense", "Income"],
+    }),
+    getLedger: builder.query<Ledger, string>({
+      query: (id) => `/accounts/${id}/ledger`,
+      providesTags: ["Account", "Expense", "Income"],
+    }),
+    openAccount: builder.mutation<CommandResponse, OpenAccountRequest>({
+      query: (body) => ({
+        url: "/accounts",
+        method: "POST",
+        body,
+      }),
+      invalidatesTags: ["Account"],
+    }),
+    recordTransfer: builder.mutation<CommandResponse, TransferRequest>({
+      query: (body) => ({
+        url: "/transfers",
+        method: "POST",
+        body,
+      }),
+      invalidatesTags: ["Account"],
+    }),
+    reconcileAccount: builder.mutation<CommandResponse, ReconcileRequest>({
+      query: ({ id, ...body }) => ({
+        url: `/accounts/${id}/reconciliations`,
+        method: "POST",
+        body,
+      }),
+      invalidatesTags: ["Account"],
+    }),
+  }),
+});
+
+export const {
+  useListAccountsQuery,
+  useGetLedgerQuery,
+  useOpenAccountMutation,
+  useRecordTransferMutation,
+  useReconcileAccountMutation,
+} = accountApi;
@@ -0,0 +1,76 @@
+export type AccountType = "cash" | "bank" | "debit" | "credit" | "emoney";
+
+export interface Reconciliation {
+  date: string;
+  actual: number;
+  // expected is the ledger's balance at the end of date.
+  expected: number;
+  // difference is actual minus expected.
+  difference: number;
+}
+
+export interface Account {
+  id: string;
+  book_id: string;
+  name: string;
+  type: AccountType;
+  currency: string;
+  opening_balance: number;
+  opened_on: string;
+}
+
+export interface AccountWithBalance extends Account {
+  balance: number;
+  // reconciliation is the latest one, or null if never reconciled.
+  reconciliation: Reconciliation | null;
+}
+
+export type EntryKind = "expense" | "income" | "transfer";
+
+export interface LedgerEntry {
+  date: string;
+  kind: EntryKind;
+  ref_id: string;
+  // label is the expense's category, the income's source, or the other
+  // account's ID for a transfer.
+  label: string;
+  memo: string;
+  amount: number;
+  // balance is the account's balance after the entry.
+  balance: number;
+}
+
+export interface Ledger {
+  account: Account;
+  balance: number;
+  entries: LedgerEntry[];
+  reconciliations: Reconciliation[];
+}
+
+export interface OpenAccountRequest {
+  book_id?: string;
+  name: string;
+  type: AccountType;
+  opening_balance: number;
+  currency?: string;
+  date: string;
+}
+
+export interface TransferRequest {
+  from: string;
+  to: string;
+  amount: number;
+  date: string;
+  memo: string;
+}
+
+export interface ReconcileRequest {
+  id: string;
+  actual: number;
+  date: string;
+}
+
+export interface CommandResponse {
+  id: string;
+  version: number;
+}
@@ -0,0 +1,43 @@
+import { render, screen } from "@testing-library/react";
+import AchievementList from "./AchievementList";
+import "@testing-library/jest-dom";
+import * as achievementApi from "./achievementApi";
+
+jest.mock("./achievementApi");
+
+const mockUseListAchievementsQuery =
+  achievementApi.useListAchievementsQuery as jest.MockedFunction<
+    typeof achievementApi.useListAchievementsQuery
+  >;
+
+test("lists achievements with the day unlocked ones were earned", () => {
+  mockUseListAchievementsQuery.mockReturnValue({
+    data: [
+      {
+        key: "first-expense",
+        name: "はじめの一歩",
+        description: "はじめて支出を記録する",
+        unlocked: true,
+        earned_on: "2026-02-01",
+        unlocked_at: "2026-02-01T12:00:00Z",
+      },
+      {
+        key: "logging-week",
+        name: "記録の習慣",
+        description: "7日続けて支出を記録する",
+        unlocked: false,
+        earned_on: null,
+        unlocked_at: null,
+      },
+    ],
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof achievementApi.useListAchievementsQuery>);
+
+  render(<AchievementList />);
+
+  expect(
+    screen.getByText("はじめて支出を記録する（2026-02-01 達成）"),
+  ).toBeInTheDocument();
+  expect(screen.getByText("7日続けて支出を記録する")).toBeInTheDocument();
+});
@@ -0,0 +1,51 @@
+import styled from "styled-components";
+import { useListAchievementsQuery } from "./achievementApi";
+
+const List = styled.ul`
+  list-style: none;
+  padding: 0;
+  margin: 1rem 0 0;
+  display: grid;
+  gap: 0.5rem;
+`;
+
+const Item = styled.li<{ $unlocked: boolean }>`
+  padding: 0.75rem;
+  border: 1px solid #333;
+  border-radius: 4px;
+  opacity: ${({ $unlocked }) => ($unlocked ? 1 : 0.4)};
+`;
+
+const Name = styled.p`
+  margin: 0;
+  font-weight: bold;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+  margin: 0.25rem 0 0;
+`;
+
+export default function AchievementList() {
+  const { data: achievements, isLoading, error } = useListAchievementsQuery();
+
+  if (isLoading) return <Message>読み込み中...</Message>;
+  if (error || !achievements) {
+    return <Message>実績の取得に失敗しました</Message>;
+  }
+
+  return (
+    <List>
+      {achievements.map((a) => (
+        <Item key={a.key} $unlocked={a.unlocked}>
+          <Name>{a.name}</Name>
+          <Message>
+            {a.description}
+            {a.earned_on && `（${a.earned_on} 達成）`}
+          </Message>
+        </Item>
+      ))}
+    </List>
+  );
+}
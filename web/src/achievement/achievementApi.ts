@@ -0,0 +1,13 @@
+import { baseApi } from "../store/baseApi";
+import type { Achievement } from "./types";
+
+export const achievementApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    listAchievements: builder.query<Achievement[], void>({
+      query: () => "/achievements",
+      providesTags: ["Achievement"],
+    }),
+  }),
+});
+
+export const { useListAchievementsQuery } = achievementApi;
@@ -0,0 +1,8 @@
+export interface Achievement {
+  key: string;
+  name: string;
+  description: string;
+  unlocked: boolean;
+  earned_on: string | null;
+  unlocked_at: string | null;
+}
@@ -0,0 +1,114 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import { Provider } from "react-redux";
+import { MemoryRouter, Route, Routes } from "react-router-dom";
+import LoginPage from "./LoginPage";
+import "@testing-library/jest-dom";
+import * as authApi from "./authApi";
+import { store } from "../store";
+import { loggedOut } from "./authSlice";
+
+jest.mock("./authApi");
+
+const mockUseLoginMutation = authApi.useLoginMutation as jest.MockedFunction<
+  typeof authApi.useLoginMutation
+>;
+const mockUseRegisterMutation =
+  authApi.useRegisterMutation as jest.MockedFunction<
+    typeof authApi.useRegisterMutation
+  >;
+
+const session = {
+  token: "token-1",
+  expires_at: "2099-01-01T00:00:00Z",
+  user: { id: "user-1", username: "alice" },
+};
+
+function mockMutation(result: () => Promise<unknown>) {
+  const trigger = jest.fn(() => ({ unwrap: result }));
+  return [trigger, { isLoading: false }] as unknown as ReturnType<
+    typeof authApi.useLoginMutation
+  >;
+}
+
+function renderPage() {
+  render(
+    <Provider store={store}>
+      <MemoryRouter initialEntries={["/login"]}>
+        <Routes>
+          <Route path="/login" element={<LoginPage />} />
+          <Route path="/" element={<p>home</p>} />
+        </Routes>
+      </MemoryRouter>
+    </Provider>,
+  );
+}
+
+beforeEach(() => {
+  store.dispatch(loggedOut());
+  mockUseRegisterMutation.mockReturnValue(
+    mockMutation(() => Promise.resolve(session.user)),
+  );
+});
+
+test("logs in and stores the session", async () => {
+  mockUseLoginMutation.mockReturnValue(
+    mockMutation(() => Promise.resolve(session)),
+  );
+
+  const user = userEvent.setup();
+  renderPage();
+  await user.type(screen.getByLabelText("ユーザー名"), "alice");
+  await user.type(screen.getByLabelText("パスワード"), "correct horse");
+  await user.click(screen.getByRole("button", { name: "ログイン" }));
+
+  expect(await screen.findByText("home")).toBeInTheDocument();
+  expect(store.getState().auth.session).toEqual(session);
+  expect(localStorage.getItem("kakei-board.session")).toContain("token-1");
+});
+
+test("shows the server's error when login fails", async () => {
+  mockUseLoginMutation.mockReturnValue(
+    mockMutation(() =>
+      Promise.reject({
+        status: 401,
+        data: { error: "invalid username or password" },
+      }),
+    ),
+  );
+
+  const user = userEvent.setup();
+  renderPage();
+  await user.type(screen.getByLabelText("ユーザー名"), "alice");
+  await user.type(screen.getByLabelText("パスワード"), "wrong horse");
+  await user.click(screen.getByRole("button", { name: "ログイン" }));
+
+  expect(await screen.findByRole("alert")).toHaveTextContent(
+    "invalid username or password",
+  );
+  expect(store.getState().auth.session).toBeNull();
+});
+
+test("registers before logging in", async () => {
+  mockUseLoginMutation.mockReturnValue(
+    mockMutation(() => Promise.resolve(session)),
+  );
+
+  const user = userEvent.setup();
+  renderPage();
+  await user.click(
+    screen.getByRole("button", { name: "アカウントを作成する" }),
+  );
+  await user.type(screen.getByLabelText("ユーザー名"), "alice");
+  await user.type(screen.getByLabelText("パスワード"), "correct horse");
+  await user.click(
+    screen.getByRole("button", { name: "登録してログイン" }),
+  );
+
+  expect(await screen.findByText("home")).toBeInTheDocument();
+  const [register] = mockUseRegisterMutation.mock.results[0].value;
+  expect(register).toHaveBeenCalledWith({
+    username: "alice",
+    password: "correct horse",
+  });
+});
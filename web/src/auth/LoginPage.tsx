@@ -0,0 +1,151 @@
+import { type FormEvent, useState } from "react";
+import { useDispatch } from "react-redux";
+import { useNavigate } from "react-router-dom";
+import styled from "styled-components";
+import { useLoginMutation, useRegisterMutation } from "./authApi";
+import { loggedIn } from "./authSlice";
+import { baseApi } from "../store/baseApi";
+
+const Page = styled.main`
+  max-width: 20rem;
+  margin: 4rem auto;
+  padding: 1.5rem;
+`;
+
+const Form = styled.form`
+  display: flex;
+  flex-direction: column;
+  gap: 0.75rem;
+`;
+
+const Field = styled.label`
+  display: flex;
+  flex-direction: column;
+  gap: 0.25rem;
+  font-size: 0.85rem;
+`;
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Button = styled.button`
+  padding: 0.5rem 1rem;
+  border: none;
+  border-radius: 4px;
+  background: #0f3460;
+  color: #fff;
+  font-size: 0.95rem;
+  cursor: pointer;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: not-allowed;
+  }
+`;
+
+const LinkButton = styled.button`
+  border: none;
+  background: none;
+  color: #646cff;
+  font-size: 0.85rem;
+  cursor: pointer;
+`;
+
+const ErrorMessage = styled.p`
+  color: #ff6b6b;
+  font-size: 0.85rem;
+  margin: 0;
+`;
+
+function errorMessage(error: unknown): string {
+  if (
+    typeof error === "object" &&
+    error !== null &&
+    "data" in error &&
+    typeof error.data === "object" &&
+    error.data !== null &&
+    "error" in error.data &&
+    typeof error.data.error === "string"
+  ) {
+    return error.data.error;
+  }
+  return "通信に失敗しました";
+}
+
+export default function LoginPage() {
+  const [mode, setMode] = useState<"login" | "register">("login");
+  const [username, setUsername] = useState("");
+  const [password, setPassword] = useState("");
+  const [error, setError] = useState<string | null>(null);
+  const [login, { isLoading: isLoggingIn }] = useLoginMutation();
+  const [register, { isLoading: isRegistering }] = useRegisterMutation();
+  const dispatch = useDispatch();
+  const navigate = useNavigate();
+
+  const handleSubmit = async (e: FormEvent) => {
+    e.preventDefault();
+    setError(null);
+    try {
+      if (mode === "register") {
+        await register({ username, password }).unwrap();
+      }
+      const session = await login({ username, password }).unwrap();
+      // Drop anything cached for the previous user.
+      dispatch(baseApi.util.resetApiState());
+      dispatch(loggedIn(session));
+      navigate("/", { replace: true });
+    } catch (err) {
+      setError(errorMessage(err));
+    }
+  };
+
+  return (
+    <Page>
+      <h2>{mode === "login" ? "ログイン" : "ユーザー登録"}</h2>
+      <Form onSubmit={handleSubmit}>
+        <Field>
+          ユーザー名
+          <Input
+            autoComplete="username"
+            value={username}
+            onChange={(e) => setUsername(e.target.value)}
+            required
+          />
+        </Field>
+        <Field>
+          パスワード
+          <Input
+            type="password"
+            autoComplete={
+              mode === "login" ? "current-password" : "new-password"
+            }
+            value={password}
+            onChange={(e) => setPassword(e.target.value)}
+            required
+          />
+        </Field>
+        {error && <ErrorMessage role="alert">{error}</ErrorMessage>}
+        <Button type="submit" disabled={isLoggingIn || isRegistering}>
+          {mode === "login" ? "ログイン" : "登録してログイン"}
+        </Button>
+      </Form>
+      <LinkButton
+        type="button"
+        onClick={() => {
+          setMode(mode === "login" ? "register" : "login");
+          setError(null);
+        }}
+      >
+        {mode === "login"
+          ? "アカウントを作成する"
+          : "登録済みの方はこちら"}
+      </LinkButton>
+    </Page>
+  );
+}
@@ -0,0 +1,23 @@
+import { baseApi } from "../store/baseApi";
+import type { Credentials, Session, User } from "./types";
+
+export const authApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    register: builder.mutation<User, Credentials>({
+      query: (body) => ({
+        url: "/users",
+        method: "POST",
+        body,
+      }),
+    }),
+    login: builder.mutation<Session, Credentials>({
+      query: (body) => ({
+        url: "/sessions",
+        method: "POST",
+        body,
+      }),
+    }),
+  }),
+});
+
+export const { useRegisterMutation, useLoginMutation } = authApi;
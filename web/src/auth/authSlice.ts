@@ -0,0 +1,45 @@
+import { createSlice, type PayloadAction } from "@reduxjs/toolkit";
+import type { Session } from "./types";
+
+const STORAGE_KEY = "kakei-board.session";
+
+export interface AuthState {
+  session: Session | null;
+}
+
+// loadSession restores the session saved by saveSession, dropping it once
+// its token has expired.
+function loadSession(): Session | null {
+  try {
+    const raw = localStorage.getItem(STORAGE_KEY);
+    if (!raw) return null;
+    const session = JSON.parse(raw) as Session;
+    return new Date(session.expires_at) > new Date() ? session : null;
+  } catch {
+    return null;
+  }
+}
+
+export function saveSession(session: Session | null) {
+  if (session) {
+    localStorage.setItem(STORAGE_KEY, JSON.stringify(session));
+  } else {
+    localStorage.removeItem(STORAGE_KEY);
+  }
+}
+
+const authSlice = createSlice({
+  name: "auth",
+  initialState: (): AuthState => ({ session: loadSession() }),
+  reducers: {
+    loggedIn(state, action: PayloadAction<Session>) {
+      state.session = action.payload;
+    },
+    loggedOut(state) {
+      state.session = null;
+    },
+  },
+});
+
+export const { loggedIn, loggedOut } = authSlice.actions;
+export default authSlice.reducer;
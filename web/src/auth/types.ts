@@ -0,0 +1,15 @@
+export interface Credentials {
+  username: string;
+  password: string;
+}
+
+export interface User {
+  id: string;
+  username: string;
+}
+
+export interface Session {
+  token: string;
+  expires_at: string;
+  user: User;
+}
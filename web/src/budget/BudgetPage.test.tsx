@@ -0,0 +1,73 @@
+import { render, screen } from "@testing-library/react";
+import BudgetPage from "./BudgetPage";
+import "@testing-library/jest-dom";
+import * as budgetApi from "./budgetApi";
+
+jest.mock("./budgetApi");
+
+const mockUseGetBudgetProgressQuery =
+  budgetApi.useGetBudgetProgressQuery as jest.MockedFunction<
+    typeof budgetApi.useGetBudgetProgressQuery
+  >;
+const mockUseSetBudgetMutation =
+  budgetApi.useSetBudgetMutation as jest.MockedFunction<
+    typeof budgetApi.useSetBudgetMutation
+  >;
+
+beforeEach(() => {
+  mockUseSetBudgetMutation.mockReturnValue([
+    jest.fn(),
+    { isLoading: false },
+  ] as unknown as ReturnType<typeof budgetApi.useSetBudgetMutation>);
+});
+
+test("displays progress against the total and category limits", () => {
+  mockUseGetBudgetProgressQuery.mockReturnValue({
+    data: {
+      month: "2026-02",
+      version: 1,
+      as_of: "2026-02-07",
+      spent: 7000,
+      total: {
+        limit: 50000,
+        spent: 7000,
+        remaining: 43000,
+        percent_used: 14,
+        projected: 28000,
+        projected_overrun: 0,
+      },
+      categories: [
+        {
+          category: "食費",
+          limit: 5000,
+          spent: 6000,
+          remaining: -1000,
+          percent_used: 120,
+          projected: 24000,
+          projected_overrun: 19000,
+        },
+      ],
+    },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof budgetApi.useGetBudgetProgressQuery>);
+
+  render(<BudgetPage />);
+
+  expect(screen.getByText("合計")).toBeInTheDocument();
+  expect(screen.getByText("43,000円")).toBeInTheDocument();
+  expect(screen.getByText("食費")).toBeInTheDocument();
+  expect(screen.getByText("-1,000円")).toBeInTheDocument();
+});
+
+test("shows a message when the month has no budget", () => {
+  mockUseGetBudgetProgressQuery.mockReturnValue({
+    data: undefined,
+    isLoading: false,
+    error: { status: 404, data: { error: "budget not found" } },
+  } as unknown as ReturnType<typeof budgetApi.useGetBudgetProgressQuery>);
+
+  render(<BudgetPage />);
+
+  expect(screen.getByText("この月の予算はまだありません")).toBeInTheDocument();
+});
@@ -0,0 +1,166 @@
+import { useState } from "react";
+import styled from "styled-components";
+import { useGetBudgetProgressQuery, useSetBudgetMutation } from "./budgetApi";
+import type { LimitProgress } from "./types";
+
+const THIS_MONTH = new Date().toISOString().slice(0, 7);
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Form = styled.form`
+  display: flex;
+  gap: 0.5rem;
+  margin: 1rem 0 1.5rem;
+`;
+
+const Button = styled.button`
+  padding: 0.5rem 1rem;
+  border: none;
+  border-radius: 4px;
+  background: #646cff;
+  color: #fff;
+  font-size: 0.95rem;
+  cursor: pointer;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: default;
+  }
+`;
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const AmountCell = styled(Td)<{ $over?: boolean }>`
+  text-align: right;
+  font-variant-numeric: tabular-nums;
+  color: ${({ $over }) => ($over ? "#ff6b6b" : "inherit")};
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+function isNotFound(error: unknown): boolean {
+  return (
+    typeof error === "object" &&
+    error !== null &&
+    "status" in error &&
+    error.status === 404
+  );
+}
+
+function ProgressRow({ label, p }: { label: string; p: LimitProgress }) {
+  return (
+    <tr>
+      <Td>{label}</Td>
+      <AmountCell>{p.limit.toLocaleString()}円</AmountCell>
+      <AmountCell $over={p.remaining < 0}>
+        {p.spent.toLocaleString()}円（{Math.round(p.percent_used)}%）
+      </AmountCell>
+      <AmountCell $over={p.remaining < 0}>
+        {p.remaining.toLocaleString()}円
+      </AmountCell>
+      <AmountCell $over={p.projected_overrun > 0}>
+        {p.projected.toLocaleString()}円
+      </AmountCell>
+    </tr>
+  );
+}
+
+export default function BudgetPage() {
+  const [month, setMonth] = useState(THIS_MONTH);
+  const [total, setTotal] = useState("");
+  const { data: progress, isLoading, error } = useGetBudgetProgressQuery(month);
+  const [setBudget, { isLoading: isSaving }] = useSetBudgetMutation();
+
+  const categories = Object.fromEntries(
+    (progress?.categories ?? []).map((c) => [c.category, c.limit]),
+  );
+
+  return (
+    <>
+      <h2>予算</h2>
+      <Input
+        type="month"
+        aria-label="月"
+        value={month}
+        onChange={(e) => e.target.value && setMonth(e.target.value)}
+      />
+      <Form
+        onSubmit={(e) => {
+          e.preventDefault();
+          setBudget({ month, total: Number(total), categories });
+          setTotal("");
+        }}
+      >
+        <Input
+          type="number"
+          min={1}
+          aria-label="月の予算"
+          placeholder={
+            progress?.total ? String(progress.total.limit) : "月の予算"
+          }
+          value={total}
+          onChange={(e) => setTotal(e.target.value)}
+        />
+        <Button type="submit" disabled={isSaving || !total}>
+          設定
+        </Button>
+      </Form>
+      {isLoading ? (
+        <Message>読み込み中...</Message>
+      ) : isNotFound(error) ? (
+        <Message>この月の予算はまだありません</Message>
+      ) : error || !progress ? (
+        <Message>予算の取得に失敗しました</Message>
+      ) : (
+        <>
+          <Message>
+            {progress.as_of} 時点の支出 {progress.spent.toLocaleString()}円
+          </Message>
+          <Table>
+            <thead>
+              <tr>
+                <Th></Th>
+                <Th style={{ textAlign: "right" }}>予算</Th>
+                <Th style={{ textAlign: "right" }}>支出</Th>
+                <Th style={{ textAlign: "right" }}>残り</Th>
+                <Th style={{ textAlign: "right" }}>月末見込み</Th>
+              </tr>
+            </thead>
+            <tbody>
+              {progress.total && <ProgressRow label="合計" p={progress.total} />}
+              {progress.categories.map((c) => (
+                <ProgressRow key={c.category} label={c.category} p={c} />
+              ))}
+            </tbody>
+          </Table>
+        </>
+      )}
+    </>
+  );
+}
@@ -0,0 +1,24 @@
+import { baseApi } from "../store/baseApi";
+import type { BudgetProgress, SetBudgetRequest } from "./types";
+
+export const budgetApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    getBudgetProgress: builder.query<BudgetProgress, string>({
+      query: (month) => `/budgets/${month}/progress`,
+      providesTags: ["Budget"],
+    }),
+    setBudget: builder.mutation<
+      { month: string; version: number },
+      SetBudgetRequest
+    >({
+      query: ({ month, ...body }) => ({
+        url: `/budgets/${month}`,
+        method: "PUT",
+        body,
+      }),
+      invalidatesTags: ["Budget"],
+    }),
+  }),
+});
+
+export const { useGetBudgetProgressQuery, useSetBudgetMutation } = budgetApi;
@@ -0,0 +1,27 @@
+export interface LimitProgress {
+  limit: number;
+  spent: number;
+  remaining: number;
+  percent_used: number;
+  projected: number;
+  projected_overrun: number;
+}
+
+export interface CategoryProgress extends LimitProgress {
+  category: string;
+}
+
+export interface BudgetProgress {
+  month: string;
+  version: number;
+  as_of: string;
+  spent: number;
+  total: LimitProgress | null;
+  categories: CategoryProgress[];
+}
+
+export interface SetBudgetRequest {
+  month: string;
+  total: number;
+  categories: Record<string, number>;
+}
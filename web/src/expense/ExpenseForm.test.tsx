@@ -3,8 +3,32 @@ import userEvent from "@testing-library/user-event";
 import ExpenseForm from "./ExpenseForm";
 import "@testing-library/jest-dom";
 import * as expenseApi from "./expenseApi";
+import * as accountApi from "../account/accountApi";
 
 jest.mock("./expenseApi");
+jest.mock("../account/accountApi");
+
+beforeEach(() => {
+  (
+    accountApi.useListAccountsQuery as jest.MockedFunction<
+      typeof accountApi.useListAccountsQuery
+    >
+  ).mockReturnValue({
+    data: [
+      {
+        id: "acc-1",
+        book_id: "",
+        name: "給与口座",
+        type: "bank",
+        currency: "JPY",
+        opening_balance: 0,
+        opened_on: "2026-01-01",
+        balance: 0,
+        reconciliation: null,
+      },
+    ],
+  } as unknown as ReturnType<typeof accountApi.useListAccountsQuery>);
+});
 
 const mockUseRecordExpenseMutation =
   expenseApi.useRecordExpenseMutation as jest.MockedFunction<
@@ -1,5 +1,6 @@
 import { type FormEvent, useState } from "react";
 import styled from "styled-components";
+import AccountSelect from "../account/AccountSelect";
 import { useRecordExpenseMutation } from "./expenseApi";
 
 const CATEGORY_PRESETS = [
@@ -68,11 +69,17 @@ const ErrorMessage = styled.p`
   margin: 0;
 `;
 
-export default function ExpenseForm() {
+interface Props {
+  // bookId is the household to record in; the personal book if omitted.
+  bookId?: string;
+}
+
+export default function ExpenseForm({ bookId }: Props) {
   const [amount, setAmount] = useState("");
   const [category, setCategory] = useState(CATEGORY_PRESETS[0]);
   const [memo, setMemo] = useState("");
   const [date, setDate] = useState(TODAY);
+  const [account, setAccount] = useState("");
 
   const [recordExpense, { isLoading, error }] = useRecordExpenseMutation();
 
@@ -83,10 +90,12 @@ export default function ExpenseForm() {
     if (!Number.isInteger(parsed) || parsed <= 0) return;
 
     await recordExpense({
+      book_id: bookId,
       amount: parsed,
       category,
       memo,
       date,
+      account_id: account || undefined,
     }).unwrap();
 
     setAmount("");
@@ -139,6 +148,11 @@ export default function ExpenseForm() {
         />
       </Field>
 
+      <Field>
+        支払元
+        <AccountSelect bookId={bookId} value={account} onChange={setAccount} />
+      </Field>
+
       <SubmitButton type="submit" disabled={isLoading}>
         {isLoading ? "記録中..." : "記録する"}
       </SubmitButton>
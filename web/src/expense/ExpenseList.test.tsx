@@ -19,6 +19,7 @@ test("displays expenses returned by the API", () => {
         category: "食費",
         memo: "コンビニ",
         date: "2026-02-20",
+        tags: [],
         created_at: "2026-02-20T00:00:00Z",
       },
     ],
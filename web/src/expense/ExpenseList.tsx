@@ -29,8 +29,15 @@ const Message = styled.p`
   font-size: 0.9rem;
 `;
 
-export default function ExpenseList() {
-  const { data: expenses, isLoading, error } = useListExpensesQuery();
+interface Props {
+  // book is the household to list; the personal book if omitted.
+  book?: string;
+}
+
+export default function ExpenseList({ book }: Props) {
+  const { data: expenses, isLoading, error } = useListExpensesQuery(
+    book ? { book } : undefined,
+  );
 
   if (isLoading) return <Message>読み込み中...</Message>;
   if (error) return <Message>一覧の取得に失敗しました</Message>;
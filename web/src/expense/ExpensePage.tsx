@@ -1,12 +1,20 @@
+import { useState } from "react";
 import ExpenseForm from "./ExpenseForm";
 import ExpenseList from "./ExpenseList";
+import BookSelect from "../household/BookSelect";
+import { useListHouseholdsQuery } from "../household/householdApi";
 
 export default function ExpensePage() {
+  const [book, setBook] = useState("");
+  const { data: households } = useListHouseholdsQuery();
+  const role = households?.find((h) => h.id === book)?.role;
+
   return (
     <>
       <h2>支出</h2>
-      <ExpenseForm />
-      <ExpenseList />
+      <BookSelect value={book} onChange={setBook} />
+      {role !== "viewer" && <ExpenseForm bookId={book || undefined} />}
+      <ExpenseList book={book || undefined} />
     </>
   );
 }
@@ -5,18 +5,50 @@ import type {
   RecordExpenseResponse,
 } from "./types";
 
-interface ListExpensesParams {
+export interface ListExpensesParams {
+  book?: string;
+  from?: string;
+  to?: string;
+  category?: string[];
+  minAmount?: number;
+  maxAmount?: number;
+  q?: string;
+  sort?: "date" | "amount" | "created_at";
+  order?: "asc" | "desc";
+  cursor?: string;
   limit?: number;
   offset?: number;
 }
 
+// toSearchParams repeats category once per value, which the API reads as a
+// multi-valued parameter.
+function toSearchParams(params: ListExpensesParams): URLSearchParams {
+  const search = new URLSearchParams();
+  const { category, minAmount, maxAmount, ...rest } = params;
+  for (const [key, value] of Object.entries(rest)) {
+    if (value !== undefined && value !== "") {
+      search.append(key, String(value));
+    }
+  }
+  for (const c of category ?? []) {
+    search.append("category", c);
+  }
+  if (minAmount !== undefined) {
+    search.append("min_amount", String(minAmount));
+  }
+  if (maxAmount !== undefined) {
+    search.append("max_amount", String(maxAmount));
+  }
+  return search;
+}
+
 export const expenseApi = baseApi.injectEndpoints({
   endpoints: (builder) => ({
     listExpenses: builder.query<Expense[], ListExpensesParams | void>({
-      query: (params) => ({
-        url: "/expenses",
-        params: params ?? undefined,
-      }),
+      query: (params) => {
+        const search = toSearchParams(params ?? {}).toString();
+        return search ? `/expenses?${search}` : "/expenses";
+      },
       providesTags: ["Expense"],
     }),
     recordExpense: builder.mutation<RecordExpenseResponse, RecordExpenseRequest>(
@@ -26,7 +58,7 @@ export const expenseApi = baseApi.injectEndpoints({
           method: "POST",
           body,
         }),
-        invalidatesTags: ["Expense"],
+        invalidatesTags: ["Expense", "Summary", "Budget", "Score"],
       },
     ),
   }),
@@ -1,17 +1,40 @@
 export interface Expense {
   id: string;
+  book_id: string;
   amount: number;
   category: string;
   memo: string;
   date: string;
+  tags: string[];
   created_at: string;
 }
 
+export type SplitMethod = "equal" | "ratio" | "fixed";
+
+export interface Share {
+  user_id: string;
+  ratio?: number;
+  amount?: number;
+}
+
+// Split says who paid for an expense in a household book and how it is
+// shared between members.
+export interface Split {
+  paid_by: string;
+  method: SplitMethod;
+  shares: Share[];
+}
+
 export interface RecordExpenseRequest {
+  book_id?: string;
   amount: number;
   category: string;
   memo: string;
   date: string;
+  // account_id is the account the expense was paid from, if any.
+  account_id?: string;
+  tags?: string[];
+  split?: Split;
 }
 
 export interface RecordExpenseResponse {
@@ -0,0 +1,63 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import BalancesPanel from "./BalancesPanel";
+import "@testing-library/jest-dom";
+import * as householdApi from "./householdApi";
+
+jest.mock("./householdApi");
+
+const mockUseGetBalancesQuery =
+  householdApi.useGetBalancesQuery as jest.MockedFunction<
+    typeof householdApi.useGetBalancesQuery
+  >;
+const mockUseRecordSettlementMutation =
+  householdApi.useRecordSettlementMutation as jest.MockedFunction<
+    typeof householdApi.useRecordSettlementMutation
+  >;
+
+let recordSettlement: jest.Mock;
+
+beforeEach(() => {
+  mockUseGetBalancesQuery.mockReturnValue({
+    data: {
+      balances: [
+        { user_id: "alice", amount: 600 },
+        { user_id: "bob", amount: -600 },
+      ],
+      transfers: [{ from: "bob", to: "alice", amount: 600 }],
+    },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof householdApi.useGetBalancesQuery>);
+  recordSettlement = jest.fn();
+  mockUseRecordSettlementMutation.mockReturnValue([
+    recordSettlement,
+    { isLoading: false },
+  ] as unknown as ReturnType<typeof householdApi.useRecordSettlementMutation>);
+});
+
+test("marks a transfer as settled", async () => {
+  const user = userEvent.setup();
+  render(
+    <BalancesPanel
+      household={{ id: "h1", name: "我が家", role: "editor", version: 2 }}
+    />,
+  );
+
+  expect(screen.getByText("bob → alice: ¥600")).toBeInTheDocument();
+  await user.click(screen.getByText("精算済みにする"));
+
+  expect(recordSettlement).toHaveBeenCalledWith(
+    expect.objectContaining({ id: "h1", from: "bob", to: "alice", amount: 600 }),
+  );
+});
+
+test("viewers cannot record settlements", () => {
+  render(
+    <BalancesPanel
+      household={{ id: "h1", name: "我が家", role: "viewer", version: 2 }}
+    />,
+  );
+
+  expect(screen.queryByText("精算済みにする")).not.toBeInTheDocument();
+});
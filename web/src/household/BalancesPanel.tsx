@@ -0,0 +1,94 @@
+import styled from "styled-components";
+import {
+  useGetBalancesQuery,
+  useRecordSettlementMutation,
+} from "./householdApi";
+import type { Household } from "./types";
+
+const Panel = styled.section`
+  margin-top: 1.5rem;
+`;
+
+const List = styled.ul`
+  list-style: none;
+  padding: 0;
+  margin: 0;
+`;
+
+const Item = styled.li`
+  display: flex;
+  align-items: center;
+  gap: 0.75rem;
+  padding: 0.5rem 0;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const Button = styled.button`
+  padding: 0.25rem 0.75rem;
+  border: none;
+  border-radius: 4px;
+  background: #646cff;
+  color: #fff;
+  font-size: 0.85rem;
+  cursor: pointer;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: default;
+  }
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+const yen = (amount: number) => `¥${amount.toLocaleString()}`;
+
+// BalancesPanel lists the transfers that settle a household's split
+// expenses. Owners and editors can mark a transfer as paid.
+export default function BalancesPanel({ household }: { household: Household }) {
+  const { data, isLoading, error } = useGetBalancesQuery(household.id);
+  const [recordSettlement, { isLoading: isRecording }] =
+    useRecordSettlementMutation();
+  const canWrite = household.role !== "viewer";
+
+  return (
+    <Panel>
+      <h3>{household.name}の精算</h3>
+      {isLoading && <Message>読み込み中...</Message>}
+      {error && <Message>貸し借りの取得に失敗しました</Message>}
+      {data && data.transfers.length === 0 && (
+        <Message>精算が必要な貸し借りはありません</Message>
+      )}
+      {data && data.transfers.length > 0 && (
+        <List>
+          {data.transfers.map((t) => (
+            <Item key={`${t.from}-${t.to}`}>
+              <span>
+                {t.from} → {t.to}: {yen(t.amount)}
+              </span>
+              {canWrite && (
+                <Button
+                  type="button"
+                  disabled={isRecording}
+                  onClick={() =>
+                    recordSettlement({
+                      id: household.id,
+                      from: t.from,
+                      to: t.to,
+                      amount: t.amount,
+                      date: new Date().toISOString().slice(0, 10),
+                    })
+                  }
+                >
+                  精算済みにする
+                </Button>
+              )}
+            </Item>
+          ))}
+        </List>
+      )}
+    </Panel>
+  );
+}
@@ -0,0 +1,37 @@
+import styled from "styled-components";
+import { useListHouseholdsQuery } from "./householdApi";
+
+const Select = styled.select`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+  margin-bottom: 1rem;
+`;
+
+interface Props {
+  // value is a household ID, or "" for the user's personal book.
+  value: string;
+  onChange: (book: string) => void;
+}
+
+export default function BookSelect({ value, onChange }: Props) {
+  const { data: households } = useListHouseholdsQuery();
+
+  return (
+    <Select
+      aria-label="帳簿"
+      value={value}
+      onChange={(e) => onChange(e.target.value)}
+    >
+      <option value="">個人</option>
+      {households?.map((h) => (
+        <option key={h.id} value={h.id}>
+          {h.name}
+        </option>
+      ))}
+    </Select>
+  );
+}
@@ -0,0 +1,94 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import HouseholdPage from "./HouseholdPage";
+import "@testing-library/jest-dom";
+import * as householdApi from "./householdApi";
+
+jest.mock("./householdApi");
+
+const mockUseListHouseholdsQuery =
+  householdApi.useListHouseholdsQuery as jest.MockedFunction<
+    typeof householdApi.useListHouseholdsQuery
+  >;
+const mockUseCreateHouseholdMutation =
+  householdApi.useCreateHouseholdMutation as jest.MockedFunction<
+    typeof householdApi.useCreateHouseholdMutation
+  >;
+const mockUseInviteMemberMutation =
+  householdApi.useInviteMemberMutation as jest.MockedFunction<
+    typeof householdApi.useInviteMemberMutation
+  >;
+const mockUseJoinHouseholdMutation =
+  householdApi.useJoinHouseholdMutation as jest.MockedFunction<
+    typeof householdApi.useJoinHouseholdMutation
+  >;
+
+function trigger<T>(result: T) {
+  return jest.fn().mockReturnValue({ unwrap: () => Promise.resolve(result) });
+}
+
+let invite: jest.Mock;
+let join: jest.Mock;
+
+beforeEach(() => {
+  mockUseListHouseholdsQuery.mockReturnValue({
+    data: [
+      { id: "h1", name: "我が家", role: "owner", version: 1 },
+      { id: "h2", name: "実家", role: "viewer", version: 3 },
+    ],
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof householdApi.useListHouseholdsQuery>);
+  mockUseCreateHouseholdMutation.mockReturnValue([
+    trigger({ id: "h3" }),
+    { isLoading: false },
+  ] as unknown as ReturnType<typeof householdApi.useCreateHouseholdMutation>);
+  invite = trigger({
+    code: "h1.secret",
+    role: "viewer",
+    expires_at: "2026-03-08T12:00:00Z",
+  });
+  mockUseInviteMemberMutation.mockReturnValue([
+    invite,
+    { isLoading: false },
+  ] as unknown as ReturnType<typeof householdApi.useInviteMemberMutation>);
+  join = trigger({ id: "h4" });
+  mockUseJoinHouseholdMutation.mockReturnValue([
+    join,
+    { isLoading: false, error: undefined },
+  ] as unknown as ReturnType<typeof householdApi.useJoinHouseholdMutation>);
+});
+
+test("lists households with the user's role", () => {
+  render(<HouseholdPage />);
+
+  expect(screen.getByText("我が家")).toBeInTheDocument();
+  expect(screen.getByText("オーナー")).toBeInTheDocument();
+  expect(screen.getByText("閲覧のみ")).toBeInTheDocument();
+  // Only owners may invite.
+  expect(screen.getAllByText("招待コードを発行")).toHaveLength(1);
+});
+
+test("shows the invitation code once issued", async () => {
+  const user = userEvent.setup();
+  render(<HouseholdPage />);
+
+  await user.selectOptions(
+    screen.getByLabelText("我が家の招待ロール"),
+    "viewer",
+  );
+  await user.click(screen.getByText("招待コードを発行"));
+
+  expect(invite).toHaveBeenCalledWith({ id: "h1", role: "viewer" });
+  expect(await screen.findByText("h1.secret")).toBeInTheDocument();
+});
+
+test("joins a household with a code", async () => {
+  const user = userEvent.setup();
+  render(<HouseholdPage />);
+
+  await user.type(screen.getByPlaceholderText("招待コード"), " h4.secret ");
+  await user.click(screen.getByText("参加する"));
+
+  expect(join).toHaveBeenCalledWith({ code: "h4.secret" });
+});
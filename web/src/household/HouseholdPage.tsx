@@ -0,0 +1,225 @@
+import { type FormEvent, useState } from "react";
+import styled from "styled-components";
+import BalancesPanel from "./BalancesPanel";
+import {
+  useCreateHouseholdMutation,
+  useInviteMemberMutation,
+  useJoinHouseholdMutation,
+  useListHouseholdsQuery,
+} from "./householdApi";
+import type { Household, Invitation, Role } from "./types";
+
+const ROLE_LABELS: Record<Role, string> = {
+  owner: "オーナー",
+  editor: "編集",
+  viewer: "閲覧のみ",
+};
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Select = styled.select`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Form = styled.form`
+  display: flex;
+  gap: 0.5rem;
+  margin: 1rem 0 1.5rem;
+`;
+
+const Button = styled.button`
+  padding: 0.5rem 1rem;
+  border: none;
+  border-radius: 4px;
+  background: #646cff;
+  color: #fff;
+  font-size: 0.95rem;
+  cursor: pointer;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: default;
+  }
+`;
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const Code = styled.code`
+  user-select: all;
+  word-break: break-all;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+const ErrorMessage = styled.p`
+  color: #ff6b6b;
+  font-size: 0.85rem;
+  margin: 0;
+`;
+
+// InviteCell issues a one-time code for the household. The code is only
+// shown once, so it stays on screen until another is issued.
+function InviteCell({ household }: { household: Household }) {
+  const [role, setRole] = useState<Role>("editor");
+  const [invitation, setInvitation] = useState<Invitation | null>(null);
+  const [inviteMember, { isLoading }] = useInviteMemberMutation();
+
+  if (household.role !== "owner") return null;
+
+  return (
+    <>
+      <Select
+        aria-label={`${household.name}の招待ロール`}
+        value={role}
+        onChange={(e) => setRole(e.target.value as Role)}
+      >
+        <option value="editor">{ROLE_LABELS.editor}</option>
+        <option value="viewer">{ROLE_LABELS.viewer}</option>
+      </Select>{" "}
+      <Button
+        type="button"
+        disabled={isLoading}
+        onClick={async () => {
+          setInvitation(
+            await inviteMember({ id: household.id, role }).unwrap(),
+          );
+        }}
+      >
+        招待コードを発行
+      </Button>
+      {invitation && (
+        <Message>
+          招待コード: <Code>{invitation.code}</Code>（
+          {new Date(invitation.expires_at).toLocaleDateString()} まで有効）
+        </Message>
+      )}
+    </>
+  );
+}
+
+export default function HouseholdPage() {
+  const { data: households, isLoading, error } = useListHouseholdsQuery();
+  const [createHousehold, { isLoading: isCreating }] =
+    useCreateHouseholdMutation();
+  const [joinHousehold, { isLoading: isJoining, error: joinError }] =
+    useJoinHouseholdMutation();
+  const [name, setName] = useState("");
+  const [code, setCode] = useState("");
+  const [selected, setSelected] = useState<Household | null>(null);
+
+  const handleCreate = async (e: FormEvent) => {
+    e.preventDefault();
+    await createHousehold({ name }).unwrap();
+    setName("");
+  };
+
+  const handleJoin = async (e: FormEvent) => {
+    e.preventDefault();
+    try {
+      await joinHousehold({ code: code.trim() }).unwrap();
+      setCode("");
+    } catch {
+      // Shown from joinError.
+    }
+  };
+
+  return (
+    <>
+      <h2>世帯</h2>
+      <Form onSubmit={handleCreate}>
+        <Input
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+          placeholder="世帯の名前"
+          maxLength={64}
+          required
+        />
+        <Button type="submit" disabled={isCreating}>
+          作成する
+        </Button>
+      </Form>
+      <Form onSubmit={handleJoin}>
+        <Input
+          value={code}
+          onChange={(e) => setCode(e.target.value)}
+          placeholder="招待コード"
+          required
+        />
+        <Button type="submit" disabled={isJoining}>
+          参加する
+        </Button>
+      </Form>
+      {joinError && (
+        <ErrorMessage role="alert">
+          招待コードが無効か、期限切れです
+        </ErrorMessage>
+      )}
+
+      {isLoading && <Message>読み込み中...</Message>}
+      {error && <Message>世帯の取得に失敗しました</Message>}
+      {households && households.length === 0 && (
+        <Message>参加している世帯はありません</Message>
+      )}
+      {households && households.length > 0 && (
+        <Table>
+          <thead>
+            <tr>
+              <Th>名前</Th>
+              <Th>ロール</Th>
+              <Th>招待</Th>
+              <Th>精算</Th>
+            </tr>
+          </thead>
+          <tbody>
+            {households.map((h) => (
+              <tr key={h.id}>
+                <Td>{h.name}</Td>
+                <Td>{ROLE_LABELS[h.role]}</Td>
+                <Td>
+                  <InviteCell household={h} />
+                </Td>
+                <Td>
+                  <Button type="button" onClick={() => setSelected(h)}>
+                    貸し借り
+                  </Button>
+                </Td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      {selected && <BalancesPanel household={selected} />}
+    </>
+  );
+}
@@ -0,0 +1,59 @@
+import { baseApi } from "../store/baseApi";
+import type {
+  Balances,
+  Household,
+  Invitation,
+  RecordSettlementRequest,
+  Role,
+} from "./types";
+
+export const householdApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    listHouseholds: builder.query<Household[], void>({
+      query: () => "/households",
+      transformResponse: (response: { households: Household[] }) =>
+        response.households,
+      providesTags: ["Household"],
+    }),
+    createHousehold: builder.mutation<Household, { name: string }>({
+      query: (body) => ({ url: "/households", method: "POST", body }),
+      invalidatesTags: ["Household"],
+    }),
+    inviteMember: builder.mutation<Invitation, { id: string; role: Role }>({
+      query: ({ id, role }) => ({
+        url: `/households/${id}/invitations`,
+        method: "POST",
+        body: { role },
+      }),
+    }),
+    joinHousehold: builder.mutation<Household, { code: string }>({
+      query: (body) => ({ url: "/households/join", method: "POST", body }),
+      invalidatesTags: ["Household"],
+    }),
+    getBalances: builder.query<Balances, string>({
+      query: (id) => `/households/${id}/balances`,
+      // Split expenses move balances too.
+      providesTags: ["Balance", "Expense"],
+    }),
+    recordSettlement: builder.mutation<
+      { version: number },
+      RecordSettlementRequest
+    >({
+      query: ({ id, ...body }) => ({
+        url: `/households/${id}/settlements`,
+        method: "POST",
+        body,
+      }),
+      invalidatesTags: ["Balance"],
+    }),
+  }),
+});
+
+export const {
+  useListHouseholdsQuery,
+  useCreateHouseholdMutation,
+  useInviteMemberMutation,
+  useJoinHouseholdMutation,
+  useGetBalancesQuery,
+  useRecordSettlementMutation,
+} = householdApi;
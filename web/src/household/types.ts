@@ -0,0 +1,45 @@
+export type Role = "owner" | "editor" | "viewer";
+
+export interface Member {
+  user_id: string;
+  role: Role;
+}
+
+export interface Household {
+  id: string;
+  name: string;
+  role: Role;
+  members?: Member[];
+  version: number;
+}
+
+export interface Invitation {
+  code: string;
+  role: Role;
+  expires_at: string;
+}
+
+export interface Balance {
+  user_id: string;
+  // amount is positive when the member is owed money.
+  amount: number;
+}
+
+export interface Transfer {
+  from: string;
+  to: string;
+  amount: number;
+}
+
+export interface Balances {
+  balances: Balance[];
+  transfers: Transfer[];
+}
+
+export interface RecordSettlementRequest {
+  id: string;
+  from: string;
+  to: string;
+  amount: number;
+  date: string;
+}
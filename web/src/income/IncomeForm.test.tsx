@@ -0,0 +1,86 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import IncomeForm from "./IncomeForm";
+import "@testing-library/jest-dom";
+import * as incomeApi from "./incomeApi";
+import * as accountApi from "../account/accountApi";
+
+jest.mock("./incomeApi");
+jest.mock("../account/accountApi");
+
+beforeEach(() => {
+  (
+    accountApi.useListAccountsQuery as jest.MockedFunction<
+      typeof accountApi.useListAccountsQuery
+    >
+  ).mockReturnValue({
+    data: [
+      {
+        id: "acc-1",
+        book_id: "",
+        name: "給与口座",
+        type: "bank",
+        currency: "JPY",
+        opening_balance: 0,
+        opened_on: "2026-01-01",
+        balance: 0,
+        reconciliation: null,
+      },
+    ],
+  } as unknown as ReturnType<typeof accountApi.useListAccountsQuery>);
+});
+
+const mockUseRecordIncomeMutation =
+  incomeApi.useRecordIncomeMutation as jest.MockedFunction<
+    typeof incomeApi.useRecordIncomeMutation
+  >;
+
+function setupMockMutation(
+  overrides: { isLoading?: boolean; error?: unknown } = {},
+) {
+  const trigger = jest.fn().mockReturnValue({
+    unwrap: () => Promise.resolve({ id: "new-id" }),
+  });
+
+  mockUseRecordIncomeMutation.mockReturnValue([
+    trigger,
+    {
+      isLoading: overrides.isLoading ?? false,
+      error: overrides.error,
+      reset: jest.fn(),
+    } as unknown as ReturnType<typeof incomeApi.useRecordIncomeMutation>[1],
+  ]);
+
+  return trigger;
+}
+
+test("submits income with the chosen source", async () => {
+  const user = userEvent.setup();
+  const trigger = setupMockMutation();
+
+  render(<IncomeForm bookId="household-1" />);
+
+  await user.type(screen.getByPlaceholderText("300000"), "5000");
+  await user.selectOptions(screen.getByLabelText("種類"), "side");
+  await user.type(screen.getByPlaceholderText("2月分"), "原稿料");
+  await user.selectOptions(screen.getByLabelText("口座"), "acc-1");
+  await user.click(screen.getByText("記録する"));
+
+  expect(trigger).toHaveBeenCalledWith(
+    expect.objectContaining({
+      book_id: "household-1",
+      amount: 5000,
+      source: "side",
+      memo: "原稿料",
+      account_id: "acc-1",
+    }),
+  );
+});
+
+test("shows error message on API failure", () => {
+  setupMockMutation({ error: { status: 500, data: "fail" } });
+
+  render(<IncomeForm />);
+
+  expect(screen.getByText("記録に失敗しました")).toBeInTheDocument();
+});
@@ -0,0 +1,166 @@
+import { type FormEvent, useState } from "react";
+import styled from "styled-components";
+import AccountSelect from "../account/AccountSelect";
+import { useRecordIncomeMutation } from "./incomeApi";
+import type { IncomeSource } from "./types";
+
+export const SOURCE_LABELS: Record<IncomeSource, string> = {
+  salary: "給与",
+  bonus: "賞与",
+  side: "副収入",
+  refund: "返金",
+};
+
+const TODAY = new Date().toISOString().slice(0, 10);
+
+const Form = styled.form`
+  display: flex;
+  flex-wrap: wrap;
+  gap: 0.75rem;
+  align-items: flex-end;
+  margin-bottom: 1.5rem;
+`;
+
+const Field = styled.label`
+  display: flex;
+  flex-direction: column;
+  gap: 0.25rem;
+  font-size: 0.85rem;
+`;
+
+const Input = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const Select = styled.select`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+`;
+
+const SubmitButton = styled.button`
+  padding: 0.5rem 1.5rem;
+  background: #0f3460;
+  color: #fff;
+  border: none;
+  border-radius: 4px;
+  cursor: pointer;
+  font-size: 0.95rem;
+
+  &:disabled {
+    opacity: 0.5;
+    cursor: not-allowed;
+  }
+`;
+
+const ErrorMessage = styled.p`
+  color: #ff6b6b;
+  font-size: 0.85rem;
+  width: 100%;
+  margin: 0;
+`;
+
+interface Props {
+  // bookId is the household to record in; the personal book if omitted.
+  bookId?: string;
+}
+
+export default function IncomeForm({ bookId }: Props) {
+  const [amount, setAmount] = useState("");
+  const [source, setSource] = useState<IncomeSource>("salary");
+  const [memo, setMemo] = useState("");
+  const [date, setDate] = useState(TODAY);
+  const [account, setAccount] = useState("");
+
+  const [recordIncome, { isLoading, error }] = useRecordIncomeMutation();
+
+  const handleSubmit = async (e: FormEvent) => {
+    e.preventDefault();
+
+    const parsed = Number(amount);
+    if (!Number.isInteger(parsed) || parsed <= 0) return;
+
+    await recordIncome({
+      book_id: bookId,
+      amount: parsed,
+      source,
+      memo,
+      date,
+      account_id: account || undefined,
+    }).unwrap();
+
+    setAmount("");
+    setMemo("");
+  };
+
+  return (
+    <Form onSubmit={handleSubmit}>
+      <Field>
+        金額
+        <Input
+          type="number"
+          min="1"
+          step="1"
+          value={amount}
+          onChange={(e) => setAmount(e.target.value)}
+          required
+          placeholder="300000"
+        />
+      </Field>
+
+      <Field>
+        種類
+        <Select
+          aria-label="種類"
+          value={source}
+          onChange={(e) => setSource(e.target.value as IncomeSource)}
+        >
+          {Object.entries(SOURCE_LABELS).map(([value, label]) => (
+            <option key={value} value={value}>
+              {label}
+            </option>
+          ))}
+        </Select>
+      </Field>
+
+      <Field>
+        メモ
+        <Input
+          type="text"
+          value={memo}
+          onChange={(e) => setMemo(e.target.value)}
+          placeholder="2月分"
+        />
+      </Field>
+
+      <Field>
+        日付
+        <Input
+          type="date"
+          value={date}
+          onChange={(e) => setDate(e.target.value)}
+          required
+        />
+      </Field>
+
+      <Field>
+        入金先
+        <AccountSelect bookId={bookId} value={account} onChange={setAccount} />
+      </Field>
+
+      <SubmitButton type="submit" disabled={isLoading}>
+        {isLoading ? "記録中..." : "記録する"}
+      </SubmitButton>
+
+      {error && <ErrorMessage>記録に失敗しました</ErrorMessage>}
+    </Form>
+  );
+}
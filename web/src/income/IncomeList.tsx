@@ -0,0 +1,70 @@
+import styled from "styled-components";
+import { useListIncomesQuery } from "./incomeApi";
+import { SOURCE_LABELS } from "./IncomeForm";
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const AmountCell = styled(Td)`
+  text-align: right;
+  font-variant-numeric: tabular-nums;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+interface Props {
+  // book is the household to list; the personal book if omitted.
+  book?: string;
+}
+
+export default function IncomeList({ book }: Props) {
+  const { data: incomes, isLoading, error } = useListIncomesQuery(
+    book ? { book } : undefined,
+  );
+
+  if (isLoading) return <Message>読み込み中...</Message>;
+  if (error) return <Message>一覧の取得に失敗しました</Message>;
+  if (!incomes || incomes.length === 0)
+    return <Message>収入の記録がありません</Message>;
+
+  return (
+    <Table>
+      <thead>
+        <tr>
+          <Th>日付</Th>
+          <Th>種類</Th>
+          <Th>メモ</Th>
+          <Th style={{ textAlign: "right" }}>金額</Th>
+        </tr>
+      </thead>
+      <tbody>
+        {incomes.map((i) => (
+          <tr key={i.id}>
+            <Td>{i.date}</Td>
+            <Td>{SOURCE_LABELS[i.source]}</Td>
+            <Td>{i.memo}</Td>
+            <AmountCell>{i.amount.toLocaleString()}円</AmountCell>
+          </tr>
+        ))}
+      </tbody>
+    </Table>
+  );
+}
@@ -0,0 +1,20 @@
+import { useState } from "react";
+import IncomeForm from "./IncomeForm";
+import IncomeList from "./IncomeList";
+import BookSelect from "../household/BookSelect";
+import { useListHouseholdsQuery } from "../household/householdApi";
+
+export default function IncomePage() {
+  const [book, setBook] = useState("");
+  const { data: households } = useListHouseholdsQuery();
+  const role = households?.find((h) => h.id === book)?.role;
+
+  return (
+    <>
+      <h2>収入</h2>
+      <BookSelect value={book} onChange={setBook} />
+      {role !== "viewer" && <IncomeForm bookId={book || undefined} />}
+      <IncomeList book={book || undefined} />
+    </>
+  );
+}
@@ -0,0 +1,38 @@
+import { baseApi } from "../store/baseApi";
+import type {
+  Income,
+  RecordIncomeRequest,
+  RecordIncomeResponse,
+} from "./types";
+
+export interface ListIncomesParams {
+  book?: string;
+  from?: string;
+  to?: string;
+}
+
+export const incomeApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    listIncomes: builder.query<Income[], ListIncomesParams | void>({
+      query: (params) => {
+        const search = new URLSearchParams();
+        for (const [key, value] of Object.entries(params ?? {})) {
+          if (value) search.append(key, value);
+        }
+        const qs = search.toString();
+        return qs ? `/incomes?${qs}` : "/incomes";
+      },
+      providesTags: ["Income"],
+    }),
+    recordIncome: builder.mutation<RecordIncomeResponse, RecordIncomeRequest>({
+      query: (body) => ({
+        url: "/incomes",
+        method: "POST",
+        body,
+      }),
+      invalidatesTags: ["Income", "Summary", "Score"],
+    }),
+  }),
+});
+
+export const { useListIncomesQuery, useRecordIncomeMutation } = incomeApi;
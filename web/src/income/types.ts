@@ -0,0 +1,26 @@
+export type IncomeSource = "salary" | "bonus" | "side" | "refund";
+
+export interface Income {
+  id: string;
+  book_id: string;
+  amount: number;
+  source: IncomeSource;
+  memo: string;
+  date: string;
+  version: number;
+  created_at: string;
+}
+
+export interface RecordIncomeRequest {
+  book_id?: string;
+  amount: number;
+  source: IncomeSource;
+  memo: string;
+  date: string;
+  // account_id is the account the income was paid into, if any.
+  account_id?: string;
+}
+
+export interface RecordIncomeResponse {
+  id: string;
+}
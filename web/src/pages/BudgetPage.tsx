@@ -1,3 +0,0 @@
-export default function BudgetPage() {
-  return <h2>予算</h2>;
-}
@@ -1,3 +0,0 @@
-export default function ScorePage() {
-  return <h2>スコア</h2>;
-}
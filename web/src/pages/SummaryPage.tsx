@@ -1,3 +0,0 @@
-export default function SummaryPage() {
-  return <h2>サマリー</h2>;
-}
@@ -0,0 +1,53 @@
+import { render, screen } from "@testing-library/react";
+import ProgressCard from "./ProgressCard";
+import "@testing-library/jest-dom";
+import * as profileApi from "./profileApi";
+
+jest.mock("./profileApi");
+
+const mockUseGetProgressQuery =
+  profileApi.useGetProgressQuery as jest.MockedFunction<
+    typeof profileApi.useGetProgressQuery
+  >;
+
+test("shows the level, XP to the next level and recent grants", () => {
+  mockUseGetProgressQuery.mockReturnValue({
+    data: {
+      xp: 160,
+      level: 1,
+      level_xp: 100,
+      next_level_xp: 300,
+      recent: [
+        {
+          event_id: 8,
+          reason: "category-under-budget",
+          subject: "2026-01/食費",
+          caused_by: 7,
+          points: 50,
+          awarded_at: "2026-02-01T12:00:00Z",
+        },
+        {
+          event_id: 2,
+          reason: "same-day-logging",
+          subject: "e1",
+          caused_by: 1,
+          points: 10,
+          awarded_at: "2026-01-10T12:00:00Z",
+        },
+      ],
+    },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof profileApi.useGetProgressQuery>);
+
+  render(<ProgressCard />);
+
+  expect(screen.getByText("Lv. 1")).toBeInTheDocument();
+  expect(
+    screen.getByText("160 XP（次のレベルまで 140 XP）"),
+  ).toBeInTheDocument();
+  expect(
+    screen.getByText("+50 XP 2026-01 の食費を予算内に"),
+  ).toBeInTheDocument();
+  expect(screen.getByText("+10 XP その日のうちに記録")).toBeInTheDocument();
+});
@@ -0,0 +1,81 @@
+import styled from "styled-components";
+import { useGetProgressQuery } from "./profileApi";
+import type { Grant } from "./types";
+
+const Level = styled.p`
+  font-size: 1.5rem;
+  margin: 0 0 0.5rem;
+`;
+
+const Bar = styled.div`
+  height: 0.5rem;
+  background: #2a2a2a;
+  border-radius: 4px;
+  overflow: hidden;
+`;
+
+const Fill = styled.div<{ $ratio: number }>`
+  height: 100%;
+  width: ${({ $ratio }) => $ratio * 100}%;
+  background: #4caf50;
+`;
+
+const List = styled.ul`
+  list-style: none;
+  padding: 0;
+  margin: 0.75rem 0 0;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+  margin: 0.25rem 0 0;
+`;
+
+function describe(g: Grant): string {
+  switch (g.reason) {
+    case "same-day-logging":
+      return "その日のうちに記録";
+    case "category-under-budget": {
+      const [month, category] = g.subject.split("/");
+      return `${month} の${category}を予算内に`;
+    }
+    case "achievement":
+      return "実績を達成";
+  }
+}
+
+export default function ProgressCard() {
+  const { data: progress, isLoading, error } = useGetProgressQuery();
+
+  if (isLoading) return <Message>読み込み中...</Message>;
+  if (error || !progress) {
+    return <Message>経験値の取得に失敗しました</Message>;
+  }
+
+  const ratio =
+    (progress.xp - progress.level_xp) /
+    (progress.next_level_xp - progress.level_xp);
+
+  return (
+    <>
+      <Level>Lv. {progress.level}</Level>
+      <Bar>
+        <Fill $ratio={ratio} />
+      </Bar>
+      <Message>
+        {progress.xp.toLocaleString()} XP（次のレベルまで{" "}
+        {(progress.next_level_xp - progress.xp).toLocaleString()} XP）
+      </Message>
+      <List>
+        {progress.recent.map((g) => (
+          <li key={g.event_id}>
+            <Message>
+              +{g.points} XP {describe(g)}
+            </Message>
+          </li>
+        ))}
+      </List>
+    </>
+  );
+}
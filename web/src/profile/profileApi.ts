@@ -0,0 +1,13 @@
+import { baseApi } from "../store/baseApi";
+import type { Progress } from "./types";
+
+export const profileApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    getProgress: builder.query<Progress, void>({
+      query: () => "/profile/progress",
+      providesTags: ["Progress"],
+    }),
+  }),
+});
+
+export const { useGetProgressQuery } = profileApi;
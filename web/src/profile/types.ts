@@ -0,0 +1,21 @@
+export type GrantReason =
+  | "same-day-logging"
+  | "category-under-budget"
+  | "achievement";
+
+export interface Grant {
+  event_id: number;
+  reason: GrantReason;
+  subject: string;
+  caused_by: number;
+  points: number;
+  awarded_at: string;
+}
+
+export interface Progress {
+  xp: number;
+  level: number;
+  level_xp: number;
+  next_level_xp: number;
+  recent: Grant[];
+}
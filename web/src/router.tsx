@@ -1,18 +1,26 @@
 import { createBrowserRouter } from "react-router-dom";
 import Layout from "./shared/components/Layout";
 import ExpensePage from "./expense/ExpensePage";
-import SummaryPage from "./pages/SummaryPage";
-import BudgetPage from "./pages/BudgetPage";
-import ScorePage from "./pages/ScorePage";
+import IncomePage from "./income/IncomePage";
+import SummaryPage from "./summary/SummaryPage";
+import BudgetPage from "./budget/BudgetPage";
+import ScorePage from "./score/ScorePage";
+import HouseholdPage from "./household/HouseholdPage";
+import AccountPage from "./account/AccountPage";
+import LoginPage from "./auth/LoginPage";
 
 export const router = createBrowserRouter([
+  { path: "login", element: <LoginPage /> },
   {
     element: <Layout />,
     children: [
       { index: true, element: <ExpensePage /> },
+      { path: "incomes", element: <IncomePage /> },
+      { path: "accounts", element: <AccountPage /> },
       { path: "summary", element: <SummaryPage /> },
       { path: "budget", element: <BudgetPage /> },
       { path: "score", element: <ScorePage /> },
+      { path: "households", element: <HouseholdPage /> },
     ],
   },
 ]);
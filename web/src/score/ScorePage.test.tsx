@@ -0,0 +1,87 @@
+import { render, screen } from "@testing-library/react";
+import ScorePage from "./ScorePage";
+import "@testing-library/jest-dom";
+import * as scoreApi from "./scoreApi";
+
+jest.mock("./scoreApi");
+jest.mock("../achievement/AchievementList", () => () => null);
+jest.mock("../profile/ProgressCard", () => () => null);
+
+const mockUseGetCurrentScoreQuery =
+  scoreApi.useGetCurrentScoreQuery as jest.MockedFunction<
+    typeof scoreApi.useGetCurrentScoreQuery
+  >;
+const mockUseGetScoreHistoryQuery =
+  scoreApi.useGetScoreHistoryQuery as jest.MockedFunction<
+    typeof scoreApi.useGetScoreHistoryQuery
+  >;
+
+const result = {
+  spent: 500,
+  ghost_spent: 750,
+  lead: 250,
+  score: 667,
+  income: 3000,
+  saved: 2500,
+  previous_saved: 1500,
+  saved_more: true,
+};
+
+test("displays the score against the ghost and past months", () => {
+  mockUseGetCurrentScoreQuery.mockReturnValue({
+    data: {
+      ...result,
+      month: "2026-02",
+      as_of: "2026-02-07",
+      day: 7,
+      days_in_month: 28,
+      ghost_months: ["2026-01"],
+      days: [],
+    },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof scoreApi.useGetCurrentScoreQuery>);
+  mockUseGetScoreHistoryQuery.mockReturnValue({
+    data: [
+      {
+        month: "2026-01",
+        as_of: "2026-01-31",
+        spent: 1500,
+        ghost_spent: 0,
+        lead: 0,
+        score: null,
+        income: 0,
+        saved: -1500,
+        previous_saved: 0,
+        saved_more: null,
+      },
+      { ...result, month: "2026-02", as_of: "2026-02-07" },
+    ],
+  } as unknown as ReturnType<typeof scoreApi.useGetScoreHistoryQuery>);
+
+  render(<ScorePage />);
+
+  expect(screen.getByText("667 点")).toBeInTheDocument();
+  expect(
+    screen.getByText("貯蓄 2,500円（先月より多く貯められています）"),
+  ).toBeInTheDocument();
+  expect(screen.getByText("2026-01")).toBeInTheDocument();
+  expect(screen.getByText("-")).toBeInTheDocument();
+});
+
+test("explains when there is no past month to race", () => {
+  mockUseGetCurrentScoreQuery.mockReturnValue({
+    data: { ...result, score: null, ghost_months: [] },
+    isLoading: false,
+    error: undefined,
+  } as unknown as ReturnType<typeof scoreApi.useGetCurrentScoreQuery>);
+  mockUseGetScoreHistoryQuery.mockReturnValue({
+    data: [],
+  } as unknown as ReturnType<typeof scoreApi.useGetScoreHistoryQuery>);
+
+  render(<ScorePage />);
+
+  expect(
+    screen.getByText("比べられる過去の月がまだありません"),
+  ).toBeInTheDocument();
+});
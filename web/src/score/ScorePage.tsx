@@ -0,0 +1,110 @@
+import styled from "styled-components";
+import AchievementList from "../achievement/AchievementList";
+import ProgressCard from "../profile/ProgressCard";
+import { useGetCurrentScoreQuery, useGetScoreHistoryQuery } from "./scoreApi";
+
+const Score = styled.p`
+  font-size: 2rem;
+  font-variant-numeric: tabular-nums;
+  margin: 0 0 0.25rem;
+`;
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+  margin-top: 1rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const AmountCell = styled(Td)<{ $behind?: boolean }>`
+  text-align: right;
+  font-variant-numeric: tabular-nums;
+  color: ${({ $behind }) => ($behind ? "#ff6b6b" : "inherit")};
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+function formatLead(lead: number): string {
+  if (lead >= 0) return `ゴーストより ${lead.toLocaleString()}円 少ない`;
+  return `ゴーストより ${(-lead).toLocaleString()}円 多い`;
+}
+
+function formatSavings(saved: number, savedMore: boolean | null): string {
+  const savings = `貯蓄 ${saved.toLocaleString()}円`;
+  if (savedMore === null) return savings;
+  return savedMore ? `${savings}（先月より多く貯められています）` : savings;
+}
+
+export default function ScorePage() {
+  const { data: board, isLoading, error } = useGetCurrentScoreQuery();
+  const { data: history } = useGetScoreHistoryQuery();
+
+  return (
+    <>
+      <h2>スコア</h2>
+      {isLoading ? (
+        <Message>読み込み中...</Message>
+      ) : error || !board ? (
+        <Message>スコアの取得に失敗しました</Message>
+      ) : board.score === null ? (
+        <Message>比べられる過去の月がまだありません</Message>
+      ) : (
+        <>
+          <Score>{board.score} 点</Score>
+          <Message>
+            {board.as_of} 時点 {board.spent.toLocaleString()}円（
+            {formatLead(board.lead)}）
+          </Message>
+          <Message>{formatSavings(board.saved, board.saved_more)}</Message>
+        </>
+      )}
+      {history && history.length > 0 && (
+        <Table>
+          <thead>
+            <tr>
+              <Th>月</Th>
+              <Th style={{ textAlign: "right" }}>支出</Th>
+              <Th style={{ textAlign: "right" }}>ゴースト</Th>
+              <Th style={{ textAlign: "right" }}>貯蓄</Th>
+              <Th style={{ textAlign: "right" }}>スコア</Th>
+            </tr>
+          </thead>
+          <tbody>
+            {history.map((r) => (
+              <tr key={r.month}>
+                <Td>{r.month}</Td>
+                <AmountCell $behind={r.lead < 0}>
+                  {r.spent.toLocaleString()}円
+                </AmountCell>
+                <AmountCell>{r.ghost_spent.toLocaleString()}円</AmountCell>
+                <AmountCell $behind={r.saved_more === false}>
+                  {r.saved.toLocaleString()}円
+                </AmountCell>
+                <AmountCell>{r.score ?? "-"}</AmountCell>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+      <h3>レベル</h3>
+      <ProgressCard />
+      <h3>実績</h3>
+      <AchievementList />
+    </>
+  );
+}
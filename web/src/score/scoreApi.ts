@@ -0,0 +1,17 @@
+import { baseApi } from "../store/baseApi";
+import type { MonthResult, ScoreBoard } from "./types";
+
+export const scoreApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    getCurrentScore: builder.query<ScoreBoard, void>({
+      query: () => "/scores/current",
+      providesTags: ["Score"],
+    }),
+    getScoreHistory: builder.query<MonthResult[], void>({
+      query: () => "/scores/history",
+      providesTags: ["Score"],
+    }),
+  }),
+});
+
+export const { useGetCurrentScoreQuery, useGetScoreHistoryQuery } = scoreApi;
@@ -0,0 +1,32 @@
+export interface ScoreResult {
+  spent: number;
+  ghost_spent: number;
+  lead: number;
+  score: number | null;
+  income: number;
+  saved: number;
+  previous_saved: number;
+  // saved_more is null when there is no previous month to beat.
+  saved_more: boolean | null;
+}
+
+export interface DayPoint {
+  day: number;
+  date: string;
+  spent: number | null;
+  ghost: number | null;
+}
+
+export interface ScoreBoard extends ScoreResult {
+  month: string;
+  as_of: string;
+  day: number;
+  days_in_month: number;
+  ghost_months: string[];
+  days: DayPoint[];
+}
+
+export interface MonthResult extends ScoreResult {
+  month: string;
+  as_of: string;
+}
@@ -1,5 +1,9 @@
-import { NavLink, Outlet } from "react-router-dom";
+import { useDispatch, useSelector } from "react-redux";
+import { Navigate, NavLink, Outlet } from "react-router-dom";
 import styled from "styled-components";
+import { loggedOut } from "../../auth/authSlice";
+import { baseApi } from "../../store/baseApi";
+import type { RootState } from "../../store";
 
 const Nav = styled.nav`
   display: flex;
@@ -26,20 +30,61 @@ const StyledNavLink = styled(NavLink)`
   }
 `;
 
+const Account = styled.div`
+  margin-left: auto;
+  display: flex;
+  align-items: center;
+  gap: 0.75rem;
+  padding: 0 1.25rem;
+  color: #ffffffcc;
+  font-size: 0.9rem;
+`;
+
+const LogoutButton = styled.button`
+  border: 1px solid #ffffff44;
+  border-radius: 4px;
+  background: none;
+  color: inherit;
+  padding: 0.25rem 0.75rem;
+  cursor: pointer;
+`;
+
 const Main = styled.main`
   padding: 1.5rem;
 `;
 
 export default function Layout() {
+  const session = useSelector((state: RootState) => state.auth.session);
+  const dispatch = useDispatch();
+
+  if (!session) {
+    return <Navigate to="/login" replace />;
+  }
+
   return (
     <>
       <Nav>
         <StyledNavLink to="/" end>
           支出
         </StyledNavLink>
+        <StyledNavLink to="/incomes">収入</StyledNavLink>
+        <StyledNavLink to="/accounts">口座</StyledNavLink>
         <StyledNavLink to="/summary">サマリー</StyledNavLink>
         <StyledNavLink to="/budget">予算</StyledNavLink>
         <StyledNavLink to="/score">スコア</StyledNavLink>
+        <StyledNavLink to="/households">世帯</StyledNavLink>
+        <Account>
+          {session.user.username}
+          <LogoutButton
+            type="button"
+            onClick={() => {
+              dispatch(loggedOut());
+              dispatch(baseApi.util.resetApiState());
+            }}
+          >
+            ログアウト
+          </LogoutButton>
+        </Account>
       </Nav>
       <Main>
         <Outlet />
@@ -1,10 +1,50 @@
-import { createApi, fetchBaseQuery } from "@reduxjs/toolkit/query/react";
+import {
+  createApi,
+  fetchBaseQuery,
+  type BaseQueryFn,
+  type FetchArgs,
+  type FetchBaseQueryError,
+} from "@reduxjs/toolkit/query/react";
+import { loggedOut, type AuthState } from "../auth/authSlice";
+
+const rawBaseQuery = fetchBaseQuery({
+  baseUrl: import.meta.env.VITE_API_BASE_URL ?? "http://localhost:8080",
+  prepareHeaders: (headers, { getState }) => {
+    headers.set("X-Client", "web");
+    const { session } = (getState() as { auth: AuthState }).auth;
+    if (session) {
+      headers.set("Authorization", `Bearer ${session.token}`);
+    }
+    return headers;
+  },
+});
+
+// baseQuery logs out when the server rejects the token, so an expired
+// session leads back to the login page.
+const baseQuery: BaseQueryFn<string | FetchArgs, unknown, FetchBaseQueryError> =
+  async (args, api, extraOptions) => {
+    const result = await rawBaseQuery(args, api, extraOptions);
+    const { session } = (api.getState() as { auth: AuthState }).auth;
+    if (result.error?.status === 401 && session) {
+      api.dispatch(loggedOut());
+    }
+    return result;
+  };
 
 export const baseApi = createApi({
   reducerPath: "api",
-  baseQuery: fetchBaseQuery({
-    baseUrl: import.meta.env.VITE_API_BASE_URL ?? "http://localhost:8080",
-  }),
-  tagTypes: ["Expense", "Summary", "Budget", "Score"],
+  baseQuery,
+  tagTypes: [
+    "Expense",
+    "Income",
+    "Summary",
+    "Budget",
+    "Score",
+    "Achievement",
+    "Progress",
+    "Household",
+    "Balance",
+    "Account",
+  ],
   endpoints: () => ({}),
 });
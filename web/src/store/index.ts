@@ -1,13 +1,24 @@
 import { configureStore } from "@reduxjs/toolkit";
 import { baseApi } from "./baseApi";
+import authReducer, { saveSession } from "../auth/authSlice";
 
 export const store = configureStore({
   reducer: {
+    auth: authReducer,
     [baseApi.reducerPath]: baseApi.reducer,
   },
   middleware: (getDefaultMiddleware) =>
     getDefaultMiddleware().concat(baseApi.middleware),
 });
 
+let session = store.getState().auth.session;
+store.subscribe(() => {
+  const next = store.getState().auth.session;
+  if (next !== session) {
+    session = next;
+    saveSession(session);
+  }
+});
+
 export type RootState = ReturnType<typeof store.getState>;
 export type AppDispatch = typeof store.dispatch;
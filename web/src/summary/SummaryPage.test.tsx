@@ -0,0 +1,51 @@
+import { render, screen } from "@testing-library/react";
+import SummaryPage from "./SummaryPage";
+import "@testing-library/jest-dom";
+import * as summaryApi from "./summaryApi";
+
+jest.mock("./summaryApi");
+
+const mockUseGetMonthlySummaryQuery =
+  summaryApi.useGetMonthlySummaryQuery as jest.MockedFunction<
+    typeof summaryApi.useGetMonthlySummaryQuery
+  >;
+
+test("displays the month's total and category breakdown", () => {
+  mockUseGetMonthlySummaryQuery.mockReturnValue({
+    data: {
+      month: "2026-02",
+      total: 3000,
+      count: 3,
+      income: 12000,
+      net_savings: 9000,
+      savings_rate: 0.75,
+      categories: [
+        { category: "食費", total: 1500, count: 2, previous_total: 2000 },
+        { category: "交通費", total: 1500, count: 1, previous_total: 0 },
+      ],
+      daily: [],
+      previous: {
+        month: "2026-01",
+        total: 2000,
+        count: 1,
+        income: 0,
+        net_savings: -2000,
+        difference: 1000,
+        change_rate: 0.5,
+      },
+    },
+    isLoading: false,
+    error: undefined,
+    refetch: jest.fn(),
+  } as unknown as ReturnType<typeof summaryApi.useGetMonthlySummaryQuery>);
+
+  render(<SummaryPage />);
+
+  expect(screen.getByText("3,000円")).toBeInTheDocument();
+  expect(screen.getByText("前月比 +1,000円（+50%）")).toBeInTheDocument();
+  expect(
+    screen.getByText("収入 12,000円・純貯蓄 9,000円（貯蓄率 75%）"),
+  ).toBeInTheDocument();
+  expect(screen.getByText("食費")).toBeInTheDocument();
+  expect(screen.getByText("交通費")).toBeInTheDocument();
+});
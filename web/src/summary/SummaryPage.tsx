@@ -0,0 +1,116 @@
+import { useState } from "react";
+import styled from "styled-components";
+import { useGetMonthlySummaryQuery } from "./summaryApi";
+import type { Comparison, MonthlySummary } from "./types";
+
+const THIS_MONTH = new Date().toISOString().slice(0, 7);
+
+const MonthInput = styled.input`
+  padding: 0.5rem;
+  border: 1px solid #444;
+  border-radius: 4px;
+  background: #1a1a1a;
+  color: inherit;
+  font-size: 0.95rem;
+  margin-bottom: 1.5rem;
+`;
+
+const Total = styled.p`
+  font-size: 1.5rem;
+  font-variant-numeric: tabular-nums;
+  margin: 0 0 0.25rem;
+`;
+
+const Table = styled.table`
+  width: 100%;
+  border-collapse: collapse;
+  font-size: 0.95rem;
+  margin-top: 1rem;
+`;
+
+const Th = styled.th`
+  text-align: left;
+  padding: 0.5rem 0.75rem;
+  border-bottom: 2px solid #333;
+  white-space: nowrap;
+`;
+
+const Td = styled.td`
+  padding: 0.5rem 0.75rem;
+  border-bottom: 1px solid #2a2a2a;
+`;
+
+const AmountCell = styled(Td)`
+  text-align: right;
+  font-variant-numeric: tabular-nums;
+`;
+
+const Message = styled.p`
+  color: #888;
+  font-size: 0.9rem;
+`;
+
+function formatComparison(previous: Comparison): string {
+  const sign = previous.difference > 0 ? "+" : "";
+  const diff = `${sign}${previous.difference.toLocaleString()}円`;
+  if (previous.change_rate === null) return `前月比 ${diff}`;
+  return `前月比 ${diff}（${sign}${Math.round(previous.change_rate * 100)}%）`;
+}
+
+function formatSavings(summary: MonthlySummary): string {
+  const savings = `収入 ${summary.income.toLocaleString()}円・純貯蓄 ${summary.net_savings.toLocaleString()}円`;
+  if (summary.savings_rate === null) return savings;
+  return `${savings}（貯蓄率 ${Math.round(summary.savings_rate * 100)}%）`;
+}
+
+export default function SummaryPage() {
+  const [month, setMonth] = useState(THIS_MONTH);
+  const { data: summary, isLoading, error } = useGetMonthlySummaryQuery(month);
+
+  return (
+    <>
+      <h2>サマリー</h2>
+      <MonthInput
+        type="month"
+        aria-label="月"
+        value={month}
+        onChange={(e) => e.target.value && setMonth(e.target.value)}
+      />
+      {isLoading ? (
+        <Message>読み込み中...</Message>
+      ) : error || !summary ? (
+        <Message>サマリーの取得に失敗しました</Message>
+      ) : (
+        <>
+          <Total>{summary.total.toLocaleString()}円</Total>
+          <Message>{formatComparison(summary.previous)}</Message>
+          <Message>{formatSavings(summary)}</Message>
+          {summary.categories.length === 0 ? (
+            <Message>この月の支出はありません</Message>
+          ) : (
+            <Table>
+              <thead>
+                <tr>
+                  <Th>カテゴリ</Th>
+                  <Th style={{ textAlign: "right" }}>件数</Th>
+                  <Th style={{ textAlign: "right" }}>金額</Th>
+                  <Th style={{ textAlign: "right" }}>前月</Th>
+                </tr>
+              </thead>
+              <tbody>
+                {summary.categories.map((c) => (
+                  <tr key={c.category}>
+                    <Td>{c.category}</Td>
+                    <AmountCell>{c.count}</AmountCell>
+                    <AmountCell>{c.total.toLocaleString()}円</AmountCell>
+                    <AmountCell>{c.previous_total.toLocaleString()}円</AmountCell>
+                  </tr>
+                ))}
+              </tbody>
+            </Table>
+          )}
+        </>
+      )}
+    </>
+  );
+}
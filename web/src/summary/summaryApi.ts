@@ -0,0 +1,27 @@
+import { baseApi } from "../store/baseApi";
+import type { MonthlySummary } from "./types";
+
+export const summaryApi = baseApi.injectEndpoints({
+  endpoints: (builder) => ({
+    getMonthlySummary: builder.query<MonthlySummary, string>({
+      query: (month) => ({
+        url: "/summaries/monthly",
+        params: { month },
+      }),
+      providesTags: ["Summary"],
+    }),
+    getMonthlySummaryRange: builder.query<
+      MonthlySummary[],
+      { from: string; to: string }
+    >({
+      query: (params) => ({
+        url: "/summaries/monthly/range",
+        params,
+      }),
+      providesTags: ["Summary"],
+    }),
+  }),
+});
+
+export const { useGetMonthlySummaryQuery, useGetMonthlySummaryRangeQuery } =
+  summaryApi;
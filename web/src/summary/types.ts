@@ -0,0 +1,35 @@
+export interface CategoryTotal {
+  category: string;
+  total: number;
+  count: number;
+  previous_total: number;
+}
+
+export interface DayTotal {
+  date: string;
+  total: number;
+  count: number;
+}
+
+export interface Comparison {
+  month: string;
+  total: number;
+  count: number;
+  income: number;
+  net_savings: number;
+  difference: number;
+  change_rate: number | null;
+}
+
+export interface MonthlySummary {
+  month: string;
+  total: number;
+  count: number;
+  income: number;
+  net_savings: number;
+  // savings_rate is null for months without income.
+  savings_rate: number | null;
+  categories: CategoryTotal[];
+  daily: DayTotal[];
+  previous: Comparison;
+}